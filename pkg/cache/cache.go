@@ -265,6 +265,21 @@ func NewCachedNotionClient(client notion.Client, cache NotionCache) *CachedNotio
 	}
 }
 
+// ListUsers implements notion.Client interface without caching
+func (c *CachedNotionClient) ListUsers(ctx context.Context) ([]notion.User, error) {
+	return c.client.ListUsers(ctx)
+}
+
+// GetUser implements notion.Client interface; the client caches users itself
+func (c *CachedNotionClient) GetUser(ctx context.Context, userID string) (*notion.User, error) {
+	return c.client.GetUser(ctx, userID)
+}
+
+// GetCurrentUser implements notion.Client interface without caching
+func (c *CachedNotionClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	return c.client.GetCurrentUser(ctx)
+}
+
 // GetPage implements notion.Client interface with caching
 func (c *CachedNotionClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
 	// Check cache first
@@ -283,6 +298,30 @@ func (c *CachedNotionClient) GetPage(ctx context.Context, pageID string) (*notio
 	return page, nil
 }
 
+// GetPagesMetadata implements notion.Client interface with caching: only
+// pages not in the cache are fetched
+func (c *CachedNotionClient) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*notion.Page, error) {
+	pages := make(map[string]*notion.Page, len(pageIDs))
+	var uncached []string
+	for _, pageID := range pageIDs {
+		if page, exists := c.cache.GetPage(ctx, pageID); exists {
+			pages[pageID] = page
+		} else {
+			uncached = append(uncached, pageID)
+		}
+	}
+	if len(uncached) == 0 {
+		return pages, nil
+	}
+
+	fetched, err := c.client.GetPagesMetadata(ctx, uncached)
+	for pageID, page := range fetched {
+		c.cache.SetPage(pageID, page, 0) // Use default TTL
+		pages[pageID] = page
+	}
+	return pages, err
+}
+
 // GetPageBlocks implements notion.Client interface with caching
 func (c *CachedNotionClient) GetPageBlocks(ctx context.Context, pageID string) ([]notion.Block, error) {
 	// Check cache first
@@ -325,18 +364,50 @@ func (c *CachedNotionClient) CreatePage(ctx context.Context, parentID string, pr
 	return c.client.CreatePage(ctx, parentID, properties)
 }
 
+func (c *CachedNotionClient) GetBlock(ctx context.Context, blockID string) (*notion.Block, error) {
+	return c.client.GetBlock(ctx, blockID)
+}
+
+func (c *CachedNotionClient) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*notion.Block, error) {
+	// Blocks are cached by page, and a block doesn't say which page it is
+	// on, so drop everything rather than serve stale content
+	c.cache.Clear()
+	return c.client.UpdateBlock(ctx, blockID, payload)
+}
+
 func (c *CachedNotionClient) UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
 	// Invalidate cache when updating
 	c.cache.InvalidatePage(pageID)
 	return c.client.UpdatePageBlocks(ctx, pageID, blocks)
 }
 
+func (c *CachedNotionClient) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	// Invalidate cache when appending
+	c.cache.InvalidatePage(pageID)
+	return c.client.AppendPageBlocks(ctx, pageID, blocks)
+}
+
+func (c *CachedNotionClient) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	// Invalidate cache when updating
+	c.cache.InvalidatePage(pageID)
+	return c.client.UpdatePageProperties(ctx, pageID, properties)
+}
+
+func (c *CachedNotionClient) GetPageProperty(ctx context.Context, pageID, propertyID string) (*notion.PropertyValue, error) {
+	return c.client.GetPageProperty(ctx, pageID, propertyID)
+}
+
 func (c *CachedNotionClient) DeletePage(ctx context.Context, pageID string) error {
 	// Invalidate cache when deleting
 	c.cache.InvalidatePage(pageID)
 	return c.client.DeletePage(ctx, pageID)
 }
 
+func (c *CachedNotionClient) TrashPage(ctx context.Context, pageID string) error {
+	c.cache.InvalidatePage(pageID)
+	return c.client.TrashPage(ctx, pageID)
+}
+
 func (c *CachedNotionClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
 	return c.client.RecreatePageWithBlocks(ctx, parentID, properties, blocks)
 }
@@ -349,12 +420,12 @@ func (c *CachedNotionClient) GetChildPages(ctx context.Context, parentID string)
 	return c.client.GetChildPages(ctx, parentID)
 }
 
-func (c *CachedNotionClient) GetAllDescendantPages(ctx context.Context, parentID string) ([]notion.Page, error) {
-	return c.client.GetAllDescendantPages(ctx, parentID)
+func (c *CachedNotionClient) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error) {
+	return c.client.GetAllDescendantPages(ctx, parentID, maxDepth)
 }
 
-func (c *CachedNotionClient) StreamDescendantPages(ctx context.Context, parentID string) *notion.PageStream {
-	return c.client.StreamDescendantPages(ctx, parentID)
+func (c *CachedNotionClient) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *notion.PageStream {
+	return c.client.StreamDescendantPages(ctx, parentID, maxDepth)
 }
 
 func (c *CachedNotionClient) StreamDatabaseRows(ctx context.Context, databaseID string) *notion.DatabaseRowStream {
@@ -22,6 +22,28 @@ type mockNotionClient struct {
 	getDatabaseErr   error
 }
 
+func (m *mockNotionClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	return &notion.User{ID: "bot-user"}, nil
+}
+
+func (m *mockNotionClient) ListUsers(ctx context.Context) ([]notion.User, error) {
+	return nil, nil
+}
+
+func (m *mockNotionClient) GetUser(ctx context.Context, userID string) (*notion.User, error) {
+	return &notion.User{ID: userID}, nil
+}
+
+func (m *mockNotionClient) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*notion.Page, error) {
+	pages := make(map[string]*notion.Page, len(pageIDs))
+	for _, pageID := range pageIDs {
+		if page, err := m.GetPage(ctx, pageID); err == nil {
+			pages[pageID] = page
+		}
+	}
+	return pages, nil
+}
+
 func (m *mockNotionClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
 	m.getPageCalls++
 	if m.getPageErr != nil {
@@ -62,14 +84,38 @@ func (m *mockNotionClient) CreatePage(ctx context.Context, parentID string, prop
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockNotionClient) GetBlock(ctx context.Context, blockID string) (*notion.Block, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotionClient) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*notion.Block, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockNotionClient) UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
 	return errors.New("not implemented")
 }
 
+func (m *mockNotionClient) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotionClient) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotionClient) GetPageProperty(ctx context.Context, pageID, propertyID string) (*notion.PropertyValue, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockNotionClient) DeletePage(ctx context.Context, pageID string) error {
 	return errors.New("not implemented")
 }
 
+func (m *mockNotionClient) TrashPage(ctx context.Context, pageID string) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockNotionClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
 	return nil, errors.New("not implemented")
 }
@@ -82,11 +128,11 @@ func (m *mockNotionClient) GetChildPages(ctx context.Context, parentID string) (
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockNotionClient) GetAllDescendantPages(ctx context.Context, parentID string) ([]notion.Page, error) {
+func (m *mockNotionClient) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockNotionClient) StreamDescendantPages(ctx context.Context, parentID string) *notion.PageStream {
+func (m *mockNotionClient) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *notion.PageStream {
 	stream := notion.NewPageStream()
 	go func() {
 		defer stream.Close()
@@ -387,6 +433,44 @@ func TestCachedNotionClient_GetPage(t *testing.T) {
 	}
 }
 
+func TestCachedNotionClient_GetPagesMetadata(t *testing.T) {
+	mockClient := &mockNotionClient{
+		pages: map[string]*notion.Page{
+			"page-1": {ID: "page-1"},
+			"page-2": {ID: "page-2"},
+		},
+	}
+
+	cache := NewNotionCache(10, 1*time.Hour)
+	cachedClient := NewCachedNotionClient(mockClient, cache)
+
+	ctx := context.Background()
+
+	if _, err := cachedClient.GetPage(ctx, "page-1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only the page not yet cached should hit the API
+	pages, err := cachedClient.GetPagesMetadata(ctx, []string{"page-1", "page-2", "missing"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(pages) != 2 || pages["page-1"] == nil || pages["page-2"] == nil {
+		t.Errorf("Expected page-1 and page-2, got %v", pages)
+	}
+	if mockClient.getPageCalls != 3 {
+		t.Errorf("Expected 3 API calls, got %d", mockClient.getPageCalls)
+	}
+
+	// Both found pages are now cached
+	if _, err := cachedClient.GetPagesMetadata(ctx, []string{"page-1", "page-2"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if mockClient.getPageCalls != 3 {
+		t.Errorf("Expected 3 API calls (cached), got %d", mockClient.getPageCalls)
+	}
+}
+
 func TestCachedNotionClient_GetPageBlocks(t *testing.T) {
 	mockClient := &mockNotionClient{
 		blocks: map[string][]notion.Block{
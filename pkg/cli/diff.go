@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [file]",
+	Short: "Show differences between local files and Notion",
+	Long: `Show a unified diff between local markdown files and their Notion pages
+without syncing anything. Only read requests are made to Notion.
+
+With --direction push (default) the diff shows what a push would change in
+Notion; with --direction pull it shows what a pull would change locally.
+
+If no file is specified, all markdown files in the directory are compared.
+
+Examples:
+  notion-md-sync diff docs/file.md                  # Preview a push of one file
+  notion-md-sync diff docs/file.md --direction pull # Preview a pull of one file
+  notion-md-sync diff --directory docs              # Preview a push of a directory`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+var (
+	diffDirection string
+	diffDirectory string
+)
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffDirection, "direction", "d", "push", "diff direction (push, pull)")
+	diffCmd.Flags().StringVar(&diffDirectory, "directory", "", "directory containing markdown files (defaults to config's markdown_root)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffDirection != "push" && diffDirection != "pull" {
+		return fmt.Errorf("invalid direction: %s (must be push or pull)", diffDirection)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var files []string
+	if len(args) > 0 {
+		if err := util.ValidateFilePath(args[0], true); err != nil {
+			return fmt.Errorf("invalid file path: %w", err)
+		}
+		files = []string{args[0]}
+	} else {
+		dir := diffDirectory
+		if dir == "" {
+			dir = cfg.Directories.MarkdownRoot
+		}
+		if err := util.ValidateDirectoryPath(dir, true); err != nil {
+			return fmt.Errorf("invalid directory: %w", err)
+		}
+		files, err = findMarkdownFiles(dir)
+		if err != nil {
+			return fmt.Errorf("failed to find markdown files: %w", err)
+		}
+	}
+
+	engine, err := newSyncEngine(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := commandContext(cfg)
+	defer cancel()
+
+	changed, err := writeDiffs(ctx, engine, files, diffDirection, os.Stdout, isTerminal(os.Stdout))
+	if err != nil {
+		return err
+	}
+
+	if len(files) > 1 || changed == 0 {
+		fmt.Printf("%d of %d file(s) differ\n", changed, len(files))
+	}
+	return nil
+}
+
+// writeDiffs writes the diff of each file to w and returns how many files
+// differ. A single file's error is returned; in directory mode errors are
+// reported and the remaining files are still compared.
+func writeDiffs(ctx context.Context, engine sync.Engine, files []string, direction string, w io.Writer, color bool) (int, error) {
+	changed := 0
+	for _, file := range files {
+		diff, err := engine.DiffFile(ctx, file, direction)
+		if err != nil {
+			if len(files) == 1 {
+				return 0, fmt.Errorf("failed to diff %s: %w", file, err)
+			}
+			util.Warning("Skipping %s: %v", file, err)
+			continue
+		}
+
+		if diff == "" {
+			printVerbose("No changes: %s", file)
+			continue
+		}
+
+		changed++
+		if color {
+			diff = sync.ColorizeDiff(diff)
+		}
+		if _, err := fmt.Fprint(w, diff); err != nil {
+			return changed, fmt.Errorf("failed to write diff: %w", err)
+		}
+	}
+	return changed, nil
+}
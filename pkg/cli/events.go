@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	gosync "sync"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+)
+
+// EventSchemaVersion is the version of the --json event schema. It changes
+// only when fields are removed or change meaning; new fields may be added
+// without a new version.
+const EventSchemaVersion = 1
+
+// Event types written by --json
+const (
+	EventStart   = "start"   // The command started
+	EventPage    = "page"    // A page or file synced
+	EventError   = "error"   // A page or file, or the whole command, failed
+	EventSummary = "summary" // The command finished; always the last event
+)
+
+// Event is one line of --json output
+type Event struct {
+	Version   int       `json:"version"`
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"` // The command: sync, pull or push
+	Direction string    `json:"direction"` // push, pull or bidirectional
+	File      string    `json:"file,omitempty"`
+	PageID    string    `json:"page_id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Completed int       `json:"completed"` // Items finished so far, including failures
+	Failed    int       `json:"failed"`
+	Skipped   int       `json:"skipped"`
+	Total     int       `json:"total"` // 0 when not known up front
+	Error     string    `json:"error,omitempty"`
+}
+
+// eventStream writes a command's events as newline-delimited JSON and keeps
+// the running counts. A nil stream ignores everything, so commands can call
+// it whether or not --json was given.
+type eventStream struct {
+	mu        gosync.Mutex
+	enc       *json.Encoder
+	now       func() time.Time
+	operation string
+	direction string
+	completed int
+	failed    int
+	skipped   int
+	total     int
+}
+
+// newEventStream writes a start event to w and returns the stream
+func newEventStream(w io.Writer, operation, direction string) *eventStream {
+	s := &eventStream{
+		enc:       json.NewEncoder(w),
+		now:       time.Now,
+		operation: operation,
+		direction: direction,
+	}
+	s.emit(Event{Type: EventStart})
+	return s
+}
+
+// openEventStream starts the event stream on stdout when --json is given
+// and silences the human-readable output that would otherwise share it.
+// It returns nil without --json.
+func openEventStream(operation, direction string) *eventStream {
+	if !jsonOutput {
+		return nil
+	}
+	util.GetDefaultLogger().SetOutput(io.Discard)
+	return newEventStream(os.Stdout, operation, direction)
+}
+
+// humanOutput is where commands print for people: stdout, or nowhere when
+// stdout carries --json events
+func humanOutput() io.Writer {
+	if jsonOutput {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// SetTotal records how many items the command will sync
+func (s *eventStream) SetTotal(total int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total = total
+}
+
+// Synced records the outcome of one file or page synced by the command
+func (s *eventStream) Synced(file, pageID string, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed++
+	if err != nil {
+		s.failed++
+	}
+	s.emit(itemEvent(Event{File: file, PageID: pageID}, err))
+}
+
+// Skipped records a file the command passed over
+func (s *eventStream) Skipped() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped++
+}
+
+// HandleProgress adapts engine progress events to the stream
+func (s *eventStream) HandleProgress(event sync.ProgressEvent) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed = event.Completed
+	s.failed = event.Failed
+	s.skipped = event.Skipped
+	if event.Total > 0 {
+		s.total = event.Total
+	}
+	s.emit(itemEvent(Event{PageID: event.PageID, Title: event.Title}, event.Err))
+}
+
+// Finish writes an error event if the command failed, then the summary
+func (s *eventStream) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.emit(Event{Type: EventError, Error: err.Error()})
+	}
+	s.emit(Event{Type: EventSummary})
+}
+
+// itemEvent makes a page event, or an error event when err is set
+func itemEvent(event Event, err error) Event {
+	event.Type = EventPage
+	if err != nil {
+		event.Type = EventError
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// emit fills in the common fields and writes the event; s.mu must be held
+func (s *eventStream) emit(event Event) {
+	event.Version = EventSchemaVersion
+	event.Time = s.now().UTC()
+	event.Operation = s.operation
+	event.Direction = s.direction
+	event.Completed = s.completed
+	event.Failed = s.failed
+	event.Skipped = s.skipped
+	event.Total = s.total
+	// Events are best effort; a closed stdout fails the command anyway
+	_ = s.enc.Encode(event)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvents decodes newline-delimited JSON events, failing on any line
+// that isn't a single well-formed event
+func readEvents(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "line %q", scanner.Text())
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestEventStream_DirectorySync(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.md", "b.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("# "+name), 0644))
+	}
+
+	engine := &mockSyncEngine{
+		syncFileToNotionFunc: func(ctx context.Context, filePath string) error {
+			if strings.HasSuffix(filePath, "b.md") {
+				return errors.New("rate limited")
+			}
+			return nil
+		},
+	}
+
+	var buf bytes.Buffer
+	events := newEventStream(&buf, "sync", "push")
+	err := performDirectorySync(context.Background(), engine, dir, "push", events)
+	events.Finish(err)
+	require.NoError(t, err)
+
+	got := readEvents(t, &buf)
+	require.Len(t, got, 4)
+
+	var types []string
+	for _, event := range got {
+		types = append(types, event["type"].(string))
+		assert.Equal(t, float64(EventSchemaVersion), event["version"])
+		assert.Equal(t, "sync", event["operation"])
+		assert.Equal(t, "push", event["direction"])
+		_, err := time.Parse(time.RFC3339Nano, event["time"].(string))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{EventStart, EventPage, EventError, EventSummary}, types)
+
+	assert.Equal(t, filepath.Join(dir, "a.md"), got[1]["file"])
+	assert.Equal(t, float64(1), got[1]["completed"])
+	assert.Equal(t, float64(2), got[1]["total"])
+	assert.NotContains(t, got[1], "error")
+
+	assert.Equal(t, filepath.Join(dir, "b.md"), got[2]["file"])
+	assert.Equal(t, "rate limited", got[2]["error"])
+	assert.Equal(t, float64(1), got[2]["failed"])
+
+	summary := got[3]
+	assert.Equal(t, float64(2), summary["completed"])
+	assert.Equal(t, float64(1), summary["failed"])
+	assert.Equal(t, float64(0), summary["skipped"])
+	assert.Equal(t, float64(2), summary["total"])
+}
+
+func TestEventStream_ProgressAndFailure(t *testing.T) {
+	var buf bytes.Buffer
+	events := newEventStream(&buf, "pull", "pull")
+	events.HandleProgress(sync.ProgressEvent{PageID: "page-1", Title: "Intro", Completed: 1, Total: 3})
+	events.Finish(errors.New("pull failed: context deadline exceeded"))
+
+	got := readEvents(t, &buf)
+	require.Len(t, got, 4)
+	assert.Equal(t, EventPage, got[1]["type"])
+	assert.Equal(t, "page-1", got[1]["page_id"])
+	assert.Equal(t, "Intro", got[1]["title"])
+	assert.Equal(t, float64(3), got[1]["total"])
+
+	assert.Equal(t, EventError, got[2]["type"])
+	assert.Equal(t, "pull failed: context deadline exceeded", got[2]["error"])
+	assert.NotContains(t, got[2], "page_id")
+	assert.Equal(t, EventSummary, got[3]["type"])
+}
+
+func TestEventStream_Nil(t *testing.T) {
+	var events *eventStream
+	assert.NotPanics(t, func() {
+		events.SetTotal(1)
+		events.Synced("a.md", "", nil)
+		events.Skipped()
+		events.HandleProgress(sync.ProgressEvent{Completed: 1})
+		events.Finish(nil)
+	})
+}
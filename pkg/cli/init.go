@@ -2,11 +2,17 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/cobra"
 )
@@ -19,94 +25,134 @@ It will create the necessary configuration files and directories to get you star
 	RunE: runInit,
 }
 
+var (
+	initYes          bool
+	initToken        string
+	initParentPage   string
+	initMarkdownRoot string
+	initSkipVerify   bool
+)
+
+// newInitClient creates the client used to verify credentials; tests replace it
+var newInitClient = func(token string) notion.Client {
+	return notion.NewClient(token)
+}
+
 func init() {
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "don't prompt; take credentials from flags or environment variables")
+	initCmd.Flags().StringVar(&initToken, "token", "", "Notion integration token (default: $NOTION_MD_SYNC_NOTION_TOKEN)")
+	initCmd.Flags().StringVar(&initParentPage, "parent-page", "", "parent page URL or ID (default: $NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID)")
+	initCmd.Flags().StringVar(&initMarkdownRoot, "markdown-root", "./docs", "directory for markdown files")
+	initCmd.Flags().BoolVar(&initSkipVerify, "skip-verify", false, "don't check the token and parent page against the Notion API")
 	rootCmd.AddCommand(initCmd)
 }
 
-func runInit(cmd *cobra.Command, args []string) error {
-	// Get current working directory for display
-	currentDir, err := os.Getwd()
-	if err != nil {
-		currentDir = "current directory"
-	}
+// initSettings are the answers init writes to config.yaml and .env
+type initSettings struct {
+	token       string
+	pageID      string
+	markdownDir string
+}
 
-	fmt.Println("🚀 Initializing notion-md-sync project...")
-	fmt.Printf("📍 Working in: %s\n\n", currentDir)
+// errInputClosed is returned when stdin ends before every question is answered
+var errInputClosed = errors.New("input ended before setup was complete")
 
-	// Check if already initialized
-	configPath := filepath.Join(currentDir, "config.yaml")
-	if _, err := os.Stat("config.yaml"); err == nil {
-		fmt.Printf("⚠️  Project already initialized!\n")
-		fmt.Printf("📄 Found existing config: %s\n", configPath)
-		
-		// Show existing .env file location if it exists
-		envPath := filepath.Join(currentDir, ".env")
-		if _, err := os.Stat(".env"); err == nil {
-			fmt.Printf("🔑 Found existing credentials: %s\n", envPath)
-		} else {
-			fmt.Printf("💡 You can create credentials at: %s\n", envPath)
-		}
-		
-		fmt.Println("\n✅ Your project is ready to use!")
-		fmt.Println("📚 Next steps:")
-		fmt.Println("   • Run: notion-md-sync pull --verbose")
-		fmt.Println("   • Or: notion-md-sync push --verbose")
-		fmt.Println("   • Or: notion-md-sync --help")
-		return nil
+// readAnswer reads one line of input, failing once input is exhausted so a
+// closed stdin can't make a prompt loop forever
+func readAnswer(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errInputClosed
 	}
+	return strings.TrimSpace(line), nil
+}
 
-	reader := bufio.NewReader(os.Stdin)
+// promptInitSettings asks for the token, parent page and markdown directory.
+// Each answer is asked again until it is valid and, unless --skip-verify is
+// set, accepted by the Notion API, so nothing is written for bad credentials
+func promptInitSettings(reader *bufio.Reader) (*initSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
 
 	fmt.Println("🔧 Let's set up your Notion integration...")
 	fmt.Println()
 
+	settings := &initSettings{}
+	var client notion.Client
+
 	// Get Notion token
-	var token string
 	for {
 		fmt.Println("🔑 Step 1: Notion Integration Token")
 		fmt.Println("   Get yours at: https://www.notion.so/my-integrations")
 		fmt.Println("   Create a new integration and copy the 'Internal Integration Token'")
 		fmt.Print("\n📋 Paste your token here (you can copy/paste): ")
-		token, _ = reader.ReadString('\n')
-		token = strings.TrimSpace(token)
+		token, err := readAnswer(reader)
+		if err != nil {
+			return nil, err
+		}
 
 		if err := util.ValidateNotionToken(token); err != nil {
 			fmt.Printf("❌ Invalid token: %v\n", err)
 			fmt.Println("   💡 Make sure you copied the full token from Notion")
 			continue
 		}
-		fmt.Println("✅ Valid token!")
+		if !initSkipVerify {
+			client = newInitClient(token)
+			user, err := notion.VerifyToken(ctx, client)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				fmt.Println("   💡 Make sure you copied the full token from Notion")
+				continue
+			}
+			fmt.Printf("✅ Connected as %s!\n", user.DisplayName())
+		} else {
+			fmt.Println("✅ Valid token!")
+		}
+		settings.token = token
 		break
 	}
 
 	// Get parent page ID
-	var pageID string
 	for {
 		fmt.Println("\n📄 Step 2: Parent Page ID")
 		fmt.Println("   1. Open your Notion page in browser")
 		fmt.Println("   2. Share the page with your integration")
-		fmt.Println("   3. Copy the page ID from the URL (long string after last '/')")
-		fmt.Print("\n📋 Paste your page ID here: ")
-		pageID, _ = reader.ReadString('\n')
-		pageID = strings.TrimSpace(pageID)
+		fmt.Println("   3. Copy the page URL, or the page ID from it (long string after last '/')")
+		fmt.Print("\n📋 Paste your page URL or ID here: ")
+		input, err := readAnswer(reader)
+		if err != nil {
+			return nil, err
+		}
 
-		if err := util.ValidateNotionPageID(pageID); err != nil {
+		id, err := util.ParseNotionID(input)
+		if err != nil {
 			fmt.Printf("❌ Invalid page ID: %v\n", err)
-			fmt.Println("   💡 Should be a 32-character string with dashes")
+			fmt.Println("   💡 Should be a Notion page URL or a 32-character ID")
 			continue
 		}
-		fmt.Println("✅ Valid page ID!")
+		if !initSkipVerify {
+			if _, err := notion.VerifyParentPage(ctx, client, id); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				fmt.Println("   💡 Share the page with your integration from its ••• menu → Connections")
+				continue
+			}
+			fmt.Println("✅ Found your page!")
+		} else {
+			fmt.Println("✅ Valid page ID!")
+		}
+		settings.pageID = id
 		break
 	}
 
 	// Get markdown directory
-	var markdownDir string
 	for {
 		fmt.Println("\n📂 Step 3: Markdown Directory")
 		fmt.Println("   Where should we store your markdown files?")
 		fmt.Print("   Directory path (default: ./docs): ")
-		markdownDir, _ = reader.ReadString('\n')
-		markdownDir = strings.TrimSpace(markdownDir)
+		markdownDir, err := readAnswer(reader)
+		if err != nil {
+			return nil, err
+		}
 		if markdownDir == "" {
 			markdownDir = "./docs"
 		}
@@ -116,9 +162,112 @@ func runInit(cmd *cobra.Command, args []string) error {
 			continue
 		}
 		fmt.Printf("✅ Will create directory: %s\n", markdownDir)
+		settings.markdownDir = markdownDir
 		break
 	}
 
+	return settings, nil
+}
+
+// initSettingsFromFlags builds the settings for init --yes. Credentials come
+// from flags or the environment and are verified when given; without them
+// init only scaffolds the project, leaving the credentials to fill in later
+func initSettingsFromFlags() (*initSettings, error) {
+	settings := &initSettings{
+		token:       initToken,
+		markdownDir: initMarkdownRoot,
+	}
+	if settings.token == "" {
+		settings.token = os.Getenv("NOTION_MD_SYNC_NOTION_TOKEN")
+	}
+	parentPage := initParentPage
+	if parentPage == "" {
+		parentPage = os.Getenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
+	}
+
+	if err := util.ValidateDirectoryPath(settings.markdownDir, false); err != nil {
+		return nil, fmt.Errorf("invalid markdown root: %w", err)
+	}
+	if settings.token != "" {
+		if err := util.ValidateNotionToken(settings.token); err != nil {
+			return nil, fmt.Errorf("invalid token: %w", err)
+		}
+	}
+	if parentPage != "" {
+		id, err := util.ParseNotionID(parentPage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent page: %w", err)
+		}
+		settings.pageID = id
+	}
+
+	if initSkipVerify || settings.token == "" {
+		return settings, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := newInitClient(settings.token)
+	user, err := notion.VerifyToken(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("✅ Connected as %s\n", user.DisplayName())
+
+	if settings.pageID != "" {
+		if _, err := notion.VerifyParentPage(ctx, client, settings.pageID); err != nil {
+			return nil, err
+		}
+		fmt.Println("✅ Found parent page")
+	}
+
+	return settings, nil
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	// Get current working directory for display
+	currentDir, err := os.Getwd()
+	if err != nil {
+		currentDir = "current directory"
+	}
+
+	fmt.Println("🚀 Initializing notion-md-sync project...")
+	fmt.Printf("📍 Working in: %s\n\n", currentDir)
+
+	// Check if already initialized
+	configPath := filepath.Join(currentDir, "config.yaml")
+	if _, err := os.Stat("config.yaml"); err == nil {
+		fmt.Printf("⚠️  Project already initialized!\n")
+		fmt.Printf("📄 Found existing config: %s\n", configPath)
+		
+		// Show existing .env file location if it exists
+		envPath := filepath.Join(currentDir, ".env")
+		if _, err := os.Stat(".env"); err == nil {
+			fmt.Printf("🔑 Found existing credentials: %s\n", envPath)
+		} else {
+			fmt.Printf("💡 You can create credentials at: %s\n", envPath)
+		}
+		
+		fmt.Println("\n✅ Your project is ready to use!")
+		fmt.Println("📚 Next steps:")
+		fmt.Println("   • Run: notion-md-sync pull --verbose")
+		fmt.Println("   • Or: notion-md-sync push --verbose")
+		fmt.Println("   • Or: notion-md-sync --help")
+		return nil
+	}
+
+	var settings *initSettings
+	if initYes {
+		settings, err = initSettingsFromFlags()
+	} else {
+		settings, err = promptInitSettings(bufio.NewReader(os.Stdin))
+	}
+	if err != nil {
+		return err
+	}
+	token, pageID, markdownDir := settings.token, settings.pageID, settings.markdownDir
+
 	// Create directories
 	if err := os.MkdirAll(markdownDir, 0755); err != nil {
 		return fmt.Errorf("failed to create markdown directory: %w", err)
@@ -132,7 +281,21 @@ notion:
 
 sync:
   direction: push
-  conflict_resolution: newer
+  conflict_resolution: newer  # local, remote, newer or manual
+  # conflict_log: %s  # Where conflicts and their resolutions are recorded; "" turns it off
+  # strict_markdown: true  # Fail pushes that use definition lists or raw HTML
+  # orphaned_pages: recreate  # Push files whose Notion page was deleted as new pages instead of failing
+  # empty_pages: skip  # Don't create Notion pages for files with no content (or placeholder)
+  # normalize_typography: true  # Pull smart quotes and dashes as plain ASCII
+  # strip_title_heading: true  # Don't push a leading "# Title" that repeats the page title
+  # preserve_html: true  # Keep unrecognized HTML blocks as html code blocks instead of dropping them
+  # delete_pages: trash  # Move pages removed by a push to the trash instead of archiving them
+  # flavor: mdx  # Pull callouts and toggles as MDX components for Docusaurus or Nextra
+  # link_style: reference  # Pull links as [text][1] with the URLs listed at the end of each page
+  # store_notion_url: false  # Don't write each page's Notion link to notion_url in its frontmatter
+  # line_endings: crlf  # Line endings of written files: lf (default), crlf or auto for the OS's
+  # overwrite_templates: true  # Let pushes replace database template pages
+  # max_pages: 10000  # Stop pulls finding more pages than this below the parent page (0 for no limit)
 
 directories:
   markdown_root: %s
@@ -140,6 +303,7 @@ directories:
     - "*.tmp"
     - "node_modules/**"
     - ".git/**"
+  # databases_dir: databases  # Export child databases here instead of next to their page
 
 mapping:
   strategy: frontmatter
@@ -152,14 +316,40 @@ performance:
   # - Medium workspaces (5-14 pages): Uses 20 workers
   # - Large workspaces (15+ pages): Uses 30 workers
   workers: 0
+
+  # How pulls size their workers: static uses the counts above; adaptive
+  # starts with 4 and adds workers while pages come back quickly, halving
+  # them whenever Notion rate limits (up to workers, or 50 when it's 0)
+  worker_scaling: static
+
+  # Blocks deleted at once when a push replaces a page's content (1-10);
+  # workers all back off together when Notion rate limits
+  delete_workers: %d
   
   # Multi-client mode (experimental)
   # Standard single client usually performs best
   use_multi_client: false
   
   # Number of HTTP clients when multi-client is enabled
-  client_count: 3
-`, markdownDir)
+  client_count: 3  # 1-10
+  
+  # Global request rate shared by all clients in multi-client mode
+  # Notion allows an average of 3 requests per second; 0 disables limiting
+  requests_per_second: 3
+
+  # Stop sending requests for a while when Notion looks down: after this many
+  # requests in a row fail with a server error or timeout, fail fast for the
+  # cooldown, then try one request to see if it recovered; 0 disables it
+  circuit_breaker_threshold: 5
+  circuit_breaker_cooldown: 30s
+
+# Deadlines for whole operations, on top of the 30s limit per request
+# Durations such as 90s or 10m; 0 disables a deadline
+timeouts:
+  sync: 5m         # A whole push, pull, sync, diff or verify run
+  page_fetch: 2m   # Fetching one page and all its nested blocks
+  page_update: 2m  # Pushing one file to its page
+`, config.DefaultConflictLog, markdownDir, notion.DefaultDeleteWorkers)
 
 	if err := os.WriteFile("config.yaml", []byte(configContent), 0644); err != nil {
 		return fmt.Errorf("failed to create config.yaml: %w", err)
@@ -234,9 +424,14 @@ Happy syncing! 🚀
 	fmt.Printf("   📝 %s (sample markdown)\n", sampleFullPath)
 	fmt.Printf("   📂 %s/ (markdown directory)\n", markdownFullPath)
 
-	fmt.Println("\n💡 Your credentials are ready!")
-	fmt.Println("   Your Notion token and page ID have been saved to .env")
-	fmt.Printf("   You can edit them anytime at: %s\n", envFullPath)
+	if token == "" || pageID == "" {
+		fmt.Println("\n💡 Add your credentials before syncing")
+		fmt.Printf("   Set your Notion token and page ID in: %s\n", envFullPath)
+	} else {
+		fmt.Println("\n💡 Your credentials are ready!")
+		fmt.Println("   Your Notion token and page ID have been saved to .env")
+		fmt.Printf("   You can edit them anytime at: %s\n", envFullPath)
+	}
 
 	fmt.Println("\n🚀 Ready to sync!")
 	fmt.Println("   • Test connection: notion-md-sync pull --verbose")
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validPageID   = "abcdef12-3456-7890-abcd-ef1234567890"
+	missingPageID = "00000000-0000-0000-0000-000000000000"
+)
+
+// credentialClient answers the calls init makes to verify credentials. Only
+// "ntn_good_token" is accepted and only validPageID is shared with it
+type credentialClient struct {
+	notion.Client
+	token string
+}
+
+func (c *credentialClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	if c.token != "ntn_good_token" {
+		return nil, fmt.Errorf("failed to get current user: %w", &notion.NotionAPIError{Code: 401, Message: "API token is invalid."})
+	}
+	return &notion.User{ID: "bot-id", Name: "Docs Sync"}, nil
+}
+
+func (c *credentialClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
+	if pageID != validPageID {
+		return nil, fmt.Errorf("failed to get page %s: %w", pageID, &notion.NotionAPIError{Code: 404, Message: "Could not find page."})
+	}
+	return &notion.Page{ID: pageID}, nil
+}
+
+// useCredentialClient routes init's verification through credentialClient
+func useCredentialClient(t *testing.T) {
+	t.Helper()
+	original := newInitClient
+	newInitClient = func(token string) notion.Client {
+		return &credentialClient{token: token}
+	}
+	t.Cleanup(func() { newInitClient = original })
+}
+
+func TestPromptInitSettings_VerifiesCredentials(t *testing.T) {
+	useCredentialClient(t)
+	initSkipVerify = false
+
+	input := strings.Join([]string{
+		"ntn_revoked_token", // rejected by /users/me
+		"ntn_good_token",    // accepted
+		missingPageID,       // not shared with the integration
+		"https://www.notion.so/Docs-abcdef1234567890abcdef1234567890",
+		"", // default markdown directory
+	}, "\n") + "\n"
+
+	settings, err := promptInitSettings(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	assert.Equal(t, "ntn_good_token", settings.token)
+	assert.Equal(t, validPageID, settings.pageID)
+	assert.Equal(t, "./docs", settings.markdownDir)
+}
+
+func TestPromptInitSettings_StopsWhenInputEnds(t *testing.T) {
+	useCredentialClient(t)
+	initSkipVerify = false
+
+	// The token is never accepted, so the prompt runs out of input
+	_, err := promptInitSettings(bufio.NewReader(strings.NewReader("ntn_revoked_token\n")))
+	assert.ErrorIs(t, err, errInputClosed)
+}
+
+func TestRunInit_YesGatesOnVerification(t *testing.T) {
+	useCredentialClient(t)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	defer func() {
+		initYes, initToken, initParentPage, initMarkdownRoot, initSkipVerify = false, "", "", "./docs", false
+	}()
+
+	tests := []struct {
+		name       string
+		token      string
+		parentPage string
+		skipVerify bool
+		wantErr    string
+	}{
+		{"rejected token", "ntn_revoked_token", validPageID, false, "rejected the token"},
+		{"unshared page", "ntn_good_token", missingPageID, false, "make sure it is shared"},
+		{"verified", "ntn_good_token", "https://www.notion.so/Docs-abcdef1234567890abcdef1234567890", false, ""},
+		{"skip verify", "ntn_revoked_token", missingPageID, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.Chdir(t.TempDir()))
+			initYes, initToken, initParentPage, initMarkdownRoot, initSkipVerify = true, tt.token, tt.parentPage, "./docs", tt.skipVerify
+
+			err := runInit(initCmd, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.NoFileExists(t, "config.yaml", "nothing should be written for bad credentials")
+				assert.NoFileExists(t, ".env")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.FileExists(t, "config.yaml")
+			env, err := os.ReadFile(".env")
+			require.NoError(t, err)
+			assert.Contains(t, string(env), "NOTION_MD_SYNC_NOTION_TOKEN="+tt.token)
+		})
+	}
+}
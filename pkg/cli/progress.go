@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+)
+
+const (
+	progressBarWidth      = 30
+	progressETAWindow     = 10              // Completions used for the rolling ETA
+	progressPrintInterval = 5 * time.Second // Plain-text update interval when not on a TTY
+)
+
+// etaEstimator predicts the remaining time from a rolling window of
+// completion timestamps, so the estimate follows the current rate rather
+// than the average since the start
+type etaEstimator struct {
+	window int
+	times  []time.Time
+}
+
+// newETAEstimator creates an estimator whose first sample is the start time
+func newETAEstimator(start time.Time, window int) *etaEstimator {
+	if window < 2 {
+		window = 2
+	}
+	return &etaEstimator{
+		window: window,
+		times:  []time.Time{start},
+	}
+}
+
+// Record adds a completion timestamp, dropping the oldest beyond the window
+func (e *etaEstimator) Record(t time.Time) {
+	e.times = append(e.times, t)
+	if len(e.times) > e.window {
+		e.times = e.times[len(e.times)-e.window:]
+	}
+}
+
+// Estimate returns the expected time to finish the remaining items, or false
+// if there aren't enough samples yet
+func (e *etaEstimator) Estimate(remaining int) (time.Duration, bool) {
+	if remaining <= 0 {
+		return 0, true
+	}
+	if len(e.times) < 2 {
+		return 0, false
+	}
+
+	elapsed := e.times[len(e.times)-1].Sub(e.times[0])
+	perItem := elapsed / time.Duration(len(e.times)-1)
+	return perItem * time.Duration(remaining), true
+}
+
+// progressBar renders sync progress, redrawing a single line on a terminal
+// and falling back to periodic plain-text lines otherwise
+type progressBar struct {
+	out         io.Writer
+	label       string
+	unit        string // e.g. "pages" or "files"
+	total       int
+	completed   int
+	failed      int
+	interactive bool
+	quiet       bool
+	eta         *etaEstimator
+	lastPrint   time.Time
+	now         func() time.Time
+}
+
+// newProgressBar creates a progress bar on stdout; total may be 0 if unknown
+func newProgressBar(label, unit string, total int) *progressBar {
+	now := time.Now()
+	return &progressBar{
+		out:         os.Stdout,
+		label:       label,
+		unit:        unit,
+		total:       total,
+		interactive: isTerminal(os.Stdout),
+		quiet:       quiet || jsonOutput,
+		eta:         newETAEstimator(now, progressETAWindow),
+		lastPrint:   now,
+		now:         time.Now,
+	}
+}
+
+// attachProgressBar routes the engine's progress events to a new progress bar
+// and to events, which may be nil. Engines that can't report progress get a
+// bar that never updates.
+func attachProgressBar(engine sync.Engine, label string, events *eventStream) *progressBar {
+	bar := newProgressBar(label, "pages", 0)
+	if reporter, ok := engine.(sync.ProgressReporter); ok {
+		reporter.SetProgressFunc(func(event sync.ProgressEvent) {
+			bar.HandleEvent(event)
+			events.HandleProgress(event)
+		})
+	}
+	return bar
+}
+
+// HandleEvent adapts engine progress events to the bar
+func (p *progressBar) HandleEvent(event sync.ProgressEvent) {
+	if event.Total > 0 {
+		p.total = event.Total
+	}
+	p.Update(event.Completed, event.Failed)
+}
+
+// Update records the latest completed and failed counts and redraws
+func (p *progressBar) Update(completed, failed int) {
+	now := p.now()
+	for i := p.completed; i < completed; i++ {
+		p.eta.Record(now)
+	}
+	p.completed = completed
+	p.failed = failed
+
+	if p.quiet {
+		return
+	}
+
+	if p.interactive {
+		_, _ = fmt.Fprintf(p.out, "\r\033[K%s", p.render())
+		return
+	}
+
+	// Without a TTY only print periodically, plus once when done
+	done := p.total > 0 && p.completed >= p.total
+	if done || now.Sub(p.lastPrint) >= progressPrintInterval {
+		_, _ = fmt.Fprintln(p.out, p.render())
+		p.lastPrint = now
+	}
+}
+
+// Finish ends the bar's line on a terminal
+func (p *progressBar) Finish() {
+	if !p.quiet && p.interactive && p.completed > 0 {
+		_, _ = fmt.Fprintln(p.out)
+	}
+}
+
+// render formats the current progress as a single line
+func (p *progressBar) render() string {
+	var sb strings.Builder
+	sb.WriteString(p.label)
+
+	if p.total <= 0 {
+		fmt.Fprintf(&sb, ": %d %s", p.completed, p.unit)
+	} else {
+		if p.interactive {
+			filled := progressBarWidth * p.completed / p.total
+			if filled > progressBarWidth {
+				filled = progressBarWidth
+			}
+			fmt.Fprintf(&sb, " [%s%s]", strings.Repeat("█", filled), strings.Repeat("░", progressBarWidth-filled))
+		} else {
+			sb.WriteString(":")
+		}
+		fmt.Fprintf(&sb, " %d/%d %s", p.completed, p.total, p.unit)
+	}
+
+	if p.failed > 0 {
+		fmt.Fprintf(&sb, " (%d failed)", p.failed)
+	}
+
+	if p.total > 0 {
+		if eta, ok := p.eta.Estimate(p.total - p.completed); ok {
+			fmt.Fprintf(&sb, ", ETA %s", formatETA(eta))
+		} else {
+			sb.WriteString(", ETA --")
+		}
+	}
+
+	return sb.String()
+}
+
+// formatETA rounds an estimate to whole seconds for display
+func formatETA(d time.Duration) string {
+	if d < time.Second {
+		return "<1s"
+	}
+	return d.Round(time.Second).String()
+}
+
+// isTerminal reports whether f is attached to a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETAEstimator(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("no estimate before first completion", func(t *testing.T) {
+		e := newETAEstimator(start, 5)
+		_, ok := e.Estimate(10)
+		assert.False(t, ok)
+	})
+
+	t.Run("steady rate", func(t *testing.T) {
+		e := newETAEstimator(start, 5)
+		for i := 1; i <= 3; i++ {
+			e.Record(start.Add(time.Duration(i) * 2 * time.Second))
+		}
+
+		eta, ok := e.Estimate(7)
+		require.True(t, ok)
+		assert.Equal(t, 14*time.Second, eta)
+	})
+
+	t.Run("rolling window follows the recent rate", func(t *testing.T) {
+		e := newETAEstimator(start, 3)
+
+		// Two slow completions, then three fast ones; only the last three
+		// timestamps (two 1s intervals) should count
+		e.Record(start.Add(10 * time.Second))
+		e.Record(start.Add(20 * time.Second))
+		e.Record(start.Add(21 * time.Second))
+		e.Record(start.Add(22 * time.Second))
+
+		eta, ok := e.Estimate(5)
+		require.True(t, ok)
+		assert.Equal(t, 5*time.Second, eta)
+	})
+
+	t.Run("nothing remaining", func(t *testing.T) {
+		e := newETAEstimator(start, 5)
+		eta, ok := e.Estimate(0)
+		require.True(t, ok)
+		assert.Equal(t, time.Duration(0), eta)
+	})
+}
+
+func TestFormatETA(t *testing.T) {
+	assert.Equal(t, "<1s", formatETA(300*time.Millisecond))
+	assert.Equal(t, "1m20s", formatETA(80*time.Second+400*time.Millisecond))
+}
+
+// newTestProgressBar returns a bar writing to a buffer with a controllable clock
+func newTestProgressBar(interactive bool, total int, clock *time.Time) (*progressBar, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return &progressBar{
+		out:         &buf,
+		label:       "Pulling",
+		unit:        "pages",
+		total:       total,
+		interactive: interactive,
+		eta:         newETAEstimator(*clock, progressETAWindow),
+		lastPrint:   *clock,
+		now:         func() time.Time { return *clock },
+	}, &buf
+}
+
+func TestProgressBar_PlainTextFallback(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bar, buf := newTestProgressBar(false, 4, &clock)
+
+	// Updates within the print interval are not printed
+	clock = clock.Add(time.Second)
+	bar.HandleEvent(sync.ProgressEvent{Completed: 1, Total: 4})
+	assert.Empty(t, buf.String())
+
+	clock = clock.Add(progressPrintInterval)
+	bar.HandleEvent(sync.ProgressEvent{Completed: 2, Failed: 1, Total: 4})
+	assert.Equal(t, "Pulling: 2/4 pages (1 failed), ETA 6s\n", buf.String())
+
+	// Completion is always printed
+	buf.Reset()
+	clock = clock.Add(time.Second)
+	bar.HandleEvent(sync.ProgressEvent{Completed: 4, Failed: 1, Total: 4})
+	assert.Equal(t, "Pulling: 4/4 pages (1 failed), ETA <1s\n", buf.String())
+}
+
+func TestProgressBar_Interactive(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bar, buf := newTestProgressBar(true, 2, &clock)
+
+	clock = clock.Add(2 * time.Second)
+	bar.Update(1, 0)
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "\r\033[K"), "interactive output should redraw in place")
+	assert.Contains(t, out, "1/2 pages")
+	assert.Contains(t, out, "ETA 2s")
+
+	bar.Finish()
+	assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+}
+
+func TestProgressBar_Quiet(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bar, buf := newTestProgressBar(true, 2, &clock)
+	bar.quiet = true
+
+	bar.Update(1, 0)
+	bar.Update(2, 0)
+	bar.Finish()
+	assert.Empty(t, buf.String())
+}
+
+func TestProgressBar_UnknownTotal(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bar, _ := newTestProgressBar(false, 0, &clock)
+
+	bar.completed = 3
+	assert.Equal(t, "Pulling: 3 pages", bar.render())
+}
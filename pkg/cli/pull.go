@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"context"
 	"fmt"
-	"time"
+	"os"
+	"path/filepath"
 
-	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 	"github.com/byvfx/go-notion-md-sync/pkg/sync"
 	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/cobra"
@@ -21,25 +21,63 @@ var pullCmd = &cobra.Command{
 var (
 	pullPageID    string
 	pullPage      string
+	pullSubtreeID string // Set when --page is given a Notion page ID
 	pullOutput    string
 	pullDirectory string
 	pullDryRun    bool
+	pullMaxDepth  int
+	pullMaxPages  int
+	pullReport    bool
+	pullFlatten   bool
+	pullArchived  bool
+	pullPruneDirs bool
+	pullPruneAll  bool
+	pullRenames   bool
+
+	pullInlineImages   bool
+	pullInlineImageMax int64
+	pullDownloadAssets bool
+	pullAssetsDir      string
+	pullAssetsBaseURL  string
+	pullFlavor         string
+	pullLinkStyle      string
 )
 
 func init() {
-	pullCmd.Flags().StringVar(&pullPageID, "page-id", "", "specific Notion page ID to pull")
-	pullCmd.Flags().StringVar(&pullPage, "page", "", "specific page filename to pull (e.g., 'Table Page.md')")
-	pullCmd.Flags().StringVarP(&pullOutput, "output", "o", "", "output file path (required when using --page-id)")
+	pullCmd.Flags().StringVar(&pullPageID, "page-id", "", "specific Notion page ID or URL to pull")
+	pullCmd.Flags().StringVar(&pullPage, "page", "", "specific page filename to pull (e.g., 'Table Page.md'), or a page ID or URL to pull that page and its sub-pages")
+	pullCmd.Flags().StringVarP(&pullOutput, "output", "o", "", "output file path with --page-id, otherwise a directory to pull into instead of markdown_root")
 	pullCmd.Flags().StringVar(&pullDirectory, "directory", "", "directory to save pulled files (defaults to config's markdown_root)")
 	pullCmd.Flags().BoolVar(&pullDryRun, "dry-run", false, "show what would be pulled without actually pulling")
+	pullCmd.Flags().BoolVar(&pullReport, "report", false, "print how many blocks of each type were converted or dropped")
+	pullCmd.Flags().BoolVar(&pullFlatten, "flatten", false, "write every page straight into the output directory instead of a directory per page")
+	pullCmd.Flags().BoolVar(&pullPruneDirs, "prune-empty-dirs", false, "remove directories the pull leaves empty, e.g. those of renamed pages")
+	pullCmd.Flags().BoolVar(&pullPruneAll, "prune-stale-dirs", false, "also remove directories holding only markdown files of pages no longer in Notion")
+	pullCmd.Flags().BoolVar(&pullRenames, "rename-detection", false, "find each page's file by notion_id and move it when the page was renamed or moved, instead of writing a new file")
+	pullCmd.Flags().BoolVar(&pullArchived, "include-archived", false, "write the content of archived and trashed pages; by default existing files are only marked archived")
+	pullCmd.Flags().BoolVar(&pullInlineImages, "inline-images", false, "embed images in the markdown as base64 data URIs for a self-contained export")
+	pullCmd.Flags().Int64Var(&pullInlineImageMax, "inline-images-max", sync.DefaultInlineImageMax, "largest image in bytes to embed with --inline-images; larger images stay links")
+	pullCmd.Flags().BoolVar(&pullDownloadAssets, "download-assets", false, "save images, files and PDFs hosted by Notion, whose links expire, and link to the local copies")
+	pullCmd.Flags().StringVar(&pullAssetsDir, "assets-dir", sync.DefaultAssetsDir, "directory for --download-assets, relative to the output directory")
+	pullCmd.Flags().StringVar(&pullAssetsBaseURL, "assets-base-url", "", "link downloaded assets under this URL, e.g. a CDN, instead of by relative path; implies --download-assets")
+	pullCmd.Flags().IntVar(&pullMaxDepth, "max-depth", notion.UnlimitedDepth, "levels of sub-pages to pull below the parent page (0 pulls only the parent, -1 is unlimited)")
+	pullCmd.Flags().IntVar(&pullMaxPages, "max-pages", -1, "stop a pull that finds more than this many pages below the parent page, 0 for no limit (overrides sync.max_pages)")
+	pullCmd.Flags().StringVar(&pullFlavor, "flavor", "", "markdown flavor to write: commonmark, or mdx for docs sites (overrides sync.flavor)")
+	pullCmd.Flags().StringVar(&pullLinkStyle, "link-style", "", "how to write links: inline, or reference to list their URLs at the end of each page (overrides sync.link_style)")
+	pullCmd.Flags().BoolVar(&jsonOutput, "json", false, "print newline-delimited JSON events instead of human-readable output")
 }
 
-func runPull(cmd *cobra.Command, args []string) error {
+func runPull(cmd *cobra.Command, args []string) (err error) {
+	events := openEventStream("pull", "pull")
+	defer func() { events.Finish(err) }()
+
 	// Validate inputs
 	if pullPageID != "" {
-		if err := util.ValidateNotionPageID(pullPageID); err != nil {
+		id, err := util.ParseNotionID(pullPageID)
+		if err != nil {
 			return fmt.Errorf("invalid page ID: %w", err)
 		}
+		pullPageID = id
 		if pullOutput == "" {
 			return fmt.Errorf("--output flag is required when pulling a specific page")
 		}
@@ -48,7 +86,11 @@ func runPull(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if pullPage != "" {
+	pullSubtreeID = ""
+	if id, err := util.ParseNotionID(pullPage); pullPage != "" && err == nil {
+		// A page ID or URL scopes the pull to that page's subtree
+		pullSubtreeID = id
+	} else if pullPage != "" {
 		sanitized, err := util.SanitizeAndValidateFilename(pullPage)
 		if err != nil {
 			return fmt.Errorf("invalid page filename: %w", err)
@@ -57,41 +99,92 @@ func runPull(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load configuration
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if pullFlavor != "" {
+		if err := util.ValidateMarkdownFlavor(pullFlavor); err != nil {
+			return fmt.Errorf("--flavor: %w", err)
+		}
+		cfg.Sync.Flavor = pullFlavor
+	}
+	if pullMaxPages >= 0 {
+		cfg.Sync.MaxPages = pullMaxPages
+	}
+	if pullLinkStyle != "" {
+		if err := util.ValidateLinkStyle(pullLinkStyle); err != nil {
+			return fmt.Errorf("--link-style: %w", err)
+		}
+		cfg.Sync.LinkStyle = pullLinkStyle
+	}
+
 	printVerbose("Loaded configuration")
 	printVerbose("Direction: pull (Notion → markdown)")
 
-	// Create sync engine
-	engine := sync.NewEngine(cfg)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
 	// Determine output directory
-	outputDir := pullDirectory
-	if outputDir == "" {
-		outputDir = cfg.Directories.MarkdownRoot
+	outputDir, err := pullOutputDir(cfg.Directories.MarkdownRoot, pullPageID, pullOutput, pullDirectory)
+	if err != nil {
+		return err
+	}
+	if outputDir != cfg.Directories.MarkdownRoot && !pullDryRun {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 
 	// Validate output directory
-	if err := util.ValidateDirectoryPath(outputDir, true); err != nil {
+	if err := util.ValidateDirectoryPath(outputDir, !pullDryRun); err != nil {
 		return fmt.Errorf("invalid output directory: %w", err)
 	}
 
+	// Pages, their directories and database exports all go under the
+	// output directory for this run
+	cfg.Directories.MarkdownRoot = outputDir
+
+	// Create sync engine
+	opts := []sync.SyncerOption{sync.WithMaxDepth(pullMaxDepth), sync.WithFlatten(pullFlatten), sync.WithArchivedPages(pullArchived)}
+	if pullInlineImages {
+		opts = append(opts, sync.WithInlineImages(pullInlineImageMax))
+	}
+	if pullDownloadAssets || pullAssetsBaseURL != "" {
+		opts = append(opts, sync.WithAssetDownloads(pullAssetsDir), sync.WithAssetsBaseURL(pullAssetsBaseURL))
+	}
+	if pullRenames {
+		opts = append(opts, sync.WithRenameDetection())
+	}
+	switch {
+	case pullPruneAll:
+		opts = append(opts, sync.WithPruneDirs(sync.PruneStaleDirs))
+	case pullPruneDirs:
+		opts = append(opts, sync.WithPruneDirs(sync.PruneEmptyDirs))
+	}
+	var stats *sync.ConversionStats
+	if pullReport {
+		stats = sync.NewConversionStats()
+		opts = append(opts, sync.WithConversionStats(stats))
+	}
+	engine, err := newSyncEngine(cfg, opts...)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := commandContext(cfg)
+	defer cancel()
+
 	if pullDryRun {
-		fmt.Println("DRY RUN: No actual changes will be made")
+		fmt.Fprintln(humanOutput(), "DRY RUN: No actual changes will be made")
 		if pullPageID != "" {
 			if pullOutput == "" {
 				return fmt.Errorf("--output flag is required when pulling a specific page")
 			}
-			fmt.Printf("Would pull page %s to %s\n", pullPageID, pullOutput)
+			fmt.Fprintf(humanOutput(), "Would pull page %s to %s\n", pullPageID, pullOutput)
+		} else if pullSubtreeID != "" {
+			fmt.Fprintf(humanOutput(), "Would pull page %s and its sub-pages to directory %s\n", pullSubtreeID, outputDir)
 		} else if pullPage != "" {
-			fmt.Printf("Would pull page %s\n", pullPage)
+			fmt.Fprintf(humanOutput(), "Would pull page %s\n", pullPage)
 		} else {
-			fmt.Printf("Would pull all child pages from parent %s to directory %s\n",
+			fmt.Fprintf(humanOutput(), "Would pull all child pages from parent %s to directory %s\n",
 				cfg.Notion.ParentPageID, outputDir)
 		}
 		return nil
@@ -103,35 +196,80 @@ func runPull(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("--output flag is required when pulling a specific page")
 		}
 
-		fmt.Printf("Pulling page from Notion...\n")
-		fmt.Printf("  Page ID: %s\n", pullPageID)
-		fmt.Printf("  Output: %s\n", pullOutput)
+		fmt.Fprintf(humanOutput(), "Pulling page from Notion...\n")
+		fmt.Fprintf(humanOutput(), "  Page ID: %s\n", pullPageID)
+		fmt.Fprintf(humanOutput(), "  Output: %s\n", pullOutput)
 		printVerbose("Pulling page: %s to %s", pullPageID, pullOutput)
 
-		if err := engine.SyncNotionToFile(ctx, pullPageID, pullOutput); err != nil {
+		events.SetTotal(1)
+		err := engine.SyncNotionToFile(ctx, pullPageID, pullOutput)
+		events.Synced(pullOutput, pullPageID, err)
+		if err != nil {
 			return fmt.Errorf("failed to pull page: %w", err)
 		}
 
-		fmt.Printf("\n✓ Successfully pulled page to %s\n", pullOutput)
+		fmt.Fprintf(humanOutput(), "\n✓ Successfully pulled page to %s\n", pullOutput)
+	} else if pullSubtreeID != "" {
+		fmt.Fprintf(humanOutput(), "Pulling page and sub-pages from Notion: %s\n", pullSubtreeID)
+		printVerbose("Pulling page subtree rooted at: %s", pullSubtreeID)
+
+		bar := attachProgressBar(engine, "Pulling", events)
+		err := engine.SyncPageSubtree(ctx, pullSubtreeID, "pull")
+		bar.Finish()
+		if err != nil {
+			return fmt.Errorf("failed to pull page %s: %w", pullSubtreeID, err)
+		}
+
+		fmt.Fprintln(humanOutput(), "\n✓ Pull completed successfully")
 	} else if pullPage != "" {
-		fmt.Printf("Pulling specific page: %s\n", pullPage)
+		fmt.Fprintf(humanOutput(), "Pulling specific page: %s\n", pullPage)
 		printVerbose("Pulling page by filename: %s", pullPage)
 
-		if err := engine.SyncSpecificFile(ctx, pullPage, "pull"); err != nil {
+		events.SetTotal(1)
+		err := engine.SyncSpecificFile(ctx, pullPage, "pull")
+		events.Synced(pullPage, "", err)
+		if err != nil {
 			return fmt.Errorf("failed to pull page %s: %w", pullPage, err)
 		}
 
-		fmt.Printf("✓ Successfully pulled %s\n", pullPage)
+		fmt.Fprintf(humanOutput(), "✓ Successfully pulled %s\n", pullPage)
 	} else {
-		fmt.Printf("Pulling all pages from Notion parent page: %s\n", cfg.Notion.ParentPageID)
+		fmt.Fprintf(humanOutput(), "Pulling all pages from Notion parent page: %s\n", cfg.Notion.ParentPageID)
 		printVerbose("Pulling all pages from parent: %s", cfg.Notion.ParentPageID)
 
-		if err := engine.SyncAll(ctx, "pull"); err != nil {
+		bar := attachProgressBar(engine, "Pulling", events)
+		err := engine.SyncAll(ctx, "pull")
+		bar.Finish()
+		if err != nil {
 			return fmt.Errorf("pull failed: %w", err)
 		}
 
-		fmt.Println("\n✓ Pull completed successfully")
+		fmt.Fprintln(humanOutput(), "\n✓ Pull completed successfully")
+	}
+
+	if stats != nil {
+		fmt.Fprintln(humanOutput())
+		stats.WriteReport(humanOutput())
 	}
 
 	return nil
 }
+
+// pullOutputDir returns the directory a pull writes into: --output when it
+// isn't naming the file for --page-id, else --directory, else root
+func pullOutputDir(root, pageID, output, directory string) (string, error) {
+	if pageID != "" || output == "" {
+		if directory != "" {
+			return directory, nil
+		}
+		return root, nil
+	}
+
+	if directory != "" && filepath.Clean(directory) != filepath.Clean(output) {
+		return "", fmt.Errorf("--output and --directory name different directories; use one of them")
+	}
+	if err := util.ValidateFilePath(output, false); err != nil {
+		return "", fmt.Errorf("invalid output directory: %w", err)
+	}
+	return output, nil
+}
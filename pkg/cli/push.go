@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/staging"
@@ -25,22 +24,38 @@ If a specific file is provided, it will be automatically staged and pushed.
 Examples:
   notion-md-sync push                    # Push all staged files
   notion-md-sync push docs/file.md       # Stage and push a specific file
-  notion-md-sync push --dry-run          # Show what would be pushed`,
+  notion-md-sync push --dry-run          # Show what would be pushed
+  notion-md-sync push --include 'docs/**' # Push only staged files under docs/
+  notion-md-sync push --strict           # Fail on markdown Notion can't represent
+  notion-md-sync push --force            # Also replace database template pages
+  notion-md-sync push --empty-pages skip # Don't create pages for files without content`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPush,
 }
 
 var (
-	pushDirectory string
-	pushDryRun    bool
+	pushDirectory  string
+	pushDryRun     bool
+	pushStrict     bool
+	pushForce      bool
+	pushIncludes   []string
+	pushEmptyPages string
 )
 
 func init() {
 	pushCmd.Flags().StringVar(&pushDirectory, "directory", "", "directory containing markdown files (defaults to config's markdown_root)")
 	pushCmd.Flags().BoolVar(&pushDryRun, "dry-run", false, "show what would be pushed without actually pushing")
+	pushCmd.Flags().BoolVar(&pushStrict, "strict", false, "fail files that use markdown Notion does not support instead of warning")
+	pushCmd.Flags().BoolVar(&pushForce, "force", false, "replace the content of database template pages instead of refusing")
+	pushCmd.Flags().StringVar(&pushEmptyPages, "empty-pages", "", "what to do with new files that have no content: create, skip or placeholder (overrides sync.empty_pages)")
+	pushCmd.Flags().StringArrayVar(&pushIncludes, "include", nil, "only push files matching this glob, relative to the directory (repeatable; ** matches any directories)")
+	pushCmd.Flags().BoolVar(&jsonOutput, "json", false, "print newline-delimited JSON events instead of human-readable output")
 }
 
-func runPush(cmd *cobra.Command, args []string) error {
+func runPush(cmd *cobra.Command, args []string) (err error) {
+	events := openEventStream("push", "push")
+	defer func() { events.Finish(err) }()
+
 	// Validate file argument if provided
 	if len(args) > 0 {
 		if err := util.ValidateFilePath(args[0], true); err != nil {
@@ -58,11 +73,24 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid working directory: %w", err)
 	}
 
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if pushStrict {
+		cfg.Sync.StrictMarkdown = true
+	}
+	if pushForce {
+		cfg.Sync.OverwriteTemplates = true
+	}
+	if pushEmptyPages != "" {
+		if err := util.ValidateEmptyPagesStrategy(pushEmptyPages); err != nil {
+			return fmt.Errorf("--empty-pages: %w", err)
+		}
+		cfg.Sync.EmptyPages = pushEmptyPages
+	}
+
 	printVerbose("Loaded configuration")
 	printVerbose("Direction: push (markdown → Notion)")
 
@@ -76,9 +104,17 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if len(pushIncludes) > 0 {
+		filesToPush = filterIncludedFiles(filesToPush, pushIncludes)
+		if len(filesToPush) == 0 {
+			fmt.Fprintln(humanOutput(), "No staged files match the include patterns.")
+			return nil
+		}
+	}
+
 	if len(filesToPush) == 0 {
-		fmt.Println("No files staged for sync.")
-		fmt.Println("Use \"notion-md-sync add <file>...\" to stage files, or \"notion-md-sync status\" to see changed files.")
+		fmt.Fprintln(humanOutput(), "No files staged for sync.")
+		fmt.Fprintln(humanOutput(), "Use \"notion-md-sync add <file>...\" to stage files, or \"notion-md-sync status\" to see changed files.")
 		return nil
 	}
 
@@ -86,7 +122,7 @@ func runPush(cmd *cobra.Command, args []string) error {
 		return performDryRunPush(filesToPush)
 	}
 
-	return performPush(cfg, workingDir, filesToPush, stagingArea)
+	return performPush(cfg, workingDir, filesToPush, stagingArea, events)
 }
 
 func getWorkingDirectory() (string, error) {
@@ -110,6 +146,20 @@ func getFilesToPush(args []string, workingDir string, stagingArea *staging.Stagi
 	return stagingArea.GetStagedFiles()
 }
 
+// filterIncludedFiles keeps the files matching at least one include glob;
+// the rest stay staged for a later push
+func filterIncludedFiles(files, includes []string) []string {
+	var included []string
+	for _, file := range files {
+		if util.MatchAnyGlob(includes, file) {
+			included = append(included, file)
+		} else {
+			printVerbose("Skipping %s: does not match include patterns", file)
+		}
+	}
+	return included
+}
+
 func stageAndGetSpecificFile(filePath, workingDir string, stagingArea *staging.StagingArea) ([]string, error) {
 	// Convert to relative path if needed
 	if filepath.IsAbs(filePath) {
@@ -130,20 +180,23 @@ func stageAndGetSpecificFile(filePath, workingDir string, stagingArea *staging.S
 }
 
 func performDryRunPush(filesToPush []string) error {
-	fmt.Println("DRY RUN: No actual changes will be made")
-	fmt.Printf("Would push %d file(s) to Notion:\n", len(filesToPush))
+	fmt.Fprintln(humanOutput(), "DRY RUN: No actual changes will be made")
+	fmt.Fprintf(humanOutput(), "Would push %d file(s) to Notion:\n", len(filesToPush))
 	for _, file := range filesToPush {
-		fmt.Printf("  %s\n", file)
+		fmt.Fprintf(humanOutput(), "  %s\n", file)
 	}
 	return nil
 }
 
-func performPush(cfg *config.Config, workingDir string, filesToPush []string, stagingArea *staging.StagingArea) error {
-	engine := sync.NewEngine(cfg)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+func performPush(cfg *config.Config, workingDir string, filesToPush []string, stagingArea *staging.StagingArea, events *eventStream) error {
+	engine, err := newSyncEngine(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := commandContext(cfg)
 	defer cancel()
 
-	results := pushFilesConcurrently(ctx, engine, workingDir, filesToPush)
+	results := pushFilesConcurrently(ctx, engine, workingDir, filesToPush, events)
 
 	return processPushResults(results, stagingArea)
 }
@@ -154,31 +207,55 @@ type pushResult struct {
 	error   error
 }
 
-func pushFilesConcurrently(ctx context.Context, engine sync.Engine, workingDir string, filesToPush []string) []pushResult {
+// pushFilesConcurrently pushes files that already have a page concurrently.
+// Files that don't are pushed by a single worker in document order, because
+// Notion lists child pages in the order they were created. Each result is
+// reported to events, which may be nil.
+func pushFilesConcurrently(ctx context.Context, engine sync.Engine, workingDir string, filesToPush []string, events *eventStream) []pushResult {
+	files := append([]string(nil), filesToPush...)
+	sync.SortByDocumentOrder(workingDir, files)
+	newFiles, existing := sync.SplitNewFiles(workingDir, files)
+
 	maxWorkers := 3
-	if len(filesToPush) < maxWorkers {
-		maxWorkers = len(filesToPush)
+	if len(existing) < maxWorkers {
+		maxWorkers = len(existing)
 	}
 
-	jobs := make(chan string, len(filesToPush))
-	results := make(chan pushResult, len(filesToPush))
+	jobs := make(chan string, len(existing))
+	newJobs := make(chan string, len(newFiles))
+	results := make(chan pushResult, len(files))
 
 	// Start workers
 	for i := 0; i < maxWorkers; i++ {
 		go pushWorker(ctx, engine, workingDir, jobs, results)
 	}
+	go pushWorker(ctx, engine, workingDir, newJobs, results)
 
 	// Send jobs
-	for _, file := range filesToPush {
+	for _, file := range existing {
 		jobs <- file
 	}
 	close(jobs)
+	for _, file := range newFiles {
+		newJobs <- file
+	}
+	close(newJobs)
 
 	// Collect results
+	bar := newProgressBar("Pushing", "files", len(files))
+	events.SetTotal(len(files))
 	var allResults []pushResult
-	for i := 0; i < len(filesToPush); i++ {
-		allResults = append(allResults, <-results)
+	failed := 0
+	for i := 0; i < len(files); i++ {
+		result := <-results
+		if !result.success {
+			failed++
+		}
+		allResults = append(allResults, result)
+		bar.Update(i+1, failed)
+		events.Synced(result.file, "", result.error)
 	}
+	bar.Finish()
 
 	return allResults
 }
@@ -203,10 +280,10 @@ func processPushResults(results []pushResult, stagingArea *staging.StagingArea)
 
 	for _, result := range results {
 		if result.success {
-			fmt.Printf("✓ Successfully pushed %s to Notion\n", result.file)
+			fmt.Fprintf(humanOutput(), "✓ Successfully pushed %s to Notion\n", result.file)
 			successfulPushes = append(successfulPushes, result.file)
 		} else {
-			fmt.Printf("✗ Failed to push %s: %v\n", result.file, result.error)
+			fmt.Fprintf(humanOutput(), "✗ Failed to push %s: %v\n", result.file, result.error)
 			failedPushes = append(failedPushes, result.file)
 		}
 	}
@@ -219,13 +296,13 @@ func processPushResults(results []pushResult, stagingArea *staging.StagingArea)
 	}
 
 	// Print summary
-	fmt.Println()
+	fmt.Fprintln(humanOutput())
 	if len(successfulPushes) > 0 {
-		fmt.Printf("Successfully pushed %d file(s) to Notion.\n", len(successfulPushes))
+		fmt.Fprintf(humanOutput(), "Successfully pushed %d file(s) to Notion.\n", len(successfulPushes))
 	}
 
 	if len(failedPushes) > 0 {
-		fmt.Printf("Failed to push %d file(s). These files remain staged.\n", len(failedPushes))
+		fmt.Fprintf(humanOutput(), "Failed to push %d file(s). These files remain staged.\n", len(failedPushes))
 		return fmt.Errorf("some files failed to push")
 	}
 
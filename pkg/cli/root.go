@@ -4,13 +4,18 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/cobra"
 )
 
 var (
-	configPath string
-	verbose    bool
+	configPath    string
+	workspace     string
+	parentPageURL string
+	verbose       bool
+	quiet         bool
+	jsonOutput    bool // --json on the sync, pull and push commands
 )
 
 var rootCmd = &cobra.Command{
@@ -27,8 +32,11 @@ func Execute() error {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path")
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path (default: config.yaml or .notion-md-sync.yaml in the working directory or a parent)")
+	rootCmd.PersistentFlags().StringVar(&workspace, "workspace", "", "workspace profile to use (default: $NOTION_MD_SYNC_PROFILE or \"default\")")
+	rootCmd.PersistentFlags().StringVar(&parentPageURL, "parent-page-url", "", "Notion parent page URL or ID, overriding notion.parent_page_id")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress progress bars")
 
 	// Set up logging based on verbose flag
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
@@ -46,6 +54,23 @@ func init() {
 	rootCmd.AddCommand(watchCmd)
 }
 
+// loadConfig loads the configuration for the selected workspace and applies
+// the --parent-page-url override
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadWorkspace(configPath, workspace)
+	if err != nil {
+		return nil, err
+	}
+	if parentPageURL != "" {
+		id, err := util.ParseNotionID(parentPageURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --parent-page-url: %w", err)
+		}
+		cfg.Notion.ParentPageID = id
+	}
+	return cfg, nil
+}
+
 func printVerbose(format string, args ...interface{}) {
 	if verbose {
 		msg := format
@@ -7,7 +7,6 @@ import (
 	"sort"
 	"time"
 
-	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 	"github.com/byvfx/go-notion-md-sync/pkg/staging"
 	"github.com/spf13/cobra"
@@ -166,7 +165,7 @@ func printSummary(files *categorizedFiles) {
 }
 
 func getParentPageTitle() (string, error) {
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		return "", err
 	}
@@ -189,16 +188,8 @@ func getParentPageTitle() (string, error) {
 
 // extractPageTitle extracts the title from a Notion page
 func extractPageTitle(page *notion.Page) string {
-	if titleProp, ok := page.Properties["title"]; ok {
-		if titleData, ok := titleProp.(map[string]interface{}); ok {
-			if titleList, ok := titleData["title"].([]interface{}); ok && len(titleList) > 0 {
-				if titleItem, ok := titleList[0].(map[string]interface{}); ok {
-					if plainText, ok := titleItem["plain_text"].(string); ok {
-						return plainText
-					}
-				}
-			}
-		}
+	if title := page.Title(); title != "" {
+		return title
 	}
 	return "Untitled"
 }
@@ -3,9 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
-	"time"
 
-	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/sync"
 	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/cobra"
@@ -25,6 +23,7 @@ var (
 	syncDirection string
 	syncDirectory string
 	dryRun        bool
+	syncConflict  string
 )
 
 func init() {
@@ -32,9 +31,11 @@ func init() {
 	syncCmd.Flags().StringVarP(&syncDirection, "direction", "d", "push", "sync direction (push, pull, bidirectional)")
 	syncCmd.Flags().StringVar(&syncDirectory, "directory", "", "directory containing markdown files (defaults to config's markdown_root)")
 	syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be synced without making changes")
+	syncCmd.Flags().StringVar(&syncConflict, "conflict", "", "conflict resolution strategy (local, remote, newer, manual); overrides sync.conflict_resolution")
+	syncCmd.Flags().BoolVar(&jsonOutput, "json", false, "print newline-delimited JSON events instead of human-readable output")
 }
 
-func runSync(cmd *cobra.Command, args []string) error {
+func runSync(cmd *cobra.Command, args []string) (err error) {
 	// Use argument as direction if provided
 	if len(args) > 0 {
 		syncDirection = args[0]
@@ -44,19 +45,44 @@ func runSync(cmd *cobra.Command, args []string) error {
 	if err := util.ValidateSyncDirection(syncDirection); err != nil {
 		return fmt.Errorf("invalid sync direction: %w", err)
 	}
+	if syncConflict != "" {
+		if err := util.ValidateConflictStrategy(syncConflict); err != nil {
+			return fmt.Errorf("invalid conflict strategy: %w", err)
+		}
+	}
+
+	events := openEventStream("sync", syncDirection)
+	defer func() { events.Finish(err) }()
 
 	// Load configuration
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	util.Debug("Loaded configuration from: %s", configPath)
+	if syncConflict != "" {
+		cfg.Sync.ConflictResolution = syncConflict
+	}
+
+	// Nobody reads prompts while --json output goes to a script
+	var opts []sync.SyncerOption
+	if jsonOutput {
+		if syncDirection == "bidirectional" && sync.IsInteractiveConflictStrategy(cfg.Sync.ConflictResolution) {
+			return fmt.Errorf("--json can't prompt to resolve conflicts with the %q strategy; use --conflict local, remote or newer",
+				cfg.Sync.ConflictResolution)
+		}
+		opts = append(opts, sync.WithNonInteractiveConflicts())
+	}
+
+	util.Debug("Loaded configuration from: %s", cfg.File)
 	util.Info("Sync direction: %s", syncDirection)
 
 	// Create sync engine
-	engine := sync.NewEngine(cfg)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	engine, err := newSyncEngine(cfg, opts...)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := commandContext(cfg)
 	defer cancel()
 
 	// Determine the working directory
@@ -85,15 +111,18 @@ func runSync(cmd *cobra.Command, args []string) error {
 	// Sync specific file or all files
 	if syncFile != "" {
 		util.Info("Syncing file: %s", syncFile)
-		return syncSingleFile(ctx, engine, syncFile, syncDirection)
+		events.SetTotal(1)
+		err := syncSingleFile(ctx, engine, syncFile, syncDirection)
+		events.Synced(syncFile, "", err)
+		return err
 	}
 
 	util.Info("Syncing all files in directory: %s", workingDir)
-	if err := performDirectorySync(ctx, engine, workingDir, syncDirection); err != nil {
+	if err := performDirectorySync(ctx, engine, workingDir, syncDirection, events); err != nil {
 		return fmt.Errorf("sync failed: %w", err)
 	}
 
-	fmt.Printf("✓ Sync completed successfully (%s)\n", syncDirection)
+	fmt.Fprintf(humanOutput(), "✓ Sync completed successfully (%s)\n", syncDirection)
 	return nil
 }
 
@@ -103,7 +132,7 @@ func syncSingleFile(ctx context.Context, engine sync.Engine, filePath, direction
 
 func performDryRun(ctx context.Context, workingDir, specificFile, direction string) error {
 	if specificFile != "" {
-		fmt.Printf("Would sync file: %s (%s)\n", specificFile, direction)
+		fmt.Fprintf(humanOutput(), "Would sync file: %s (%s)\n", specificFile, direction)
 		return nil
 	}
 
@@ -114,16 +143,16 @@ func performDryRun(ctx context.Context, workingDir, specificFile, direction stri
 	}
 
 	if len(files) == 0 {
-		fmt.Printf("No markdown files found in %s\n", workingDir)
+		fmt.Fprintf(humanOutput(), "No markdown files found in %s\n", workingDir)
 		return nil
 	}
 
 	actionVerb := getActionVerb(direction)
-	fmt.Printf("Found %d markdown files in %s\n", len(files), workingDir)
-	fmt.Printf("The following files would be %s:\n", actionVerb)
+	fmt.Fprintf(humanOutput(), "Found %d markdown files in %s\n", len(files), workingDir)
+	fmt.Fprintf(humanOutput(), "The following files would be %s:\n", actionVerb)
 
 	for _, file := range files {
-		fmt.Printf("  %s\n", file)
+		fmt.Fprintf(humanOutput(), "  %s\n", file)
 	}
 
 	return nil
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
@@ -20,6 +21,8 @@ type mockSyncEngine struct {
 	syncNotionToFileFunc func(ctx context.Context, pageID, filePath string) error
 	syncAllFunc          func(ctx context.Context, direction string) error
 	syncSpecificFileFunc func(ctx context.Context, filename, direction string) error
+	syncPageSubtreeFunc  func(ctx context.Context, pageID, direction string) error
+	diffFileFunc         func(ctx context.Context, filePath, direction string) (string, error)
 }
 
 func (m *mockSyncEngine) SyncFileToNotion(ctx context.Context, filePath string) error {
@@ -50,6 +53,20 @@ func (m *mockSyncEngine) SyncSpecificFile(ctx context.Context, filename, directi
 	return nil
 }
 
+func (m *mockSyncEngine) SyncPageSubtree(ctx context.Context, pageID, direction string) error {
+	if m.syncPageSubtreeFunc != nil {
+		return m.syncPageSubtreeFunc(ctx, pageID, direction)
+	}
+	return nil
+}
+
+func (m *mockSyncEngine) DiffFile(ctx context.Context, filePath, direction string) (string, error) {
+	if m.diffFileFunc != nil {
+		return m.diffFileFunc(ctx, filePath, direction)
+	}
+	return "", nil
+}
+
 func TestRunSync_DirectionValidation(t *testing.T) {
 	// Only test direction validation logic
 	validDirections := []string{"push", "pull", "bidirectional"}
@@ -243,3 +260,29 @@ directories:
 	engine := sync.NewEngine(cfg)
 	assert.NotNil(t, engine)
 }
+
+func TestWriteDiffs(t *testing.T) {
+	diffs := map[string]string{
+		"changed.md": "--- notion:page-id\n+++ changed.md\n@@ -1,1 +1,1 @@\n-old line\n+new line\n",
+		"same.md":    "",
+	}
+	engine := &mockSyncEngine{
+		diffFileFunc: func(ctx context.Context, filePath, direction string) (string, error) {
+			assert.Equal(t, "push", direction)
+			if diff, ok := diffs[filePath]; ok {
+				return diff, nil
+			}
+			return "", fmt.Errorf("no notion_id")
+		},
+	}
+
+	var out strings.Builder
+	changed, err := writeDiffs(context.Background(), engine, []string{"changed.md", "same.md", "broken.md"}, "push", &out, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, diffs["changed.md"], out.String())
+
+	// A single file's error is returned rather than skipped
+	_, err = writeDiffs(context.Background(), engine, []string{"broken.md"}, "push", &out, false)
+	assert.Error(t, err)
+}
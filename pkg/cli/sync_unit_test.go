@@ -2,10 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSyncCommand_ArgumentParsing(t *testing.T) {
@@ -85,3 +89,77 @@ func TestSyncCommand_ArgumentParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterIncludedFiles(t *testing.T) {
+	files := []string{"docs/intro.md", "docs/api/client.md", "notes.md", "guides/setup.md"}
+
+	included := filterIncludedFiles(files, []string{"docs/**", "guides/*.md"})
+	assert.Equal(t, []string{"docs/intro.md", "docs/api/client.md", "guides/setup.md"}, included)
+
+	assert.Empty(t, filterIncludedFiles(files, []string{"blog/**"}))
+}
+
+func TestPullOutputDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageID    string
+		output    string
+		directory string
+		want      string
+		wantErr   bool
+	}{
+		{name: "markdown root by default", want: "docs"},
+		{name: "output overrides the root", output: "scratch", want: "scratch"},
+		{name: "directory overrides the root", directory: "scratch", want: "scratch"},
+		{name: "output names the file with page-id", pageID: "page-id", output: "page.md", want: "docs"},
+		{name: "matching output and directory", output: "scratch", directory: "./scratch", want: "scratch"},
+		{name: "conflicting output and directory", output: "scratch", directory: "other", wantErr: true},
+		{name: "output escaping the working tree", output: "../scratch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pullOutputDir("docs", tt.pageID, tt.output, tt.directory)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSyncCommand_JSONRejectsInteractiveConflicts(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+notion:
+  token: test_token
+  parent_page_id: abcdef1234567890abcdef1234567890
+`), 0644))
+
+	logOutput := util.GetDefaultLogger().Writer()
+	t.Cleanup(func() {
+		jsonOutput = false
+		syncConflict = ""
+		util.GetDefaultLogger().SetOutput(logOutput)
+	})
+
+	cmd := &cobra.Command{
+		Use:  "sync [direction]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSync,
+	}
+	cmd.Flags().StringVarP(&syncDirection, "direction", "d", "push", "sync direction")
+	cmd.Flags().StringVar(&syncConflict, "conflict", "", "conflict resolution strategy")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "print JSON events")
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "config file path")
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	// The default diff strategy would wait for an answer nobody gives
+	cmd.SetArgs([]string{"bidirectional", "--json", "--config", configFile})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--json can't prompt")
+}
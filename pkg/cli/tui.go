@@ -44,8 +44,9 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get config path: %w", err)
 	}
 
-	// Create the TUI model
-	model := tui.NewModel(configPath)
+	// Create the TUI model, loading the config as the other commands do so
+	// --workspace and --parent-page-url apply
+	model := tui.NewModelWithLoader(configPath, loadConfig)
 
 	// Create the Bubble Tea program
 	p := tea.NewProgram(
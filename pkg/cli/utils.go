@@ -7,10 +7,41 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
 	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 )
 
+// newSyncer creates the library facade with output going to the CLI's
+// default logger
+func newSyncer(cfg *config.Config, opts ...sync.SyncerOption) (*sync.Syncer, error) {
+	opts = append([]sync.SyncerOption{sync.WithLogger(util.GetDefaultLogger())}, opts...)
+	syncer, err := sync.NewSyncer(cfg, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sync engine: %w", err)
+	}
+	return syncer, nil
+}
+
+// newSyncEngine creates the sync engine through the library facade
+func newSyncEngine(cfg *config.Config, opts ...sync.SyncerOption) (sync.Engine, error) {
+	syncer, err := newSyncer(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return syncer.Engine(), nil
+}
+
+// commandContext bounds a whole command run by timeouts.sync, or not at all
+// when it is zero
+func commandContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	if cfg.Timeouts.Sync <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), cfg.Timeouts.Sync)
+}
+
 // findMarkdownFiles recursively finds all markdown files in a directory
 func findMarkdownFiles(dir string) ([]string, error) {
 	var files []string
@@ -31,8 +62,9 @@ func findMarkdownFiles(dir string) ([]string, error) {
 	return files, err
 }
 
-// performDirectorySync syncs all markdown files in a directory
-func performDirectorySync(ctx context.Context, engine sync.Engine, workingDir, direction string) error {
+// performDirectorySync syncs all markdown files in a directory, reporting
+// each file to events, which may be nil
+func performDirectorySync(ctx context.Context, engine sync.Engine, workingDir, direction string, events *eventStream) error {
 	// Find all markdown files in directory
 	files, err := findMarkdownFiles(workingDir)
 	if err != nil {
@@ -40,11 +72,12 @@ func performDirectorySync(ctx context.Context, engine sync.Engine, workingDir, d
 	}
 
 	if len(files) == 0 {
-		fmt.Printf("No markdown files found in %s\n", workingDir)
+		fmt.Fprintf(humanOutput(), "No markdown files found in %s\n", workingDir)
 		return nil
 	}
 
-	fmt.Printf("Found %d markdown files in %s\n", len(files), workingDir)
+	fmt.Fprintf(humanOutput(), "Found %d markdown files in %s\n", len(files), workingDir)
+	events.SetTotal(len(files))
 
 	successCount := 0
 	failureCount := 0
@@ -55,39 +88,46 @@ func performDirectorySync(ctx context.Context, engine sync.Engine, workingDir, d
 
 		switch direction {
 		case "push":
-			if err := engine.SyncFileToNotion(ctx, file); err != nil {
-				fmt.Printf("❌ Failed to push %s: %v\n", file, err)
+			err := engine.SyncFileToNotion(ctx, file)
+			if err != nil {
+				fmt.Fprintf(humanOutput(), "❌ Failed to push %s: %v\n", file, err)
 				failureCount++
 			} else {
-				fmt.Printf("✅ Pushed %s\n", file)
+				fmt.Fprintf(humanOutput(), "✅ Pushed %s\n", file)
 				successCount++
 			}
+			events.Synced(file, "", err)
 		case "pull":
-			if err := syncSingleFileHelper(ctx, engine, file, "pull"); err != nil {
-				if err.Error() == "no notion_id found in frontmatter - cannot pull without page ID" {
-					printVerbose("Skipped %s: No notion_id in frontmatter", file)
-					skippedCount++
-				} else {
-					fmt.Printf("❌ Failed to pull %s: %v\n", file, err)
-					failureCount++
-				}
+			err := syncSingleFileHelper(ctx, engine, file, "pull")
+			if err != nil && err.Error() == "no notion_id found in frontmatter - cannot pull without page ID" {
+				printVerbose("Skipped %s: No notion_id in frontmatter", file)
+				skippedCount++
+				events.Skipped()
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(humanOutput(), "❌ Failed to pull %s: %v\n", file, err)
+				failureCount++
 			} else {
-				fmt.Printf("✅ Pulled %s\n", file)
+				fmt.Fprintf(humanOutput(), "✅ Pulled %s\n", file)
 				successCount++
 			}
+			events.Synced(file, "", err)
 		case "bidirectional":
 			// Try push first, then pull if file has notion_id
-			if err := engine.SyncFileToNotion(ctx, file); err != nil {
-				fmt.Printf("❌ Failed to push %s: %v\n", file, err)
+			err := engine.SyncFileToNotion(ctx, file)
+			if err != nil {
+				fmt.Fprintf(humanOutput(), "❌ Failed to push %s: %v\n", file, err)
 				failureCount++
 			} else {
-				fmt.Printf("✅ Pushed %s\n", file)
+				fmt.Fprintf(humanOutput(), "✅ Pushed %s\n", file)
 				successCount++
 			}
+			events.Synced(file, "", err)
 		}
 	}
 
-	fmt.Printf("\n📊 Sync completed: %d succeeded, %d failed, %d skipped\n",
+	fmt.Fprintf(humanOutput(), "\n📊 Sync completed: %d succeeded, %d failed, %d skipped\n",
 		successCount, failureCount, skippedCount)
 
 	return nil
@@ -198,7 +198,7 @@ notion_id: test-id
 
 			// Run function
 			ctx := context.Background()
-			err := performDirectorySync(ctx, engine, tempDir, tt.direction)
+			err := performDirectorySync(ctx, engine, tempDir, tt.direction, nil)
 
 			// Restore stdout and read output
 			_ = w.Close()
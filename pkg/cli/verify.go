@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
 	"github.com/spf13/cobra"
 )
 
@@ -17,17 +18,25 @@ This command checks:
 - That all required settings are present
 - The current parent page ID in Notion
 - The markdown root directory
-- Sync direction and conflict resolution settings`,
+- Sync direction and conflict resolution settings
+- That every markdown file's notion_id still points at a live page under
+  the parent page (skip with --config-only)
+
+Exits non-zero when any notion_id is missing, archived or outside the
+parent page, so it can run in CI. Nothing is changed in Notion or on disk.`,
 	RunE: runVerify,
 }
 
+var verifyConfigOnly bool
+
 func init() {
+	verifyCmd.Flags().BoolVar(&verifyConfigOnly, "config-only", false, "only check the configuration, without contacting Notion")
 	rootCmd.AddCommand(verifyCmd)
 }
 
 func runVerify(cmd *cobra.Command, args []string) error {
 	// Load configuration
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		// Configuration not found or invalid
 		fmt.Println("❌ Configuration Status: NOT CONFIGURED")
@@ -68,5 +77,46 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if !configValid || verifyConfigOnly {
+		return nil
+	}
+	return verifyPageLinks(cfg)
+}
+
+// verifyPageLinks reports every file whose notion_id no longer resolves to
+// a page under the parent page
+func verifyPageLinks(cfg *config.Config) error {
+	syncer, err := newSyncer(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cfg)
+	defer cancel()
+
+	checks, err := syncer.VerifyLinks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify page links: %w", err)
+	}
+
+	var broken []sync.LinkCheck
+	for _, check := range checks {
+		if check.Broken() {
+			broken = append(broken, check)
+		}
+	}
+
+	if len(broken) > 0 {
+		fmt.Printf("❌ Page Links: %d of %d broken\n", len(broken), len(checks))
+		for _, check := range broken {
+			if check.Err != nil {
+				fmt.Printf("   - %s: %s (%s: %v)\n", check.FilePath, check.NotionID, check.Status, check.Err)
+			} else {
+				fmt.Printf("   - %s: %s (%s)\n", check.FilePath, check.NotionID, check.Status)
+			}
+		}
+		return fmt.Errorf("%d of %d notion_ids are broken", len(broken), len(checks))
+	}
+	fmt.Printf("✅ Page Links: all %d valid\n", len(checks))
 	return nil
 }
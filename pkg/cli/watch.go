@@ -8,30 +8,49 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/byvfx/go-notion-md-sync/pkg/config"
-	"github.com/byvfx/go-notion-md-sync/pkg/sync"
 	"github.com/byvfx/go-notion-md-sync/pkg/watcher"
 	"github.com/spf13/cobra"
 )
 
 var watchCmd = &cobra.Command{
 	Use:   "watch",
-	Short: "Watch for file changes and auto-sync",
-	Long:  `Watch the markdown directory for changes and automatically sync files to Notion.`,
-	RunE:  runWatch,
+	Short: "Watch for changes and auto-sync",
+	Long: `Watch the markdown directory for changes and automatically sync files to Notion.
+
+With --direction pull, Notion is polled for pages edited since the last poll
+and those pages are pulled. Bidirectional mode does both.
+
+Examples:
+  notion-md-sync watch                                  # Push local changes
+  notion-md-sync watch --direction pull                 # Pull remote changes
+  notion-md-sync watch -d bidirectional --poll-interval 1m`,
+	RunE: runWatch,
 }
 
 var (
-	watchInterval time.Duration
+	watchInterval     time.Duration
+	watchDirection    string
+	watchPollInterval time.Duration
 )
 
 func init() {
 	watchCmd.Flags().DurationVar(&watchInterval, "interval", 1*time.Second, "debounce interval for file changes")
+	watchCmd.Flags().StringVarP(&watchDirection, "direction", "d", "push", "what to watch: push (local files), pull (Notion) or bidirectional")
+	watchCmd.Flags().DurationVar(&watchPollInterval, "poll-interval", 30*time.Second, "how often to poll Notion for changes when pulling")
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
+	pushing := watchDirection == "push" || watchDirection == "bidirectional"
+	pulling := watchDirection == "pull" || watchDirection == "bidirectional"
+	if !pushing && !pulling {
+		return fmt.Errorf("invalid watch direction: %s (must be push, pull, or bidirectional)", watchDirection)
+	}
+	if pulling && watchPollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+
 	// Load configuration
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -40,18 +59,11 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	printVerbose("Watching directory: %s", cfg.Directories.MarkdownRoot)
 
 	// Create sync engine
-	engine := sync.NewEngine(cfg)
-
-	// Create file watcher
-	w, err := watcher.NewWatcher(cfg, engine)
+	syncer, err := newSyncer(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create watcher: %w", err)
+		return err
 	}
-	defer func() {
-		if err := w.Close(); err != nil {
-			fmt.Printf("Warning: failed to close watcher: %v\n", err)
-		}
-	}()
+	engine := syncer.Engine()
 
 	// Set up signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -66,12 +78,52 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	fmt.Printf("🔍 Watching for changes in %s\n", cfg.Directories.MarkdownRoot)
+	var w *watcher.Watcher
+	if pushing {
+		// Create file watcher
+		w, err = watcher.NewWatcher(cfg, engine)
+		if err != nil {
+			return fmt.Errorf("failed to create watcher: %w", err)
+		}
+		defer func() {
+			if err := w.Close(); err != nil {
+				fmt.Printf("Warning: failed to close watcher: %v\n", err)
+			}
+		}()
+	}
+
+	errCh := make(chan error, 2)
+	running := 0
+
+	if pulling {
+		poller := watcher.NewPoller(cfg, syncer.Client(), engine, watchPollInterval)
+		if w != nil {
+			w.IgnorePulls(poller)
+		}
+
+		fmt.Printf("☁️  Polling Notion for changes every %s\n", watchPollInterval)
+		running++
+		go func() { errCh <- poller.Start(ctx) }()
+	}
+
+	if w != nil {
+		fmt.Printf("🔍 Watching for changes in %s\n", cfg.Directories.MarkdownRoot)
+		running++
+		go func() { errCh <- w.Start(ctx) }()
+	}
+
 	fmt.Println("Press Ctrl+C to stop")
 
-	// Start watching
-	if err := w.Start(ctx); err != nil && err != context.Canceled {
-		return fmt.Errorf("watcher error: %w", err)
+	// Stop everything as soon as either loop exits
+	var watchErr error
+	for i := 0; i < running; i++ {
+		if err := <-errCh; err != nil && err != context.Canceled && watchErr == nil {
+			watchErr = err
+		}
+		cancel()
+	}
+	if watchErr != nil {
+		return fmt.Errorf("watcher error: %w", watchErr)
 	}
 
 	fmt.Println("✓ Watch stopped")
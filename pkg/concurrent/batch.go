@@ -3,10 +3,12 @@ package concurrent
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/cache"
+	"github.com/byvfx/go-notion-md-sync/pkg/concurrent/batch"
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 	syncpkg "github.com/byvfx/go-notion-md-sync/pkg/sync"
 )
@@ -278,21 +280,93 @@ func NewBulkSyncManager(client notion.Client, converter syncpkg.Converter, confi
 	}
 }
 
-// BulkSyncPages synchronizes multiple pages concurrently
-func (bsm *BulkSyncManager) BulkSyncPages(ctx context.Context, pageIDs []string, outputDir string) (*BatchResult, error) {
-	operations := make([]BatchOperation, len(pageIDs))
-	for i, pageID := range pageIDs {
-		operations[i] = BatchOperation{
-			ID:   pageID,
-			Type: "page_sync",
-			Payload: map[string]interface{}{
-				"page_id":    pageID,
-				"output_dir": outputDir,
-			},
+// PageSyncResult is the outcome of one page of a bulk sync
+type PageSyncResult struct {
+	PageID   string
+	FilePath string // Where the page's markdown was written
+	Attempts int    // Number of times the page was fetched
+	Err      error  // Set if the page still failed after retrying
+}
+
+// BulkProgressFunc is called as each page of a bulk sync finishes, with the
+// number of pages finished so far. Calls are never concurrent.
+type BulkProgressFunc func(result PageSyncResult, done, total int)
+
+// BulkSyncResult represents the result of a bulk page sync
+type BulkSyncResult struct {
+	BatchResult
+	Pages []PageSyncResult // In the order the page IDs were given
+}
+
+// BulkSyncPages writes each page to outputDir/<page ID>.md with at most
+// MaxConcurrency pages in flight. Fetches that fail with a rate limit or
+// server error are retried up to RetryAttempts times with an exponential
+// backoff starting at RetryDelay. A page that still fails doesn't stop the
+// others; its error is recorded in its PageSyncResult. progress may be nil.
+func (bsm *BulkSyncManager) BulkSyncPages(ctx context.Context, pageIDs []string, outputDir string, progress BulkProgressFunc) (*BulkSyncResult, error) {
+	startTime := time.Now()
+	result := &BulkSyncResult{
+		BatchResult: BatchResult{Metadata: make(map[string]interface{})},
+		Pages:       make([]PageSyncResult, len(pageIDs)),
+	}
+	if len(pageIDs) == 0 {
+		return result, nil
+	}
+
+	workers := bsm.processor.config.MaxConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	done := 0
+	processor := batch.Processor{
+		Workers: workers,
+		OnDone: func(index int, err error) {
+			page := result.Pages[index]
+			if page.Err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, page.Err)
+			} else {
+				result.Success++
+			}
+			done++
+			if progress != nil {
+				progress(page, done, len(pageIDs))
+			}
+		},
+	}
+	errs := processor.Run(ctx, len(pageIDs), func(ctx context.Context, index int) error {
+		page := bsm.syncPage(ctx, pageIDs[index], outputDir)
+		result.Pages[index] = page
+		return page.Err
+	})
+
+	// Pages never started because ctx was cancelled fail with its error
+	for index, page := range result.Pages {
+		if page.PageID == "" {
+			result.Pages[index] = PageSyncResult{PageID: pageIDs[index], Err: errs[index]}
+			result.Failed++
+			result.Errors = append(result.Errors, errs[index])
 		}
 	}
 
-	return bsm.processor.ProcessBatch(ctx, operations)
+	result.Duration = time.Since(startTime)
+	result.Metadata["workers"] = workers
+	return result, nil
+}
+
+// syncPage fetches one page, retrying transient failures, and writes it as
+// markdown
+func (bsm *BulkSyncManager) syncPage(ctx context.Context, pageID, outputDir string) PageSyncResult {
+	job := &PageSyncJob{
+		PageID:    pageID,
+		Client:    bsm.client,
+		Converter: bsm.converter,
+		FilePath:  filepath.Join(outputDir, pageID+".md"),
+	}
+
+	attempts, err := bsm.withRetry(ctx, job.Execute)
+	return PageSyncResult{PageID: pageID, FilePath: job.FilePath, Attempts: attempts, Err: err}
 }
 
 // BulkSyncBlocks synchronizes blocks for multiple pages
@@ -328,6 +402,117 @@ func (bsm *BulkSyncManager) BulkSyncDatabases(ctx context.Context, databaseIDs [
 	return bsm.processor.ProcessBatch(ctx, operations)
 }
 
+// PageCreateRequest describes a page to create from a markdown file
+type PageCreateRequest struct {
+	Source     string // Markdown file the page is created from
+	Properties map[string]interface{}
+	Blocks     []map[string]interface{}
+}
+
+// BulkCreateResult represents the result of a bulk page creation
+type BulkCreateResult struct {
+	BatchResult
+	PageIDs map[string]string // Created page IDs keyed by request source
+}
+
+// BulkCreatePages creates pages under parentID with at most MaxConcurrency
+// requests in flight. Rate limit and server errors are retried up to
+// RetryAttempts times with an exponential backoff starting at RetryDelay.
+// Pages that still fail are counted and reported in Errors, so the IDs of
+// the rest can be written back to their files.
+func (bsm *BulkSyncManager) BulkCreatePages(ctx context.Context, parentID string, requests []PageCreateRequest) (*BulkCreateResult, error) {
+	startTime := time.Now()
+	result := &BulkCreateResult{
+		BatchResult: BatchResult{Metadata: make(map[string]interface{})},
+		PageIDs:     make(map[string]string, len(requests)),
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	workers := bsm.processor.config.MaxConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	pages := make([]*notion.Page, len(requests))
+	processor := batch.Processor{Workers: workers}
+	errs := processor.Run(ctx, len(requests), func(ctx context.Context, i int) error {
+		page, err := bsm.createPageWithRetry(ctx, parentID, requests[i])
+		pages[i] = page
+		return err
+	})
+
+	for i, req := range requests {
+		if errs[i] != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("failed to create page for %s: %w", req.Source, errs[i]))
+		} else {
+			result.Success++
+			result.PageIDs[req.Source] = pages[i].ID
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+	result.Metadata["workers"] = workers
+	return result, nil
+}
+
+// createPageWithRetry creates a single page, retrying transient errors
+func (bsm *BulkSyncManager) createPageWithRetry(ctx context.Context, parentID string, req PageCreateRequest) (*notion.Page, error) {
+	var page *notion.Page
+	_, err := bsm.withRetry(ctx, func(opCtx context.Context) error {
+		var err error
+		page, err = bsm.client.RecreatePageWithBlocks(opCtx, parentID, req.Properties, req.Blocks)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if page == nil || page.ID == "" {
+		return nil, fmt.Errorf("no page ID returned")
+	}
+	return page, nil
+}
+
+// withRetry runs op with the configured timeout, backing off exponentially
+// between attempts that fail with a transient error. It returns the number
+// of attempts made.
+func (bsm *BulkSyncManager) withRetry(ctx context.Context, op func(ctx context.Context) error) (int, error) {
+	config := bsm.processor.config
+	delay := config.RetryDelay
+
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt <= config.RetryAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return attempts, fmt.Errorf("operation cancelled: %w", ctx.Err())
+		}
+
+		opCtx, cancel := context.WithTimeout(ctx, config.Timeout)
+		err := op(opCtx)
+		cancel()
+		attempts++
+		if err == nil {
+			return attempts, nil
+		}
+
+		lastErr = err
+		if !notion.IsTransientError(err) || attempt == config.RetryAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return attempts, fmt.Errorf("operation cancelled during retry: %w", ctx.Err())
+		}
+	}
+
+	return attempts, lastErr
+}
+
 // OptimizedBatch provides optimized batch processing with intelligent scheduling
 type OptimizedBatch struct {
 	config    *BatchConfig
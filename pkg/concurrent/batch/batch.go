@@ -0,0 +1,94 @@
+// Package batch runs batches of independent operations with a bounded number
+// in flight. It depends on nothing else in this module, so the notion and
+// sync packages, which package concurrent itself builds on, can use it too.
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotRun is recorded for operations a stopped batch never started, or cut
+// short once another operation had failed
+var ErrNotRun = errors.New("not run after an earlier operation failed")
+
+// Processor runs operations with at most Workers of them in flight
+type Processor struct {
+	Workers     int  // Operations run at once; 1 when not positive
+	StopOnError bool // Once an operation fails, cancel those running and start no more
+
+	// OnDone, when set, is called with each operation's index and error as it
+	// finishes. Calls are never concurrent.
+	OnDone func(i int, err error)
+}
+
+// Run calls op for every index from 0 to n-1 and returns the errors by index.
+// Operations that never start because ctx is done get ctx's error.
+func (p Processor) Run(ctx context.Context, n int, op func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+
+	workers := p.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	stopped := false
+	queue := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				err := op(runCtx, i)
+
+				mu.Lock()
+				if err != nil && stopped && ctx.Err() == nil && errors.Is(err, context.Canceled) {
+					err = ErrNotRun
+				}
+				errs[i] = err
+				if err != nil && p.StopOnError && !stopped {
+					stopped = true
+					cancel()
+				}
+				if p.OnDone != nil {
+					p.OnDone(i, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Hand out operations in index order until they run out or the batch
+	// stops
+	next := 0
+	for next < n && runCtx.Err() == nil {
+		select {
+		case queue <- next:
+			next++
+		case <-runCtx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for i := next; i < n; i++ {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+		} else {
+			errs[i] = ErrNotRun
+		}
+	}
+	return errs
+}
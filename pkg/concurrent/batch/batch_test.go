@@ -0,0 +1,97 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProcessor_RunBoundsWorkers(t *testing.T) {
+	var inFlight, peak int32
+	done := make([]bool, 20)
+
+	errs := Processor{Workers: 3}.Run(context.Background(), len(done), func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		done[i] = true
+		if i == 7 {
+			return errors.New("row 7 failed")
+		}
+		return nil
+	})
+
+	if peak > 3 {
+		t.Errorf("peak in flight = %d, want at most 3", peak)
+	}
+	for i, ran := range done {
+		if !ran {
+			t.Errorf("operation %d didn't run", i)
+		}
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 7) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+}
+
+func TestProcessor_RunStopOnError(t *testing.T) {
+	var finished []int
+	started := make(chan struct{})
+	errs := Processor{
+		Workers:     2,
+		StopOnError: true,
+		OnDone:      func(i int, err error) { finished = append(finished, i) },
+	}.Run(context.Background(), 10, func(ctx context.Context, i int) error {
+		switch i {
+		case 0:
+			<-started
+			return errors.New("failed")
+		case 1:
+			// Still running when 0 fails
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if errs[0] == nil || errors.Is(errs[0], ErrNotRun) {
+		t.Errorf("errs[0] = %v, want the operation's error", errs[0])
+	}
+	for i := 1; i < len(errs); i++ {
+		if !errors.Is(errs[i], ErrNotRun) {
+			t.Errorf("errs[%d] = %v, want ErrNotRun", i, errs[i])
+		}
+	}
+	if len(finished) != 2 {
+		t.Errorf("OnDone called for %v, want the two operations that started", finished)
+	}
+}
+
+func TestProcessor_RunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := Processor{Workers: 2}.Run(ctx, 3, func(ctx context.Context, i int) error {
+		return nil
+	})
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("errs[%d] = %v, want context.Canceled", i, err)
+		}
+	}
+
+	if errs := (Processor{}).Run(context.Background(), 0, nil); len(errs) != 0 {
+		t.Errorf("empty batch returned %v", errs)
+	}
+}
@@ -3,6 +3,9 @@ package concurrent
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -212,7 +215,7 @@ func TestBulkSyncManager_BulkSyncPages(t *testing.T) {
 	pageIDs := []string{"page1", "page2", "page3", "page4", "page5"}
 	ctx := context.Background()
 
-	result, err := manager.BulkSyncPages(ctx, pageIDs, "/tmp/output")
+	result, err := manager.BulkSyncPages(ctx, pageIDs, t.TempDir(), nil)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -227,6 +230,197 @@ func TestBulkSyncManager_BulkSyncPages(t *testing.T) {
 	}
 }
 
+// flakyNotionClient fails the first failures[pageID] block fetches for a
+// page with a 503
+type flakyNotionClient struct {
+	mockNotionClient
+	failures map[string]int
+	err      error
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (c *flakyNotionClient) GetPageBlocks(ctx context.Context, pageID string) ([]notion.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts[pageID]++
+	if c.attempts[pageID] <= c.failures[pageID] {
+		return nil, c.err
+	}
+	return []notion.Block{{ID: "block-1"}}, nil
+}
+
+func TestBulkSyncManager_BulkSyncPages_RetriesTransientFailures(t *testing.T) {
+	config := DefaultBatchConfig()
+	config.MaxConcurrency = 2
+	config.RetryAttempts = 3
+	config.RetryDelay = time.Millisecond
+
+	client := &flakyNotionClient{
+		failures: map[string]int{"page2": 1, "page4": 3, "page5": 10},
+		err:      &notion.NotionAPIError{Code: 503, Message: "service unavailable"},
+		attempts: make(map[string]int),
+	}
+	manager := NewBulkSyncManager(client, &mockConverter{}, config)
+
+	var progressed []int
+	pageIDs := []string{"page1", "page2", "page3", "page4", "page5"}
+	outputDir := t.TempDir()
+	result, err := manager.BulkSyncPages(context.Background(), pageIDs, outputDir, func(page PageSyncResult, done, total int) {
+		if total != len(pageIDs) {
+			t.Errorf("Expected total %d, got %d", len(pageIDs), total)
+		}
+		progressed = append(progressed, done)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Success != 4 || result.Failed != 1 {
+		t.Errorf("Expected 4 synced and 1 failed, got %d and %d: %v", result.Success, result.Failed, result.Errors)
+	}
+	if len(progressed) != len(pageIDs) || progressed[len(progressed)-1] != len(pageIDs) {
+		t.Errorf("Expected a progress call per page, got %v", progressed)
+	}
+
+	wantAttempts := map[string]int{"page1": 1, "page2": 2, "page3": 1, "page4": 4, "page5": 4}
+	for i, page := range result.Pages {
+		if page.PageID != pageIDs[i] {
+			t.Errorf("Expected result %d to be for %s, got %s", i, pageIDs[i], page.PageID)
+		}
+		if page.Attempts != wantAttempts[page.PageID] {
+			t.Errorf("Expected %d attempts for %s, got %d", wantAttempts[page.PageID], page.PageID, page.Attempts)
+		}
+
+		_, statErr := os.Stat(filepath.Join(outputDir, page.PageID+".md"))
+		if page.PageID == "page5" {
+			if page.Err == nil {
+				t.Errorf("Expected page5 to fail after exhausting retries")
+			}
+			if statErr == nil {
+				t.Errorf("Expected no file for the failed page")
+			}
+		} else if page.Err != nil || statErr != nil {
+			t.Errorf("Expected %s to be written after retrying, got %v / %v", page.PageID, page.Err, statErr)
+		}
+	}
+}
+
+// creatingNotionClient creates pages with IDs derived from their titles,
+// failing the first attempt for each title in rateLimited with a 429
+type creatingNotionClient struct {
+	mockNotionClient
+	rateLimited map[string]bool
+
+	mu          sync.Mutex
+	attempts    map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *creatingNotionClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
+	title := properties["title"].(string)
+
+	c.mu.Lock()
+	c.attempts[title]++
+	attempt := c.attempts[title]
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if c.rateLimited[title] && attempt == 1 {
+		return nil, &notion.NotionAPIError{Code: 429, Message: "rate limited"}
+	}
+	return &notion.Page{ID: "id-" + title}, nil
+}
+
+func TestBulkSyncManager_BulkCreatePages(t *testing.T) {
+	config := DefaultBatchConfig()
+	config.MaxConcurrency = 4
+	config.RetryDelay = time.Millisecond
+
+	client := &creatingNotionClient{
+		rateLimited: map[string]bool{"page3": true, "page7": true},
+		attempts:    make(map[string]int),
+	}
+	manager := NewBulkSyncManager(client, &mockConverter{}, config)
+
+	var requests []PageCreateRequest
+	for i := 0; i < 10; i++ {
+		title := fmt.Sprintf("page%d", i)
+		requests = append(requests, PageCreateRequest{
+			Source:     title + ".md",
+			Properties: map[string]interface{}{"title": title},
+		})
+	}
+
+	result, err := manager.BulkCreatePages(context.Background(), "parent-id", requests)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Success != 10 || result.Failed != 0 {
+		t.Errorf("Expected 10 created and 0 failed, got %d and %d: %v", result.Success, result.Failed, result.Errors)
+	}
+	if len(result.PageIDs) != 10 {
+		t.Errorf("Expected 10 page IDs, got %d", len(result.PageIDs))
+	}
+	for i := 0; i < 10; i++ {
+		source := fmt.Sprintf("page%d.md", i)
+		if want := fmt.Sprintf("id-page%d", i); result.PageIDs[source] != want {
+			t.Errorf("Expected %s to map to %s, got %q", source, want, result.PageIDs[source])
+		}
+	}
+
+	if client.attempts["page3"] != 2 || client.attempts["page0"] != 1 {
+		t.Errorf("Expected only rate limited pages to be retried, got attempts %v", client.attempts)
+	}
+	if client.maxInFlight < 2 || client.maxInFlight > config.MaxConcurrency {
+		t.Errorf("Expected between 2 and %d concurrent creates, got %d", config.MaxConcurrency, client.maxInFlight)
+	}
+}
+
+func TestBulkSyncManager_BulkCreatePages_PermanentFailure(t *testing.T) {
+	config := DefaultBatchConfig()
+	config.RetryDelay = time.Millisecond
+
+	attempts := 0
+	client := &failingCreateClient{err: &notion.NotionAPIError{Code: 400, Message: "invalid"}, attempts: &attempts}
+	manager := NewBulkSyncManager(client, &mockConverter{}, config)
+
+	result, err := manager.BulkCreatePages(context.Background(), "parent-id", []PageCreateRequest{{Source: "bad.md"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Failed != 1 || len(result.Errors) != 1 || len(result.PageIDs) != 0 {
+		t.Errorf("Expected one failure and no page IDs, got %+v", result)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected validation errors not to be retried, got %d attempts", attempts)
+	}
+}
+
+type failingCreateClient struct {
+	mockNotionClient
+	err      error
+	attempts *int
+}
+
+func (c *failingCreateClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
+	*c.attempts++
+	return nil, c.err
+}
+
 func TestBulkSyncManager_BulkSyncBlocks(t *testing.T) {
 	config := DefaultBatchConfig()
 	config.BatchSize = 2
@@ -486,6 +680,26 @@ func TestAdvancedBatchProcessor_Timeout(t *testing.T) {
 // Mock implementations for testing
 type mockNotionClient struct{}
 
+func (m *mockNotionClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	return &notion.User{ID: "bot-user"}, nil
+}
+
+func (m *mockNotionClient) ListUsers(ctx context.Context) ([]notion.User, error) {
+	return nil, nil
+}
+
+func (m *mockNotionClient) GetUser(ctx context.Context, userID string) (*notion.User, error) {
+	return &notion.User{ID: userID}, nil
+}
+
+func (m *mockNotionClient) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*notion.Page, error) {
+	pages := make(map[string]*notion.Page, len(pageIDs))
+	for _, pageID := range pageIDs {
+		pages[pageID] = &notion.Page{ID: pageID}
+	}
+	return pages, nil
+}
+
 func (m *mockNotionClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
 	return &notion.Page{ID: pageID}, nil
 }
@@ -498,7 +712,7 @@ func (m *mockNotionClient) GetDatabase(ctx context.Context, databaseID string) (
 	return &notion.Database{ID: databaseID}, nil
 }
 
-func (m *mockNotionClient) StreamDescendantPages(ctx context.Context, parentID string) *notion.PageStream {
+func (m *mockNotionClient) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *notion.PageStream {
 	return notion.NewPageStream()
 }
 
@@ -510,14 +724,38 @@ func (m *mockNotionClient) CreatePage(ctx context.Context, parentID string, prop
 	return nil, nil
 }
 
+func (m *mockNotionClient) GetBlock(ctx context.Context, blockID string) (*notion.Block, error) {
+	return nil, nil
+}
+
+func (m *mockNotionClient) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*notion.Block, error) {
+	return nil, nil
+}
+
 func (m *mockNotionClient) UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
 	return nil
 }
 
+func (m *mockNotionClient) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockNotionClient) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockNotionClient) GetPageProperty(ctx context.Context, pageID, propertyID string) (*notion.PropertyValue, error) {
+	return nil, nil
+}
+
 func (m *mockNotionClient) DeletePage(ctx context.Context, pageID string) error {
 	return nil
 }
 
+func (m *mockNotionClient) TrashPage(ctx context.Context, pageID string) error {
+	return nil
+}
+
 func (m *mockNotionClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
 	return nil, nil
 }
@@ -530,7 +768,7 @@ func (m *mockNotionClient) GetChildPages(ctx context.Context, parentID string) (
 	return nil, nil
 }
 
-func (m *mockNotionClient) GetAllDescendantPages(ctx context.Context, parentID string) ([]notion.Page, error) {
+func (m *mockNotionClient) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error) {
 	return nil, nil
 }
 
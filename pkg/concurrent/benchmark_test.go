@@ -355,6 +355,30 @@ type benchmarkNotionClient struct {
 	callCount    int
 }
 
+func (c *benchmarkNotionClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	return &notion.User{ID: "bot-user"}, nil
+}
+
+func (c *benchmarkNotionClient) ListUsers(ctx context.Context) ([]notion.User, error) {
+	return nil, nil
+}
+
+func (c *benchmarkNotionClient) GetUser(ctx context.Context, userID string) (*notion.User, error) {
+	return &notion.User{ID: userID}, nil
+}
+
+func (c *benchmarkNotionClient) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*notion.Page, error) {
+	pages := make(map[string]*notion.Page, len(pageIDs))
+	for _, pageID := range pageIDs {
+		page, err := c.GetPage(ctx, pageID)
+		if err != nil {
+			return pages, err
+		}
+		pages[pageID] = page
+	}
+	return pages, nil
+}
+
 func (c *benchmarkNotionClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
 	c.mu.Lock()
 	c.callCount++
@@ -420,14 +444,38 @@ func (c *benchmarkNotionClient) CreatePage(ctx context.Context, parentID string,
 	return nil, nil
 }
 
+func (c *benchmarkNotionClient) GetBlock(ctx context.Context, blockID string) (*notion.Block, error) {
+	return &notion.Block{ID: blockID}, nil
+}
+
+func (c *benchmarkNotionClient) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*notion.Block, error) {
+	return &notion.Block{ID: blockID}, nil
+}
+
 func (c *benchmarkNotionClient) UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
 	return nil
 }
 
+func (c *benchmarkNotionClient) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	return nil
+}
+
+func (c *benchmarkNotionClient) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	return nil
+}
+
+func (c *benchmarkNotionClient) GetPageProperty(ctx context.Context, pageID, propertyID string) (*notion.PropertyValue, error) {
+	return nil, nil
+}
+
 func (c *benchmarkNotionClient) DeletePage(ctx context.Context, pageID string) error {
 	return nil
 }
 
+func (c *benchmarkNotionClient) TrashPage(ctx context.Context, pageID string) error {
+	return nil
+}
+
 func (c *benchmarkNotionClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*notion.Page, error) {
 	return nil, nil
 }
@@ -440,7 +488,7 @@ func (c *benchmarkNotionClient) GetChildPages(ctx context.Context, parentID stri
 	return nil, nil
 }
 
-func (c *benchmarkNotionClient) GetAllDescendantPages(ctx context.Context, parentID string) ([]notion.Page, error) {
+func (c *benchmarkNotionClient) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error) {
 	return nil, nil
 }
 
@@ -460,7 +508,7 @@ func (c *benchmarkNotionClient) UpdateDatabaseRow(ctx context.Context, pageID st
 	return nil, nil
 }
 
-func (c *benchmarkNotionClient) StreamDescendantPages(ctx context.Context, parentID string) *notion.PageStream {
+func (c *benchmarkNotionClient) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *notion.PageStream {
 	return notion.NewPageStream()
 }
 
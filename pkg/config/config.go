@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/spf13/viper"
 	"github.com/subosito/gotenv"
 )
@@ -16,27 +21,110 @@ type Config struct {
 	} `yaml:"notion" mapstructure:"notion"`
 
 	Sync struct {
-		Direction          string `yaml:"direction" mapstructure:"direction"`
-		ConflictResolution string `yaml:"conflict_resolution" mapstructure:"conflict_resolution"`
+		Direction           string `yaml:"direction" mapstructure:"direction"`
+		ConflictResolution  string `yaml:"conflict_resolution" mapstructure:"conflict_resolution"`
+		StrictMarkdown      bool   `yaml:"strict_markdown" mapstructure:"strict_markdown"`           // Fail pushes that use unsupported markdown
+		OrphanedPages       string `yaml:"orphaned_pages" mapstructure:"orphaned_pages"`             // What a push does when notion_id points at a deleted page
+		EmptyPages          string `yaml:"empty_pages" mapstructure:"empty_pages"`                   // What a push does with new files that have no content
+		NormalizeTypography bool   `yaml:"normalize_typography" mapstructure:"normalize_typography"` // Replace smart quotes, dashes and non-breaking spaces on pull
+		StripTitleHeading   bool   `yaml:"strip_title_heading" mapstructure:"strip_title_heading"`   // Leave out a leading "# <title>" on push and restore it on pull
+		PreserveHTML        bool   `yaml:"preserve_html" mapstructure:"preserve_html"`               // Push unrecognized HTML blocks as html code blocks instead of dropping them
+		DeletePages         string `yaml:"delete_pages" mapstructure:"delete_pages"`                 // How pushes remove pages from Notion: archive or trash
+		Flavor              string `yaml:"flavor" mapstructure:"flavor"`                             // Markdown flavor pulls write: commonmark or mdx
+		LinkStyle           string `yaml:"link_style" mapstructure:"link_style"`                     // How pulls write links: inline or reference
+		StoreNotionURL      bool   `yaml:"store_notion_url" mapstructure:"store_notion_url"`         // Write each page's Notion link to notion_url on pull
+		LineEndings         string `yaml:"line_endings" mapstructure:"line_endings"`                 // Line endings of written markdown: lf, crlf or auto for the OS's
+		OverwriteTemplates  bool   `yaml:"overwrite_templates" mapstructure:"overwrite_templates"`   // Let pushes replace the content of database template pages
+		MaxPages            int    `yaml:"max_pages" mapstructure:"max_pages"`                       // Most pages a pull may find below the parent page; 0 is unlimited
+		ConflictLog         string `yaml:"conflict_log" mapstructure:"conflict_log"`                 // File bidirectional syncs record conflicts in, relative to markdown_root; empty disables it
 	} `yaml:"sync" mapstructure:"sync"`
 
 	Performance struct {
-		Workers        int  `yaml:"workers" mapstructure:"workers"`
-		UseMultiClient bool `yaml:"use_multi_client" mapstructure:"use_multi_client"`
-		ClientCount    int  `yaml:"client_count" mapstructure:"client_count"`
+		Workers           int     `yaml:"workers" mapstructure:"workers"`
+		UseMultiClient    bool    `yaml:"use_multi_client" mapstructure:"use_multi_client"`
+		ClientCount       int     `yaml:"client_count" mapstructure:"client_count"`
+		RequestsPerSecond float64 `yaml:"requests_per_second" mapstructure:"requests_per_second"`
+		WorkerScaling     string  `yaml:"worker_scaling" mapstructure:"worker_scaling"` // static sizes pull workers by page count; adaptive scales them by how Notion responds
+		DeleteWorkers     int     `yaml:"delete_workers" mapstructure:"delete_workers"` // Blocks deleted at once when a push replaces a page's content
+
+		// Fail fast after this many requests in a row hit a server error
+		// or timeout, for the cooldown; 0 disables the circuit breaker
+		CircuitBreakerThreshold int           `yaml:"circuit_breaker_threshold" mapstructure:"circuit_breaker_threshold"`
+		CircuitBreakerCooldown  time.Duration `yaml:"circuit_breaker_cooldown" mapstructure:"circuit_breaker_cooldown"`
 	} `yaml:"performance" mapstructure:"performance"`
 
+	// Timeouts bound whole operations, on top of the client's per-request
+	// timeout. Zero means no deadline.
+	Timeouts struct {
+		Sync       time.Duration `yaml:"sync" mapstructure:"sync"`               // A whole command run
+		PageFetch  time.Duration `yaml:"page_fetch" mapstructure:"page_fetch"`   // Fetching one page and its block tree
+		PageUpdate time.Duration `yaml:"page_update" mapstructure:"page_update"` // Pushing one file to its page
+	} `yaml:"timeouts" mapstructure:"timeouts"`
+
 	Directories struct {
 		MarkdownRoot     string   `yaml:"markdown_root" mapstructure:"markdown_root"`
 		ExcludedPatterns []string `yaml:"excluded_patterns" mapstructure:"excluded_patterns"`
+		IncludedPatterns []string `yaml:"included_patterns" mapstructure:"included_patterns"` // Empty includes every file
+		DatabasesDir     string   `yaml:"databases_dir" mapstructure:"databases_dir"`         // Empty keeps database exports next to their page
 	} `yaml:"directories" mapstructure:"directories"`
 
 	Mapping struct {
 		Strategy string `yaml:"strategy" mapstructure:"strategy"`
+
+		// Frontmatter keys for Notion property names, used both ways; other
+		// properties get their name slugified, so "Due Date" is due_date
+		Properties map[string]string `yaml:"properties" mapstructure:"properties"`
 	} `yaml:"mapping" mapstructure:"mapping"`
+
+	// Workspaces holds named profiles that override the top-level settings
+	Workspaces map[string]WorkspaceProfile `yaml:"workspaces" mapstructure:"workspaces"`
+
+	// Workspace is the name of the profile selected when loading
+	Workspace string `yaml:"-" mapstructure:"-"`
+
+	// File is the config file that was read, empty when none was found
+	File string `yaml:"-" mapstructure:"-"`
+}
+
+// WorkspaceProfile is a named Notion workspace; empty fields fall back to the top-level settings
+type WorkspaceProfile struct {
+	Token        string `yaml:"token" mapstructure:"token"`
+	ParentPageID string `yaml:"parent_page_id" mapstructure:"parent_page_id"`
+	MarkdownRoot string `yaml:"markdown_root" mapstructure:"markdown_root"`
+}
+
+// DefaultWorkspace is used when no profile is selected
+const DefaultWorkspace = "default"
+
+// ProfileEnvVar selects a workspace profile when no --workspace flag is given
+const ProfileEnvVar = "NOTION_MD_SYNC_PROFILE"
+
+// ConfigFileNames are the config files looked for in each directory from
+// the working directory up, in order of preference
+var ConfigFileNames = []string{
+	"config.yaml",
+	"config.yml",
+	".notion-md-sync.yaml",
+	".notion-md-sync.yml",
+	filepath.Join("configs", "config.yaml"),
 }
 
+// DefaultConflictLog is where bidirectional syncs record conflicts, relative
+// to the markdown root, unless sync.conflict_log says otherwise
+const DefaultConflictLog = ".notion-sync-conflicts.log"
+
+// maxDeleteWorkers caps concurrent block deletes; Notion's rate limit makes
+// more pointless
+const maxDeleteWorkers = 10
+
+// Load reads the configuration using the profile named by NOTION_MD_SYNC_PROFILE
 func Load(configPath string) (*Config, error) {
+	return LoadWorkspace(configPath, "")
+}
+
+// LoadWorkspace reads the configuration and applies the named workspace profile.
+// An empty name falls back to NOTION_MD_SYNC_PROFILE, then to the default profile.
+func LoadWorkspace(configPath, workspace string) (*Config, error) {
 	// Load .env file if it exists
 	loadEnvFile()
 
@@ -45,13 +133,31 @@ func Load(configPath string) (*Config, error) {
 	// Set defaults
 	v.SetDefault("sync.direction", "push")
 	v.SetDefault("sync.conflict_resolution", "diff")
+	v.SetDefault("sync.orphaned_pages", "error")
+	v.SetDefault("sync.empty_pages", "create")
+	v.SetDefault("sync.delete_pages", "archive")
+	v.SetDefault("sync.flavor", "commonmark")
+	v.SetDefault("sync.link_style", "inline")
+	v.SetDefault("sync.store_notion_url", true)
+	v.SetDefault("sync.line_endings", "lf")
+	v.SetDefault("sync.max_pages", 10000)
+	v.SetDefault("sync.conflict_log", DefaultConflictLog)
 	v.SetDefault("directories.markdown_root", "./")
 	v.SetDefault("mapping.strategy", "filename")
 
 	// Performance defaults based on optimization testing
-	v.SetDefault("performance.workers", 0)              // 0 = auto-detect (30 for large workspaces)
-	v.SetDefault("performance.use_multi_client", false) // Standard client by default
-	v.SetDefault("performance.client_count", 3)         // 3 clients if multi-client is enabled
+	v.SetDefault("performance.workers", 0)               // 0 = auto-detect (30 for large workspaces)
+	v.SetDefault("performance.use_multi_client", false)  // Standard client by default
+	v.SetDefault("performance.client_count", 3)          // 3 clients if multi-client is enabled
+	v.SetDefault("performance.requests_per_second", 3)   // Global limit shared by all multi-client sub-clients
+	v.SetDefault("performance.worker_scaling", "static") // Fixed worker ladder; adaptive is opt-in
+	v.SetDefault("performance.delete_workers", notion.DefaultDeleteWorkers)
+	v.SetDefault("performance.circuit_breaker_threshold", 5) // Requests in a row failing before Notion is treated as down
+	v.SetDefault("performance.circuit_breaker_cooldown", 30*time.Second)
+
+	v.SetDefault("timeouts.sync", 5*time.Minute)
+	v.SetDefault("timeouts.page_fetch", 2*time.Minute)
+	v.SetDefault("timeouts.page_update", 2*time.Minute)
 
 	// Environment variable support
 	v.SetEnvPrefix("NOTION_MD_SYNC")
@@ -62,22 +168,13 @@ func Load(configPath string) (*Config, error) {
 	_ = v.BindEnv("notion.parent_page_id", "NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
 
 	// Config file
+	baseDir := "" // Directory a discovered config's relative paths start from
+	if configPath == "" {
+		configPath, baseDir = findConfig()
+	}
 	if configPath != "" {
 		v.SetConfigFile(configPath)
-	} else {
-		// Look for config in working directory and home directory
-		v.SetConfigName("config")
-		v.SetConfigType("yaml")
-		v.AddConfigPath(".")
-		v.AddConfigPath("./configs")
-
-		if home, err := os.UserHomeDir(); err == nil {
-			v.AddConfigPath(filepath.Join(home, ".notion-md-sync"))
-		}
-	}
-
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		if err := v.ReadInConfig(); err != nil {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
 	}
@@ -87,6 +184,14 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := config.applyWorkspace(workspace); err != nil {
+		return nil, err
+	}
+	config.File = configPath
+	if baseDir != "" {
+		config.Directories.MarkdownRoot = resolveFrom(baseDir, config.Directories.MarkdownRoot)
+	}
+
 	// Validate required fields
 	if config.Notion.Token == "" {
 		return nil, fmt.Errorf("notion.token is required")
@@ -94,10 +199,183 @@ func Load(configPath string) (*Config, error) {
 	if config.Notion.ParentPageID == "" {
 		return nil, fmt.Errorf("notion.parent_page_id is required")
 	}
+	// Store a pasted page URL, or an ID with or without dashes, as the
+	// dashed ID Notion returns, so it compares equal to page IDs
+	if id, err := util.ParseNotionID(config.Notion.ParentPageID); err == nil {
+		config.Notion.ParentPageID = id
+	} else if strings.Contains(config.Notion.ParentPageID, "/") {
+		return nil, fmt.Errorf("notion.parent_page_id: %w", err)
+	}
+	if config.Performance.UseMultiClient {
+		if config.Performance.ClientCount < 1 || config.Performance.ClientCount > notion.MaxBatchClients {
+			return nil, fmt.Errorf("performance.client_count must be between 1 and %d (got %d)",
+				notion.MaxBatchClients, config.Performance.ClientCount)
+		}
+	}
+	if err := util.ValidateConflictStrategy(config.Sync.ConflictResolution); err != nil {
+		return nil, fmt.Errorf("sync.conflict_resolution: %w", err)
+	}
+	if err := util.ValidateOrphanedPagesStrategy(config.Sync.OrphanedPages); err != nil {
+		return nil, fmt.Errorf("sync.orphaned_pages: %w", err)
+	}
+	if err := util.ValidateEmptyPagesStrategy(config.Sync.EmptyPages); err != nil {
+		return nil, fmt.Errorf("sync.empty_pages: %w", err)
+	}
+	if err := util.ValidateDeletePagesStrategy(config.Sync.DeletePages); err != nil {
+		return nil, fmt.Errorf("sync.delete_pages: %w", err)
+	}
+	if err := util.ValidateMarkdownFlavor(config.Sync.Flavor); err != nil {
+		return nil, fmt.Errorf("sync.flavor: %w", err)
+	}
+	if err := util.ValidateLinkStyle(config.Sync.LinkStyle); err != nil {
+		return nil, fmt.Errorf("sync.link_style: %w", err)
+	}
+	if err := util.ValidateLineEnding(config.Sync.LineEndings); err != nil {
+		return nil, fmt.Errorf("sync.line_endings: %w", err)
+	}
+	if config.Sync.MaxPages < 0 {
+		return nil, fmt.Errorf("sync.max_pages must not be negative (got %d)", config.Sync.MaxPages)
+	}
+	if err := validatePropertyKeys(config.Mapping.Properties); err != nil {
+		return nil, fmt.Errorf("mapping.properties: %w", err)
+	}
+	if err := util.ValidateWorkerScaling(config.Performance.WorkerScaling); err != nil {
+		return nil, fmt.Errorf("performance.worker_scaling: %w", err)
+	}
+	if config.Performance.DeleteWorkers < 1 || config.Performance.DeleteWorkers > maxDeleteWorkers {
+		return nil, fmt.Errorf("performance.delete_workers must be between 1 and %d (got %d)",
+			maxDeleteWorkers, config.Performance.DeleteWorkers)
+	}
+	if config.Performance.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("performance.requests_per_second must not be negative (got %g)",
+			config.Performance.RequestsPerSecond)
+	}
+	if config.Performance.CircuitBreakerThreshold < 0 {
+		return nil, fmt.Errorf("performance.circuit_breaker_threshold must not be negative (got %d)",
+			config.Performance.CircuitBreakerThreshold)
+	}
+	for name, timeout := range map[string]time.Duration{
+		"performance.circuit_breaker_cooldown": config.Performance.CircuitBreakerCooldown,
+		"timeouts.sync":                        config.Timeouts.Sync,
+		"timeouts.page_fetch":                  config.Timeouts.PageFetch,
+		"timeouts.page_update":                 config.Timeouts.PageUpdate,
+	} {
+		if timeout < 0 {
+			return nil, fmt.Errorf("%s must not be negative (got %s)", name, timeout)
+		}
+	}
 
 	return &config, nil
 }
 
+// validatePropertyKeys checks every mapped property has a key of its own, so
+// a push can tell which property a frontmatter key sets
+func validatePropertyKeys(keys map[string]string) error {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]string, len(keys))
+	for _, name := range names {
+		key := keys[name]
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("property %q has an empty key", name)
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("properties %q and %q both map to %q", other, name, key)
+		}
+		seen[key] = name
+	}
+	return nil
+}
+
+// findConfig looks for a config file in the working directory and each of
+// its parents, like git looks for a repository, then in ~/.notion-md-sync.
+// It returns the file and, for one found above the home directory fallback,
+// the directory it was found in.
+func findConfig() (path, baseDir string) {
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			for _, name := range ConfigFileNames {
+				candidate := filepath.Join(dir, name)
+				if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+					return candidate, dir
+				}
+			}
+
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break // reached root
+			}
+			dir = parent
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		candidate := filepath.Join(home, ".notion-md-sync", "config.yaml")
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, ""
+		}
+	}
+	return "", ""
+}
+
+// resolveFrom makes a path relative to baseDir relative to the working
+// directory instead. Absolute paths are returned unchanged.
+func resolveFrom(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filepath.Join(baseDir, path)
+	}
+	rel, err := filepath.Rel(cwd, baseDir)
+	if err != nil {
+		return filepath.Join(baseDir, path)
+	}
+	if rel == "." {
+		// Found in the working directory, so nothing changes
+		return path
+	}
+	return filepath.Join(rel, path)
+}
+
+// applyWorkspace overlays the selected profile onto the top-level settings
+func (c *Config) applyWorkspace(name string) error {
+	if name == "" {
+		name = os.Getenv(ProfileEnvVar)
+	}
+	if name == "" {
+		name = DefaultWorkspace
+	}
+	// Viper lowercases map keys, so profile names match case-insensitively
+	name = strings.ToLower(name)
+	c.Workspace = name
+
+	profile, ok := c.Workspaces[name]
+	if !ok {
+		// Configs without a workspaces section keep working as the default profile
+		if name == DefaultWorkspace {
+			return nil
+		}
+		return fmt.Errorf("unknown workspace %q", name)
+	}
+
+	if profile.Token != "" {
+		c.Notion.Token = profile.Token
+	}
+	if profile.ParentPageID != "" {
+		c.Notion.ParentPageID = profile.ParentPageID
+	}
+	if profile.MarkdownRoot != "" {
+		c.Directories.MarkdownRoot = profile.MarkdownRoot
+	}
+	return nil
+}
+
 // loadEnvFile loads .env file from current directory or parent directories
 func loadEnvFile() {
 	// Try to load .env from current directory first
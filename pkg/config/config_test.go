@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoad(t *testing.T) {
@@ -186,6 +187,307 @@ notion:
 	}
 }
 
+func TestLoadPerformanceValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		performance string
+		wantErr     bool
+	}{
+		{
+			name:        "multi-client with valid client count",
+			performance: "use_multi_client: true\n  client_count: 5",
+			wantErr:     false,
+		},
+		{
+			name:        "multi-client with zero clients",
+			performance: "use_multi_client: true\n  client_count: 0",
+			wantErr:     true,
+		},
+		{
+			name:        "multi-client with too many clients",
+			performance: "use_multi_client: true\n  client_count: 11",
+			wantErr:     true,
+		},
+		{
+			name:        "client count ignored in single-client mode",
+			performance: "use_multi_client: false\n  client_count: 50",
+			wantErr:     false,
+		},
+		{
+			name:        "negative requests per second",
+			performance: "requests_per_second: -1",
+			wantErr:     true,
+		},
+		{
+			name:        "circuit breaker disabled",
+			performance: "circuit_breaker_threshold: 0",
+			wantErr:     false,
+		},
+		{
+			name:        "negative circuit breaker threshold",
+			performance: "circuit_breaker_threshold: -1",
+			wantErr:     true,
+		},
+		{
+			name:        "negative circuit breaker cooldown",
+			performance: "circuit_breaker_cooldown: -1s",
+			wantErr:     true,
+		},
+		{
+			name:        "adaptive worker scaling",
+			performance: "worker_scaling: adaptive",
+			wantErr:     false,
+		},
+		{
+			name:        "invalid worker scaling",
+			performance: "worker_scaling: turbo",
+			wantErr:     true,
+		},
+		{
+			name:        "serial block deletes",
+			performance: "delete_workers: 1",
+			wantErr:     false,
+		},
+		{
+			name:        "zero delete workers",
+			performance: "delete_workers: 0",
+			wantErr:     true,
+		},
+		{
+			name:        "too many delete workers",
+			performance: "delete_workers: 11",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			configPath := filepath.Join(tempDir, "test_config.yaml")
+
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+performance:
+  ` + tt.performance + "\n"
+
+			err := os.WriteFile(configPath, []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			_, err = Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConflictResolutionValidation(t *testing.T) {
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{strategy: "local", wantErr: false},
+		{strategy: "remote", wantErr: false},
+		{strategy: "manual", wantErr: false},
+		{strategy: "theirs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "test_config.yaml")
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+sync:
+  conflict_resolution: ` + tt.strategy + "\n"
+
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			_, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadOrphanedPagesValidation(t *testing.T) {
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{strategy: "error", wantErr: false},
+		{strategy: "recreate", wantErr: false},
+		{strategy: "delete", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "test_config.yaml")
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+sync:
+  orphaned_pages: ` + tt.strategy + "\n"
+
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			_, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEmptyPagesValidation(t *testing.T) {
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{strategy: "create", wantErr: false},
+		{strategy: "skip", wantErr: false},
+		{strategy: "placeholder", wantErr: false},
+		{strategy: "ignore", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "test_config.yaml")
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+sync:
+  empty_pages: ` + tt.strategy + "\n"
+
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			_, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPropertyKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		want       map[string]string
+		wantErr    bool
+	}{
+		{
+			name:       "mapped keys",
+			properties: "\"Due Date\": due_date\n    \"Status 🚦\": status",
+			want:       map[string]string{"due date": "due_date", "status 🚦": "status"},
+		},
+		{
+			name:       "empty key",
+			properties: "\"Due Date\": \"\"",
+			wantErr:    true,
+		},
+		{
+			name:       "two properties with one key",
+			properties: "Due: due\n    \"Due Date\": due",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			configPath := filepath.Join(tempDir, "test_config.yaml")
+
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+mapping:
+  properties:
+    ` + tt.properties + "\n"
+
+			err := os.WriteFile(configPath, []byte(content), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			cfg, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// Viper lowercases map keys
+			if len(cfg.Mapping.Properties) != len(tt.want) {
+				t.Fatalf("Expected properties %v, got %v", tt.want, cfg.Mapping.Properties)
+			}
+			for name, key := range tt.want {
+				if cfg.Mapping.Properties[name] != key {
+					t.Errorf("Expected %q to map to %q, got %q", name, key, cfg.Mapping.Properties[name])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeouts  string
+		wantFetch time.Duration
+		wantErr   bool
+	}{
+		{name: "defaults", timeouts: "", wantFetch: 2 * time.Minute},
+		{name: "duration string", timeouts: "timeouts:\n  page_fetch: 90s\n", wantFetch: 90 * time.Second},
+		{name: "zero disables", timeouts: "timeouts:\n  page_fetch: 0s\n", wantFetch: 0},
+		{name: "negative", timeouts: "timeouts:\n  page_fetch: -1s\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "test_config.yaml")
+			content := `
+notion:
+  token: "valid_token"
+  parent_page_id: "valid_page_id"
+` + tt.timeouts
+
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create test config file: %v", err)
+			}
+
+			cfg, err := Load(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Timeouts.PageFetch != tt.wantFetch {
+				t.Errorf("Timeouts.PageFetch = %s, want %s", cfg.Timeouts.PageFetch, tt.wantFetch)
+			}
+			if cfg.Timeouts.Sync != 5*time.Minute {
+				t.Errorf("Timeouts.Sync = %s, want the 5m default", cfg.Timeouts.Sync)
+			}
+		})
+	}
+}
+
 func TestConfigDefaults(t *testing.T) {
 	// Create a minimal config file
 	tempDir := t.TempDir()
@@ -227,4 +529,251 @@ func TestConfigDefaults(t *testing.T) {
 	if cfg.Mapping.Strategy != "filename" {
 		t.Errorf("Expected default strategy 'filename', got '%s'", cfg.Mapping.Strategy)
 	}
+
+	if !cfg.Sync.StoreNotionURL {
+		t.Error("Expected store_notion_url to default to true")
+	}
+
+	if cfg.Performance.RequestsPerSecond != 3 {
+		t.Errorf("Expected default requests_per_second 3, got %g", cfg.Performance.RequestsPerSecond)
+	}
+
+	if cfg.Performance.DeleteWorkers != 4 {
+		t.Errorf("Expected default delete_workers 4, got %d", cfg.Performance.DeleteWorkers)
+	}
+
+	if cfg.Performance.CircuitBreakerThreshold != 5 || cfg.Performance.CircuitBreakerCooldown != 30*time.Second {
+		t.Errorf("Expected default circuit breaker 5 failures, 30s cooldown, got %d, %s",
+			cfg.Performance.CircuitBreakerThreshold, cfg.Performance.CircuitBreakerCooldown)
+	}
+}
+
+func TestLoadWorkspaceProfiles(t *testing.T) {
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_TOKEN")
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
+	t.Setenv(ProfileEnvVar, "")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+notion:
+  token: "shared_token"
+  parent_page_id: "top_level_page"
+
+directories:
+  markdown_root: "./docs"
+
+workspaces:
+  default:
+    parent_page_id: "personal_page"
+    markdown_root: "./personal"
+  work:
+    token: "work_token"
+    parent_page_id: "work_page"
+    markdown_root: "./work"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		workspace  string
+		env        string
+		wantToken  string
+		wantPage   string
+		wantRoot   string
+		wantSelect string
+	}{
+		{"default profile", "", "", "shared_token", "personal_page", "./personal", "default"},
+		{"flag selects profile", "work", "", "work_token", "work_page", "./work", "work"},
+		{"env selects profile", "", "work", "work_token", "work_page", "./work", "work"},
+		{"flag beats env", "default", "work", "shared_token", "personal_page", "./personal", "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ProfileEnvVar, tt.env)
+
+			cfg, err := LoadWorkspace(configPath, tt.workspace)
+			if err != nil {
+				t.Fatalf("LoadWorkspace() error = %v", err)
+			}
+			if cfg.Notion.Token != tt.wantToken {
+				t.Errorf("Expected token %q, got %q", tt.wantToken, cfg.Notion.Token)
+			}
+			if cfg.Notion.ParentPageID != tt.wantPage {
+				t.Errorf("Expected parent_page_id %q, got %q", tt.wantPage, cfg.Notion.ParentPageID)
+			}
+			if cfg.Directories.MarkdownRoot != tt.wantRoot {
+				t.Errorf("Expected markdown_root %q, got %q", tt.wantRoot, cfg.Directories.MarkdownRoot)
+			}
+			if cfg.Workspace != tt.wantSelect {
+				t.Errorf("Expected workspace %q, got %q", tt.wantSelect, cfg.Workspace)
+			}
+		})
+	}
+
+	if _, err := LoadWorkspace(configPath, "missing"); err == nil {
+		t.Error("Expected an error for an unknown workspace")
+	}
+}
+
+func TestLoadWithoutWorkspaces(t *testing.T) {
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_TOKEN")
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
+	t.Setenv(ProfileEnvVar, "")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+notion:
+  token: "test_token"
+  parent_page_id: "test_page_id"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Workspace != DefaultWorkspace {
+		t.Errorf("Expected workspace %q, got %q", DefaultWorkspace, cfg.Workspace)
+	}
+	if cfg.Notion.ParentPageID != "test_page_id" {
+		t.Errorf("Expected parent_page_id 'test_page_id', got '%s'", cfg.Notion.ParentPageID)
+	}
+
+	if _, err := LoadWorkspace(configPath, "work"); err == nil {
+		t.Error("Expected an error for a workspace missing from the config")
+	}
+}
+
+func TestLoadParentPageURL(t *testing.T) {
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_TOKEN")
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
+	t.Setenv(ProfileEnvVar, "")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+notion:
+  token: "test_token"
+  parent_page_id: "https://www.notion.so/My-Docs-abcdef1234567890abcdef1234567890"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := "abcdef12-3456-7890-abcd-ef1234567890"; cfg.Notion.ParentPageID != want {
+		t.Errorf("Expected parent_page_id %q, got %q", want, cfg.Notion.ParentPageID)
+	}
+
+	// A bare ID without dashes is stored in the same form
+	configContent = `
+notion:
+  token: "test_token"
+  parent_page_id: "ABCDEF1234567890ABCDEF1234567890"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	cfg, err = Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := "abcdef12-3456-7890-abcd-ef1234567890"; cfg.Notion.ParentPageID != want {
+		t.Errorf("Expected parent_page_id %q, got %q", want, cfg.Notion.ParentPageID)
+	}
+
+	configContent = `
+notion:
+  token: "test_token"
+  parent_page_id: "https://www.notion.so/My-Docs"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Error("Expected an error for a URL without a page ID")
+	}
+}
+
+func TestLoadDiscoversConfigInParentDirectory(t *testing.T) {
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_TOKEN")
+	_ = os.Unsetenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID")
+	t.Setenv(ProfileEnvVar, "")
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	configContent := `
+notion:
+  token: "test_token"
+  parent_page_id: "test_page_id"
+directories:
+  markdown_root: "./docs"
+`
+	configPath := filepath.Join(root, ".notion-md-sync.yaml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	nested := filepath.Join(root, "docs", "guides", "setup")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, nested)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.File != configPath {
+		t.Errorf("Expected config from %s, got %q", configPath, cfg.File)
+	}
+	// The markdown root is relative to the config, not the working directory
+	if got := filepath.Join(nested, cfg.Directories.MarkdownRoot); got != filepath.Join(root, "docs") {
+		t.Errorf("Expected markdown_root to resolve to %s, got %s (%s)", filepath.Join(root, "docs"), got, cfg.Directories.MarkdownRoot)
+	}
+
+	// A config in the working directory is used as is
+	chdir(t, root)
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Directories.MarkdownRoot != "./docs" {
+		t.Errorf("Expected markdown_root './docs', got '%s'", cfg.Directories.MarkdownRoot)
+	}
+
+	// config.yaml wins over .notion-md-sync.yaml in the same directory
+	preferred := filepath.Join(root, "config.yaml")
+	if err := os.WriteFile(preferred, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, nested)
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.File != preferred {
+		t.Errorf("Expected config from %s, got %q", preferred, cfg.File)
+	}
+}
+
+// chdir changes the working directory for the rest of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(previous) })
 }
@@ -0,0 +1,200 @@
+// Package manifest stores which markdown file each synced Notion page lives
+// in. Manifests are versioned JSON, optionally gzipped, and are always
+// replaced atomically so a crash mid-write can't lose the mapping.
+//
+// The sync engine doesn't use it yet: it finds each page's file through the
+// notion_id in the file's frontmatter, which moves with the file and needs
+// no separate state. A manifest is for state that can't live in the files,
+// such as pages whose files were deleted.
+package manifest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Version is the manifest format written by Save. Older versions are
+// migrated when loaded.
+const Version = 1
+
+// Manifest maps Notion page IDs to the files they sync with
+type Manifest struct {
+	Version int              `json:"version"`
+	Pages   map[string]Entry `json:"pages"` // Keyed by Notion page ID
+}
+
+// Entry is a single synced page
+type Entry struct {
+	Path        string    `json:"path"` // Relative to the markdown root
+	ContentHash string    `json:"content_hash,omitempty"`
+	LastSynced  time.Time `json:"last_synced"`
+}
+
+// New returns an empty manifest
+func New() *Manifest {
+	return &Manifest{
+		Version: Version,
+		Pages:   make(map[string]Entry),
+	}
+}
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// migrations[v] upgrades a manifest from version v to v+1
+var migrations = []func(data []byte) ([]byte, error){
+	migrateV0,
+}
+
+// rename is os.Rename; tests replace it to simulate a crash before the new
+// manifest is moved into place
+var rename = os.Rename
+
+// Load reads the manifest at path, migrating older versions. A missing file
+// yields an empty manifest. Gzipped manifests are detected automatically.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		if data, err = gunzip(data); err != nil {
+			return nil, fmt.Errorf("failed to decompress manifest: %w", err)
+		}
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if header.Version < 0 {
+		return nil, fmt.Errorf("manifest %s has invalid version %d", path, header.Version)
+	}
+	if header.Version > Version {
+		return nil, fmt.Errorf("manifest %s has version %d, newer than the supported version %d", path, header.Version, Version)
+	}
+
+	for v := header.Version; v < Version; v++ {
+		if data, err = migrations[v](data); err != nil {
+			return nil, fmt.Errorf("failed to migrate manifest %s from version %d: %w", path, v, err)
+		}
+	}
+
+	m := New()
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Pages == nil {
+		m.Pages = make(map[string]Entry)
+	}
+	m.Version = Version
+	return m, nil
+}
+
+// Save writes m to path, gzipped when path ends in .gz. The manifest is
+// written to a temporary file in the same directory and renamed into place,
+// so readers see either the old manifest or the new one, never a partial file.
+func Save(path string, m *Manifest) error {
+	m.Version = Version
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if strings.HasSuffix(path, ".gz") {
+		if data, err = gzipBytes(data); err != nil {
+			return fmt.Errorf("failed to compress manifest: %w", err)
+		}
+	}
+
+	return writeAtomic(path, data)
+}
+
+// writeAtomic replaces path with data via a temporary file and a rename
+func writeAtomic(path string, data []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary manifest: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	// Flush to disk before the rename, or a crash could leave an empty file
+	// in place of the old manifest
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace manifest: %w", err)
+	}
+	return nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+	return io.ReadAll(zr)
+}
+
+// migrateV0 upgrades the unversioned layout, which mapped page IDs straight
+// to file paths, to entries that can carry sync state
+func migrateV0(data []byte) ([]byte, error) {
+	var old struct {
+		Pages map[string]string `json:"pages"`
+	}
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]Entry, len(old.Pages))
+	for pageID, path := range old.Pages {
+		pages[pageID] = Entry{Path: path}
+	}
+	return json.Marshal(Manifest{Version: 1, Pages: pages})
+}
@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	synced := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, name := range []string{"manifest.json", "manifest.json.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			m := New()
+			m.Pages["page-1"] = Entry{Path: "docs/a.md", ContentHash: "abc", LastSynced: synced}
+			if err := Save(path, m); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read manifest: %v", err)
+			}
+			if gzipped := bytes.HasPrefix(data, gzipMagic); gzipped != strings.HasSuffix(name, ".gz") {
+				t.Errorf("gzipped = %v for %s", gzipped, name)
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			entry := loaded.Pages["page-1"]
+			if entry.Path != "docs/a.md" || entry.ContentHash != "abc" || !entry.LastSynced.Equal(synced) {
+				t.Errorf("loaded entry = %+v", entry)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	m, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Version != Version || len(m.Pages) != 0 {
+		t.Errorf("expected an empty manifest, got %+v", m)
+	}
+}
+
+func TestSaveFailureKeepsOldManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "manifest.json")
+
+	old := New()
+	old.Pages["page-1"] = Entry{Path: "old.md"}
+	if err := Save(path, old); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	before, _ := os.ReadFile(path)
+
+	// Crash between writing the new manifest and moving it into place
+	rename = func(oldpath, newpath string) error { return errors.New("simulated crash") }
+	defer func() { rename = os.Rename }()
+
+	updated := New()
+	updated.Pages["page-1"] = Entry{Path: "new.md"}
+	if err := Save(path, updated); err == nil {
+		t.Fatal("Save() succeeded, want error")
+	}
+
+	after, _ := os.ReadFile(path)
+	if !bytes.Equal(before, after) {
+		t.Errorf("manifest changed after a failed save:\n%s", after)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := m.Pages["page-1"].Path; got != "old.md" {
+		t.Errorf("path = %q, want old.md", got)
+	}
+
+	// The temporary file is cleaned up
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the manifest in %s, found %d entries", dir, len(entries))
+	}
+}
+
+func TestLoadMigratesUnversionedManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	legacy := `{"pages": {"page-1": "docs/a.md", "page-2": "b.md"}}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Version != Version {
+		t.Errorf("Version = %d, want %d", m.Version, Version)
+	}
+	if m.Pages["page-1"].Path != "docs/a.md" || m.Pages["page-2"].Path != "b.md" {
+		t.Errorf("migrated pages = %+v", m.Pages)
+	}
+
+	// Saving writes the current version
+	if err := Save(path, m); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"version": 1`) {
+		t.Errorf("saved manifest isn't version 1:\n%s", data)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "pages": {}}`), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Load() error = %v, want a version error", err)
+	}
+}
+
+func TestLoadRejectsNegativeVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(`{"version": -1, "pages": {}}`), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "invalid version") {
+		t.Errorf("Load() error = %v, want a version error", err)
+	}
+}
@@ -2,19 +2,81 @@ package markdown
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // FrontmatterFields defines common frontmatter fields for Notion integration
 type FrontmatterFields struct {
-	Title       string                 `yaml:"title,omitempty"`
-	NotionID    string                 `yaml:"notion_id,omitempty"`
-	CreatedAt   *time.Time             `yaml:"created_at,omitempty"`
-	UpdatedAt   *time.Time             `yaml:"updated_at,omitempty"`
-	Tags        []string               `yaml:"tags,omitempty"`
-	Status      string                 `yaml:"status,omitempty"`
-	Properties  map[string]interface{} `yaml:"properties,omitempty"`
-	SyncEnabled bool                   `yaml:"sync_enabled,omitempty"`
+	Title        string                 `yaml:"title,omitempty"`
+	NotionID     string                 `yaml:"notion_id,omitempty"`
+	NotionURL    string                 `yaml:"notion_url,omitempty"`    // Link to the page in Notion; written by pulls, never pushed
+	NotionParent string                 `yaml:"notion_parent,omitempty"` // Page ID or markdown file to create new pages under
+	CreatedAt    *time.Time             `yaml:"created_at,omitempty"`
+	UpdatedAt    *time.Time             `yaml:"updated_at,omitempty"`
+	Tags         []string               `yaml:"tags,omitempty"`
+	Status       string                 `yaml:"status,omitempty"`
+	Properties   map[string]interface{} `yaml:"properties,omitempty"`
+	SyncEnabled  bool                   `yaml:"sync_enabled,omitempty"`
+	SyncMode     string                 `yaml:"sync_mode,omitempty"`
+	SyncAction   string                 `yaml:"sync_action,omitempty"`  // One-off action for the next push, e.g. archive
+	ContentHash  string                 `yaml:"content_hash,omitempty"` // Hash of what was last synced, to skip unchanged pushes
+	BodyHash     string                 `yaml:"body_hash,omitempty"`    // Hash of the body alone as last synced, to tell body edits from frontmatter edits
+	Order        *float64               `yaml:"order,omitempty"`        // Position among sibling pages when they're created
+	Archived     bool                   `yaml:"archived,omitempty"`     // The page is archived or in the trash; pushing true archives it
+}
+
+// Sync modes controlling how a push updates an existing page
+const (
+	SyncModeReplace = "replace" // Clear existing blocks and rewrite (default)
+	SyncModeAppend  = "append"  // Append new blocks after existing content
+)
+
+// SyncActionArchive archives the file's page on the next push. Unlike
+// sync_enabled: false, which leaves the page as it is, the page is removed
+// from the workspace.
+const SyncActionArchive = "archive"
+
+// Timestamp returns t as stored in frontmatter: UTC, whole seconds, so it
+// survives an RFC3339 round trip unchanged. The zero time yields nil.
+func Timestamp(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	ts := t.UTC().Truncate(time.Second)
+	return &ts
+}
+
+// PulledFields are the frontmatter keys a pull writes from Notion. Other keys
+// already in the file, such as tags or aliases for a static site generator,
+// are left as they are
+var PulledFields = []string{"title", "notion_id", "notion_url", "created_at", "updated_at", "properties", "sync_enabled", "content_hash", "body_hash", "archived"}
+
+// SyncFields are the frontmatter keys the sync itself reads or writes. Other
+// top-level keys set the page property of the same name, if there is one
+var SyncFields = []string{"title", "notion_id", "notion_url", "notion_parent", "created_at", "updated_at", "properties",
+	"sync_enabled", "sync_mode", "sync_action", "content_hash", "body_hash", "order", "archived"}
+
+// FieldOrder is the order frontmatter keys are written in, following
+// FrontmatterFields. Other keys come after them in alphabetical order
+var FieldOrder = []string{"title", "notion_id", "notion_url", "notion_parent", "created_at", "updated_at", "tags", "status",
+	"properties", "sync_enabled", "sync_mode", "sync_action", "content_hash", "body_hash", "order", "archived"}
+
+// MergePulledMetadata returns existing with its PulledFields replaced by
+// those in pulled. A pulled field that is absent removes the existing one.
+func MergePulledMetadata(existing, pulled map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(existing)+len(pulled))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for _, key := range PulledFields {
+		delete(merged, key)
+	}
+	for key, value := range pulled {
+		merged[key] = value
+	}
+	return merged
 }
 
 // ExtractFrontmatter extracts and validates frontmatter from metadata
@@ -31,6 +93,14 @@ func ExtractFrontmatter(metadata map[string]interface{}) (*FrontmatterFields, er
 		fm.NotionID = notionID
 	}
 
+	if notionURL, ok := metadata["notion_url"].(string); ok {
+		fm.NotionURL = notionURL
+	}
+
+	if notionParent, ok := metadata["notion_parent"].(string); ok {
+		fm.NotionParent = notionParent
+	}
+
 	if createdAt, ok := metadata["created_at"]; ok {
 		if t, err := parseTime(createdAt); err == nil {
 			fm.CreatedAt = &t
@@ -61,8 +131,39 @@ func ExtractFrontmatter(metadata map[string]interface{}) (*FrontmatterFields, er
 		fm.SyncEnabled = syncEnabled
 	}
 
-	if properties, ok := metadata["properties"].(map[string]interface{}); ok {
+	switch properties := metadata["properties"].(type) {
+	case map[string]interface{}:
 		fm.Properties = properties
+	case map[interface{}]interface{}:
+		// goldmark-meta decodes nested YAML maps with interface{} keys
+		fm.Properties = make(map[string]interface{}, len(properties))
+		for key, value := range properties {
+			fm.Properties[fmt.Sprint(key)] = value
+		}
+	}
+
+	if syncMode, ok := metadata["sync_mode"].(string); ok {
+		fm.SyncMode = syncMode
+	}
+
+	if syncAction, ok := metadata["sync_action"].(string); ok {
+		fm.SyncAction = syncAction
+	}
+
+	if contentHash, ok := metadata["content_hash"].(string); ok {
+		fm.ContentHash = contentHash
+	}
+
+	if bodyHash, ok := metadata["body_hash"].(string); ok {
+		fm.BodyHash = bodyHash
+	}
+
+	if order, ok := parseOrder(metadata["order"]); ok {
+		fm.Order = &order
+	}
+
+	if archived, ok := metadata["archived"].(bool); ok {
+		fm.Archived = archived
 	}
 
 	return fm, nil
@@ -80,12 +181,20 @@ func (fm *FrontmatterFields) ToMetadata() map[string]interface{} {
 		metadata["notion_id"] = fm.NotionID
 	}
 
+	if fm.NotionURL != "" {
+		metadata["notion_url"] = fm.NotionURL
+	}
+
+	if fm.NotionParent != "" {
+		metadata["notion_parent"] = fm.NotionParent
+	}
+
 	if fm.CreatedAt != nil {
-		metadata["created_at"] = fm.CreatedAt.Format(time.RFC3339)
+		metadata["created_at"] = fm.CreatedAt.UTC().Format(time.RFC3339)
 	}
 
 	if fm.UpdatedAt != nil {
-		metadata["updated_at"] = fm.UpdatedAt.Format(time.RFC3339)
+		metadata["updated_at"] = fm.UpdatedAt.UTC().Format(time.RFC3339)
 	}
 
 	if len(fm.Tags) > 0 {
@@ -102,9 +211,52 @@ func (fm *FrontmatterFields) ToMetadata() map[string]interface{} {
 		metadata["properties"] = fm.Properties
 	}
 
+	if fm.SyncMode != "" {
+		metadata["sync_mode"] = fm.SyncMode
+	}
+
+	if fm.SyncAction != "" {
+		metadata["sync_action"] = fm.SyncAction
+	}
+
+	if fm.ContentHash != "" {
+		metadata["content_hash"] = fm.ContentHash
+	}
+
+	if fm.BodyHash != "" {
+		metadata["body_hash"] = fm.BodyHash
+	}
+
+	if fm.Order != nil {
+		metadata["order"] = *fm.Order
+	}
+
+	if fm.Archived {
+		metadata["archived"] = true
+	}
+
 	return metadata
 }
 
+// parseOrder reads the order field, which YAML may decode as an integer,
+// a float or a quoted number
+func parseOrder(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		order, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return order, err == nil
+	}
+	return 0, false
+}
+
 // parseTime attempts to parse various time formats
 func parseTime(timeVal interface{}) (time.Time, error) {
 	switch v := timeVal.(type) {
@@ -43,6 +43,34 @@ func TestExtractFrontmatter(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "notion parent",
+			metadata: map[string]interface{}{
+				"notion_parent": "projects/index.md",
+			},
+			want: &FrontmatterFields{
+				NotionParent: "projects/index.md",
+				SyncEnabled:  true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "properties decoded with interface keys",
+			metadata: map[string]interface{}{
+				"properties": map[interface{}]interface{}{
+					"Status": "In progress",
+					"Done":   false,
+				},
+			},
+			want: &FrontmatterFields{
+				SyncEnabled: true,
+				Properties: map[string]interface{}{
+					"Status": "In progress",
+					"Done":   false,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "minimal frontmatter",
 			metadata: map[string]interface{}{
@@ -156,8 +184,8 @@ func TestFrontmatterFields_ToMetadata(t *testing.T) {
 			want: map[string]interface{}{
 				"title":        "Test Title",
 				"notion_id":    "abc123",
-				"created_at":   now.Format(time.RFC3339),
-				"updated_at":   now.Format(time.RFC3339),
+				"created_at":   now.UTC().Format(time.RFC3339),
+				"updated_at":   now.UTC().Format(time.RFC3339),
 				"tags":         []string{"tag1", "tag2"},
 				"status":       "published",
 				"sync_enabled": true,
@@ -281,8 +309,8 @@ func TestFrontmatterRoundTrip(t *testing.T) {
 	original := map[string]interface{}{
 		"title":        "Round Trip Test",
 		"notion_id":    "test123",
-		"created_at":   now.Format(time.RFC3339),
-		"updated_at":   now.Format(time.RFC3339),
+		"created_at":   now.UTC().Format(time.RFC3339),
+		"updated_at":   now.UTC().Format(time.RFC3339),
 		"tags":         []interface{}{"go", "testing"},
 		"status":       "draft",
 		"sync_enabled": true,
@@ -308,13 +336,29 @@ func TestFrontmatterRoundTrip(t *testing.T) {
 	assert.Equal(t, []string{"go", "testing"}, result["tags"])
 
 	// Times should be in RFC3339 format
-	assert.Equal(t, now.Format(time.RFC3339), result["created_at"])
-	assert.Equal(t, now.Format(time.RFC3339), result["updated_at"])
+	assert.Equal(t, now.UTC().Format(time.RFC3339), result["created_at"])
+	assert.Equal(t, now.UTC().Format(time.RFC3339), result["updated_at"])
 
 	// Properties should be preserved
 	assert.Equal(t, original["properties"], result["properties"])
 }
 
+func TestTimestamp(t *testing.T) {
+	assert.Nil(t, Timestamp(time.Time{}))
+
+	local := time.FixedZone("UTC+2", 2*60*60)
+	ts := Timestamp(time.Date(2024, 6, 15, 19, 45, 12, 500000000, local))
+	require.NotNil(t, ts)
+	assert.Equal(t, time.Date(2024, 6, 15, 17, 45, 12, 0, time.UTC), *ts)
+
+	// Stored timestamps survive formatting and parsing unchanged
+	metadata := (&FrontmatterFields{UpdatedAt: ts}).ToMetadata()
+	assert.Equal(t, "2024-06-15T17:45:12Z", metadata["updated_at"])
+	fm, err := ExtractFrontmatter(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, *ts, *fm.UpdatedAt)
+}
+
 // Helper function for tests
 func mustParseTime(timeStr string) *time.Time {
 	t, err := time.Parse(time.RFC3339, timeStr)
@@ -323,3 +367,30 @@ func mustParseTime(timeStr string) *time.Time {
 	}
 	return &t
 }
+
+func TestMergePulledMetadata(t *testing.T) {
+	existing := map[string]interface{}{
+		"title":      "Old",
+		"notion_id":  "page-id",
+		"tags":       []interface{}{"go"},
+		"draft":      true,
+		"properties": map[string]interface{}{"Status": "Done"},
+	}
+	pulled := map[string]interface{}{
+		"title":        "New",
+		"notion_id":    "page-id",
+		"sync_enabled": true,
+	}
+
+	merged := MergePulledMetadata(existing, pulled)
+
+	want := map[string]interface{}{
+		"title":        "New",
+		"notion_id":    "page-id",
+		"sync_enabled": true,
+		"tags":         []interface{}{"go"},
+		"draft":        true,
+	}
+	assert.Equal(t, want, merged)
+	assert.Equal(t, "Old", existing["title"], "existing metadata must not be modified")
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark-meta"
@@ -27,19 +31,54 @@ type Document struct {
 }
 
 type markdownParser struct {
-	md goldmark.Markdown
+	md      goldmark.Markdown
+	newline string // Line ending files are written with
 }
 
-func NewParser() Parser {
+// Line ending styles files may be written with
+const (
+	LineEndingLF   = "lf"   // \n, the default
+	LineEndingCRLF = "crlf" // \r\n, as Windows editors expect
+	LineEndingAuto = "auto" // crlf on Windows, lf elsewhere
+)
+
+// ParserOption customizes a parser created by NewParser
+type ParserOption func(*markdownParser)
+
+// WithLineEnding writes files with the given line ending style. Files are
+// read the same whatever their line endings.
+func WithLineEnding(style string) ParserOption {
+	return func(p *markdownParser) {
+		switch strings.ToLower(strings.TrimSpace(style)) {
+		case LineEndingCRLF:
+			p.newline = "\r\n"
+		case LineEndingAuto:
+			if runtime.GOOS == "windows" {
+				p.newline = "\r\n"
+			} else {
+				p.newline = "\n"
+			}
+		default:
+			p.newline = "\n"
+		}
+	}
+}
+
+func NewParser(opts ...ParserOption) Parser {
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			meta.Meta,
 		),
 	)
 
-	return &markdownParser{
-		md: md,
+	p := &markdownParser{
+		md:      md,
+		newline: "\n",
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 func (p *markdownParser) ParseFile(filePath string) (*Document, error) {
@@ -48,6 +87,10 @@ func (p *markdownParser) ParseFile(filePath string) (*Document, error) {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
+	// Files written with CRLF line endings read the same as with LF, so
+	// content compares equal whichever a file was saved with
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
 	// Parse the markdown with frontmatter
 	ctx := parser.NewContext()
 	doc := p.md.Parser().Parse(text.NewReader(content), parser.WithContext(ctx))
@@ -105,7 +148,7 @@ func (p *markdownParser) CreateMarkdownWithFrontmatter(filePath string, metadata
 	if len(metadata) > 0 {
 		buf.WriteString("---\n")
 
-		yamlData, err := yaml.Marshal(metadata)
+		yamlData, err := marshalFrontmatter(metadata)
 		if err != nil {
 			return fmt.Errorf("failed to marshal metadata to YAML: %w", err)
 		}
@@ -115,16 +158,95 @@ func (p *markdownParser) CreateMarkdownWithFrontmatter(filePath string, metadata
 	}
 
 	// Write content
-	buf.WriteString(content)
+	buf.WriteString(strings.ReplaceAll(content, "\r\n", "\n"))
+
+	data := buf.Bytes()
+	if p.newline != "\n" {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte(p.newline))
+	}
 
 	// Write file
-	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", filePath, err)
 	}
 
 	return nil
 }
 
+// marshalFrontmatter writes metadata as YAML that is the same byte for byte
+// whenever the metadata is: keys follow FieldOrder, and values are
+// normalized first, so a timestamp read back from a file as a time.Time is
+// written just like the RFC3339 string it was pulled as, and nested maps
+// decoded with interface{} keys like those with string keys
+func marshalFrontmatter(metadata map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	rank := make(map[string]int, len(FieldOrder))
+	for i, key := range FieldOrder {
+		rank[key] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iKnown := rank[keys[i]]
+		rj, jKnown := rank[keys[j]]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("frontmatter key can't be empty")
+		}
+		var value yaml.Node
+		if err := value.Encode(normalizeFrontmatterValue(metadata[key])); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &value)
+	}
+	return yaml.Marshal(doc)
+}
+
+// normalizeFrontmatterValue converts a value to the types frontmatter is
+// pulled as, whatever it was decoded or built as
+func normalizeFrontmatterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339)
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = normalizeFrontmatterValue(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeFrontmatterValue(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeFrontmatterValue(item)
+		}
+		return normalized
+	}
+	return value
+}
+
 // Helper functions for AST traversal
 
 func ExtractTextFromAST(node ast.Node, source []byte) string {
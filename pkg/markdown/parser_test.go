@@ -3,6 +3,7 @@ package markdown
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -142,6 +143,152 @@ func TestParser_CreateMarkdownWithFrontmatter(t *testing.T) {
 	}
 }
 
+func TestParser_CreateMarkdownWithFrontmatter_Stable(t *testing.T) {
+	parser := NewParser()
+	dir := t.TempDir()
+
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	metadata := map[string]interface{}{
+		"aliases":      []interface{}{"intro"},
+		"sync_enabled": true,
+		"properties":   map[string]interface{}{"status": "Done", "priority": 2},
+		"created_at":   created.Format(time.RFC3339),
+		"notion_id":    "12345",
+		"title":        "2024: a review",
+		"layout":       "post",
+	}
+	write := func(name string, metadata map[string]interface{}) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := parser.CreateMarkdownWithFrontmatter(path, metadata, "Body"); err != nil {
+			t.Fatalf("CreateMarkdownWithFrontmatter() error = %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		return string(data)
+	}
+
+	first := write("first.md", metadata)
+	want := "---\n" +
+		"title: '2024: a review'\n" +
+		"notion_id: \"12345\"\n" +
+		"created_at: \"2024-03-01T09:30:00Z\"\n" +
+		"properties:\n    priority: 2\n    status: Done\n" +
+		"sync_enabled: true\n" +
+		"aliases:\n    - intro\n" +
+		"layout: post\n" +
+		"---\n\nBody"
+	if first != want {
+		t.Errorf("CreateMarkdownWithFrontmatter() wrote\n%s\nwant\n%s", first, want)
+	}
+	for i := 0; i < 10; i++ {
+		if again := write("first.md", metadata); again != first {
+			t.Fatalf("Writing the same metadata again gave\n%s\nwant\n%s", again, first)
+		}
+	}
+
+	// Reading the file back decodes the timestamp as a time and the
+	// properties with interface{} keys; writing it again changes nothing
+	doc, err := parser.ParseFile(filepath.Join(dir, "first.md"))
+	if err != nil {
+		t.Fatalf("Failed to parse created file: %v", err)
+	}
+	if second := write("second.md", doc.Metadata); second != first {
+		t.Errorf("Rewriting the parsed metadata gave\n%s\nwant\n%s", second, first)
+	}
+
+	reloaded := map[string]interface{}{}
+	for key, value := range metadata {
+		reloaded[key] = value
+	}
+	reloaded["created_at"] = created.In(time.FixedZone("CET", 3600))
+	reloaded["properties"] = map[interface{}]interface{}{"status": "Done", "priority": 2}
+	if third := write("third.md", reloaded); third != first {
+		t.Errorf("Writing the metadata as decoded types gave\n%s\nwant\n%s", third, first)
+	}
+}
+
+func TestParser_CreateMarkdownWithFrontmatter_StringKeys(t *testing.T) {
+	parser := NewParser()
+	path := filepath.Join(t.TempDir(), "keys.md")
+
+	// Keys that YAML would otherwise read as a bool, a number or null
+	metadata := map[string]interface{}{
+		"true": "yes",
+		"123":  "number",
+		"null": "kept",
+		"1.5":  "float",
+	}
+	if err := parser.CreateMarkdownWithFrontmatter(path, metadata, "Body"); err != nil {
+		t.Fatalf("CreateMarkdownWithFrontmatter() error = %v", err)
+	}
+
+	doc, err := parser.ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to parse created file: %v", err)
+	}
+	for key, want := range metadata {
+		if got, ok := doc.Metadata[key]; !ok || got != want {
+			t.Errorf("Metadata[%q] = %v (present %v), want %v", key, got, ok, want)
+		}
+	}
+	if len(doc.Metadata) != len(metadata) {
+		t.Errorf("Metadata = %v, want %v", doc.Metadata, metadata)
+	}
+}
+
+func TestParser_LineEndings(t *testing.T) {
+	native := "\n"
+	if runtime.GOOS == "windows" {
+		native = "\r\n"
+	}
+
+	tests := []struct {
+		style   string
+		newline string
+	}{
+		{LineEndingLF, "\n"},
+		{LineEndingCRLF, "\r\n"},
+		{LineEndingAuto, native},
+		{"", "\n"},
+	}
+
+	metadata := map[string]interface{}{"title": "Notes"}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			parser := NewParser(WithLineEnding(tt.style))
+			path := filepath.Join(t.TempDir(), "notes.md")
+
+			// Content mixing both endings is written with just one
+			if err := parser.CreateMarkdownWithFrontmatter(path, metadata, "# Notes\r\nFirst\nSecond"); err != nil {
+				t.Fatalf("CreateMarkdownWithFrontmatter() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", path, err)
+			}
+
+			want := strings.ReplaceAll("---\ntitle: Notes\n---\n\n# Notes\nFirst\nSecond", "\n", tt.newline)
+			if string(data) != want {
+				t.Errorf("CreateMarkdownWithFrontmatter() wrote %q, want %q", data, want)
+			}
+
+			doc, err := parser.ParseFile(path)
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+			if doc.Metadata["title"] != "Notes" {
+				t.Errorf("ParseFile() title = %v, want Notes", doc.Metadata["title"])
+			}
+			if doc.Content != "\n# Notes\nFirst\nSecond" {
+				t.Errorf("ParseFile() content = %q, want LF line endings", doc.Content)
+			}
+		})
+	}
+}
+
 func TestParser_ExtractFrontmatter(t *testing.T) {
 	tests := []struct {
 		name     string
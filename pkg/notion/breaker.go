@@ -0,0 +1,113 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting Notion while the circuit
+// breaker is open after repeated server errors or timeouts
+var ErrCircuitOpen = errors.New("notion appears to be unavailable")
+
+// breakerState is the state of a circuitBreaker
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Requests flow normally
+	breakerOpen                         // Requests fail fast until the cooldown ends
+	breakerHalfOpen                     // One probe request is in flight
+)
+
+// circuitBreaker stops a client from sending requests during a Notion
+// outage. After threshold consecutive server errors or timeouts it opens and
+// fails requests fast for the cooldown, then lets one probe through: success
+// closes it again, failure reopens it. A single breaker may be shared by
+// several clients.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// newCircuitBreaker creates a breaker, or nil (never opens) when threshold
+// is not positive
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns an error wrapping ErrCircuitOpen if the request must not be
+// sent. Once the cooldown has passed, the first caller is let through as the
+// probe.
+func (b *circuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		remaining := b.cooldown - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w: %d requests in a row failed; trying again in %s",
+				ErrCircuitOpen, b.failures, remaining.Round(time.Second))
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return fmt.Errorf("%w: %d requests in a row failed; waiting for a test request", ErrCircuitOpen, b.failures)
+	}
+	return nil
+}
+
+// Record updates the breaker with the outcome of a request it allowed.
+// Only failures that point at an outage count; a 404 or validation error
+// means Notion is up.
+func (b *circuitBreaker) Record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Cancel ends a request the caller gave up on before Notion answered, which
+// says nothing about Notion. A cancelled probe leaves the breaker open with
+// its cooldown over, so the next request probes instead.
+func (b *circuitBreaker) Cancel() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/concurrent/batch"
 )
 
 const (
@@ -17,18 +24,41 @@ const (
 )
 
 type Client interface {
+	GetCurrentUser(ctx context.Context) (*User, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	GetUser(ctx context.Context, userID string) (*User, error)
 	GetPage(ctx context.Context, pageID string) (*Page, error)
+	// GetPagesMetadata fetches many pages in fewer requests than a GetPage
+	// each where the API allows, keyed by the IDs as given. Pages that don't
+	// exist are left out; other failures are returned as a
+	// *PageMetadataError
+	GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*Page, error)
 	GetPageBlocks(ctx context.Context, pageID string) ([]Block, error)
+	GetBlock(ctx context.Context, blockID string) (*Block, error)
+	// UpdateBlock patches a single block; payload holds the block type's
+	// fields, e.g. {"paragraph": {"rich_text": [...]}}, or "archived"
+	UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*Block, error)
 	CreatePage(ctx context.Context, parentID string, properties map[string]interface{}) (*Page, error)
 	UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error
-	DeletePage(ctx context.Context, pageID string) error
+	AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error
+	UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error
+	GetPageProperty(ctx context.Context, pageID, propertyID string) (*PropertyValue, error)
+	DeletePage(ctx context.Context, pageID string) error // Archives the page
+	TrashPage(ctx context.Context, pageID string) error  // Moves the page to the trash
 	RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*Page, error)
 	SearchPages(ctx context.Context, query string) ([]Page, error)
 	GetChildPages(ctx context.Context, parentID string) ([]Page, error)
-	GetAllDescendantPages(ctx context.Context, parentID string) ([]Page, error)
-
-	// Streaming methods for large operations
-	StreamDescendantPages(ctx context.Context, parentID string) *PageStream
+	// GetAllDescendantPages returns pages up to maxDepth levels below parentID:
+	// 1 returns direct children, 2 adds grandchildren, and UnlimitedDepth
+	// returns the whole tree. Child pages the integration can't access are
+	// left out and listed in a *RestrictedPagesError returned with the rest.
+	// Lookups stop with a *PageLimitError past the limit set by WithMaxPages
+	GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]Page, error)
+
+	// Streaming methods for large operations. Child pages the integration
+	// can't access are listed in a *RestrictedPagesError sent once the walk
+	// is done
+	StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *PageStream // maxDepth as for GetAllDescendantPages
 	StreamDatabaseRows(ctx context.Context, databaseID string) *DatabaseRowStream
 
 	// Database methods
@@ -40,9 +70,152 @@ type Client interface {
 }
 
 type client struct {
-	httpClient *http.Client
-	token      string
-	baseURL    string
+	httpClient    *http.Client
+	token         string
+	baseURL       string
+	notionVersion string
+	limiter       *rateLimiter    // Optional; may be shared between clients
+	breaker       *circuitBreaker // Optional; may be shared between clients
+	warnings      io.Writer       // Destination for non-fatal warnings; stdout when nil
+	deleteWorkers int             // Concurrent block deletes when clearing a page; DefaultDeleteWorkers when 0
+	maxPages      int             // Most pages a descendant lookup may find; unlimited when 0
+
+	usersMu     sync.Mutex
+	users       map[string]User // User directory, loaded by the first GetUser
+	usersLoaded bool
+	usersErr    error // Why the directory couldn't be loaded; kept so it isn't retried
+}
+
+// ClientOption customizes a client created by NewClient and friends
+type ClientOption func(*client)
+
+// WithBaseURL points the client at a different API root, such as a proxy or
+// mock gateway. A trailing slash is ignored.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithNotionVersion pins the Notion-Version header sent with every request
+func WithNotionVersion(version string) ClientOption {
+	return func(c *client) {
+		c.notionVersion = version
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client, e.g. to add a custom
+// transport or timeout
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after
+// threshold requests in a row end in a server error or timeout, instead of
+// sending more requests into an outage. After cooldown one request is let
+// through to test whether Notion has recovered. Clients created with the
+// same option, such as a BatchClient's, share one breaker. A threshold of 0
+// disables it.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	breaker := newCircuitBreaker(threshold, cooldown)
+	return func(c *client) {
+		c.breaker = breaker
+	}
+}
+
+// WithDeleteWorkers sets how many blocks are deleted at once when a page's
+// content is replaced. 1 deletes them one by one; 0 or less uses
+// DefaultDeleteWorkers.
+func WithDeleteWorkers(workers int) ClientOption {
+	return func(c *client) {
+		c.deleteWorkers = workers
+	}
+}
+
+// WithMaxPages makes descendant page lookups fail with a *PageLimitError
+// once they find more than max pages, so a parent page pointing at a huge
+// workspace can't start a runaway pull. 0 or less leaves them unlimited.
+func WithMaxPages(max int) ClientOption {
+	return func(c *client) {
+		c.maxPages = max
+	}
+}
+
+// WithWarningWriter sends non-fatal warnings, such as a failed fetch of one
+// page's children, to w instead of stdout. Use io.Discard to silence them.
+func WithWarningWriter(w io.Writer) ClientOption {
+	return func(c *client) {
+		c.warnings = w
+	}
+}
+
+// applyOptions applies opts to c and returns it
+func (c *client) applyOptions(opts []ClientOption) *client {
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// warnf writes a non-fatal warning
+func (c *client) warnf(format string, args ...interface{}) {
+	w := c.warnings
+	if w == nil {
+		w = os.Stdout
+	}
+	_, _ = fmt.Fprintf(w, format, args...)
+}
+
+// Categories of Notion API failures. Errors returned by the client match
+// them with errors.Is according to the response status, while errors.As
+// still yields the underlying *NotionAPIError or *HTTPError
+var (
+	ErrPageNotFound = errors.New("notion object not found")          // 404
+	ErrUnauthorized = errors.New("notion request not authorized")    // 401, e.g. an invalid token
+	ErrForbidden    = errors.New("notion request forbidden")         // 403, e.g. a page not shared
+	ErrRateLimited  = errors.New("notion rate limit exceeded")       // 429
+	ErrValidation   = errors.New("notion request failed validation") // 400
+)
+
+// RestrictedPagesError is returned along with the pages that could be
+// fetched when Notion denied access to some of them, e.g. sub-pages of a
+// shared page that aren't shared with the integration themselves
+type RestrictedPagesError struct {
+	PageIDs []string
+}
+
+func (e *RestrictedPagesError) Error() string {
+	return fmt.Sprintf("%d pages skipped due to missing access: %s", len(e.PageIDs), strings.Join(e.PageIDs, ", "))
+}
+
+// PageLimitError is returned when a descendant page lookup finds more pages
+// than the client's limit; see WithMaxPages. The lookup stops there.
+type PageLimitError struct {
+	Limit    int
+	ParentID string
+}
+
+func (e *PageLimitError) Error() string {
+	return fmt.Sprintf("found more than %d pages below %s", e.Limit, e.ParentID)
+}
+
+// statusError returns the error category for an HTTP status code, or nil
+func statusError(code int) error {
+	switch code {
+	case http.StatusNotFound:
+		return ErrPageNotFound
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest:
+		return ErrValidation
+	}
+	return nil
 }
 
 type NotionAPIError struct {
@@ -51,6 +224,17 @@ type NotionAPIError struct {
 	PageID  string `json:"-"`
 }
 
+// HTTPError is returned for error responses whose body isn't a Notion API
+// error, such as HTML pages from an intermediate proxy
+type HTTPError struct {
+	Code int
+	Body string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http error %d: %s", e.Code, e.Body)
+}
+
 func (e *NotionAPIError) Error() string {
 	if e.PageID != "" {
 		return fmt.Sprintf("notion api error %d: %s (page: %s)", e.Code, e.Message, e.PageID)
@@ -58,14 +242,26 @@ func (e *NotionAPIError) Error() string {
 	return fmt.Sprintf("notion api error %d: %s", e.Code, e.Message)
 }
 
-func NewClient(token string) Client {
-	return &client{
+// Is reports whether target is the category of the error's status code
+func (e *NotionAPIError) Is(target error) bool {
+	return target != nil && statusError(e.Code) == target
+}
+
+// Is reports whether target is the category of the error's status code
+func (e *HTTPError) Is(target error) bool {
+	return target != nil && statusError(e.Code) == target
+}
+
+func NewClient(token string, opts ...ClientOption) Client {
+	c := &client{
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		token:   token,
-		baseURL: BaseURL,
+		token:         token,
+		baseURL:       BaseURL,
+		notionVersion: NotionVersion,
 	}
+	return c.applyOptions(opts)
 }
 
 func (c *client) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
@@ -84,25 +280,40 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, body in
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Notion-Version", NotionVersion)
+	req.Header.Set("Notion-Version", c.notionVersion)
 	req.Header.Set("Content-Type", "application/json")
 
+	// Check the breaker first, so requests fail fast during an outage
+	// instead of waiting for a rate limit token they won't use
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		c.breaker.Cancel()
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			c.breaker.Cancel()
+		} else {
+			c.breaker.Record(true)
+		}
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	c.breaker.Record(resp.StatusCode >= 500)
 
 	if resp.StatusCode >= 400 {
 		defer func() {
 			if err := resp.Body.Close(); err != nil {
 				// Log error but don't fail the operation
-				fmt.Printf("Warning: failed to close response body: %v\n", err)
+				c.warnf("Warning: failed to close response body: %v\n", err)
 			}
 		}()
 		var apiErr NotionAPIError
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		if err := json.Unmarshal(bodyBytes, &apiErr); err != nil {
-			return nil, fmt.Errorf("http error %d: %s", resp.StatusCode, string(bodyBytes))
+			return nil, &HTTPError{Code: resp.StatusCode, Body: string(bodyBytes)}
 		}
 		apiErr.Code = resp.StatusCode
 		return nil, &apiErr
@@ -111,6 +322,148 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, body in
 	return resp, nil
 }
 
+// GetCurrentUser returns the bot user the token belongs to, which makes it a
+// cheap way to check that a token is valid
+func (c *client) GetCurrentUser(ctx context.Context) (*User, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/me", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// VerifyToken checks a token against the Notion API and returns the
+// integration's bot user
+func VerifyToken(ctx context.Context, client Client) (*User, error) {
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, fmt.Errorf("notion rejected the token: %w", err)
+		}
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+	return user, nil
+}
+
+// VerifyParentPage checks that the integration can read the parent page.
+// Notion reports pages that aren't shared with the integration as missing
+func VerifyParentPage(ctx context.Context, client Client, pageID string) (*Page, error) {
+	page, err := client.GetPage(ctx, pageID)
+	if err != nil {
+		if errors.Is(err, ErrPageNotFound) || errors.Is(err, ErrForbidden) {
+			return nil, fmt.Errorf("can't access page %s; make sure it is shared with your integration: %w", pageID, err)
+		}
+		return nil, fmt.Errorf("failed to verify parent page: %w", err)
+	}
+	return page, nil
+}
+
+// ListUsers returns every user in the workspace, following pagination.
+// Guests aren't included.
+func (c *client) ListUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	cursor := ""
+
+	for {
+		endpoint := "/users?page_size=100"
+		if cursor != "" {
+			endpoint += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+
+		page, err := c.getUsersPage(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page.Results...)
+
+		if !page.HasMore || page.NextCursor == nil {
+			return users, nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+func (c *client) getUsersPage(ctx context.Context, endpoint string) (*UsersResponse, error) {
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	var page UsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode users response: %w", err)
+	}
+	return &page, nil
+}
+
+// GetUser looks a user up in the workspace's user directory, which is
+// fetched once and kept for the life of the client. Users missing from the
+// directory, such as guests, are fetched individually and cached too. When
+// the directory can't be loaded, e.g. because the integration lacks user
+// capabilities, every lookup fails with that error without asking again.
+func (c *client) GetUser(ctx context.Context, userID string) (*User, error) {
+	c.usersMu.Lock()
+	defer c.usersMu.Unlock()
+
+	if c.usersErr != nil {
+		return nil, c.usersErr
+	}
+	if !c.usersLoaded {
+		users, err := c.ListUsers(ctx)
+		if err != nil {
+			// A cancelled lookup says nothing about the directory
+			if ctx.Err() == nil {
+				c.usersErr = err
+			}
+			return nil, err
+		}
+		if c.users == nil {
+			c.users = make(map[string]User, len(users))
+		}
+		for _, user := range users {
+			c.users[user.ID] = user
+		}
+		c.usersLoaded = true
+	}
+
+	if user, ok := c.users[userID]; ok {
+		return &user, nil
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/users/"+userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	var user User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode user response: %w", err)
+	}
+	c.users[user.ID] = user
+	return &user, nil
+}
+
 func (c *client) GetPage(ctx context.Context, pageID string) (*Page, error) {
 	resp, err := c.doRequest(ctx, "GET", "/pages/"+pageID, nil)
 	if err != nil {
@@ -122,7 +475,7 @@ func (c *client) GetPage(ctx context.Context, pageID string) (*Page, error) {
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -142,6 +495,56 @@ func (c *client) GetPageBlocks(ctx context.Context, pageID string) ([]Block, err
 	return blocks, nil
 }
 
+// GetBlock retrieves a single block without its children
+func (c *client) GetBlock(ctx context.Context, blockID string) (*Block, error) {
+	resp, err := c.doRequest(ctx, "GET", "/blocks/"+blockID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockID, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	var block Block
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return nil, fmt.Errorf("failed to decode block response: %w", err)
+	}
+
+	return &block, nil
+}
+
+// UpdateBlock patches a single block's content and returns the updated block
+func (c *client) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*Block, error) {
+	resp, err := c.doRequest(ctx, "PATCH", "/blocks/"+blockID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update block %s: %w", blockID, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	var block Block
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return nil, fmt.Errorf("failed to decode update block response: %w", err)
+	}
+
+	return &block, nil
+}
+
+// nestedBlockTypes keep their child blocks in Block.Children, since their
+// markdown wraps the children: quotes and callouts quote them, list items
+// indent them
+var nestedBlockTypes = map[string]bool{
+	"quote":              true,
+	"callout":            true,
+	"bulleted_list_item": true,
+	"numbered_list_item": true,
+}
+
 func (c *client) getBlocksRecursive(ctx context.Context, blockID string) ([]Block, error) {
 	resp, err := c.doRequest(ctx, "GET", "/blocks/"+blockID+"/children", nil)
 	if err != nil {
@@ -153,7 +556,7 @@ func (c *client) getBlocksRecursive(ctx context.Context, blockID string) ([]Bloc
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -164,18 +567,31 @@ func (c *client) getBlocksRecursive(ctx context.Context, blockID string) ([]Bloc
 
 	var allBlocks []Block
 	for _, block := range blocksResp.Results {
-		allBlocks = append(allBlocks, block)
+		// If this block has children, fetch them recursively. A child page's
+		// content belongs to that page, a synced reference's content to the
+		// page holding the original, and a template button's to the pages it
+		// creates, not to the one they are nested in
+		if !block.HasChildren || block.Type == "child_page" || block.IsSyncedReference() || block.IsTemplateButton() {
+			allBlocks = append(allBlocks, block)
+			continue
+		}
 
-		// If this block has children, fetch them recursively
-		if block.HasChildren {
-			childBlocks, err := c.getBlocksRecursive(ctx, block.ID)
-			if err != nil {
-				// Log the error but continue - don't fail the entire operation
-				fmt.Printf("Warning: failed to get child blocks for %s: %v\n", block.ID, err)
-				continue
-			}
-			allBlocks = append(allBlocks, childBlocks...)
+		childBlocks, err := c.getBlocksRecursive(ctx, block.ID)
+		if err != nil {
+			// Log the error but continue - don't fail the entire operation
+			c.warnf("Warning: failed to get child blocks for %s: %v\n", block.ID, err)
+			allBlocks = append(allBlocks, block)
+			continue
 		}
+
+		// Blocks whose markdown wraps their children keep them nested
+		if nestedBlockTypes[block.Type] {
+			block.Children = childBlocks
+			allBlocks = append(allBlocks, block)
+			continue
+		}
+		allBlocks = append(allBlocks, block)
+		allBlocks = append(allBlocks, childBlocks...)
 	}
 
 	return allBlocks, nil
@@ -197,7 +613,7 @@ func (c *client) CreatePage(ctx context.Context, parentID string, properties map
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -218,33 +634,79 @@ func (c *client) UpdatePageBlocks(ctx context.Context, pageID string, blocks []m
 	// Wait a bit for Notion to process deletions
 	time.Sleep(200 * time.Millisecond)
 
-	// Add new blocks in chunks
-	const maxBlocksPerRequest = 100
+	return c.appendBlocks(ctx, pageID, blocks)
+}
+
+// AppendPageBlocks adds blocks to the end of a page without touching existing content
+func (c *client) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	return c.appendBlocks(ctx, pageID, blocks)
+}
 
+// Notion accepts at most 100 children per append request, nested at most two
+// levels deep
+const (
+	maxBlocksPerRequest = 100
+	maxNestingDepth     = 2
+)
+
+// appendBlocks PATCHes blocks onto a page or block in chunks of at most 100
+// children. Blocks whose children are nested too deeply for one request are
+// created without them, and the children are then appended to the created
+// block in follow-up requests.
+func (c *client) appendBlocks(ctx context.Context, parentID string, blocks []map[string]interface{}) error {
 	for i := 0; i < len(blocks); i += maxBlocksPerRequest {
 		end := i + maxBlocksPerRequest
 		if end > len(blocks) {
 			end = len(blocks)
 		}
 
-		chunk := blocks[i:end]
+		chunk := make([]map[string]interface{}, end-i)
+		deferred := make([][]map[string]interface{}, end-i)
+		hasDeferred := false
+		for j, block := range blocks[i:end] {
+			if fitsInRequest(block, maxNestingDepth) {
+				chunk[j] = block
+				continue
+			}
+			chunk[j], deferred[j] = splitBlockChildren(block)
+			hasDeferred = true
+		}
 
 		updateReq := map[string]interface{}{
 			"children": chunk,
 		}
 
-		resp, err := c.doRequest(ctx, "PATCH", "/blocks/"+pageID+"/children", updateReq)
+		resp, err := c.doRequest(ctx, "PATCH", "/blocks/"+parentID+"/children", updateReq)
 		if err != nil {
 			if apiErr, ok := err.(*NotionAPIError); ok {
-				apiErr.PageID = pageID
+				apiErr.PageID = parentID
 			}
-			return fmt.Errorf("failed to update blocks for page %s (chunk %d-%d): %w", pageID, i+1, end, err)
+			return fmt.Errorf("failed to update blocks for page %s (chunk %d-%d): %w", parentID, i+1, end, err)
 		}
-		defer func() {
-			if err := resp.Body.Close(); err != nil {
-				fmt.Printf("Warning: failed to close response body: %v\n", err)
+
+		var created BlocksResponse
+		if hasDeferred {
+			err = json.NewDecoder(resp.Body).Decode(&created)
+		}
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode created blocks: %w", err)
+		}
+
+		// Rebuild the remaining depth under the blocks that were just created
+		for j, children := range deferred {
+			if children == nil {
+				continue
 			}
-		}()
+			if j >= len(created.Results) || created.Results[j].ID == "" {
+				return fmt.Errorf("missing created block ID for nested children at position %d", i+j+1)
+			}
+			if err := c.appendBlocks(ctx, created.Results[j].ID, children); err != nil {
+				return fmt.Errorf("failed to append nested children: %w", err)
+			}
+		}
 
 		// Small delay between chunks to avoid rate limiting
 		if end < len(blocks) {
@@ -255,33 +717,381 @@ func (c *client) UpdatePageBlocks(ctx context.Context, pageID string, blocks []m
 	return nil
 }
 
+// fitsInRequest reports whether a block's children can be sent in the same
+// request, given how many more levels of nesting are allowed
+func fitsInRequest(block map[string]interface{}, depth int) bool {
+	children := blockChildren(block)
+	if len(children) == 0 {
+		return true
+	}
+	if depth == 0 || len(children) > maxBlocksPerRequest {
+		return false
+	}
+	for _, child := range children {
+		if !fitsInRequest(child, depth-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// blockChildren returns the children nested in a block's type object
+func blockChildren(block map[string]interface{}) []map[string]interface{} {
+	blockType, _ := block["type"].(string)
+	content, ok := block[blockType].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	switch children := content["children"].(type) {
+	case []map[string]interface{}:
+		return children
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(children))
+		for _, child := range children {
+			if m, ok := child.(map[string]interface{}); ok {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// splitBlockChildren returns a copy of block without its children, along
+// with the children that were removed
+func splitBlockChildren(block map[string]interface{}) (map[string]interface{}, []map[string]interface{}) {
+	blockType, _ := block["type"].(string)
+	content, _ := block[blockType].(map[string]interface{})
+
+	stripped := make(map[string]interface{}, len(content))
+	for k, v := range content {
+		if k != "children" {
+			stripped[k] = v
+		}
+	}
+
+	result := make(map[string]interface{}, len(block))
+	for k, v := range block {
+		result[k] = v
+	}
+	result[blockType] = stripped
+
+	return result, blockChildren(block)
+}
+
 func (c *client) clearPageBlocks(ctx context.Context, pageID string) error {
 	// Get existing blocks
 	existingBlocks, err := c.GetPageBlocks(ctx, pageID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing blocks: %w", err)
 	}
+	if len(existingBlocks) == 0 {
+		return nil
+	}
 
-	// Delete existing blocks sequentially for reliability
+	// Synced references are left alone: their content is edited on the page
+	// that owns it. So are blocks the API doesn't support and template
+	// buttons, which couldn't be recreated
+	var blockIDs []string
+	keptSynced, keptUnsupported, keptTemplates := 0, 0, 0
 	for _, block := range existingBlocks {
-		_, err := c.doRequest(ctx, "DELETE", "/blocks/"+block.ID, nil)
+		switch {
+		case block.IsSyncedReference():
+			keptSynced++
+		case block.IsUnsupported():
+			keptUnsupported++
+		case block.IsTemplateButton():
+			keptTemplates++
+		default:
+			blockIDs = append(blockIDs, block.ID)
+		}
+	}
+
+	failed := c.deleteBlocks(ctx, blockIDs)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d of %d blocks: %s",
+			len(failed), len(blockIDs), strings.Join(failed, ", "))
+	}
+	if keptSynced > 0 {
+		c.warnf("Warning: kept %d synced block reference(s) on page %s; shared content can only be edited on the page it is synced from\n",
+			keptSynced, pageID)
+	}
+	if keptUnsupported > 0 {
+		c.warnf("Warning: kept %d block(s) the Notion API doesn't support on page %s; they stay where they are and the pushed content follows them\n",
+			keptUnsupported, pageID)
+	}
+	if keptTemplates > 0 {
+		c.warnf("Warning: kept %d template button(s) on page %s; edit their templates in Notion\n",
+			keptTemplates, pageID)
+	}
+
+	// Make sure nothing is left behind before new content is written, so a
+	// partial clear can't leave stale blocks mixed with the new ones
+	remaining, err := c.GetPageBlocks(ctx, pageID)
+	if err != nil {
+		return fmt.Errorf("failed to verify blocks were cleared: %w", err)
+	}
+	left := 0
+	for _, block := range remaining {
+		if !block.IsSyncedReference() && !block.IsUnsupported() && !block.IsTemplateButton() {
+			left++
+		}
+	}
+	if left > 0 {
+		return fmt.Errorf("page still has %d blocks after clearing", left)
+	}
+
+	return nil
+}
+
+// DefaultDeleteWorkers is how many blocks are deleted at once when a page's
+// content is replaced. Notion allows about 3 requests per second on
+// average, so more rarely helps
+const DefaultDeleteWorkers = 4
+
+// Retry settings for block deletion; variables so tests can shorten them
+var (
+	blockDeleteAttempts   = 3
+	blockDeleteRetryDelay = 500 * time.Millisecond
+)
+
+// deleteBlocks deletes blocks with a small pool of workers and returns a
+// description of each block that couldn't be deleted
+func (c *client) deleteBlocks(ctx context.Context, blockIDs []string) []string {
+	workers := c.deleteWorkers
+	if workers <= 0 {
+		workers = DefaultDeleteWorkers
+	}
+
+	// Failures come back by position, so they're reported in page order
+	// whatever order the workers finish in
+	gate := &backoffGate{}
+	processor := batch.Processor{Workers: workers}
+	errs := processor.Run(ctx, len(blockIDs), func(ctx context.Context, i int) error {
+		return c.deleteBlockWithRetry(ctx, blockIDs[i], gate)
+	})
+
+	var failed []string
+	for i, err := range errs {
 		if err != nil {
-			// Log warning but continue - some blocks might not be deletable
-			fmt.Printf("Warning: failed to delete block %s: %v\n", block.ID, err)
-			continue
+			failed = append(failed, fmt.Sprintf("%s (%v)", blockIDs[i], err))
+		}
+	}
+	return failed
+}
+
+// backoffGate holds back every worker sharing it once one of them is rate
+// limited or hits a server error, so they back off together rather than
+// each keep sending requests Notion will refuse
+type backoffGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// hold keeps workers waiting for at least d from now
+func (g *backoffGate) hold(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := time.Now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
+
+// wait blocks until the gate opens or ctx is done
+func (g *backoffGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	wait := time.Until(g.until)
+	g.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deleteBlockWithRetry deletes a block, retrying rate-limit and server errors
+// with a linear backoff shared through gate. A block that is already gone
+// counts as deleted.
+func (c *client) deleteBlockWithRetry(ctx context.Context, blockID string, gate *backoffGate) error {
+	var lastErr error
+	for attempt := 1; attempt <= blockDeleteAttempts; attempt++ {
+		if err := gate.wait(ctx); err != nil {
+			return err
 		}
+		resp, err := c.doRequest(ctx, "DELETE", "/blocks/"+blockID, nil)
+		if err == nil {
+			if err := resp.Body.Close(); err != nil {
+				c.warnf("Warning: failed to close response body: %v\n", err)
+			}
+			return nil
+		}
+
+		if errors.Is(err, ErrPageNotFound) {
+			return nil
+		}
+		if !IsTransientError(err) {
+			return err
+		}
+		lastErr = err
+
+		if attempt < blockDeleteAttempts {
+			gate.hold(time.Duration(attempt) * blockDeleteRetryDelay)
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", blockDeleteAttempts, lastErr)
+}
+
+// IsTransientError reports whether err is a rate limit (429) or server (5xx)
+// error that may succeed if retried
+func IsTransientError(err error) bool {
+	code := 0
+	var apiErr *NotionAPIError
+	var httpErr *HTTPError
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.Code
+	case errors.As(err, &httpErr):
+		code = httpErr.Code
+	}
+	return code == http.StatusTooManyRequests || code >= 500
+}
 
-		// Small delay to avoid rate limiting
-		time.Sleep(50 * time.Millisecond)
+// UpdatePageProperties PATCHes the given property values onto a page, leaving
+// its content and any unlisted properties untouched
+func (c *client) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	updateReq := map[string]interface{}{
+		"properties": properties,
 	}
 
+	resp, err := c.doRequest(ctx, "PATCH", "/pages/"+pageID, updateReq)
+	if err != nil {
+		if apiErr, ok := err.(*NotionAPIError); ok {
+			apiErr.PageID = pageID
+		}
+		return fmt.Errorf("failed to update properties of page %s: %w", pageID, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
 	return nil
 }
 
+// GetPageProperty retrieves a single property of a page, following the
+// endpoint's pagination so relation, rollup, title, rich text and people
+// values aren't cut off at the 25 the page object includes
+func (c *client) GetPageProperty(ctx context.Context, pageID, propertyID string) (*PropertyValue, error) {
+	var value *PropertyValue
+	cursor := ""
+
+	for {
+		// Property IDs come from the API already URL-encoded
+		endpoint := fmt.Sprintf("/pages/%s/properties/%s?page_size=100", pageID, propertyID)
+		if cursor != "" {
+			endpoint += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+
+		page, err := c.getPropertyItemPage(ctx, pageID, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		// Types that don't paginate come back as a single property item
+		if page.single != nil {
+			return page.single, nil
+		}
+
+		if value == nil {
+			value = &PropertyValue{ID: page.list.PropertyItem.ID, Type: page.list.PropertyItem.Type}
+		}
+		value.appendItems(page.list.Results)
+		if page.list.PropertyItem.Rollup != nil {
+			// Each page carries the rollup computed so far; the last is complete
+			value.Rollup = page.list.PropertyItem.Rollup
+		}
+
+		if !page.list.HasMore || page.list.NextCursor == nil {
+			return value, nil
+		}
+		cursor = *page.list.NextCursor
+	}
+}
+
+// propertyItemPage is one response from the page property endpoint: either
+// a single value or a page of a paginated list
+type propertyItemPage struct {
+	single *PropertyValue
+	list   propertyItemList
+}
+
+func (c *client) getPropertyItemPage(ctx context.Context, pageID, endpoint string) (*propertyItemPage, error) {
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		if apiErr, ok := err.(*NotionAPIError); ok {
+			apiErr.PageID = pageID
+		}
+		return nil, fmt.Errorf("failed to get property of page %s: %w", pageID, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.warnf("Warning: failed to close response body: %v\n", err)
+		}
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read property response: %w", err)
+	}
+
+	var probe struct {
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to decode property response: %w", err)
+	}
+
+	page := &propertyItemPage{}
+	if probe.Object == "list" {
+		err = json.Unmarshal(data, &page.list)
+	} else {
+		page.single = &PropertyValue{}
+		err = json.Unmarshal(data, page.single)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode property response: %w", err)
+	}
+	return page, nil
+}
+
 func (c *client) DeletePage(ctx context.Context, pageID string) error {
 	// Archive the page (Notion doesn't allow true deletion)
+	return c.removePage(ctx, pageID, "archived")
+}
+
+// TrashPage moves a page to the trash, where Notion deletes it for good
+// after 30 days. Unlike archived pages, trashed pages drop out of search
+// and queries.
+func (c *client) TrashPage(ctx context.Context, pageID string) error {
+	return c.removePage(ctx, pageID, "in_trash")
+}
+
+// removePage sets the page's archived or in_trash flag
+func (c *client) removePage(ctx context.Context, pageID, flag string) error {
 	updateReq := map[string]interface{}{
-		"archived": true,
+		flag: true,
 	}
 
 	resp, err := c.doRequest(ctx, "PATCH", "/pages/"+pageID, updateReq)
@@ -293,7 +1103,7 @@ func (c *client) DeletePage(ctx context.Context, pageID string) error {
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -318,7 +1128,7 @@ func (c *client) RecreatePageWithBlocks(ctx context.Context, parentID string, pr
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -346,7 +1156,7 @@ func (c *client) SearchPages(ctx context.Context, query string) ([]Page, error)
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -359,26 +1169,45 @@ func (c *client) SearchPages(ctx context.Context, query string) ([]Page, error)
 }
 
 func (c *client) GetChildPages(ctx context.Context, parentID string) ([]Page, error) {
+	pages, _, err := c.childPages(ctx, parentID)
+	return pages, err
+}
+
+// childPages returns the child pages of parentID, and the IDs of those the
+// integration isn't allowed to read. Notion answers 404 rather than 403 for
+// most pages that aren't shared with the integration, so both count as
+// denied. A rejected token is an error; child pages that can't be fetched
+// for any other reason are left out.
+func (c *client) childPages(ctx context.Context, parentID string) ([]Page, []string, error) {
 	resp, err := c.doRequest(ctx, "GET", "/blocks/"+parentID+"/children", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get child pages: %w", err)
+		return nil, nil, fmt.Errorf("failed to get child pages: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
 	var blocksResp BlocksResponse
 	if err := json.NewDecoder(resp.Body).Decode(&blocksResp); err != nil {
-		return nil, fmt.Errorf("failed to decode child pages response: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode child pages response: %w", err)
 	}
 
 	var pages []Page
+	var restricted []string
 	for _, block := range blocksResp.Results {
 		if block.Type == "child_page" {
 			page, err := c.GetPage(ctx, block.ID)
+			if errors.Is(err, ErrForbidden) || errors.Is(err, ErrPageNotFound) {
+				restricted = append(restricted, block.ID)
+				continue
+			}
+			if errors.Is(err, ErrUnauthorized) {
+				// A rejected token fails every request, not just this page
+				return nil, nil, fmt.Errorf("failed to get child page %s: %w", block.ID, err)
+			}
 			if err != nil {
 				continue
 			}
@@ -386,27 +1215,93 @@ func (c *client) GetChildPages(ctx context.Context, parentID string) ([]Page, er
 		}
 	}
 
-	return pages, nil
+	return pages, restricted, nil
+}
+
+// UnlimitedDepth makes descendant page lookups walk the whole tree. Any
+// negative depth does the same.
+const UnlimitedDepth = -1
+
+func (c *client) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]Page, error) {
+	// Track visited pages so a page reachable from two places, or a cycle
+	// back up the tree, is fetched and returned once
+	walk := &descendantWalk{rootID: parentID, visited: map[string]bool{parentID: true}}
+	pages, err := c.getDescendantPages(ctx, parentID, maxDepth, walk)
+	if err == nil && len(walk.restricted) > 0 {
+		err = &RestrictedPagesError{PageIDs: walk.restricted}
+	}
+	return pages, err
+}
+
+// descendantWalk is the state of one descendant page lookup
+type descendantWalk struct {
+	rootID     string
+	visited    map[string]bool // Pages already found, or denied
+	restricted []string        // Child pages the integration can't read
+	found      int
 }
 
-func (c *client) GetAllDescendantPages(ctx context.Context, parentID string) ([]Page, error) {
+// add marks pageID found, failing instead once the walk has already found
+// max pages; max <= 0 is unlimited
+func (w *descendantWalk) add(pageID string, max int) error {
+	if max > 0 && w.found >= max {
+		return &PageLimitError{Limit: max, ParentID: w.rootID}
+	}
+	w.visited[pageID] = true
+	w.found++
+	return nil
+}
+
+// deny records child pages the integration can't read
+func (w *descendantWalk) deny(pageIDs []string) {
+	for _, id := range pageIDs {
+		if !w.visited[id] {
+			w.visited[id] = true
+			w.restricted = append(w.restricted, id)
+		}
+	}
+}
+
+// getDescendantPages returns the pages up to depth levels below parentID.
+// It stops with a *PageLimitError, returning the pages found so far, once
+// the walk finds more pages than the client's limit.
+func (c *client) getDescendantPages(ctx context.Context, parentID string, depth int, walk *descendantWalk) ([]Page, error) {
+	if depth == 0 {
+		return nil, nil
+	}
+
 	var allPages []Page
 
 	// Get direct children first
-	directChildren, err := c.GetChildPages(ctx, parentID)
+	directChildren, denied, err := c.childPages(ctx, parentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get child pages: %w", err)
 	}
+	walk.deny(denied)
 
-	// Add direct children to results
-	allPages = append(allPages, directChildren...)
+	// Add unvisited direct children to results
+	var newChildren []Page
+	for _, page := range directChildren {
+		if walk.visited[page.ID] {
+			continue
+		}
+		if err := walk.add(page.ID, c.maxPages); err != nil {
+			return append(allPages, newChildren...), err
+		}
+		newChildren = append(newChildren, page)
+	}
+	allPages = append(allPages, newChildren...)
 
 	// Recursively get children of each child page
-	for _, page := range directChildren {
-		descendants, err := c.GetAllDescendantPages(ctx, page.ID)
+	for _, page := range newChildren {
+		descendants, err := c.getDescendantPages(ctx, page.ID, depth-1, walk)
+		var limit *PageLimitError
+		if errors.As(err, &limit) || errors.Is(err, ErrUnauthorized) {
+			return append(allPages, descendants...), err
+		}
 		if err != nil {
 			// Log error but continue with other pages
-			fmt.Printf("Warning: failed to get descendants of page %s: %v\n", page.ID, err)
+			c.warnf("Warning: failed to get descendants of page %s: %v\n", page.ID, err)
 			continue
 		}
 		allPages = append(allPages, descendants...)
@@ -425,7 +1320,7 @@ func (c *client) GetDatabase(ctx context.Context, databaseID string) (*Database,
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -445,7 +1340,7 @@ func (c *client) QueryDatabase(ctx context.Context, databaseID string, request *
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -465,7 +1360,7 @@ func (c *client) CreateDatabase(ctx context.Context, request *CreateDatabaseRequ
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -493,7 +1388,7 @@ func (c *client) CreateDatabaseRow(ctx context.Context, databaseID string, prope
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -518,7 +1413,7 @@ func (c *client) UpdateDatabaseRow(ctx context.Context, pageID string, propertie
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("Warning: failed to close response body: %v\n", err)
+			c.warnf("Warning: failed to close response body: %v\n", err)
 		}
 	}()
 
@@ -1,13 +1,18 @@
 package notion
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,9 +20,12 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// Mock HTTP server for testing
+// Mock HTTP server for testing. Requests are handled one at a time, so
+// handlers can keep state without locking even when the client sends
+// requests concurrently
 type mockServer struct {
 	*httptest.Server
+	mu       sync.Mutex
 	requests []recordedRequest
 }
 
@@ -34,6 +42,9 @@ func newMockServer(t *testing.T, handler http.HandlerFunc) *mockServer {
 	}
 
 	ms.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ms.mu.Lock()
+		defer ms.mu.Unlock()
+
 		// Read body and store it
 		body, _ := io.ReadAll(r.Body)
 		ms.requests = append(ms.requests, recordedRequest{
@@ -50,13 +61,64 @@ func newMockServer(t *testing.T, handler http.HandlerFunc) *mockServer {
 	return ms
 }
 
+// newTestClient creates a client pointed at a mock server
+func newTestClient(baseURL string) *client {
+	return NewClient("test-token", WithBaseURL(baseURL)).(*client)
+}
+
 func TestNewClient(t *testing.T) {
 	token := "test-token"
 	c := NewClient(token)
 
 	assert.NotNil(t, c)
-	// Since client is an interface, we can't directly access internal fields
-	// The test verifies that NewClient returns a non-nil implementation
+
+	// Defaults are unchanged when no options are given
+	impl := c.(*client)
+	assert.Equal(t, BaseURL, impl.baseURL)
+	assert.Equal(t, NotionVersion, impl.notionVersion)
+	assert.Equal(t, DefaultTimeout, impl.httpClient.Timeout)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	var gotVersion, gotPath string
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotVersion = r.Header.Get("Notion-Version")
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(Page{ID: "test-page-id"})
+	})
+	defer server.Close()
+
+	t.Run("WithBaseURL", func(t *testing.T) {
+		c := NewClient("test-token", WithBaseURL(server.URL+"/v1/"))
+		_, err := c.GetPage(context.Background(), "test-page-id")
+		require.NoError(t, err)
+		assert.Equal(t, "/v1/pages/test-page-id", gotPath)
+		assert.Equal(t, NotionVersion, gotVersion)
+	})
+
+	t.Run("WithNotionVersion", func(t *testing.T) {
+		c := NewClient("test-token", WithBaseURL(server.URL), WithNotionVersion("2025-09-03"))
+		_, err := c.GetPage(context.Background(), "test-page-id")
+		require.NoError(t, err)
+		assert.Equal(t, "2025-09-03", gotVersion)
+	})
+
+	t.Run("WithHTTPClient", func(t *testing.T) {
+		custom := &http.Client{Timeout: 5 * time.Second}
+		c := NewClient("test-token", WithHTTPClient(custom), WithBaseURL(server.URL))
+		assert.Same(t, custom, c.(*client).httpClient)
+
+		_, err := c.GetPage(context.Background(), "test-page-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("burst client accepts options", func(t *testing.T) {
+		c := NewBurstClient("test-token", WithBaseURL(server.URL), WithNotionVersion("2025-09-03"))
+		_, err := c.GetPage(context.Background(), "test-page-id")
+		require.NoError(t, err)
+		assert.Equal(t, "2025-09-03", gotVersion)
+	})
 }
 
 func TestClient_GetPage(t *testing.T) {
@@ -142,11 +204,7 @@ func TestClient_GetPage(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			page, err := c.GetPage(context.Background(), tt.pageID)
 
@@ -246,11 +304,7 @@ func TestClient_GetPageBlocks(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			blocks, err := c.GetPageBlocks(context.Background(), tt.pageID)
 
@@ -267,6 +321,39 @@ func TestClient_GetPageBlocks(t *testing.T) {
 	}
 }
 
+func TestClient_GetPageBlocks_AttachesQuoteChildren(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var results []Block
+		switch r.URL.Path {
+		case "/blocks/page-id/children":
+			results = []Block{
+				{ID: "quote-1", Type: "quote", HasChildren: true, Quote: &RichTextBlock{}},
+				{ID: "after", Type: "paragraph", Paragraph: &RichTextBlock{}},
+			}
+		case "/blocks/quote-1/children":
+			results = []Block{
+				{ID: "child-1", Type: "paragraph", Paragraph: &RichTextBlock{}},
+				{ID: "child-2", Type: "paragraph", Paragraph: &RichTextBlock{}},
+			}
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	})
+	defer server.Close()
+
+	blocks, err := newTestClient(server.URL).GetPageBlocks(context.Background(), "page-id")
+	require.NoError(t, err)
+
+	// The quote's paragraphs stay nested instead of following it in the list
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "quote-1", blocks[0].ID)
+	require.Len(t, blocks[0].Children, 2)
+	assert.Equal(t, "child-1", blocks[0].Children[0].ID)
+	assert.Equal(t, "child-2", blocks[0].Children[1].ID)
+	assert.Equal(t, "after", blocks[1].ID)
+}
+
 func TestClient_CreatePage(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -331,11 +418,7 @@ func TestClient_CreatePage(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			page, err := c.CreatePage(context.Background(), tt.parentID, tt.properties)
 
@@ -392,20 +475,28 @@ func TestClient_UpdatePageBlocks(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			callCount := 0
 			deleteCalls := 0
+			deleted := make(map[string]bool)
 
 			server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
 				callCount++
 
 				switch r.Method {
 				case "GET":
-					// Getting existing blocks
+					// Getting existing blocks that haven't been deleted yet
 					assert.Equal(t, "/blocks/"+tt.pageID+"/children", r.URL.Path)
+					remaining := []Block{}
+					for _, block := range tt.existingBlocks {
+						if !deleted[block.ID] {
+							remaining = append(remaining, block)
+						}
+					}
 					w.WriteHeader(http.StatusOK)
-					_ = json.NewEncoder(w).Encode(BlocksResponse{Results: tt.existingBlocks})
+					_ = json.NewEncoder(w).Encode(BlocksResponse{Results: remaining})
 
 				case "DELETE":
 					// Deleting existing blocks
 					deleteCalls++
+					deleted[strings.TrimPrefix(r.URL.Path, "/blocks/")] = true
 					w.WriteHeader(http.StatusOK)
 
 				case "PATCH":
@@ -422,11 +513,7 @@ func TestClient_UpdatePageBlocks(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			// Initialize blocks for chunking test
 			if tt.name == "update with many blocks (chunking)" {
@@ -454,6 +541,623 @@ func TestClient_UpdatePageBlocks(t *testing.T) {
 	}
 }
 
+// blockClearServer simulates a page whose blocks can be deleted, with
+// deleteStatus deciding the status code for each DELETE attempt of a block
+func blockClearServer(t *testing.T, existing []Block, deleteStatus func(blockID string, attempt int) int) (*mockServer, *int) {
+	deleted := make(map[string]bool)
+	attempts := make(map[string]int)
+	patchCalls := 0
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			remaining := []Block{}
+			for _, block := range existing {
+				if !deleted[block.ID] {
+					remaining = append(remaining, block)
+				}
+			}
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(BlocksResponse{Results: remaining})
+		case "DELETE":
+			blockID := strings.TrimPrefix(r.URL.Path, "/blocks/")
+			attempts[blockID]++
+			status := deleteStatus(blockID, attempts[blockID])
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				_ = json.NewEncoder(w).Encode(NotionAPIError{Message: "delete failed"})
+				return
+			}
+			deleted[blockID] = true
+			w.WriteHeader(http.StatusOK)
+		case "PATCH":
+			patchCalls++
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	return server, &patchCalls
+}
+
+func TestClient_UpdatePageBlocks_RetriesTransientDelete(t *testing.T) {
+	oldDelay := blockDeleteRetryDelay
+	blockDeleteRetryDelay = time.Millisecond
+	defer func() { blockDeleteRetryDelay = oldDelay }()
+
+	existing := []Block{{ID: "block-1"}, {ID: "block-2"}}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		// block-2 is rate limited once, then deletes fine
+		if blockID == "block-2" && attempt == 1 {
+			return http.StatusTooManyRequests
+		}
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+	require.NoError(t, err)
+
+	deletes := 0
+	for _, req := range server.requests {
+		if req.Method == "DELETE" {
+			deletes++
+		}
+	}
+	assert.Equal(t, 3, deletes, "block-2 should be retried once")
+	assert.Equal(t, 1, *patchCalls, "new content should be written once the page is empty")
+}
+
+func TestClient_UpdatePageBlocks_AbortsWhenClearFails(t *testing.T) {
+	oldDelay := blockDeleteRetryDelay
+	blockDeleteRetryDelay = time.Millisecond
+	defer func() { blockDeleteRetryDelay = oldDelay }()
+
+	existing := []Block{{ID: "block-1"}, {ID: "block-2"}}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		// block-1 never deletes
+		if blockID == "block-1" {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to clear existing blocks")
+	assert.Contains(t, err.Error(), "block-1")
+	assert.Equal(t, 0, *patchCalls, "no content should be written after a failed clear")
+
+	attempts := 0
+	for _, req := range server.requests {
+		if req.Method == "DELETE" && req.Path == "/blocks/block-1" {
+			attempts++
+		}
+	}
+	assert.Equal(t, blockDeleteAttempts, attempts)
+}
+
+func TestClient_UpdatePageBlocks_ParallelDeletes(t *testing.T) {
+	var existing []Block
+	for i := 0; i < 40; i++ {
+		existing = append(existing, Block{ID: fmt.Sprintf("block-%02d", i), Type: "paragraph"})
+	}
+
+	// clearPage replaces the page's content with the given delete workers and
+	// returns the DELETE requests per block and the most sent at once
+	clearPage := func(t *testing.T, workers int) (map[string]int, int32) {
+		var mu sync.Mutex
+		deletes := make(map[string]int)
+		var inFlight, maxInFlight int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				mu.Lock()
+				remaining := []Block{}
+				for _, block := range existing {
+					if deletes[block.ID] == 0 {
+						remaining = append(remaining, block)
+					}
+				}
+				mu.Unlock()
+				_ = json.NewEncoder(w).Encode(BlocksResponse{Results: remaining})
+			case "DELETE":
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					peak := atomic.LoadInt32(&maxInFlight)
+					if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				mu.Lock()
+				deletes[strings.TrimPrefix(r.URL.Path, "/blocks/")]++
+				mu.Unlock()
+			case "PATCH":
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		defer server.Close()
+
+		c := NewClient("test-token", WithBaseURL(server.URL), WithDeleteWorkers(workers))
+		err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+			{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+		})
+		require.NoError(t, err)
+		return deletes, atomic.LoadInt32(&maxInFlight)
+	}
+
+	serial, serialPeak := clearPage(t, 1)
+	parallel, parallelPeak := clearPage(t, 4)
+
+	// Both delete every block exactly once, so the page was verified empty
+	require.Len(t, serial, len(existing))
+	assert.Equal(t, serial, parallel)
+	for blockID, count := range parallel {
+		assert.Equal(t, 1, count, "%s deleted %d times", blockID, count)
+	}
+
+	assert.Equal(t, int32(1), serialPeak)
+	assert.Greater(t, parallelPeak, int32(1))
+	assert.LessOrEqual(t, parallelPeak, int32(4))
+}
+
+func TestClient_UpdatePageBlocks_ParallelDeleteFailures(t *testing.T) {
+	oldDelay := blockDeleteRetryDelay
+	blockDeleteRetryDelay = time.Millisecond
+	defer func() { blockDeleteRetryDelay = oldDelay }()
+
+	var existing []Block
+	for i := 0; i < 10; i++ {
+		existing = append(existing, Block{ID: fmt.Sprintf("block-%d", i), Type: "paragraph"})
+	}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		switch blockID {
+		case "block-2", "block-7":
+			return http.StatusBadRequest
+		case "block-4":
+			// Rate limited once, then deleted on the retry
+			if attempt == 1 {
+				return http.StatusTooManyRequests
+			}
+		}
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL), WithDeleteWorkers(4))
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+
+	// Every failure is reported, in page order
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete 2 of 10 blocks: block-2 (")
+	assert.Regexp(t, `block-2 \(.*\), block-7 \(`, err.Error())
+	assert.NotContains(t, err.Error(), "block-4")
+	assert.Equal(t, 0, *patchCalls)
+}
+
+func TestBackoffGate(t *testing.T) {
+	gate := &backoffGate{}
+	require.NoError(t, gate.wait(context.Background()), "an unused gate is open")
+
+	gate.hold(20 * time.Millisecond)
+	gate.hold(time.Millisecond) // A shorter hold doesn't cut the longer one short
+	start := time.Now()
+	require.NoError(t, gate.wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	gate.hold(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, gate.wait(ctx), context.Canceled)
+}
+
+func TestClient_UpdatePageBlocks_KeepsSyncedReferences(t *testing.T) {
+	reference := Block{
+		ID:          "reference-1",
+		Type:        "synced_block",
+		HasChildren: true,
+		SyncedBlock: &SyncedBlockBlock{SyncedFrom: &SyncedFrom{Type: "block_id", BlockID: "original-1"}},
+	}
+	existing := []Block{{ID: "block-1", Type: "paragraph"}, reference, {ID: "block-2", Type: "paragraph"}}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	c := NewClient("test-token", WithBaseURL(server.URL), WithWarningWriter(&warnings)).(*client)
+
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+	require.NoError(t, err)
+
+	var deleted []string
+	for _, req := range server.requests {
+		if req.Method == "DELETE" {
+			deleted = append(deleted, strings.TrimPrefix(req.Path, "/blocks/"))
+		}
+		// The reference's children belong to the original block
+		assert.NotEqual(t, "/blocks/reference-1/children", req.Path)
+	}
+	assert.ElementsMatch(t, []string{"block-1", "block-2"}, deleted, "the synced reference should not be deleted")
+	assert.Equal(t, 1, *patchCalls)
+	assert.Contains(t, warnings.String(), "kept 1 synced block reference")
+}
+
+func TestClient_UpdatePageBlocks_KeepsUnsupportedBlocks(t *testing.T) {
+	existing := []Block{{ID: "block-1", Type: "paragraph"}, {ID: "form-1", Type: "unsupported"}, {ID: "block-2", Type: "paragraph"}}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	c := NewClient("test-token", WithBaseURL(server.URL), WithWarningWriter(&warnings)).(*client)
+
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+	require.NoError(t, err)
+
+	var deleted []string
+	for _, req := range server.requests {
+		if req.Method == "DELETE" {
+			deleted = append(deleted, strings.TrimPrefix(req.Path, "/blocks/"))
+		}
+	}
+	assert.ElementsMatch(t, []string{"block-1", "block-2"}, deleted, "the unsupported block can't be recreated, so it should not be deleted")
+	assert.Equal(t, 1, *patchCalls)
+	assert.Contains(t, warnings.String(), "kept 1 block(s) the Notion API doesn't support")
+}
+
+func TestClient_UpdatePageBlocks_KeepsTemplateButtons(t *testing.T) {
+	button := Block{ID: "template-1", Type: "template", HasChildren: true}
+	existing := []Block{{ID: "block-1", Type: "paragraph"}, button, {ID: "block-2", Type: "paragraph"}}
+	server, patchCalls := blockClearServer(t, existing, func(blockID string, attempt int) int {
+		return http.StatusOK
+	})
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	c := NewClient("test-token", WithBaseURL(server.URL), WithWarningWriter(&warnings)).(*client)
+
+	err := c.UpdatePageBlocks(context.Background(), "test-page-id", []map[string]interface{}{
+		{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}},
+	})
+	require.NoError(t, err)
+
+	var deleted []string
+	for _, req := range server.requests {
+		if req.Method == "DELETE" {
+			deleted = append(deleted, strings.TrimPrefix(req.Path, "/blocks/"))
+		}
+		// The button's children are its template, not page content
+		assert.NotEqual(t, "/blocks/template-1/children", req.Path)
+	}
+	assert.ElementsMatch(t, []string{"block-1", "block-2"}, deleted, "the template button can't be recreated, so it should not be deleted")
+	assert.Equal(t, 1, *patchCalls)
+	assert.Contains(t, warnings.String(), "kept 1 template button(s)")
+}
+
+// nestedToggle builds a toggle block with the given children
+func nestedToggle(title string, children ...map[string]interface{}) map[string]interface{} {
+	toggle := map[string]interface{}{
+		"rich_text": []map[string]interface{}{
+			{"type": "text", "text": map[string]interface{}{"content": title}},
+		},
+	}
+	if len(children) > 0 {
+		toggle["children"] = children
+	}
+	return map[string]interface{}{"type": "toggle", "toggle": toggle}
+}
+
+func TestClient_AppendPageBlocks_DeepNesting(t *testing.T) {
+	// The server stores every created block so the final tree can be rebuilt,
+	// and rejects requests nested more than two levels deep like Notion does
+	type storedBlock struct {
+		title    string
+		children []string
+	}
+	stored := map[string]*storedBlock{"test-page-id": {}}
+	nextID := 0
+
+	var create func(parentID string, blocks []interface{}, depth int) []Block
+	create = func(parentID string, blocks []interface{}, depth int) []Block {
+		var created []Block
+		for _, b := range blocks {
+			block := b.(map[string]interface{})
+			toggle := block["toggle"].(map[string]interface{})
+			title := toggle["rich_text"].([]interface{})[0].(map[string]interface{})["text"].(map[string]interface{})["content"].(string)
+
+			nextID++
+			id := fmt.Sprintf("block-%d", nextID)
+			stored[id] = &storedBlock{title: title}
+			stored[parentID].children = append(stored[parentID].children, id)
+
+			if children, ok := toggle["children"].([]interface{}); ok {
+				assert.Less(t, depth, 2, "request nests %q too deeply", title)
+				create(id, children, depth+1)
+			}
+			created = append(created, Block{ID: id, Type: "toggle"})
+		}
+		return created
+	}
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "PATCH", r.Method)
+		parentID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		require.Contains(t, stored, parentID)
+
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		created := create(parentID, req["children"].([]interface{}), 0)
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: created})
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	blocks := []map[string]interface{}{
+		nestedToggle("level 1",
+			nestedToggle("level 2",
+				nestedToggle("level 3",
+					nestedToggle("level 4"),
+				),
+			),
+		),
+		nestedToggle("sibling"),
+	}
+
+	require.NoError(t, c.AppendPageBlocks(context.Background(), "test-page-id", blocks))
+
+	// The deep structure needed a follow-up request against a created block
+	assert.Len(t, server.requests, 2)
+	assert.Equal(t, "/blocks/block-1/children", server.requests[1].Path)
+
+	var render func(id, indent string) string
+	render = func(id, indent string) string {
+		var sb strings.Builder
+		for _, childID := range stored[id].children {
+			sb.WriteString(indent + stored[childID].title + "\n")
+			sb.WriteString(render(childID, indent+"  "))
+		}
+		return sb.String()
+	}
+	assert.Equal(t, "level 1\n  level 2\n    level 3\n      level 4\nsibling\n", render("test-page-id", ""))
+}
+
+func TestClient_AppendPageBlocks(t *testing.T) {
+	var appended []interface{}
+	patchCalls := 0
+	deleteCalls := 0
+	getCalls := 0
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			getCalls++
+		case "DELETE":
+			deleteCalls++
+		case "PATCH":
+			patchCalls++
+			assert.Equal(t, "/blocks/test-page-id/children", r.URL.Path)
+			var req map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			children, _ := req["children"].([]interface{})
+			assert.LessOrEqual(t, len(children), 100)
+			appended = append(appended, children...)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	blocks := make([]map[string]interface{}, 150)
+	for i := range blocks {
+		blocks[i] = map[string]interface{}{
+			"type": "paragraph",
+			"paragraph": map[string]interface{}{
+				"rich_text": []map[string]interface{}{
+					{"text": map[string]interface{}{"content": fmt.Sprintf("Note %d", i)}},
+				},
+			},
+		}
+	}
+
+	err := c.AppendPageBlocks(context.Background(), "test-page-id", blocks)
+	require.NoError(t, err)
+
+	// Existing blocks are never fetched or deleted
+	assert.Equal(t, 0, getCalls)
+	assert.Equal(t, 0, deleteCalls)
+
+	// New blocks are sent in two chunks
+	assert.Equal(t, 2, patchCalls)
+	assert.Len(t, appended, 150)
+}
+
+func TestClient_UpdatePageProperties(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(Page{ID: "test-page-id"})
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	err := c.UpdatePageProperties(context.Background(), "test-page-id", map[string]interface{}{
+		"Status": map[string]interface{}{"status": map[string]interface{}{"name": "Done"}},
+		"Done":   map[string]interface{}{"checkbox": true},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, server.requests, 1)
+	req := server.requests[0]
+	assert.Equal(t, "PATCH", req.Method)
+	assert.Equal(t, "/pages/test-page-id", req.Path)
+
+	var body map[string]map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(req.Body), &body))
+	assert.Equal(t, map[string]interface{}{"name": "Done"}, body["properties"]["Status"]["status"])
+	assert.Equal(t, true, body["properties"]["Done"]["checkbox"])
+}
+
+func TestClient_GetBlock(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object": "block", "id": "block-1", "type": "paragraph", "has_children": true,
+			"paragraph": {"rich_text": [{"type": "text", "plain_text": "Hello", "text": {"content": "Hello"}}]}}`))
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	block, err := c.GetBlock(context.Background(), "block-1")
+	require.NoError(t, err)
+
+	require.Len(t, server.requests, 1)
+	assert.Equal(t, "GET", server.requests[0].Method)
+	assert.Equal(t, "/blocks/block-1", server.requests[0].Path)
+
+	assert.Equal(t, "block-1", block.ID)
+	assert.True(t, block.HasChildren)
+	require.NotNil(t, block.Paragraph)
+	assert.Equal(t, "Hello", block.Paragraph.RichText[0].PlainText)
+}
+
+func TestClient_UpdateBlock(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"object": "block", "id": "block-1", "type": "to_do",
+			"to_do": {"rich_text": [{"type": "text", "plain_text": "Ship it", "text": {"content": "Ship it"}}], "checked": true}}`))
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	block, err := c.UpdateBlock(context.Background(), "block-1", map[string]interface{}{
+		"to_do": map[string]interface{}{
+			"rich_text": []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": "Ship it"}}},
+			"checked":   true,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "block-1", block.ID)
+
+	require.Len(t, server.requests, 1)
+	req := server.requests[0]
+	assert.Equal(t, "PATCH", req.Method)
+	assert.Equal(t, "/blocks/block-1", req.Path)
+
+	var body map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(req.Body), &body))
+	assert.Equal(t, true, body["to_do"]["checked"])
+	assert.Equal(t, "Ship it", body["to_do"]["rich_text"].([]interface{})[0].(map[string]interface{})["text"].(map[string]interface{})["content"])
+}
+
+func TestClient_UpdateBlock_NotFound(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusNotFound, Message: "Could not find block"})
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	_, err := c.UpdateBlock(context.Background(), "missing", map[string]interface{}{"archived": true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to update block missing")
+}
+
+func TestClient_GetPageProperty_PaginatesRelation(t *testing.T) {
+	const total = 60
+	var cursors []string
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("start_cursor"))
+
+		start := 0
+		if cursor := r.URL.Query().Get("start_cursor"); cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		end := start + 25
+		if end > total {
+			end = total
+		}
+
+		results := []map[string]interface{}{}
+		for i := start; i < end; i++ {
+			results = append(results, map[string]interface{}{
+				"object":   "property_item",
+				"type":     "relation",
+				"relation": map[string]interface{}{"id": fmt.Sprintf("related-%d", i)},
+			})
+		}
+
+		resp := map[string]interface{}{
+			"object":        "list",
+			"results":       results,
+			"has_more":      end < total,
+			"next_cursor":   nil,
+			"type":          "property_item",
+			"property_item": map[string]interface{}{"id": "rel%3A", "type": "relation", "relation": map[string]interface{}{}},
+		}
+		if end < total {
+			resp["next_cursor"] = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	value, err := c.GetPageProperty(context.Background(), "row-id", "rel%3A")
+	require.NoError(t, err)
+
+	assert.Equal(t, "relation", value.Type)
+	require.Len(t, value.Relation, total)
+	assert.Equal(t, "related-0", value.Relation[0].ID)
+	assert.Equal(t, "related-59", value.Relation[total-1].ID)
+
+	assert.Equal(t, []string{"", "25", "50"}, cursors)
+	require.NotEmpty(t, server.requests)
+	assert.Equal(t, "GET", server.requests[0].Method)
+	assert.Equal(t, "/pages/row-id/properties/rel:", server.requests[0].Path)
+}
+
+func TestClient_GetPageProperty_SingleValue(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"object": "property_item", "id": "num", "type": "number", "number": 42}`))
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	value, err := c.GetPageProperty(context.Background(), "row-id", "num")
+	require.NoError(t, err)
+	assert.Equal(t, "number", value.Type)
+	require.NotNil(t, value.Number)
+	assert.Equal(t, 42.0, *value.Number)
+}
+
 func TestClient_DeletePage(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -496,11 +1200,7 @@ func TestClient_DeletePage(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			err := c.DeletePage(context.Background(), tt.pageID)
 
@@ -513,6 +1213,148 @@ func TestClient_DeletePage(t *testing.T) {
 	}
 }
 
+func TestClient_DeletePageModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		remove func(c Client, pageID string) error
+		flag   string
+	}{
+		{"archive", func(c Client, pageID string) error { return c.DeletePage(context.Background(), pageID) }, "archived"},
+		{"trash", func(c Client, pageID string) error { return c.TrashPage(context.Background(), pageID) }, "in_trash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(Page{ID: "test-page-id"})
+			})
+			defer server.Close()
+
+			require.NoError(t, tt.remove(newTestClient(server.URL), "test-page-id"))
+
+			require.Len(t, server.requests, 1)
+			assert.Equal(t, "PATCH", server.requests[0].Method)
+			assert.Equal(t, "/pages/test-page-id", server.requests[0].Path)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(server.requests[0].Body), &body))
+			assert.Equal(t, map[string]interface{}{tt.flag: true}, body, "only the %s flag is sent", tt.flag)
+		})
+	}
+}
+
+// newMetadataServer serves pages page-00 to page-NN. Search returns them
+// 20 at a time with other pages mixed in, stopping after searchable pages;
+// page-29 doesn't exist and page-bad fails. It counts requests by method.
+func newMetadataServer(t *testing.T, searchable int) (*httptest.Server, map[string]int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method]++
+		mu.Unlock()
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/search":
+			var req SearchRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, searchPageSize, req.PageSize)
+			start := 0
+			if req.StartCursor != "" {
+				start, _ = strconv.Atoi(req.StartCursor)
+			}
+			resp := SearchResponse{Results: []Page{{ID: fmt.Sprintf("other-%d", start)}}}
+			for i := start; i < start+20 && i < searchable; i++ {
+				resp.Results = append(resp.Results, Page{ID: fmt.Sprintf("page-%02d", i)})
+			}
+			if start+20 < searchable {
+				resp.HasMore, resp.NextCursor = true, strconv.Itoa(start+20)
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case r.Method == "GET" && r.URL.Path == "/pages/page-29":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusNotFound, Message: "Page not found"})
+		case r.Method == "GET" && r.URL.Path == "/pages/page-bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusBadRequest, Message: "Invalid request"})
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/pages/"):
+			_ = json.NewEncoder(w).Encode(Page{ID: strings.TrimPrefix(r.URL.Path, "/pages/")})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	return server, requests
+}
+
+func TestClient_GetPagesMetadata_BatchesWithSearch(t *testing.T) {
+	server, requests := newMetadataServer(t, 27)
+	defer server.Close()
+
+	var ids []string
+	for i := 0; i < 30; i++ {
+		ids = append(ids, fmt.Sprintf("page-%02d", i))
+	}
+	// IDs match whether or not they are written with dashes
+	ids[5] = "PAGE-05"
+
+	pages, err := newTestClient(server.URL).GetPagesMetadata(context.Background(), ids)
+	require.NoError(t, err)
+
+	assert.Len(t, pages, 29, "the page that doesn't exist is left out")
+	assert.NotContains(t, pages, "page-29")
+	assert.Equal(t, "page-05", pages["PAGE-05"].ID)
+	assert.Equal(t, "page-28", pages["page-28"].ID)
+
+	// Two searches find 27 pages and the other three are fetched directly:
+	// 5 round trips instead of 30
+	assert.Equal(t, 2, requests["POST"])
+	assert.Equal(t, 3, requests["GET"])
+}
+
+func TestClient_GetPagesMetadata_SmallBatchSkipsSearch(t *testing.T) {
+	server, requests := newMetadataServer(t, 27)
+	defer server.Close()
+
+	pages, err := newTestClient(server.URL).GetPagesMetadata(context.Background(), []string{"page-01", "page-02", "page-29"})
+	require.NoError(t, err)
+
+	assert.Len(t, pages, 2)
+	assert.Equal(t, 0, requests["POST"])
+	assert.Equal(t, 3, requests["GET"])
+}
+
+func TestClient_GetPagesMetadata_ReportsFailedPages(t *testing.T) {
+	server, _ := newMetadataServer(t, 0)
+	defer server.Close()
+
+	pages, err := newTestClient(server.URL).GetPagesMetadata(context.Background(), []string{"page-01", "page-29", "page-bad"})
+	assert.Len(t, pages, 1)
+
+	// Missing pages are left out, failed ones reported by ID
+	var failed *PageMetadataError
+	require.ErrorAs(t, err, &failed)
+	assert.Len(t, failed.Errs, 1)
+	assert.ErrorContains(t, failed.Errs["page-bad"], "Invalid request")
+}
+
+func TestClient_GetPagesMetadata_FetchesWhatSearchMisses(t *testing.T) {
+	// Search runs out of results without finding any of the pages, so every
+	// one is fetched directly
+	server, requests := newMetadataServer(t, 0)
+	defer server.Close()
+
+	var ids []string
+	for i := 0; i < 12; i++ {
+		ids = append(ids, fmt.Sprintf("page-%02d", i))
+	}
+	pages, err := newTestClient(server.URL).GetPagesMetadata(context.Background(), ids)
+	require.NoError(t, err)
+
+	assert.Len(t, pages, 12)
+	assert.Equal(t, 1, requests["POST"], "search had no more results")
+	assert.Equal(t, 12, requests["GET"])
+}
+
 func TestClient_SearchPages(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -560,11 +1402,7 @@ func TestClient_SearchPages(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			pages, err := c.SearchPages(context.Background(), tt.query)
 
@@ -613,11 +1451,7 @@ func TestClient_GetChildPages(t *testing.T) {
 	})
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	pages, err := c.GetChildPages(context.Background(), parentID)
 
@@ -626,6 +1460,260 @@ func TestClient_GetChildPages(t *testing.T) {
 	assert.Equal(t, childPageID, pages[0].ID)
 }
 
+func TestClient_GetAllDescendantPages_Deduplicates(t *testing.T) {
+	// "shared" is listed under both a and b, and links back up to a
+	children := map[string][]string{
+		"root":   {"a", "b"},
+		"a":      {"shared"},
+		"b":      {"shared", "a"},
+		"shared": {"a"},
+	}
+
+	childFetches := make(map[string]int)
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		if strings.HasPrefix(r.URL.Path, "/pages/") {
+			_ = json.NewEncoder(w).Encode(Page{ID: strings.TrimPrefix(r.URL.Path, "/pages/"), Object: "page"})
+			return
+		}
+
+		parentID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		childFetches[parentID]++
+		var blocks []Block
+		for _, id := range children[parentID] {
+			blocks = append(blocks, Block{ID: id, Type: "child_page"})
+		}
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: blocks})
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	pages, err := c.GetAllDescendantPages(context.Background(), "root", UnlimitedDepth)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "shared"}, ids)
+
+	for id, count := range childFetches {
+		assert.Equal(t, 1, count, "children of %s fetched %d times", id, count)
+	}
+
+	// The streaming walk applies the same guard
+	var streamed []string
+	stream := c.StreamDescendantPages(context.Background(), "root", UnlimitedDepth)
+	for page := range stream.Pages() {
+		streamed = append(streamed, page.ID)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "shared"}, streamed)
+}
+
+// newRestrictedPagesServer serves a page tree in which Notion denies access
+// to some pages, answering 403 for two of them and 404 for another
+func newRestrictedPagesServer(t *testing.T) *mockServer {
+	children := map[string][]string{
+		"root":   {"shared", "private", "gone"},
+		"shared": {"secret", "child"},
+	}
+
+	return newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/pages/") {
+			switch id := strings.TrimPrefix(r.URL.Path, "/pages/"); id {
+			case "private", "secret":
+				w.WriteHeader(http.StatusForbidden)
+				_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusForbidden, Message: "restricted_resource"})
+			case "gone":
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusNotFound, Message: "Page not found"})
+			default:
+				_ = json.NewEncoder(w).Encode(Page{ID: id, Object: "page"})
+			}
+			return
+		}
+
+		parentID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		var blocks []Block
+		for _, id := range children[parentID] {
+			blocks = append(blocks, Block{ID: id, Type: "child_page"})
+		}
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: blocks})
+	})
+}
+
+func TestClient_GetAllDescendantPages_RestrictedPages(t *testing.T) {
+	server := newRestrictedPagesServer(t)
+	defer server.Close()
+
+	pages, err := newTestClient(server.URL).GetAllDescendantPages(context.Background(), "root", UnlimitedDepth)
+
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	assert.ElementsMatch(t, []string{"shared", "child"}, ids)
+
+	// Pages Notion answers 404 for are usually just not shared, so they're
+	// reported along with the forbidden ones
+	var restricted *RestrictedPagesError
+	require.ErrorAs(t, err, &restricted)
+	assert.Equal(t, []string{"private", "gone", "secret"}, restricted.PageIDs)
+	assert.Contains(t, err.Error(), "3 pages skipped due to missing access")
+}
+
+func TestClient_StreamDescendantPages_RestrictedPages(t *testing.T) {
+	server := newRestrictedPagesServer(t)
+	defer server.Close()
+
+	var ids []string
+	stream := newTestClient(server.URL).StreamDescendantPages(context.Background(), "root", UnlimitedDepth)
+	for page := range stream.Pages() {
+		ids = append(ids, page.ID)
+	}
+	assert.ElementsMatch(t, []string{"shared", "child"}, ids)
+
+	var restricted *RestrictedPagesError
+	err := <-stream.Errors()
+	require.ErrorAs(t, err, &restricted)
+	assert.Equal(t, []string{"private", "gone", "secret"}, restricted.PageIDs)
+}
+
+func TestClient_GetAllDescendantPages_InvalidToken(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/pages/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(NotionAPIError{Code: http.StatusUnauthorized, Message: "API token is invalid."})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: []Block{{ID: "child", Type: "child_page"}}})
+	})
+	defer server.Close()
+
+	// A rejected token fails the lookup rather than skipping the page
+	_, err := newTestClient(server.URL).GetAllDescendantPages(context.Background(), "root", UnlimitedDepth)
+	require.ErrorIs(t, err, ErrUnauthorized)
+	var restricted *RestrictedPagesError
+	assert.False(t, errors.As(err, &restricted))
+}
+
+func TestClient_GetAllDescendantPages_MaxDepth(t *testing.T) {
+	children := map[string][]string{
+		"root":       {"child-1", "child-2"},
+		"child-1":    {"grandchild"},
+		"grandchild": {"great-grandchild"},
+	}
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/pages/") {
+			_ = json.NewEncoder(w).Encode(Page{ID: strings.TrimPrefix(r.URL.Path, "/pages/"), Object: "page"})
+			return
+		}
+
+		parentID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		var blocks []Block
+		for _, id := range children[parentID] {
+			blocks = append(blocks, Block{ID: id, Type: "child_page"})
+		}
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: blocks})
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, nil},
+		{1, []string{"child-1", "child-2"}},
+		{2, []string{"child-1", "child-2", "grandchild"}},
+		{UnlimitedDepth, []string{"child-1", "child-2", "grandchild", "great-grandchild"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("depth %d", tt.depth), func(t *testing.T) {
+			pages, err := c.GetAllDescendantPages(context.Background(), "root", tt.depth)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, page := range pages {
+				ids = append(ids, page.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+
+			var streamed []string
+			for page := range c.StreamDescendantPages(context.Background(), "root", tt.depth).Pages() {
+				streamed = append(streamed, page.ID)
+			}
+			assert.ElementsMatch(t, tt.want, streamed)
+		})
+	}
+}
+
+func TestClient_GetAllDescendantPages_MaxPages(t *testing.T) {
+	children := map[string][]string{
+		"root": {"a", "b", "c"},
+		"a":    {"a-1", "a-2"},
+		"b":    {"b-1"},
+	}
+
+	var mu sync.Mutex
+	childFetches := make(map[string]int)
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/pages/") {
+			_ = json.NewEncoder(w).Encode(Page{ID: strings.TrimPrefix(r.URL.Path, "/pages/"), Object: "page"})
+			return
+		}
+
+		parentID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+		mu.Lock()
+		childFetches[parentID]++
+		mu.Unlock()
+		var blocks []Block
+		for _, id := range children[parentID] {
+			blocks = append(blocks, Block{ID: id, Type: "child_page"})
+		}
+		_ = json.NewEncoder(w).Encode(BlocksResponse{Results: blocks})
+	})
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL), WithMaxPages(4)).(*client)
+
+	pages, err := c.GetAllDescendantPages(context.Background(), "root", UnlimitedDepth)
+
+	var limit *PageLimitError
+	require.ErrorAs(t, err, &limit)
+	assert.Equal(t, 4, limit.Limit)
+	assert.Equal(t, "root", limit.ParentID)
+	assert.Contains(t, err.Error(), "more than 4 pages below root")
+
+	// The walk stops at the fifth page instead of going on through b
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "c", "a-1"}, ids)
+	assert.Zero(t, childFetches["b"], "the walk should stop before fetching b's children")
+
+	// The streaming walk stops the same way
+	var streamed []string
+	stream := c.StreamDescendantPages(context.Background(), "root", UnlimitedDepth)
+	for page := range stream.Pages() {
+		streamed = append(streamed, page.ID)
+	}
+	require.ErrorAs(t, <-stream.Errors(), &limit)
+	assert.Len(t, streamed, 4)
+
+	// A tree of exactly the limit is fine
+	c.maxPages = 6
+	pages, err = c.GetAllDescendantPages(context.Background(), "root", UnlimitedDepth)
+	require.NoError(t, err)
+	assert.Len(t, pages, 6)
+}
+
 func TestClient_RecreatePageWithBlocks(t *testing.T) {
 	parentID := "parent-page-id"
 	properties := map[string]interface{}{
@@ -676,11 +1764,7 @@ func TestClient_RecreatePageWithBlocks(t *testing.T) {
 	})
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	page, err := c.RecreatePageWithBlocks(context.Background(), parentID, properties, blocks)
 
@@ -721,6 +1805,48 @@ func TestNotionAPIError(t *testing.T) {
 	}
 }
 
+func TestClient_ErrorCategories(t *testing.T) {
+	categories := []error{ErrPageNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited, ErrValidation}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{name: "not found", status: 404, body: `{"code": "object_not_found", "message": "Could not find page"}`, want: ErrPageNotFound},
+		{name: "unauthorized", status: 401, body: `{"code": "unauthorized", "message": "API token is invalid"}`, want: ErrUnauthorized},
+		{name: "forbidden", status: 403, body: `{"code": "restricted_resource", "message": "No access"}`, want: ErrForbidden},
+		{name: "rate limited", status: 429, body: `{"code": "rate_limited", "message": "Slow down"}`, want: ErrRateLimited},
+		{name: "validation", status: 400, body: `{"code": "validation_error", "message": "Invalid property"}`, want: ErrValidation},
+		{name: "rate limited by a proxy", status: 429, body: "<html>Too Many Requests</html>", want: ErrRateLimited},
+		{name: "server error", status: 500, body: `{"code": "internal_server_error", "message": "Oops"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer server.Close()
+
+			c := newTestClient(server.URL)
+			_, err := c.GetPage(context.Background(), "page-id")
+			require.Error(t, err)
+
+			for _, category := range categories {
+				assert.Equal(t, category == tt.want, errors.Is(err, category), "errors.Is(err, %v)", category)
+			}
+
+			// The status is still available from the underlying error
+			var apiErr *NotionAPIError
+			var httpErr *HTTPError
+			assert.True(t, errors.As(err, &apiErr) || errors.As(err, &httpErr))
+		})
+	}
+}
+
 func TestClient_ContextCancellation(t *testing.T) {
 	// Server that delays response
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -730,11 +1856,7 @@ func TestClient_ContextCancellation(t *testing.T) {
 	}))
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	// Create a context that cancels immediately
 	ctx, cancel := context.WithCancel(context.Background())
@@ -761,11 +1883,7 @@ func TestClient_doRequest_ErrorHandling(t *testing.T) {
 			endpoint: "/test",
 			body:     make(chan int), // Channels can't be marshaled to JSON
 			setupFunc: func() *client {
-				return &client{
-					httpClient: &http.Client{},
-					token:      "test",
-					baseURL:    "http://test",
-				}
+				return NewClient("test", WithBaseURL("http://test")).(*client)
 			},
 			wantErr:     true,
 			errContains: "failed to marshal request body",
@@ -776,11 +1894,7 @@ func TestClient_doRequest_ErrorHandling(t *testing.T) {
 			endpoint: "/test",
 			body:     nil,
 			setupFunc: func() *client {
-				return &client{
-					httpClient: &http.Client{},
-					token:      "test",
-					baseURL:    "http://[::1]:namedport", // Invalid URL
-				}
+				return NewClient("test", WithBaseURL("http://[::1]:namedport")).(*client) // Invalid URL
 			},
 			wantErr:     true,
 			errContains: "failed to create request",
@@ -791,13 +1905,10 @@ func TestClient_doRequest_ErrorHandling(t *testing.T) {
 			endpoint: "/test",
 			body:     nil,
 			setupFunc: func() *client {
-				return &client{
-					httpClient: &http.Client{
-						Timeout: 1 * time.Millisecond,
-					},
-					token:   "test",
-					baseURL: "http://192.0.2.0", // Non-routable IP
-				}
+				return NewClient("test",
+					WithHTTPClient(&http.Client{Timeout: 1 * time.Millisecond}),
+					WithBaseURL("http://192.0.2.0"), // Non-routable IP
+				).(*client)
 			},
 			wantErr:     true,
 			errContains: "request failed",
@@ -812,11 +1923,7 @@ func TestClient_doRequest_ErrorHandling(t *testing.T) {
 					w.WriteHeader(http.StatusBadRequest)
 					_, _ = w.Write([]byte("not json"))
 				}))
-				return &client{
-					httpClient: &http.Client{},
-					token:      "test",
-					baseURL:    server.URL,
-				}
+				return NewClient("test", WithBaseURL(server.URL)).(*client)
 			},
 			wantErr:     true,
 			errContains: "http error 400: not json",
@@ -857,11 +1964,7 @@ func TestClient_RateLimiting(t *testing.T) {
 	})
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	// Current implementation doesn't retry, so it should fail
 	_, err := c.GetPage(context.Background(), "test-page-id")
@@ -869,6 +1972,151 @@ func TestClient_RateLimiting(t *testing.T) {
 	assert.Contains(t, err.Error(), "429")
 }
 
+func TestBatchClient_SharedRateLimit(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(Page{ID: "test"})
+	}))
+	defer server.Close()
+
+	const (
+		clientCount       = 3
+		requests          = 9
+		requestsPerSecond = 20.0
+	)
+
+	bc := NewBatchClientWithRateLimit("test-token", clientCount, requestsPerSecond)
+	require.Len(t, bc.clients, clientCount)
+	for _, c := range bc.clients {
+		c.(*client).baseURL = server.URL
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := bc.GetPage(context.Background(), "test-page-id")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	assert.Equal(t, int32(requests), atomic.LoadInt32(&requestCount))
+
+	// The first request goes out immediately; every later one must wait for
+	// its slot in the shared limiter regardless of which sub-client sends it
+	minElapsed := time.Duration(float64(requests-1) / requestsPerSecond * float64(time.Second))
+	assert.GreaterOrEqual(t, elapsed, minElapsed,
+		"combined rate across sub-clients exceeded the global limit")
+}
+
+func TestRateLimiter_ContextCancellation(t *testing.T) {
+	rl := newRateLimiter(1)
+	require.NoError(t, rl.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := rl.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A nil limiter never blocks
+	var unlimited *rateLimiter
+	assert.NoError(t, unlimited.Wait(context.Background()))
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "page-id", "message": "down"})
+	})
+	defer server.Close()
+
+	c := NewClient("test-token", WithBaseURL(server.URL), WithCircuitBreaker(3, time.Minute)).(*client)
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The breaker opens after 3 failures in a row and then fails fast
+	for i := 0; i < 3; i++ {
+		_, err := c.GetPage(ctx, "page-id")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	_, err := c.GetPage(ctx, "page-id")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Contains(t, err.Error(), "trying again in 1m0s")
+	assert.Len(t, server.requests, 3, "no request is sent while the breaker is open")
+
+	// Rejected requests don't wait for or use up rate limit tokens
+	limiter := c.limiter
+	c.limiter = newRateLimiter(1)
+	shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		_, err := c.GetPage(shortCtx, "page-id")
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	}
+	require.NoError(t, c.limiter.Wait(shortCtx))
+	c.limiter = limiter
+
+	// After the cooldown one probe goes through; its failure reopens the
+	// breaker for another cooldown
+	now = now.Add(time.Minute)
+	_, err = c.GetPage(ctx, "page-id")
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Len(t, server.requests, 4)
+	_, err = c.GetPage(ctx, "page-id")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// A successful probe closes it again
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	_, err = c.GetPage(ctx, "page-id")
+	require.NoError(t, err)
+	_, err = c.GetPage(ctx, "page-id")
+	require.NoError(t, err)
+	assert.Len(t, server.requests, 6)
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	b := newCircuitBreaker(2, time.Second)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	// Client errors such as a 404 mean Notion is up and reset the count
+	b.Record(true)
+	b.Record(false)
+	b.Record(true)
+	require.NoError(t, b.Allow())
+	b.Record(true)
+	assert.ErrorIs(t, b.Allow(), ErrCircuitOpen)
+
+	// Only one probe is let through once the cooldown is over
+	now = now.Add(time.Second)
+	require.NoError(t, b.Allow())
+	assert.ErrorIs(t, b.Allow(), ErrCircuitOpen)
+
+	// A cancelled probe hands the probe to the next request
+	b.Cancel()
+	require.NoError(t, b.Allow())
+	b.Record(false)
+	assert.NoError(t, b.Allow())
+
+	// A zero threshold disables the breaker
+	disabled := newCircuitBreaker(0, time.Second)
+	for i := 0; i < 10; i++ {
+		disabled.Record(true)
+	}
+	assert.NoError(t, disabled.Allow())
+}
+
 func TestClient_LargeBlockUpdate(t *testing.T) {
 	// Test updating with exactly 100, 101, and 200 blocks to verify chunking
 	testCases := []struct {
@@ -905,11 +2153,7 @@ func TestClient_LargeBlockUpdate(t *testing.T) {
 			})
 			defer server.Close()
 
-			c := &client{
-				httpClient: &http.Client{Timeout: DefaultTimeout},
-				token:      "test-token",
-				baseURL:    server.URL,
-			}
+			c := newTestClient(server.URL)
 
 			// Create blocks
 			blocks := make([]map[string]interface{}, tc.blockCount)
@@ -943,11 +2187,7 @@ func TestClient_Headers(t *testing.T) {
 	})
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	_, err := c.GetPage(context.Background(), "test-page")
 	assert.NoError(t, err)
@@ -1000,21 +2240,75 @@ func TestClient_RecursiveBlockFetching(t *testing.T) {
 	})
 	defer server.Close()
 
-	c := &client{
-		httpClient: &http.Client{Timeout: DefaultTimeout},
-		token:      "test-token",
-		baseURL:    server.URL,
-	}
+	c := newTestClient(server.URL)
 
 	blocks, err := c.GetPageBlocks(context.Background(), "page-id")
 	assert.NoError(t, err)
 
-	// Should have all blocks including nested ones
-	assert.Len(t, blocks, 5) // 2 top-level + 2 from block1 + 1 from block1-1
+	// Should have all blocks including nested ones; the list item's child
+	// stays nested under it
+	require.Len(t, blocks, 4) // 2 top-level + 2 from block1
 
 	// Verify order (depth-first traversal)
-	expectedIDs := []string{"block1", "block1-1", "block1-1-1", "block1-2", "block2"}
+	expectedIDs := []string{"block1", "block1-1", "block1-2", "block2"}
 	for i, expectedID := range expectedIDs {
 		assert.Equal(t, expectedID, blocks[i].ID)
 	}
+	require.Len(t, blocks[1].Children, 1)
+	assert.Equal(t, "block1-1-1", blocks[1].Children[0].ID)
+}
+
+func TestClient_GetUser_CachesDirectoryFailure(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"object": "error", "status": 403, "code": "restricted_resource", "message": "Insufficient permissions"}`))
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	ctx := context.Background()
+
+	for _, id := range []string{"user-1", "user-2", "user-1"} {
+		_, err := c.GetUser(ctx, id)
+		require.Error(t, err)
+	}
+	assert.Len(t, server.requests, 1, "a failed directory load should not be retried")
+}
+
+func TestClient_GetUser_CachesDirectory(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/users" && r.URL.Query().Get("start_cursor") == "":
+			_, _ = w.Write([]byte(`{"object": "list", "results": [{"object": "user", "id": "user-1", "type": "person", "name": "Ada Lovelace"}], "has_more": true, "next_cursor": "page-2"}`))
+		case r.URL.Path == "/users":
+			_, _ = w.Write([]byte(`{"object": "list", "results": [{"object": "user", "id": "user-2", "type": "person", "name": "Grace Hopper"}], "has_more": false, "next_cursor": null}`))
+		case r.URL.Path == "/users/guest-1":
+			_, _ = w.Write([]byte(`{"object": "user", "id": "guest-1", "type": "person", "name": "Guest User"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	ctx := context.Background()
+
+	user, err := c.GetUser(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, "Grace Hopper", user.Name)
+	require.Len(t, server.requests, 2, "first lookup should list every page of users")
+
+	user, err = c.GetUser(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada Lovelace", user.Name)
+
+	// Guests aren't listed, so they are fetched once and then cached
+	for i := 0; i < 2; i++ {
+		user, err = c.GetUser(ctx, "guest-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Guest User", user.Name)
+	}
+
+	require.Len(t, server.requests, 3)
+	assert.Equal(t, "/users/guest-1", server.requests[2].Path)
 }
@@ -0,0 +1,156 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/concurrent/batch"
+)
+
+// Batching settings for GetPagesMetadata
+const (
+	// metadataSearchMinPages is the fewest pages worth looking for with
+	// search; fewer are fetched one by one
+	metadataSearchMinPages = 10
+
+	// metadataFetchWorkers bounds concurrent GetPage requests for pages
+	// search didn't turn up; the rate limiter still applies
+	metadataFetchWorkers = 5
+
+	// searchPageSize is the most results Notion returns per search request
+	searchPageSize = 100
+)
+
+// PageMetadataError is returned by GetPagesMetadata, along with the pages
+// that were fetched, when some pages failed for a reason other than not
+// existing. Errs holds each failure keyed by the page ID as given.
+type PageMetadataError struct {
+	Errs map[string]error
+}
+
+func (e *PageMetadataError) Error() string {
+	ids := make([]string, 0, len(e.Errs))
+	for id := range e.Errs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	failures := make([]string, len(ids))
+	for i, id := range ids {
+		failures[i] = fmt.Sprintf("%s: %v", id, e.Errs[id])
+	}
+	return fmt.Sprintf("failed to fetch %d pages: %s", len(ids), strings.Join(failures, "; "))
+}
+
+// Unwrap returns the failures, so errors.Is finds e.g. a cancelled context
+func (e *PageMetadataError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// GetPagesMetadata fetches many pages' metadata, keyed by the IDs as given.
+// Large batches are first looked for in workspace search, 100 pages per
+// request, for as long as that takes fewer requests than fetching the pages
+// still missing one by one. Whatever search doesn't find is fetched with
+// concurrent GetPage requests. Pages that don't exist or aren't shared with
+// the integration are left out of the map; other failures are returned as a
+// *PageMetadataError alongside the pages that were fetched.
+func (c *client) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*Page, error) {
+	pages := make(map[string]*Page, len(pageIDs))
+	missing := make(map[string][]string, len(pageIDs)) // Normalized ID to the IDs as given
+	for _, id := range pageIDs {
+		key := metadataKey(id)
+		missing[key] = append(missing[key], id)
+	}
+
+	if len(missing) >= metadataSearchMinPages {
+		if err := c.searchPagesMetadata(ctx, missing, pages); err != nil {
+			if ctx.Err() != nil {
+				return pages, ctx.Err()
+			}
+			// Search is only a shortcut; the pages can still be fetched
+			c.warnf("Warning: page search failed, fetching pages one by one: %v\n", err)
+		}
+	}
+
+	keys := make([]string, 0, len(missing))
+	for key := range missing {
+		keys = append(keys, key)
+	}
+	processor := batch.Processor{Workers: metadataFetchWorkers}
+	fetched := make([]*Page, len(keys))
+	errs := processor.Run(ctx, len(keys), func(ctx context.Context, i int) error {
+		page, err := c.GetPage(ctx, missing[keys[i]][0])
+		if errors.Is(err, ErrPageNotFound) {
+			return nil
+		}
+		fetched[i] = page
+		return err
+	})
+	failed := make(map[string]error)
+	for i, page := range fetched {
+		for _, id := range missing[keys[i]] {
+			if errs[i] != nil {
+				failed[id] = errs[i]
+			} else if page != nil {
+				pages[id] = page
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return pages, &PageMetadataError{Errs: failed}
+	}
+	return pages, nil
+}
+
+// searchPagesMetadata pages through workspace search, moving pages it finds
+// from missing into pages. It stops once every page is found, search runs
+// out of results, or another search request would cost more than fetching
+// the rest directly.
+func (c *client) searchPagesMetadata(ctx context.Context, missing map[string][]string, pages map[string]*Page) error {
+	request := SearchRequest{
+		Filter:   Filter{Value: "page", Property: "object"},
+		PageSize: searchPageSize,
+	}
+	for requests := 0; len(missing) > 0 && requests < len(missing); requests++ {
+		resp, err := c.doRequest(ctx, "POST", "/search", request)
+		if err != nil {
+			return fmt.Errorf("failed to search pages: %w", err)
+		}
+
+		var searchResp SearchResponse
+		err = json.NewDecoder(resp.Body).Decode(&searchResp)
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			c.warnf("Warning: failed to close response body: %v\n", closeErr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode search response: %w", err)
+		}
+
+		for i := range searchResp.Results {
+			page := &searchResp.Results[i]
+			key := metadataKey(page.ID)
+			for _, id := range missing[key] {
+				pages[id] = page
+			}
+			delete(missing, key)
+		}
+
+		if !searchResp.HasMore || searchResp.NextCursor == "" {
+			return nil
+		}
+		request.StartCursor = searchResp.NextCursor
+	}
+	return nil
+}
+
+// metadataKey normalizes a page ID with or without dashes
+func metadataKey(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
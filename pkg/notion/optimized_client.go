@@ -5,11 +5,12 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 // NewOptimizedClient creates a Notion client with optimized HTTP settings
-func NewOptimizedClient(token string) Client {
+func NewOptimizedClient(token string, opts ...ClientOption) Client {
 	// Create an optimized HTTP transport
 	transport := &http.Transport{
 		// Connection pooling settings
@@ -48,15 +49,22 @@ func NewOptimizedClient(token string) Client {
 		Timeout:   5 * time.Minute, // Increased overall timeout
 	}
 
-	return &client{
-		baseURL:    "https://api.notion.com/v1",
-		token:      token,
-		httpClient: httpClient,
+	c := &client{
+		baseURL:       BaseURL,
+		notionVersion: NotionVersion,
+		token:         token,
+		httpClient:    httpClient,
 	}
+	return c.applyOptions(opts)
 }
 
 // NewBurstClient creates a client optimized for burst requests
-func NewBurstClient(token string) Client {
+func NewBurstClient(token string, opts ...ClientOption) Client {
+	return newBurstClient(token, nil).applyOptions(opts)
+}
+
+// newBurstClient creates a burst client that waits on the given limiter
+func newBurstClient(token string, limiter *rateLimiter) *client {
 	// Even more aggressive settings for burst workloads
 	transport := &http.Transport{
 		MaxIdleConns:        200,               // Double the connections
@@ -86,30 +94,46 @@ func NewBurstClient(token string) Client {
 	}
 
 	return &client{
-		baseURL:    "https://api.notion.com/v1",
-		token:      token,
-		httpClient: httpClient,
+		baseURL:       BaseURL,
+		notionVersion: NotionVersion,
+		token:         token,
+		httpClient:    httpClient,
+		limiter:       limiter,
 	}
 }
 
+// MaxBatchClients caps how many sub-clients a BatchClient will create
+const MaxBatchClients = 10
+
 // BatchClient creates multiple clients for true parallel processing
 type BatchClient struct {
+	mu      sync.Mutex
 	clients []Client
 	current int
 }
 
-// NewBatchClient creates multiple clients to work around connection limits
+// NewBatchClient creates multiple clients to work around connection limits,
+// sharing Notion's default global rate limit between them
 func NewBatchClient(token string, clientCount int) *BatchClient {
+	return NewBatchClientWithRateLimit(token, clientCount, DefaultRequestsPerSecond)
+}
+
+// NewBatchClientWithRateLimit creates multiple clients that share a single
+// rate limiter, so the combined request rate across all sub-clients stays
+// within requestsPerSecond. A non-positive rate disables limiting.
+func NewBatchClientWithRateLimit(token string, clientCount int, requestsPerSecond float64, opts ...ClientOption) *BatchClient {
 	if clientCount < 1 {
 		clientCount = 1
 	}
-	if clientCount > 10 {
-		clientCount = 10 // Cap to avoid overwhelming the API
+	if clientCount > MaxBatchClients {
+		clientCount = MaxBatchClients // Cap to avoid overwhelming the API
 	}
 
+	limiter := newRateLimiter(requestsPerSecond)
+
 	clients := make([]Client, clientCount)
 	for i := 0; i < clientCount; i++ {
-		clients[i] = NewBurstClient(token)
+		clients[i] = newBurstClient(token, limiter).applyOptions(opts)
 	}
 
 	return &BatchClient{
@@ -120,16 +144,40 @@ func NewBatchClient(token string, clientCount int) *BatchClient {
 
 // GetClient returns the next client in round-robin fashion
 func (bc *BatchClient) GetClient() Client {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
 	client := bc.clients[bc.current]
 	bc.current = (bc.current + 1) % len(bc.clients)
 	return client
 }
 
+// ListUsers uses round-robin client selection
+func (bc *BatchClient) ListUsers(ctx context.Context) ([]User, error) {
+	return bc.GetClient().ListUsers(ctx)
+}
+
+// GetUser always uses the first client, so the user directory is fetched
+// and cached once rather than once per client
+func (bc *BatchClient) GetUser(ctx context.Context, userID string) (*User, error) {
+	return bc.clients[0].GetUser(ctx, userID)
+}
+
+// GetCurrentUser uses round-robin client selection
+func (bc *BatchClient) GetCurrentUser(ctx context.Context) (*User, error) {
+	return bc.GetClient().GetCurrentUser(ctx)
+}
+
 // GetPage uses round-robin client selection
 func (bc *BatchClient) GetPage(ctx context.Context, pageID string) (*Page, error) {
 	return bc.GetClient().GetPage(ctx, pageID)
 }
 
+// GetPagesMetadata uses round-robin client selection
+func (bc *BatchClient) GetPagesMetadata(ctx context.Context, pageIDs []string) (map[string]*Page, error) {
+	return bc.GetClient().GetPagesMetadata(ctx, pageIDs)
+}
+
 // GetPageBlocks uses round-robin client selection
 func (bc *BatchClient) GetPageBlocks(ctx context.Context, pageID string) ([]Block, error) {
 	return bc.GetClient().GetPageBlocks(ctx, pageID)
@@ -145,16 +193,46 @@ func (bc *BatchClient) CreatePage(ctx context.Context, parentID string, properti
 	return bc.GetClient().CreatePage(ctx, parentID, properties)
 }
 
+// GetBlock uses round-robin client selection
+func (bc *BatchClient) GetBlock(ctx context.Context, blockID string) (*Block, error) {
+	return bc.GetClient().GetBlock(ctx, blockID)
+}
+
+// UpdateBlock uses round-robin client selection
+func (bc *BatchClient) UpdateBlock(ctx context.Context, blockID string, payload map[string]interface{}) (*Block, error) {
+	return bc.GetClient().UpdateBlock(ctx, blockID, payload)
+}
+
 // UpdatePageBlocks uses round-robin client selection
 func (bc *BatchClient) UpdatePageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
 	return bc.GetClient().UpdatePageBlocks(ctx, pageID, blocks)
 }
 
+// AppendPageBlocks uses round-robin client selection
+func (bc *BatchClient) AppendPageBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	return bc.GetClient().AppendPageBlocks(ctx, pageID, blocks)
+}
+
+// UpdatePageProperties uses round-robin client selection
+func (bc *BatchClient) UpdatePageProperties(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	return bc.GetClient().UpdatePageProperties(ctx, pageID, properties)
+}
+
+// GetPageProperty uses round-robin client selection
+func (bc *BatchClient) GetPageProperty(ctx context.Context, pageID, propertyID string) (*PropertyValue, error) {
+	return bc.GetClient().GetPageProperty(ctx, pageID, propertyID)
+}
+
 // DeletePage uses round-robin client selection
 func (bc *BatchClient) DeletePage(ctx context.Context, pageID string) error {
 	return bc.GetClient().DeletePage(ctx, pageID)
 }
 
+// TrashPage uses round-robin client selection
+func (bc *BatchClient) TrashPage(ctx context.Context, pageID string) error {
+	return bc.GetClient().TrashPage(ctx, pageID)
+}
+
 // RecreatePageWithBlocks uses round-robin client selection
 func (bc *BatchClient) RecreatePageWithBlocks(ctx context.Context, parentID string, properties map[string]interface{}, blocks []map[string]interface{}) (*Page, error) {
 	return bc.GetClient().RecreatePageWithBlocks(ctx, parentID, properties, blocks)
@@ -171,8 +249,8 @@ func (bc *BatchClient) GetChildPages(ctx context.Context, parentID string) ([]Pa
 }
 
 // GetAllDescendantPages uses round-robin client selection
-func (bc *BatchClient) GetAllDescendantPages(ctx context.Context, parentID string) ([]Page, error) {
-	return bc.GetClient().GetAllDescendantPages(ctx, parentID)
+func (bc *BatchClient) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]Page, error) {
+	return bc.GetClient().GetAllDescendantPages(ctx, parentID, maxDepth)
 }
 
 // QueryDatabase uses round-robin client selection
@@ -196,8 +274,8 @@ func (bc *BatchClient) UpdateDatabaseRow(ctx context.Context, pageID string, pro
 }
 
 // StreamDescendantPages uses round-robin client selection
-func (bc *BatchClient) StreamDescendantPages(ctx context.Context, parentID string) *PageStream {
-	return bc.GetClient().StreamDescendantPages(ctx, parentID)
+func (bc *BatchClient) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *PageStream {
+	return bc.GetClient().StreamDescendantPages(ctx, parentID, maxDepth)
 }
 
 // StreamDatabaseRows uses round-robin client selection
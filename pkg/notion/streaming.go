@@ -2,6 +2,7 @@ package notion
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -31,6 +32,11 @@ func (ps *PageStream) SendPage(page Page) {
 	ps.pages <- page
 }
 
+// SendError reports an error on the stream (for testing)
+func (ps *PageStream) SendError(err error) {
+	ps.errors <- err
+}
+
 // Errors returns the channel of errors
 func (ps *PageStream) Errors() <-chan error {
 	return ps.errors
@@ -49,13 +55,18 @@ func (ps *PageStream) Close() {
 }
 
 // StreamDescendantPages streams descendant pages without loading them all into memory
-func (c *client) StreamDescendantPages(ctx context.Context, parentID string) *PageStream {
+func (c *client) StreamDescendantPages(ctx context.Context, parentID string, maxDepth int) *PageStream {
 	stream := NewPageStream()
 
 	go func() {
 		defer stream.Close()
 
-		if err := c.streamDescendantPagesRecursive(ctx, parentID, stream); err != nil {
+		walk := &descendantWalk{rootID: parentID, visited: map[string]bool{parentID: true}}
+		err := c.streamDescendantPagesRecursive(ctx, parentID, maxDepth, walk, stream)
+		if err == nil && len(walk.restricted) > 0 {
+			err = &RestrictedPagesError{PageIDs: walk.restricted}
+		}
+		if err != nil {
 			select {
 			case stream.errors <- err:
 			case <-ctx.Done():
@@ -67,16 +78,30 @@ func (c *client) StreamDescendantPages(ctx context.Context, parentID string) *Pa
 	return stream
 }
 
-// streamDescendantPagesRecursive recursively streams pages without keeping them all in memory
-func (c *client) streamDescendantPagesRecursive(ctx context.Context, parentID string, stream *PageStream) error {
+// streamDescendantPagesRecursive recursively streams pages up to depth levels below parentID
+// without keeping them all in memory. walk holds the IDs of pages already streamed, so each
+// is streamed once, and stops the walk with a *PageLimitError once it passes the client's limit
+func (c *client) streamDescendantPagesRecursive(ctx context.Context, parentID string, depth int, walk *descendantWalk, stream *PageStream) error {
+	if depth == 0 {
+		return nil
+	}
+
 	// Get direct children
-	directChildren, err := c.GetChildPages(ctx, parentID)
+	directChildren, denied, err := c.childPages(ctx, parentID)
 	if err != nil {
 		return fmt.Errorf("failed to get child pages for %s: %w", parentID, err)
 	}
+	walk.deny(denied)
 
 	// Stream direct children
 	for _, page := range directChildren {
+		if walk.visited[page.ID] {
+			continue
+		}
+		if err := walk.add(page.ID, c.maxPages); err != nil {
+			return err
+		}
+
 		select {
 		case stream.pages <- page:
 		case <-ctx.Done():
@@ -84,9 +109,14 @@ func (c *client) streamDescendantPagesRecursive(ctx context.Context, parentID st
 		}
 
 		// Recursively stream descendants
-		if err := c.streamDescendantPagesRecursive(ctx, page.ID, stream); err != nil {
+		err := c.streamDescendantPagesRecursive(ctx, page.ID, depth-1, walk, stream)
+		var limit *PageLimitError
+		if errors.As(err, &limit) || errors.Is(err, ErrUnauthorized) {
+			return err
+		}
+		if err != nil {
 			// Log warning but continue with other pages
-			fmt.Printf("Warning: failed to stream descendants of page %s: %v\n", page.ID, err)
+			c.warnf("Warning: failed to stream descendants of page %s: %v\n", page.ID, err)
 		}
 	}
 
@@ -114,6 +144,16 @@ func (drs *DatabaseRowStream) Rows() <-chan DatabaseRow {
 	return drs.rows
 }
 
+// SendRow sends a row to the stream (for testing)
+func (drs *DatabaseRowStream) SendRow(row DatabaseRow) {
+	drs.rows <- row
+}
+
+// SendError reports an error on the stream (for testing)
+func (drs *DatabaseRowStream) SendError(err error) {
+	drs.errors <- err
+}
+
 // Errors returns the channel of errors
 func (drs *DatabaseRowStream) Errors() <-chan error {
 	return drs.errors
@@ -48,17 +48,80 @@ func (nd NotionDate) MarshalJSON() ([]byte, error) {
 type User struct {
 	ID     string `json:"id"`
 	Object string `json:"object"`
+	Type   string `json:"type,omitempty"` // "person" or "bot"
 	Name   string `json:"name"`
 }
 
+// DisplayName names a user for messages, falling back to its ID
+func (u *User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.ID
+}
+
+// UsersResponse is a page of the workspace's users
+type UsersResponse struct {
+	Results    []User  `json:"results"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
 type Page struct {
-	ID          string                 `json:"id"`
-	Object      string                 `json:"object"`
-	CreatedTime time.Time              `json:"created_time"`
-	CreatedBy   User                   `json:"created_by"`
-	Properties  map[string]interface{} `json:"properties"`
-	URL         string                 `json:"url"`
-	Parent      Parent                 `json:"parent"`
+	ID             string                 `json:"id"`
+	Object         string                 `json:"object"`
+	CreatedTime    time.Time              `json:"created_time"`
+	LastEditedTime time.Time              `json:"last_edited_time"`
+	CreatedBy      User                   `json:"created_by"`
+	Properties     map[string]interface{} `json:"properties"`
+	URL            string                 `json:"url"`
+	Parent         Parent                 `json:"parent"`
+	Archived       bool                   `json:"archived"`
+	InTrash        bool                   `json:"in_trash"`
+
+	// IsTemplate marks a database template: the page a database's "New"
+	// button copies, rather than a page of real content
+	IsTemplate bool `json:"is_template,omitempty"`
+}
+
+// TitlePropertyName returns the key of the page's title property. Regular pages
+// always use "title", but database pages name it after the column ("Name",
+// "Task", ...), so the property is located by its type rather than its key.
+func (p *Page) TitlePropertyName() string {
+	for name, prop := range p.Properties {
+		if propData, ok := prop.(map[string]interface{}); ok {
+			if propType, ok := propData["type"].(string); ok && propType == "title" {
+				return name
+			}
+		}
+	}
+	if _, ok := p.Properties["title"]; ok {
+		return "title"
+	}
+	return ""
+}
+
+// Title returns the plain text of the page's title property, or an empty
+// string if the page has no readable title.
+func (p *Page) Title() string {
+	titleData, ok := p.Properties[p.TitlePropertyName()].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	titleList, ok := titleData["title"].([]interface{})
+	if !ok {
+		return ""
+	}
+
+	var title strings.Builder
+	for _, item := range titleList {
+		if titleItem, ok := item.(map[string]interface{}); ok {
+			if plainText, ok := titleItem["plain_text"].(string); ok {
+				title.WriteString(plainText)
+			}
+		}
+	}
+	return title.String()
 }
 
 type Parent struct {
@@ -85,12 +148,21 @@ type Block struct {
 	Table            *TableBlock         `json:"table,omitempty"`
 	TableRow         *TableRowBlock      `json:"table_row,omitempty"`
 	Image            *ImageBlock         `json:"image,omitempty"`
+	File             *FileBlock          `json:"file,omitempty"`
+	PDF              *FileBlock          `json:"pdf,omitempty"`
 	Callout          *CalloutBlock       `json:"callout,omitempty"`
 	Toggle           *ToggleBlock        `json:"toggle,omitempty"`
 	Bookmark         *BookmarkBlock      `json:"bookmark,omitempty"`
 	Divider          *DividerBlock       `json:"divider,omitempty"`
 	Equation         *EquationBlock      `json:"equation,omitempty"`
 	ChildDatabase    *ChildDatabaseBlock `json:"child_database,omitempty"`
+	ChildPage        *ChildPageBlock     `json:"child_page,omitempty"`
+	SyncedBlock      *SyncedBlockBlock   `json:"synced_block,omitempty"`
+
+	// Children holds the nested blocks of quotes, callouts and list items.
+	// Other nested blocks follow their parent in the flat list returned by
+	// GetPageBlocks.
+	Children []Block `json:"-"`
 
 	// For unknown block types, keep the raw content
 	Content map[string]interface{} `json:",inline"`
@@ -102,14 +174,24 @@ type RichTextBlock struct {
 
 type CodeBlock struct {
 	RichText []RichText `json:"rich_text"`
+	Caption  []RichText `json:"caption,omitempty"`
 	Language string     `json:"language"`
 }
 
 type RichText struct {
-	Type        string       `json:"type"`
-	Text        *TextContent `json:"text,omitempty"`
-	Annotations *Annotations `json:"annotations,omitempty"`
-	PlainText   string       `json:"plain_text"`
+	Type        string         `json:"type"`
+	Text        *TextContent   `json:"text,omitempty"`
+	Equation    *EquationBlock `json:"equation,omitempty"` // Set for inline equations
+	Mention     *Mention       `json:"mention,omitempty"`  // Set for mentions
+	Annotations *Annotations   `json:"annotations,omitempty"`
+	PlainText   string         `json:"plain_text"`
+}
+
+// Mention is an @-mention inside rich text. Only user mentions carry
+// details here; other kinds keep their plain text.
+type Mention struct {
+	Type string `json:"type"`
+	User *User  `json:"user,omitempty"`
 }
 
 type TextContent struct {
@@ -137,8 +219,10 @@ type CreatePageRequest struct {
 }
 
 type SearchRequest struct {
-	Query  string `json:"query"`
-	Filter Filter `json:"filter,omitempty"`
+	Query       string `json:"query"`
+	Filter      Filter `json:"filter,omitempty"`
+	StartCursor string `json:"start_cursor,omitempty"`
+	PageSize    int    `json:"page_size,omitempty"`
 }
 
 type Filter struct {
@@ -147,7 +231,9 @@ type Filter struct {
 }
 
 type SearchResponse struct {
-	Results []Page `json:"results"`
+	Results    []Page `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
 }
 
 type BlocksResponse struct {
@@ -171,6 +257,26 @@ type ImageBlock struct {
 	Caption  []RichText    `json:"caption,omitempty"`
 }
 
+// FileBlock is the content of file and pdf blocks
+type FileBlock struct {
+	Type     string        `json:"type"`
+	External *ExternalFile `json:"external,omitempty"`
+	File     *InternalFile `json:"file,omitempty"`
+	Caption  []RichText    `json:"caption,omitempty"`
+	Name     string        `json:"name,omitempty"` // Original file name; file blocks only
+}
+
+// URL returns the address of the file, wherever it is hosted
+func (f *FileBlock) URL() string {
+	switch {
+	case f.File != nil:
+		return f.File.URL
+	case f.External != nil:
+		return f.External.URL
+	}
+	return ""
+}
+
 type ExternalFile struct {
 	URL string `json:"url"`
 }
@@ -213,6 +319,42 @@ type ChildDatabaseBlock struct {
 	Title      string `json:"title,omitempty"`
 }
 
+type ChildPageBlock struct {
+	Title string `json:"title"`
+}
+
+// SyncedBlockBlock is either an original synced block, whose children live
+// on this page, or a reference to one defined elsewhere
+type SyncedBlockBlock struct {
+	SyncedFrom *SyncedFrom `json:"synced_from"`
+}
+
+type SyncedFrom struct {
+	Type    string `json:"type"`
+	BlockID string `json:"block_id"`
+}
+
+// IsSyncedReference reports whether the block shows a synced block owned by
+// another page. Its children belong to the original, so they must not be
+// rewritten from this page
+func (b *Block) IsSyncedReference() bool {
+	return b.Type == "synced_block" && b.SyncedBlock != nil && b.SyncedBlock.SyncedFrom != nil
+}
+
+// IsUnsupported reports whether the block is one the API doesn't expose, such
+// as a form or an AI block. It can't be created through the API, so a page
+// rewrite must leave it in place
+func (b *Block) IsUnsupported() bool {
+	return b.Type == "unsupported"
+}
+
+// IsTemplateButton reports whether the block is a template button. Its
+// children are the content the button copies, not content of the page, and
+// the API can no longer create one, so a page rewrite must leave it in place
+func (b *Block) IsTemplateButton() bool {
+	return b.Type == "template"
+}
+
 // Database types
 type Database struct {
 	ID          string              `json:"id"`
@@ -233,6 +375,7 @@ type Property struct {
 	Text           *TextProperty           `json:"rich_text,omitempty"`
 	Number         *NumberProperty         `json:"number,omitempty"`
 	Select         *SelectProperty         `json:"select,omitempty"`
+	Status         *StatusProperty         `json:"status,omitempty"`
 	MultiSelect    *MultiSelectProperty    `json:"multi_select,omitempty"`
 	Date           *DateProperty           `json:"date,omitempty"`
 	People         *PeopleProperty         `json:"people,omitempty"`
@@ -259,6 +402,19 @@ type NumberProperty struct {
 type SelectProperty struct {
 	Options []SelectOption `json:"options"`
 }
+
+// StatusProperty differs from select in that its options are grouped
+// (e.g. "To-do", "In progress", "Complete")
+type StatusProperty struct {
+	Options []SelectOption `json:"options"`
+	Groups  []StatusGroup  `json:"groups"`
+}
+type StatusGroup struct {
+	ID        string   `json:"id,omitempty"`
+	Name      string   `json:"name"`
+	Color     string   `json:"color,omitempty"`
+	OptionIDs []string `json:"option_ids,omitempty"`
+}
 type MultiSelectProperty struct {
 	Options []SelectOption `json:"options"`
 }
@@ -306,6 +462,17 @@ type DatabaseRow struct {
 	URL         string                   `json:"url"`
 }
 
+// TitlePropertyName returns the key of the row's title property, whatever the
+// database happens to call it
+func (r *DatabaseRow) TitlePropertyName() string {
+	for name, prop := range r.Properties {
+		if prop.Type == "title" {
+			return name
+		}
+	}
+	return ""
+}
+
 // Property values in database rows
 type PropertyValue struct {
 	ID             string          `json:"id,omitempty"`
@@ -314,6 +481,7 @@ type PropertyValue struct {
 	RichText       []RichText      `json:"rich_text,omitempty"`
 	Number         *float64        `json:"number,omitempty"`
 	Select         *SelectOption   `json:"select,omitempty"`
+	Status         *SelectOption   `json:"status,omitempty"`
 	MultiSelect    []SelectOption  `json:"multi_select,omitempty"`
 	Date           *DateValue      `json:"date,omitempty"`
 	People         []User          `json:"people,omitempty"`
@@ -325,6 +493,7 @@ type PropertyValue struct {
 	Formula        *FormulaValue   `json:"formula,omitempty"`
 	Relation       []RelationValue `json:"relation,omitempty"`
 	Rollup         *RollupValue    `json:"rollup,omitempty"`
+	HasMore        bool            `json:"has_more,omitempty"` // Values were truncated; fetch the rest with GetPageProperty
 	CreatedTime    *time.Time      `json:"created_time,omitempty"`
 	CreatedBy      *User           `json:"created_by,omitempty"`
 	LastEditedTime *time.Time      `json:"last_edited_time,omitempty"`
@@ -352,6 +521,44 @@ type FormulaValue struct {
 	Date    *DateValue `json:"date,omitempty"`
 }
 
+// propertyItemList is a page of values from the page property endpoint for
+// paginated property types
+type propertyItemList struct {
+	Results      []propertyItem `json:"results"`
+	NextCursor   *string        `json:"next_cursor"`
+	HasMore      bool           `json:"has_more"`
+	PropertyItem struct {
+		ID     string       `json:"id"`
+		Type   string       `json:"type"`
+		Rollup *RollupValue `json:"rollup,omitempty"`
+	} `json:"property_item"`
+}
+
+// propertyItem is a single value of a paginated property
+type propertyItem struct {
+	Type     string         `json:"type"`
+	Title    *RichText      `json:"title,omitempty"`
+	RichText *RichText      `json:"rich_text,omitempty"`
+	People   *User          `json:"people,omitempty"`
+	Relation *RelationValue `json:"relation,omitempty"`
+}
+
+// appendItems adds the values of a page of property items
+func (p *PropertyValue) appendItems(items []propertyItem) {
+	for _, item := range items {
+		switch {
+		case item.Relation != nil:
+			p.Relation = append(p.Relation, *item.Relation)
+		case item.Title != nil:
+			p.Title = append(p.Title, *item.Title)
+		case item.RichText != nil:
+			p.RichText = append(p.RichText, *item.RichText)
+		case item.People != nil:
+			p.People = append(p.People, *item.People)
+		}
+	}
+}
+
 type RelationValue struct {
 	ID string `json:"id"`
 }
@@ -0,0 +1,131 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	gosync "sync"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+// Worker scaling modes for concurrent pulls, set by performance.worker_scaling
+const (
+	WorkerScalingStatic   = "static"   // A worker count fixed by page count
+	WorkerScalingAdaptive = "adaptive" // Workers scaled by how Notion responds
+)
+
+const (
+	// maxPullWorkers caps concurrent page pulls to avoid overwhelming the API
+	maxPullWorkers = 50
+
+	// adaptiveInitialWorkers is how many pages an adaptive pull starts
+	// fetching at once
+	adaptiveInitialWorkers = 4
+
+	// adaptiveLatencyTolerance is how much slower than the running average
+	// a page may be and still count towards adding a worker
+	adaptiveLatencyTolerance = 2.0
+)
+
+// workerController scales concurrency AIMD style, like TCP congestion
+// control: it allows one more worker after each window of successes with
+// steady latency, and halves the workers as soon as Notion rate limits.
+// Workers take a slot with acquire before each page and hand it back with
+// release.
+type workerController struct {
+	mu   gosync.Mutex
+	cond *gosync.Cond
+	now  func() time.Time
+
+	limit int // Workers allowed to run at once
+	max   int
+	peak  int
+
+	active    int           // Workers holding a slot
+	successes int           // Steady successes since the limit last changed
+	latency   time.Duration // Running average time per page
+	backedOff time.Time     // When the limit was last halved
+}
+
+// newWorkerController starts at a conservative limit and grows up to max
+func newWorkerController(max int) *workerController {
+	limit := adaptiveInitialWorkers
+	if limit > max {
+		limit = max
+	}
+	c := &workerController{now: time.Now, limit: limit, max: max, peak: limit}
+	c.cond = gosync.NewCond(&c.mu)
+	return c
+}
+
+// acquire waits for a free slot and returns when the page started. It fails
+// only when ctx is done first.
+func (c *workerController) acquire(ctx context.Context) (time.Time, error) {
+	stop := context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cond.Broadcast()
+	})
+	defer stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.active >= c.limit {
+		if err := ctx.Err(); err != nil {
+			return time.Time{}, err
+		}
+		c.cond.Wait()
+	}
+	c.active++
+	return c.now(), nil
+}
+
+// release hands back a slot and adjusts the limit by how the page went
+func (c *workerController) release(started time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+	defer c.cond.Broadcast()
+
+	now := c.now()
+	switch {
+	case errors.Is(err, notion.ErrRateLimited):
+		// Pages already running when the limit was halved were sent at the
+		// old concurrency, so their 429s don't halve it again
+		if started.Before(c.backedOff) {
+			return
+		}
+		c.limit = max(c.limit/2, 1)
+		c.successes = 0
+		c.backedOff = now
+	case err != nil:
+		// Other failures say nothing about load
+	default:
+		elapsed := now.Sub(started)
+		steady := c.latency == 0 || float64(elapsed) <= adaptiveLatencyTolerance*float64(c.latency)
+		if c.latency == 0 {
+			c.latency = elapsed
+		} else {
+			c.latency = (4*c.latency + elapsed) / 5
+		}
+		if !steady {
+			c.successes = 0
+			return
+		}
+		c.successes++
+		// A window is one page per allowed worker
+		if c.successes >= c.limit && c.limit < c.max {
+			c.limit++
+			c.successes = 0
+			c.peak = max(c.peak, c.limit)
+		}
+	}
+}
+
+// current returns how many workers are allowed to run now
+func (c *workerController) current() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
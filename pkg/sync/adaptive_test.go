@@ -0,0 +1,144 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestController returns a controller on a fake clock that advances by
+// step each time it's read
+func newTestController(max int, step *time.Duration) *workerController {
+	c := newWorkerController(max)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time {
+		now = now.Add(*step)
+		return now
+	}
+	return c
+}
+
+// runPages acquires and releases n slots one at a time with result err
+func runPages(t *testing.T, c *workerController, n int, err error) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		started, acquireErr := c.acquire(context.Background())
+		require.NoError(t, acquireErr)
+		c.release(started, err)
+	}
+}
+
+func TestWorkerController_GrowsWhileSteady(t *testing.T) {
+	step := 100 * time.Millisecond
+	c := newTestController(6, &step)
+	assert.Equal(t, adaptiveInitialWorkers, c.current())
+
+	// One worker more per window of limit successes
+	runPages(t, c, adaptiveInitialWorkers, nil)
+	assert.Equal(t, adaptiveInitialWorkers+1, c.current())
+
+	// Never past the maximum
+	runPages(t, c, 50, nil)
+	assert.Equal(t, 6, c.current())
+	assert.Equal(t, 6, c.peak)
+}
+
+func TestWorkerController_LatencySpikeHoldsGrowth(t *testing.T) {
+	step := 100 * time.Millisecond
+	c := newTestController(10, &step)
+	runPages(t, c, adaptiveInitialWorkers-1, nil)
+
+	// A page taking much longer than average restarts the window
+	step = time.Second
+	runPages(t, c, 1, nil)
+	assert.Equal(t, adaptiveInitialWorkers, c.current())
+}
+
+func TestWorkerController_BacksOffOnRateLimits(t *testing.T) {
+	step := 100 * time.Millisecond
+	c := newTestController(20, &step)
+	runPages(t, c, 200, nil)
+	require.Equal(t, 20, c.current())
+
+	rateLimited := fmt.Errorf("failed to get page: %w", notion.ErrRateLimited)
+
+	// Pages in flight when Notion starts rate limiting halve the limit once
+	var inFlight []time.Time
+	for i := 0; i < 5; i++ {
+		started, err := c.acquire(context.Background())
+		require.NoError(t, err)
+		inFlight = append(inFlight, started)
+	}
+	for _, started := range inFlight {
+		c.release(started, rateLimited)
+	}
+	assert.Equal(t, 10, c.current())
+
+	// As 429s keep coming, each new one halves it again, down to one worker
+	runPages(t, c, 1, rateLimited)
+	assert.Equal(t, 5, c.current())
+	runPages(t, c, 10, rateLimited)
+	assert.Equal(t, 1, c.current())
+
+	// Other failures neither shrink nor grow it
+	runPages(t, c, 10, assert.AnError)
+	assert.Equal(t, 1, c.current())
+
+	// Then it climbs back as pages succeed
+	runPages(t, c, 1, nil)
+	assert.Equal(t, 2, c.current())
+}
+
+func TestWorkerController_AcquireHonorsContext(t *testing.T) {
+	c := newWorkerController(1)
+	_, err := c.acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSyncer_AdaptiveWorkerScaling(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	cfg.Performance.WorkerScaling = WorkerScalingAdaptive
+
+	children := make([]string, 15)
+	for i := range children {
+		children[i] = fmt.Sprintf("child-%d", i+1)
+	}
+	mockNotion := newReversedPullClient(children, func(string) {})
+
+	// Every page is rate limited, so the pull never runs more pages at once
+	// than it started with
+	var mu gosync.Mutex
+	running, peak := 0, 0
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to get blocks: %w", notion.ErrRateLimited)
+	}
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion))
+	require.NoError(t, err)
+
+	result, err := syncer.PullPage(context.Background(), "root-id")
+	require.Error(t, err)
+	assert.Equal(t, len(children)+1, result.Failed())
+	assert.LessOrEqual(t, peak, adaptiveInitialWorkers)
+}
@@ -0,0 +1,220 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+)
+
+// DefaultAssetsDir is where pulls save downloaded images and files,
+// relative to the markdown root
+const DefaultAssetsDir = "assets"
+
+// assetStore saves files hosted by Notion, whose signed URLs expire after
+// an hour, into one directory. Files with the same content are saved once.
+type assetStore struct {
+	dir    string
+	client *http.Client
+
+	// baseURL, when set, is where the saved files are published, e.g. a
+	// CDN. Files are then named by their content hash and linked under it.
+	baseURL string
+
+	mu     gosync.Mutex
+	byHash map[string]string // Content hash -> saved file
+}
+
+func newAssetStore(dir string) *assetStore {
+	return &assetStore{
+		dir:    dir,
+		client: &http.Client{Timeout: assetFetchTimeout},
+		byHash: make(map[string]string),
+	}
+}
+
+// downloadAssets saves the Notion-hosted files of image, file and pdf blocks
+// and points the blocks at the saved copies, relative to filePath. Files
+// that can't be downloaded keep their URL. External files are left alone,
+// since their links don't expire.
+func (e *engine) downloadAssets(ctx context.Context, blocks []notion.Block, filePath string) {
+	if e.assets == nil {
+		return
+	}
+
+	for i := range blocks {
+		block := &blocks[i]
+
+		var file *notion.InternalFile
+		var fileBlock *notion.FileBlock
+		switch {
+		case block.Image != nil:
+			file = block.Image.File
+		case block.File != nil:
+			fileBlock = block.File
+		case block.PDF != nil:
+			fileBlock = block.PDF
+		}
+		if fileBlock != nil {
+			file = fileBlock.File
+		}
+
+		if file != nil && file.URL != "" && !strings.HasPrefix(file.URL, "data:") {
+			var name string
+			if fileBlock != nil {
+				if fileBlock.Name == "" {
+					// Keep the label the converter would take from the URL
+					fileBlock.Name = urlFileName(file.URL)
+				}
+				name = fileBlock.Name
+			}
+
+			saved, err := e.assets.save(ctx, file.URL, name)
+			if err != nil {
+				e.log().Warning("Keeping link to %s: %v", file.URL, err)
+			} else if link, err := e.assets.link(filePath, saved); err == nil {
+				file.URL = link
+			}
+		}
+		e.downloadAssets(ctx, block.Children, filePath)
+	}
+}
+
+// save downloads rawURL into the assets directory as name, or the last
+// element of the URL's path when name is empty, and returns the saved path.
+// A name already used by a different file gets a short content hash added.
+// With a base URL the file is named by its content hash instead, keeping
+// the name's extension.
+func (s *assetStore) save(ctx context.Context, rawURL, name string) (string, error) {
+	if name == "" {
+		name = urlFileName(rawURL)
+	}
+	name = util.SanitizeFileName(name)
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create assets directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash, err := s.fetch(ctx, rawURL, tmp)
+	if err != nil {
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if saved, ok := s.byHash[hash]; ok {
+		return saved, nil
+	}
+
+	if s.baseURL != "" {
+		name = hashedAssetName(hash, name)
+	}
+	target := filepath.Join(s.dir, name)
+	if existing, err := fileHash(target); err == nil && existing != hash {
+		ext := filepath.Ext(name)
+		target = filepath.Join(s.dir, strings.TrimSuffix(name, ext)+"_"+hash[:8]+ext)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("failed to save %s: %w", name, err)
+	}
+
+	s.byHash[hash] = target
+	return target, nil
+}
+
+// link returns the link to a saved file from the markdown file at from:
+// relative to it, or under the base URL when one is set
+func (s *assetStore) link(from, saved string) (string, error) {
+	if s.baseURL == "" {
+		return relativeLink(from, saved)
+	}
+	return strings.TrimSuffix(s.baseURL, "/") + "/" + url.PathEscape(filepath.Base(saved)), nil
+}
+
+// hashedAssetName names an asset by the first 16 characters of its content
+// hash, so a published URL changes whenever the file does
+func hashedAssetName(hash, name string) string {
+	return hash[:16] + strings.ToLower(filepath.Ext(name))
+}
+
+// fetch downloads rawURL into w and returns the content's hash
+func (s *assetStore) fetch(ctx context.Context, rawURL string, w io.Writer) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download: %s", resp.Status)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, h), resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// urlFileName returns the last element of a URL's path, unescaped
+func urlFileName(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return path.Base(rawURL)
+	}
+	return path.Base(parsed.Path)
+}
+
+// relativeLink returns a markdown link from the file at from to target
+func relativeLink(from, target string) (string, error) {
+	rel, err := filepath.Rel(filepath.Dir(from), target)
+	if err != nil {
+		return "", err
+	}
+	link := filepath.ToSlash(rel)
+	if !strings.HasPrefix(link, "../") {
+		link = "./" + link
+	}
+	return strings.ReplaceAll(link, " ", "%20"), nil
+}
@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_DownloadAssets(t *testing.T) {
+	files := map[string]string{
+		"/signed/report.pdf":       "report v1",
+		"/signed/copy/report.pdf":  "report v1", // Same content under another URL
+		"/signed/other/report.pdf": "report v2", // Same name, different content
+		"/signed/diagram.png":      "\x89PNG\r\n\x1a\n",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	hosted := func(path string) *notion.InternalFile {
+		return &notion.InternalFile{URL: server.URL + path + "?X-Amz-Signature=abc"}
+	}
+
+	e, mockNotion, mockParser, _ := createTestEngine(t)
+	e.converter = NewConverter()
+	root := e.config.Directories.MarkdownRoot
+	e.assets = newAssetStore(filepath.Join(root, DefaultAssetsDir))
+
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		return []notion.Block{
+			{Type: "file", File: &notion.FileBlock{Type: "file", File: hosted("/signed/report.pdf"), Name: "Q3 report.pdf"}},
+			{Type: "pdf", PDF: &notion.FileBlock{Type: "file", File: hosted("/signed/copy/report.pdf")}},
+			{Type: "pdf", PDF: &notion.FileBlock{Type: "file", File: hosted("/signed/other/report.pdf")}},
+			{Type: "image", Image: &notion.ImageBlock{Type: "file", File: hosted("/signed/diagram.png")}},
+			{Type: "file", File: &notion.FileBlock{Type: "external", External: &notion.ExternalFile{URL: "https://example.com/spec.pdf"}}},
+			{Type: "file", File: &notion.FileBlock{Type: "file", File: hosted("/signed/missing.zip"), Name: "missing.zip"}},
+		}, nil
+	}
+	var content string
+	mockParser.createMarkdownWithFrontmatterFunc = func(filePath string, metadata map[string]interface{}, c string) error {
+		content = c
+		return nil
+	}
+
+	filePath := filepath.Join(root, "docs", "page.md")
+	require.NoError(t, e.SyncNotionToFile(context.Background(), "page-id", filePath))
+
+	assert.Equal(t, "[Q3 report.pdf](../assets/Q3%20report.pdf)\n\n"+
+		"[report.pdf](../assets/Q3%20report.pdf)\n\n"+
+		"[report.pdf](../assets/report.pdf)\n\n"+
+		"![](../assets/diagram.png)\n\n"+
+		"[spec.pdf](https://example.com/spec.pdf)\n\n"+
+		"[missing.zip]("+server.URL+"/signed/missing.zip?X-Amz-Signature=abc)", content)
+
+	saved, err := os.ReadDir(filepath.Join(root, DefaultAssetsDir))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range saved {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"Q3 report.pdf", "report.pdf", "diagram.png"}, names,
+		"identical content is saved once and failed downloads leave nothing behind")
+}
+
+func TestAssetStore_NameCollision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	store := newAssetStore(dir)
+	ctx := context.Background()
+
+	first, err := store.save(ctx, server.URL+"/a", "notes.txt")
+	require.NoError(t, err)
+	second, err := store.save(ctx, server.URL+"/b", "notes.txt")
+	require.NoError(t, err)
+	again, err := store.save(ctx, server.URL+"/a", "renamed.txt")
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "notes.txt"), first)
+	assert.NotEqual(t, first, second)
+	assert.Regexp(t, `notes_[0-9a-f]{8}\.txt$`, second)
+	assert.Equal(t, first, again, "content already saved is reused")
+
+	// A later run reuses the file it saved before
+	rerun, err := newAssetStore(dir).save(ctx, server.URL+"/a", "notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, first, rerun)
+}
+
+func TestEngine_DownloadAssets_BaseURL(t *testing.T) {
+	const png = "\x89PNG\r\n\x1a\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(png))
+	}))
+	defer server.Close()
+
+	e, mockNotion, mockParser, _ := createTestEngine(t)
+	e.converter = NewConverter()
+	root := e.config.Directories.MarkdownRoot
+	e.assets = newAssetStore(filepath.Join(root, DefaultAssetsDir))
+	e.assets.baseURL = "https://cdn.example.com/assets/"
+
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		return []notion.Block{
+			{Type: "image", Image: &notion.ImageBlock{Type: "file", File: &notion.InternalFile{URL: server.URL + "/signed/Diagram.PNG?X-Amz-Signature=abc"}}},
+		}, nil
+	}
+	var content string
+	mockParser.createMarkdownWithFrontmatterFunc = func(filePath string, metadata map[string]interface{}, c string) error {
+		content = c
+		return nil
+	}
+
+	filePath := filepath.Join(root, "docs", "page.md")
+	require.NoError(t, e.SyncNotionToFile(context.Background(), "page-id", filePath))
+
+	// The file is saved locally and linked on the CDN under the same name,
+	// taken from its content hash
+	sum := sha256.Sum256([]byte(png))
+	name := hex.EncodeToString(sum[:])[:16] + ".png"
+	assert.Equal(t, "![](https://cdn.example.com/assets/"+name+")", content)
+
+	saved, err := os.ReadFile(filepath.Join(root, DefaultAssetsDir, name))
+	require.NoError(t, err)
+	assert.Equal(t, png, string(saved))
+}
+
+func TestNewSyncer_AssetsBaseURL(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(&mockNotionClient{}), WithAssetsBaseURL("https://cdn.example.com/assets"))
+	require.NoError(t, err)
+	require.NotNil(t, syncer.engine.assets, "a base URL turns on asset downloads")
+	assert.Equal(t, filepath.Join(cfg.Directories.MarkdownRoot, DefaultAssetsDir), syncer.engine.assets.dir)
+
+	_, err = NewSyncer(cfg, WithAssetsBaseURL("cdn.example.com/assets"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,277 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RichText is a run of text in a block built by BlockBuilder. Content longer
+// than Notion's 2000 character limit is split into several text objects.
+type RichText struct {
+	Content string
+	Link    string // URL the text links to, if any
+
+	Bold          bool
+	Italic        bool
+	Strikethrough bool
+	Underline     bool
+	Code          bool
+	Color         string // Empty keeps Notion's default
+}
+
+// Text returns plain rich text
+func Text(content string) RichText {
+	return RichText{Content: content}
+}
+
+// BlockBuilder builds Notion block objects in the shape the API expects, for
+// use with RegisterNodeConverter or direct API calls. Methods chain; the first
+// invalid block is reported by Blocks and later calls are ignored.
+//
+//	blocks, err := sync.NewBlockBuilder().
+//		Heading(1, sync.Text("Release notes")).
+//		ToDo(false, sync.Text("Update the changelog")).
+//		Code("go", `fmt.Println("hi")`).
+//		Blocks()
+type BlockBuilder struct {
+	blocks []map[string]interface{}
+	err    error
+}
+
+// NewBlockBuilder returns an empty builder
+func NewBlockBuilder() *BlockBuilder {
+	return &BlockBuilder{}
+}
+
+// Blocks returns the blocks built so far, or the first validation error
+func (b *BlockBuilder) Blocks() ([]map[string]interface{}, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.blocks, nil
+}
+
+// Paragraph adds a paragraph block
+func (b *BlockBuilder) Paragraph(text ...RichText) *BlockBuilder {
+	return b.add("paragraph", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock("paragraph", rt)
+	})
+}
+
+// Heading adds a heading block. Notion supports levels 1 to 3.
+func (b *BlockBuilder) Heading(level int, text ...RichText) *BlockBuilder {
+	if level < 1 || level > 3 {
+		return b.fail(fmt.Errorf("heading level must be between 1 and 3 (got %d)", level))
+	}
+	return b.add("heading", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock(fmt.Sprintf("heading_%d", level), rt)
+	})
+}
+
+// BulletedListItem adds a bulleted list item block
+func (b *BlockBuilder) BulletedListItem(text ...RichText) *BlockBuilder {
+	return b.add("bulleted list item", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock("bulleted_list_item", rt)
+	})
+}
+
+// NumberedListItem adds a numbered list item block
+func (b *BlockBuilder) NumberedListItem(text ...RichText) *BlockBuilder {
+	return b.add("numbered list item", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock("numbered_list_item", rt)
+	})
+}
+
+// ToDo adds a to-do block
+func (b *BlockBuilder) ToDo(checked bool, text ...RichText) *BlockBuilder {
+	return b.add("to-do", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return toDoBlock(checked, rt)
+	})
+}
+
+// Quote adds a quote block
+func (b *BlockBuilder) Quote(text ...RichText) *BlockBuilder {
+	return b.add("quote", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock("quote", rt)
+	})
+}
+
+// Toggle adds a toggle block
+func (b *BlockBuilder) Toggle(text ...RichText) *BlockBuilder {
+	return b.add("toggle", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return textBlock("toggle", rt)
+	})
+}
+
+// Callout adds a gray callout block. An empty emoji leaves out the icon.
+func (b *BlockBuilder) Callout(emoji string, text ...RichText) *BlockBuilder {
+	return b.add("callout", text, func(rt []map[string]interface{}) map[string]interface{} {
+		return calloutBlock(emoji, rt)
+	})
+}
+
+// Code adds a code block. Languages Notion doesn't know become "plain text".
+func (b *BlockBuilder) Code(language, text string) *BlockBuilder {
+	return b.add("code", []RichText{Text(text)}, func(rt []map[string]interface{}) map[string]interface{} {
+		return codeBlock(language, rt)
+	})
+}
+
+// Divider adds a divider block
+func (b *BlockBuilder) Divider() *BlockBuilder {
+	if b.err == nil {
+		b.blocks = append(b.blocks, createDividerBlock())
+	}
+	return b
+}
+
+// Image adds an image block for an external URL with an optional caption
+func (b *BlockBuilder) Image(url string, caption ...RichText) *BlockBuilder {
+	if strings.TrimSpace(url) == "" {
+		return b.fail(fmt.Errorf("image URL is required"))
+	}
+	return b.add("image caption", caption, func(rt []map[string]interface{}) map[string]interface{} {
+		return imageBlock(url, rt)
+	})
+}
+
+// Equation adds a block equation
+func (b *BlockBuilder) Equation(expression string) *BlockBuilder {
+	if strings.TrimSpace(expression) == "" {
+		return b.fail(fmt.Errorf("equation expression is required"))
+	}
+	if b.err == nil {
+		b.blocks = append(b.blocks, createEquationBlock(expression))
+	}
+	return b
+}
+
+// add validates text and appends the block built from it
+func (b *BlockBuilder) add(kind string, text []RichText, build func([]map[string]interface{}) map[string]interface{}) *BlockBuilder {
+	if b.err != nil {
+		return b
+	}
+	richText := richTextObjects(text...)
+	if len(richText) > maxRichTextSegments {
+		return b.fail(fmt.Errorf("%s text needs %d rich text objects, more than Notion's limit of %d",
+			kind, len(richText), maxRichTextSegments))
+	}
+	b.blocks = append(b.blocks, build(richText))
+	return b
+}
+
+func (b *BlockBuilder) fail(err error) *BlockBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf("block %d: %w", len(b.blocks)+1, err)
+	}
+	return b
+}
+
+// richTextObjects builds a rich_text array. Plain spans match newRichText.
+func richTextObjects(spans ...RichText) []map[string]interface{} {
+	richText := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		annotations := span.annotations()
+		for _, object := range newRichText(span.Content) {
+			if span.Link != "" {
+				object["text"].(map[string]interface{})["link"] = map[string]interface{}{"url": span.Link}
+			}
+			if annotations != nil {
+				object["annotations"] = annotations
+			}
+			richText = append(richText, object)
+		}
+	}
+	return richText
+}
+
+// annotations returns the styles set on r, or nil for plain text
+func (r RichText) annotations() map[string]interface{} {
+	annotations := map[string]interface{}{}
+	for name, set := range map[string]bool{
+		"bold":          r.Bold,
+		"italic":        r.Italic,
+		"strikethrough": r.Strikethrough,
+		"underline":     r.Underline,
+		"code":          r.Code,
+	} {
+		if set {
+			annotations[name] = true
+		}
+	}
+	if r.Color != "" {
+		annotations["color"] = r.Color
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// textBlock builds a block whose content is a single rich_text array
+func textBlock(blockType string, richText []map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": blockType,
+		blockType: map[string]interface{}{
+			"rich_text": richText,
+		},
+	}
+}
+
+func toDoBlock(checked bool, richText []map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "to_do",
+		"to_do": map[string]interface{}{
+			"rich_text": richText,
+			"checked":   checked,
+		},
+	}
+}
+
+func codeBlock(language string, richText []map[string]interface{}) map[string]interface{} {
+	// Notion requires a valid language, use "plain text" for empty languages
+	if language == "" {
+		language = "plain text"
+	}
+
+	return map[string]interface{}{
+		"type": "code",
+		"code": map[string]interface{}{
+			"rich_text": richText,
+			"language":  normalizeNotionLanguage(language),
+		},
+	}
+}
+
+func calloutBlock(emoji string, richText []map[string]interface{}) map[string]interface{} {
+	callout := map[string]interface{}{
+		"rich_text": richText,
+		"color":     "gray_background",
+	}
+	if emoji != "" {
+		callout["icon"] = map[string]interface{}{
+			"type":  "emoji",
+			"emoji": emoji,
+		}
+	}
+	return map[string]interface{}{
+		"type":    "callout",
+		"callout": callout,
+	}
+}
+
+func imageBlock(url string, caption []map[string]interface{}) map[string]interface{} {
+	image := map[string]interface{}{
+		"type": "external",
+		"external": map[string]interface{}{
+			"url": url,
+		},
+	}
+	if len(caption) > 0 {
+		image["caption"] = caption
+	}
+	return map[string]interface{}{
+		"type":  "image",
+		"image": image,
+	}
+}
@@ -0,0 +1,233 @@
+package sync
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBlockBuilder_MatchesConverterShapes(t *testing.T) {
+	text := func(content string) []map[string]interface{} {
+		return []map[string]interface{}{
+			{"type": "text", "text": map[string]interface{}{"content": content}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		builder *BlockBuilder
+		want    map[string]interface{}
+	}{
+		{
+			name:    "paragraph",
+			builder: NewBlockBuilder().Paragraph(Text("Hello")),
+			want: map[string]interface{}{
+				"type":      "paragraph",
+				"paragraph": map[string]interface{}{"rich_text": text("Hello")},
+			},
+		},
+		{
+			name:    "heading",
+			builder: NewBlockBuilder().Heading(2, Text("Title")),
+			want: map[string]interface{}{
+				"type":      "heading_2",
+				"heading_2": map[string]interface{}{"rich_text": text("Title")},
+			},
+		},
+		{
+			name:    "bulleted list item",
+			builder: NewBlockBuilder().BulletedListItem(Text("Item")),
+			want: map[string]interface{}{
+				"type":               "bulleted_list_item",
+				"bulleted_list_item": map[string]interface{}{"rich_text": text("Item")},
+			},
+		},
+		{
+			name:    "code",
+			builder: NewBlockBuilder().Code("go", "x := 1"),
+			want: map[string]interface{}{
+				"type": "code",
+				"code": map[string]interface{}{"rich_text": text("x := 1"), "language": "go"},
+			},
+		},
+		{
+			name:    "code without language",
+			builder: NewBlockBuilder().Code("", "plain"),
+			want: map[string]interface{}{
+				"type": "code",
+				"code": map[string]interface{}{"rich_text": text("plain"), "language": "plain text"},
+			},
+		},
+		{
+			name:    "callout",
+			builder: NewBlockBuilder().Callout("💡", Text("Tip")),
+			want: map[string]interface{}{
+				"type": "callout",
+				"callout": map[string]interface{}{
+					"rich_text": text("Tip"),
+					"color":     "gray_background",
+					"icon":      map[string]interface{}{"type": "emoji", "emoji": "💡"},
+				},
+			},
+		},
+		{
+			name:    "image with caption",
+			builder: NewBlockBuilder().Image("https://example.com/a.png", Text("Alt")),
+			want: map[string]interface{}{
+				"type": "image",
+				"image": map[string]interface{}{
+					"type":     "external",
+					"external": map[string]interface{}{"url": "https://example.com/a.png"},
+					"caption":  text("Alt"),
+				},
+			},
+		},
+		{
+			name:    "image without caption",
+			builder: NewBlockBuilder().Image("https://example.com/a.png"),
+			want: map[string]interface{}{
+				"type": "image",
+				"image": map[string]interface{}{
+					"type":     "external",
+					"external": map[string]interface{}{"url": "https://example.com/a.png"},
+				},
+			},
+		},
+		{
+			name:    "toggle",
+			builder: NewBlockBuilder().Toggle(Text("Details")),
+			want: map[string]interface{}{
+				"type":   "toggle",
+				"toggle": map[string]interface{}{"rich_text": text("Details")},
+			},
+		},
+		{
+			name:    "divider",
+			builder: NewBlockBuilder().Divider(),
+			want:    map[string]interface{}{"type": "divider", "divider": map[string]interface{}{}},
+		},
+		{
+			name:    "equation",
+			builder: NewBlockBuilder().Equation("E = mc^2"),
+			want: map[string]interface{}{
+				"type":     "equation",
+				"equation": map[string]interface{}{"expression": "E = mc^2"},
+			},
+		},
+		{
+			name:    "to-do",
+			builder: NewBlockBuilder().ToDo(true, Text("Done")),
+			want: map[string]interface{}{
+				"type":  "to_do",
+				"to_do": map[string]interface{}{"rich_text": text("Done"), "checked": true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := tt.builder.Blocks()
+			if err != nil {
+				t.Fatalf("Blocks() error = %v", err)
+			}
+			if len(blocks) != 1 {
+				t.Fatalf("got %d blocks, want 1", len(blocks))
+			}
+			assertSameJSON(t, blocks[0], tt.want)
+		})
+	}
+}
+
+func TestBlockBuilder_MatchesConverterHelpers(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *BlockBuilder
+		want    map[string]interface{}
+	}{
+		{"paragraph", NewBlockBuilder().Paragraph(Text("Hello")), createParagraphBlock("Hello")},
+		{"heading", NewBlockBuilder().Heading(3, Text("Title")), createHeadingBlock(5, "Title")},
+		{"code", NewBlockBuilder().Code("js", "let x"), createCodeBlock("let x", "js")},
+		{"callout", NewBlockBuilder().Callout("⚠️", Text("Careful")), createCalloutBlock("⚠️ Careful")},
+		{"image", NewBlockBuilder().Image("a.png"), createImageBlock("a.png", "")},
+		{"toggle", NewBlockBuilder().Toggle(Text("More")), createToggleBlock("More")},
+		{"divider", NewBlockBuilder().Divider(), createDividerBlock()},
+		{"equation", NewBlockBuilder().Equation("x^2"), createEquationBlock("x^2")},
+		{"long paragraph", NewBlockBuilder().Paragraph(Text(strings.Repeat("a", 4500))), createParagraphBlock(strings.Repeat("a", 4500))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := tt.builder.Blocks()
+			if err != nil {
+				t.Fatalf("Blocks() error = %v", err)
+			}
+			assertSameJSON(t, blocks[0], tt.want)
+		})
+	}
+}
+
+func TestBlockBuilder_RichTextStyles(t *testing.T) {
+	blocks, err := NewBlockBuilder().
+		Paragraph(Text("See "), RichText{Content: "docs", Link: "https://example.com", Bold: true, Color: "red"}).
+		Blocks()
+	if err != nil {
+		t.Fatalf("Blocks() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"type": "paragraph",
+		"paragraph": map[string]interface{}{
+			"rich_text": []map[string]interface{}{
+				{"type": "text", "text": map[string]interface{}{"content": "See "}},
+				{
+					"type":        "text",
+					"text":        map[string]interface{}{"content": "docs", "link": map[string]interface{}{"url": "https://example.com"}},
+					"annotations": map[string]interface{}{"bold": true, "color": "red"},
+				},
+			},
+		},
+	}
+	assertSameJSON(t, blocks[0], want)
+}
+
+func TestBlockBuilder_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *BlockBuilder
+		wantErr string
+	}{
+		{"heading level too high", NewBlockBuilder().Heading(4, Text("x")), "heading level"},
+		{"heading level too low", NewBlockBuilder().Heading(0, Text("x")), "heading level"},
+		{"empty image URL", NewBlockBuilder().Image(" "), "image URL"},
+		{"empty equation", NewBlockBuilder().Equation(""), "equation expression"},
+		{"too much text", NewBlockBuilder().Paragraph(Text(strings.Repeat("a", maxRichTextLength*maxRichTextSegments+1))), "rich text objects"},
+		{"error names the block", NewBlockBuilder().Paragraph(Text("ok")).Heading(9), "block 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := tt.builder.Divider().Blocks()
+			if err == nil {
+				t.Fatalf("Blocks() = %v, want error", blocks)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func assertSameJSON(t *testing.T, got, want map[string]interface{}) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("failed to marshal builder output: %v", err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to marshal expected block: %v", err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("block mismatch\n got: %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
@@ -2,91 +2,164 @@ package sync
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
+// Conflict describes a file whose markdown and Notion content differ
+type Conflict struct {
+	FilePath       string
+	LocalContent   string
+	RemoteContent  string
+	LocalModified  time.Time // Zero when unknown
+	RemoteModified time.Time // Zero when unknown
+}
+
+// Resolution is the side of a conflict that wins
+type Resolution int
+
+const (
+	// KeepLocal pushes the markdown file to Notion
+	KeepLocal Resolution = iota
+	// KeepRemote pulls the Notion page over the markdown file
+	KeepRemote
+)
+
+func (r Resolution) String() string {
+	if r == KeepRemote {
+		return "remote"
+	}
+	return "local"
+}
+
+// ErrConflictNeedsChoice is returned for a conflict only the user can
+// resolve when there is no one to ask; see WithNonInteractiveConflicts
+var ErrConflictNeedsChoice = errors.New("conflict needs an interactive choice")
+
 // ConflictResolver handles conflict resolution between local and remote content
 type ConflictResolver struct {
-	strategy string
+	strategy       string
+	in             io.Reader // Source of interactive choices
+	out            io.Writer // Destination for diffs and prompts
+	nonInteractive bool      // Skip conflicts that need a choice instead of prompting
 }
 
-// NewConflictResolver creates a new conflict resolver with the given strategy
+// NewConflictResolver creates a new conflict resolver with the given strategy:
+// local, remote, newer, or manual/diff for an interactive choice
 func NewConflictResolver(strategy string) *ConflictResolver {
 	return &ConflictResolver{
-		strategy: strategy,
+		strategy: strings.ToLower(strings.TrimSpace(strategy)),
+		in:       os.Stdin,
+		out:      os.Stdout,
 	}
 }
 
-// ResolveConflict resolves a conflict between local and remote content
-func (cr *ConflictResolver) ResolveConflict(localContent, remoteContent, filePath string) (string, error) {
+// IsInteractiveConflictStrategy reports whether strategy asks the user to
+// resolve every conflict. newer only asks when it can't tell which side is
+// newer.
+func IsInteractiveConflictStrategy(strategy string) bool {
+	switch strings.ToLower(strings.TrimSpace(strategy)) {
+	case "local", "markdown_wins", "remote", "notion_wins", "newer":
+		return false
+	}
+	return true
+}
+
+// Resolve decides which side of c wins. Interactive strategies return an
+// error when the user skips the file
+func (cr *ConflictResolver) Resolve(c Conflict) (Resolution, error) {
 	switch cr.strategy {
+	case "local", "markdown_wins":
+		return KeepLocal, nil
+	case "remote", "notion_wins":
+		return KeepRemote, nil
 	case "newer":
-		return cr.resolveByNewer(localContent, remoteContent)
-	case "notion_wins":
-		return remoteContent, nil
-	case "markdown_wins":
-		return localContent, nil
-	case "diff":
-		return cr.resolveByDiff(localContent, remoteContent, filePath)
+		return cr.resolveByNewer(c)
 	default:
-		return cr.resolveByDiff(localContent, remoteContent, filePath)
+		return cr.resolveByDiff(c.LocalContent, c.RemoteContent, c.FilePath)
+	}
+}
+
+// ResolveConflict resolves a conflict between local and remote content,
+// returning the winning content
+func (cr *ConflictResolver) ResolveConflict(localContent, remoteContent, filePath string) (string, error) {
+	resolution, err := cr.Resolve(Conflict{
+		FilePath:      filePath,
+		LocalContent:  localContent,
+		RemoteContent: remoteContent,
+	})
+	if err != nil {
+		return "", err
 	}
+	if resolution == KeepRemote {
+		return remoteContent, nil
+	}
+	return localContent, nil
 }
 
-// resolveByNewer resolves conflict by choosing the newer version
-// For now, we'll default to showing diff since we need timestamp comparison
-func (cr *ConflictResolver) resolveByNewer(localContent, remoteContent string) (string, error) {
-	// TODO: Implement timestamp comparison when available
-	// For now, fallback to diff resolution
-	return cr.resolveByDiff(localContent, remoteContent, "")
+// resolveByNewer keeps whichever side was modified last, asking the user
+// when either modification time is unknown or both are the same
+func (cr *ConflictResolver) resolveByNewer(c Conflict) (Resolution, error) {
+	if c.LocalModified.IsZero() || c.RemoteModified.IsZero() || c.LocalModified.Equal(c.RemoteModified) {
+		return cr.resolveByDiff(c.LocalContent, c.RemoteContent, c.FilePath)
+	}
+	if c.RemoteModified.After(c.LocalModified) {
+		return KeepRemote, nil
+	}
+	return KeepLocal, nil
 }
 
 // resolveByDiff shows a diff and lets the user choose
-func (cr *ConflictResolver) resolveByDiff(localContent, remoteContent, filePath string) (string, error) {
-	// Check if content is actually different
-	if localContent == remoteContent {
-		return localContent, nil
+func (cr *ConflictResolver) resolveByDiff(localContent, remoteContent, filePath string) (Resolution, error) {
+	// Check if content is actually different; line endings don't count
+	if !HasConflict(localContent, remoteContent) {
+		return KeepLocal, nil
+	}
+	if cr.nonInteractive {
+		return KeepLocal, fmt.Errorf("%w; set sync.conflict_resolution to local or remote to resolve it unattended", ErrConflictNeedsChoice)
 	}
 
-	fmt.Printf("\n🔄 Conflict detected for: %s\n", filePath)
-	fmt.Println("=" + strings.Repeat("=", 60) + "=")
+	fmt.Fprintf(cr.out, "\n🔄 Conflict detected for: %s\n", filePath)
+	fmt.Fprintln(cr.out, "="+strings.Repeat("=", 60)+"=")
 
 	// Show diff
 	if err := cr.showDiff(localContent, remoteContent); err != nil {
-		return "", fmt.Errorf("failed to show diff: %w", err)
+		return KeepLocal, fmt.Errorf("failed to show diff: %w", err)
 	}
 
 	// Prompt user for choice
-	fmt.Println("\nChoose resolution:")
-	fmt.Println("  [l] Keep local (markdown) version")
-	fmt.Println("  [r] Keep remote (Notion) version")
-	fmt.Println("  [s] Skip this file")
-	fmt.Print("\nYour choice [l/r/s]: ")
+	fmt.Fprintln(cr.out, "\nChoose resolution:")
+	fmt.Fprintln(cr.out, "  [l] Keep local (markdown) version")
+	fmt.Fprintln(cr.out, "  [r] Keep remote (Notion) version")
+	fmt.Fprintln(cr.out, "  [s] Skip this file")
+	fmt.Fprint(cr.out, "\nYour choice [l/r/s]: ")
 
-	reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(cr.in)
 	choice, err := reader.ReadString('\n')
 	if err != nil {
-		return "", fmt.Errorf("failed to read user input: %w", err)
+		return KeepLocal, fmt.Errorf("failed to read user input: %w", err)
 	}
 
 	choice = strings.TrimSpace(strings.ToLower(choice))
 	switch choice {
 	case "l", "local":
-		fmt.Println("✅ Using local version")
-		return localContent, nil
+		fmt.Fprintln(cr.out, "✅ Using local version")
+		return KeepLocal, nil
 	case "r", "remote":
-		fmt.Println("✅ Using remote version")
-		return remoteContent, nil
+		fmt.Fprintln(cr.out, "✅ Using remote version")
+		return KeepRemote, nil
 	case "s", "skip":
-		fmt.Println("⏭️  Skipping file")
-		return "", fmt.Errorf("user chose to skip file")
+		fmt.Fprintln(cr.out, "⏭️  Skipping file")
+		return KeepLocal, fmt.Errorf("user chose to skip file")
 	default:
-		fmt.Println("❌ Invalid choice, skipping file")
-		return "", fmt.Errorf("invalid user choice")
+		fmt.Fprintln(cr.out, "❌ Invalid choice, skipping file")
+		return KeepLocal, fmt.Errorf("invalid user choice")
 	}
 }
 
@@ -98,87 +171,88 @@ func (cr *ConflictResolver) showDiff(localContent, remoteContent string) error {
 	// Clean up for better readability
 	diffs = dmp.DiffCleanupSemantic(diffs)
 
-	printDiffHeader()
+	cr.printDiffHeader()
 
 	for _, diff := range diffs {
 		switch diff.Type {
 		case diffmatchpatch.DiffEqual:
-			printContextLines(diff.Text)
+			cr.printContextLines(diff.Text)
 		case diffmatchpatch.DiffDelete:
-			printDeletedLines(diff.Text)
+			cr.printDeletedLines(diff.Text)
 		case diffmatchpatch.DiffInsert:
-			printInsertedLines(diff.Text)
+			cr.printInsertedLines(diff.Text)
 		}
 	}
 
 	return nil
 }
 
-func printDiffHeader() {
-	fmt.Println("\nDifferences:")
-	fmt.Println("  + Added in remote (Notion)")
-	fmt.Println("  - Removed in remote (Notion)")
-	fmt.Println()
+func (cr *ConflictResolver) printDiffHeader() {
+	fmt.Fprintln(cr.out, "\nDifferences:")
+	fmt.Fprintln(cr.out, "  + Added in remote (Notion)")
+	fmt.Fprintln(cr.out, "  - Removed in remote (Notion)")
+	fmt.Fprintln(cr.out)
 }
 
-func printContextLines(text string) {
+func (cr *ConflictResolver) printContextLines(text string) {
 	lines := strings.Split(text, "\n")
 
 	if len(lines) > 6 {
-		printTruncatedContext(lines)
+		cr.printTruncatedContext(lines)
 	} else {
-		printFullContext(lines)
+		cr.printFullContext(lines)
 	}
 }
 
-func printTruncatedContext(lines []string) {
+func (cr *ConflictResolver) printTruncatedContext(lines []string) {
 	// Show first 2 lines
 	for i := 0; i < 2 && i < len(lines); i++ {
 		if lines[i] != "" {
-			fmt.Printf("   %s\n", lines[i])
+			fmt.Fprintf(cr.out, "   %s\n", lines[i])
 		}
 	}
 
 	// Show ellipsis if needed
 	if len(lines) > 4 {
-		fmt.Println("   ...")
+		fmt.Fprintln(cr.out, "   ...")
 	}
 
 	// Show last 2 lines
 	for i := len(lines) - 2; i < len(lines); i++ {
 		if i >= 0 && lines[i] != "" {
-			fmt.Printf("   %s\n", lines[i])
+			fmt.Fprintf(cr.out, "   %s\n", lines[i])
 		}
 	}
 }
 
-func printFullContext(lines []string) {
+func (cr *ConflictResolver) printFullContext(lines []string) {
 	for _, line := range lines {
 		if line != "" {
-			fmt.Printf("   %s\n", line)
+			fmt.Fprintf(cr.out, "   %s\n", line)
 		}
 	}
 }
 
-func printDeletedLines(text string) {
+func (cr *ConflictResolver) printDeletedLines(text string) {
 	lines := strings.Split(text, "\n")
 	for _, line := range lines {
 		if line != "" {
-			fmt.Printf(" - %s\n", line)
+			fmt.Fprintf(cr.out, " - %s\n", line)
 		}
 	}
 }
 
-func printInsertedLines(text string) {
+func (cr *ConflictResolver) printInsertedLines(text string) {
 	lines := strings.Split(text, "\n")
 	for _, line := range lines {
 		if line != "" {
-			fmt.Printf(" + %s\n", line)
+			fmt.Fprintf(cr.out, " + %s\n", line)
 		}
 	}
 }
 
-// HasConflict checks if there's a conflict between local and remote content
+// HasConflict checks if there's a conflict between local and remote content,
+// ignoring line ending and trailing whitespace differences
 func HasConflict(localContent, remoteContent string) bool {
-	return localContent != remoteContent
+	return NormalizeContent(localContent) != NormalizeContent(remoteContent)
 }
@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Resolutions recorded in the conflict log besides the winning side
+const conflictSkipped = "skipped"
+
+// ConflictLogEntry is one line of the conflict log: a conflict a
+// bidirectional sync ran into and how it was resolved
+type ConflictLogEntry struct {
+	Time            time.Time `json:"time"`
+	File            string    `json:"file"`              // Relative to the markdown root
+	Strategy        string    `json:"strategy"`          // sync.conflict_resolution
+	Resolution      string    `json:"resolution"`        // local, remote or skipped
+	Reason          string    `json:"reason,omitempty"`  // Why the conflict was skipped
+	LocalModified   time.Time `json:"local_modified"`    // Zero when unknown
+	RemoteModified  time.Time `json:"remote_modified"`   // Zero when unknown
+	LocalOnlyLines  int       `json:"local_only_lines"`  // Lines of the file Notion doesn't have
+	RemoteOnlyLines int       `json:"remote_only_lines"` // Lines of the page the file doesn't have
+}
+
+// newConflictLogEntry describes how c was resolved; err is the reason it
+// was skipped, if it was
+func newConflictLogEntry(c Conflict, strategy string, resolution Resolution, err error) ConflictLogEntry {
+	entry := ConflictLogEntry{
+		Time:           time.Now().UTC(),
+		File:           c.FilePath,
+		Strategy:       strategy,
+		Resolution:     resolution.String(),
+		LocalModified:  c.LocalModified,
+		RemoteModified: c.RemoteModified,
+	}
+	if err != nil {
+		entry.Resolution = conflictSkipped
+		entry.Reason = err.Error()
+	}
+	for _, line := range diffLines(NormalizeContent(c.LocalContent), NormalizeContent(c.RemoteContent)) {
+		switch line.op {
+		case '-':
+			entry.LocalOnlyLines++
+		case '+':
+			entry.RemoteOnlyLines++
+		}
+	}
+	return entry
+}
+
+// logConflict appends entry to the conflict log as a line of JSON. The sync
+// goes on when the log can't be written.
+func (e *engine) logConflict(entry ConflictLogEntry) {
+	path := e.config.Sync.ConflictLog
+	if path == "" {
+		return
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(e.config.Directories.MarkdownRoot, path)
+	}
+	entry.File = e.relPath(entry.File)
+
+	if err := appendJSONLine(path, entry); err != nil {
+		e.log().Warning("Failed to record the conflict in %s in %s: %v", entry.File, path, err)
+	}
+}
+
+// appendJSONLine appends v to the file at path as one line of JSON
+func appendJSONLine(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode log entry: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	return file.Close()
+}
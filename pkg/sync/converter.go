@@ -2,8 +2,10 @@ package sync
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 	"github.com/yuin/goldmark"
@@ -11,6 +13,7 @@ import (
 	"github.com/yuin/goldmark/extension"
 	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 type Converter interface {
@@ -18,32 +21,91 @@ type Converter interface {
 	BlocksToMarkdown(blocks []notion.Block) (string, error)
 }
 
-type converter struct{}
+type converter struct {
+	stats        *ConversionStats // Optional; counts converted blocks when set
+	preserveHTML bool             // Push unrecognized HTML blocks as raw HTML code blocks
+	flavor       string           // Markdown flavor BlocksToMarkdown writes
+	linkStyle    string           // How BlocksToMarkdown writes links
+	links        *linkReferences  // Reference links collected while converting a page
+	nested       bool             // Converting the blocks nested in a page's blocks
+}
+
+// Markdown flavors BlocksToMarkdown can write
+const (
+	FlavorCommonMark = "commonmark" // Markdown that pushes read back; the default
+	FlavorMDX        = "mdx"        // MDX for docs sites, with callouts and toggles as components
+)
+
+// Link styles BlocksToMarkdown can write
+const (
+	LinkStyleInline    = "inline"    // [text](url); the default
+	LinkStyleReference = "reference" // [text][1], with the URLs listed at the end of the page
+)
+
+// ConverterOptions changes how the converter handles content Notion has no
+// equivalent for
+type ConverterOptions struct {
+	Stats        *ConversionStats // Counts the blocks converted to markdown when set
+	PreserveHTML bool             // Keep unrecognized HTML blocks instead of dropping them
+	Flavor       string           // Markdown flavor to pull as; FlavorCommonMark when empty
+	LinkStyle    string           // How to write links; LinkStyleInline when empty
+}
 
 func NewConverter() Converter {
 	return &converter{}
 }
 
+// NewConverterWithStats returns a converter that tallies the blocks it
+// converts to markdown in stats
+func NewConverterWithStats(stats *ConversionStats) Converter {
+	return &converter{stats: stats}
+}
+
+// NewConverterWithOptions returns a converter configured by opts
+func NewConverterWithOptions(opts ConverterOptions) Converter {
+	return &converter{
+		stats:        opts.Stats,
+		preserveHTML: opts.PreserveHTML,
+		flavor:       strings.ToLower(strings.TrimSpace(opts.Flavor)),
+		linkStyle:    strings.ToLower(strings.TrimSpace(opts.LinkStyle)),
+	}
+}
+
 func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{}, error) {
 	// Pre-process content to extract math blocks and replace with placeholders
 	content, mathBlocks := c.extractMathBlocks(content)
 
-	// Parse markdown into AST with table extension
+	// Parse markdown into AST with table and footnote extensions
 	md := goldmark.New(
-		goldmark.WithExtensions(extension.Table),
+		goldmark.WithExtensions(extension.Table, extension.Footnote),
 	)
 	reader := text.NewReader([]byte(content))
 	doc := md.Parser().Parse(reader)
 
+	blocks, err := c.convertNodes(doc.FirstChild(), []byte(content), mathBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert markdown to blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// convertNodes converts first and the sibling nodes that follow it to Notion
+// blocks
+func (c *converter) convertNodes(first ast.Node, source []byte, mathBlocks []string) ([]map[string]interface{}, error) {
 	var blocks []map[string]interface{}
-	source := []byte(content)
 
 	// Walk the AST and convert nodes to Notion blocks
-	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+	visit := func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
 			return ast.WalkContinue, nil
 		}
 
+		if custom, ok := convertCustomNode(n, source); ok {
+			blocks = append(blocks, custom...)
+			return ast.WalkSkipChildren, nil
+		}
+
 		switch n.Kind() {
 		case ast.KindHeading:
 			heading := n.(*ast.Heading)
@@ -55,8 +117,10 @@ func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{},
 		case ast.KindParagraph:
 			paragraph := n.(*ast.Paragraph)
 			// Check if paragraph contains only an image
-			if imageBlock := c.extractImageFromParagraph(paragraph, source); imageBlock != nil {
-				blocks = append(blocks, imageBlock)
+			if imageBlock, ok := c.extractImageFromParagraph(paragraph, source); ok {
+				if imageBlock != nil {
+					blocks = append(blocks, imageBlock)
+				}
 			} else {
 				text := extractTextFromNode(paragraph, source)
 				if strings.TrimSpace(text) != "" {
@@ -85,23 +149,26 @@ func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{},
 			codeBlock := n.(*ast.CodeBlock)
 			text := extractCodeBlockContent(codeBlock, source)
 			language := extractLanguageFromCodeBlock(codeBlock, source)
-			block := createCodeBlock(text, language)
-			blocks = append(blocks, block)
+			caption, _ := extractCodeCaption(n.PreviousSibling(), source)
+			blocks = append(blocks, withCodeCaption(createCodeBlocks(text, language), caption)...)
 			return ast.WalkSkipChildren, nil
 
 		case ast.KindFencedCodeBlock:
 			fencedCodeBlock := n.(*ast.FencedCodeBlock)
 			text := extractFencedCodeBlockContent(fencedCodeBlock, source)
 			language := extractLanguageFromFencedCodeBlock(fencedCodeBlock, source)
+			caption, _ := extractCodeCaption(n.PreviousSibling(), source)
 
-			// Special handling for Mermaid diagrams - keep as code blocks but ensure proper language
-			if language == "mermaid" {
-				block := createCodeBlock(text, "mermaid")
-				blocks = append(blocks, block)
-			} else {
-				block := createCodeBlock(text, language)
-				blocks = append(blocks, block)
+			// Mermaid diagrams stay code blocks; Notion renders the mermaid
+			// language as a diagram and keeps the source
+			if isMermaid(language) {
+				language = mermaidLanguage
 			}
+			blocks = append(blocks, withCodeCaption(createCodeBlocks(text, language), caption)...)
+			return ast.WalkSkipChildren, nil
+
+		case east.KindFootnoteList:
+			blocks = append(blocks, convertFootnoteList(n.(*east.FootnoteList), source)...)
 			return ast.WalkSkipChildren, nil
 
 		case east.KindTable:
@@ -111,9 +178,10 @@ func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{},
 			return ast.WalkSkipChildren, nil
 
 		case ast.KindBlockquote:
-			blockquote := n.(*ast.Blockquote)
-			text := extractTextFromNode(blockquote, source)
-			block := createCalloutBlock(text)
+			block, err := c.convertBlockquote(n.(*ast.Blockquote), source, mathBlocks)
+			if err != nil {
+				return ast.WalkStop, err
+			}
 			blocks = append(blocks, block)
 			return ast.WalkSkipChildren, nil
 
@@ -124,10 +192,26 @@ func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{},
 
 		case ast.KindHTMLBlock:
 			htmlBlock := n.(*ast.HTMLBlock)
+			if _, ok := extractCodeCaption(n, source); ok {
+				// Applied to the code block that follows
+				return ast.WalkSkipChildren, nil
+			}
+			if isKeptPlaceholder(htmlBlock, source) {
+				// The block stays on the page when it is pushed
+				return ast.WalkSkipChildren, nil
+			}
+			if placeholder := extractPlaceholderBlock(htmlBlock, source); placeholder != nil {
+				blocks = append(blocks, placeholder)
+				return ast.WalkSkipChildren, nil
+			}
 			if toggleBlock := c.extractToggleFromHTML(htmlBlock, source); toggleBlock != nil {
 				blocks = append(blocks, toggleBlock)
 				return ast.WalkSkipChildren, nil
 			}
+			if c.preserveHTML && htmlBlock.HTMLBlockType != ast.HTMLBlockType2 {
+				blocks = append(blocks, createRawHTMLBlocks(htmlBlock, source)...)
+				return ast.WalkSkipChildren, nil
+			}
 
 		default:
 			// Check for math blocks (display math)
@@ -144,16 +228,54 @@ func (c *converter) MarkdownToBlocks(content string) ([]map[string]interface{},
 		}
 
 		return ast.WalkContinue, nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert markdown to blocks: %w", err)
 	}
 
+	for n := first; n != nil; n = n.NextSibling() {
+		if err := ast.Walk(n, visit); err != nil {
+			return nil, err
+		}
+	}
 	return blocks, nil
 }
 
 func (c *converter) BlocksToMarkdown(blocks []notion.Block) (string, error) {
+	// Nested blocks are converted by the same call, so only the outermost
+	// one restores footnotes and lists the page's reference links
+	if c.nested {
+		return c.blocksToMarkdown(blocks)
+	}
+
+	page := *c
+	page.nested = true
+	if c.linkStyle == LinkStyleReference {
+		page.links = &linkReferences{}
+	}
+
+	body, footnotes := splitFootnotes(blocks)
+	md, err := page.blocksToMarkdown(body)
+	if err != nil {
+		return "", err
+	}
+	if len(footnotes) > 0 {
+		if restored, ok := page.restoreFootnotes(md, footnotes); ok {
+			md = restored
+		} else if section, err := page.blocksToMarkdown(footnotes); err != nil {
+			return "", err
+		} else if md == "" {
+			md = section
+		} else {
+			md += "\n\n" + section
+		}
+	}
+
+	if page.links != nil {
+		md = page.links.appendTo(md)
+	}
+	return md, nil
+}
+
+// blocksToMarkdown converts blocks, writing links in the converter's style
+func (c *converter) blocksToMarkdown(blocks []notion.Block) (string, error) {
 	var md strings.Builder
 
 	// Track table state
@@ -161,7 +283,22 @@ func (c *converter) BlocksToMarkdown(blocks []notion.Block) (string, error) {
 		rows: [][]string{},
 	}
 
+	// Track where the last list ended, so a list of the same type that
+	// follows with nothing written in between isn't merged into it
+	lastListType := ""
+	lastListEnd := -1
+
 	for i, block := range blocks {
+		if isListItem(block.Type) && (i == 0 || blocks[i-1].Type != block.Type) &&
+			block.Type == lastListType && md.Len() == lastListEnd {
+			md.WriteString(c.comment(listSeparator) + "\n\n")
+		}
+
+		if writeCustomBlock(&md, &block) {
+			c.stats.record(block.Type, true)
+			continue
+		}
+
 		switch block.Type {
 		case "heading_1", "heading_2", "heading_3":
 			c.writeHeading(&md, &block)
@@ -169,11 +306,18 @@ func (c *converter) BlocksToMarkdown(blocks []notion.Block) (string, error) {
 		case "paragraph":
 			c.writeParagraph(&md, &block)
 
-		case "bulleted_list_item":
-			c.writeBulletedListItem(&md, &block)
-
-		case "numbered_list_item":
-			c.writeNumberedListItem(&md, &block)
+		case "bulleted_list_item", "numbered_list_item":
+			if block.Type == "bulleted_list_item" {
+				c.writeBulletedListItem(&md, &block)
+			} else {
+				c.writeNumberedListItem(&md, &block)
+			}
+			// A blank line ends the list; without one, a following paragraph
+			// would continue the last item
+			if i == len(blocks)-1 || blocks[i+1].Type != block.Type {
+				md.WriteString("\n")
+				lastListType, lastListEnd = block.Type, md.Len()
+			}
 
 		case "code":
 			c.writeCodeBlock(&md, &block)
@@ -193,6 +337,9 @@ func (c *converter) BlocksToMarkdown(blocks []notion.Block) (string, error) {
 		case "image":
 			c.writeImage(&md, &block)
 
+		case "file", "pdf":
+			c.writeFile(&md, &block)
+
 		case "callout":
 			c.writeCallout(&md, &block)
 
@@ -204,12 +351,47 @@ func (c *converter) BlocksToMarkdown(blocks []notion.Block) (string, error) {
 
 		case "equation":
 			c.writeEquation(&md, &block)
+
+		case "child_page":
+			c.writeChildPage(&md, &block)
+
+		case "breadcrumb", "table_of_contents":
+			md.WriteString(c.comment(formatPlaceholder(block.Type)) + "\n\n")
+
+		case "synced_block":
+			if block.IsSyncedReference() {
+				md.WriteString(c.comment(formatSyncedReference(block.SyncedBlock.SyncedFrom.BlockID)) + "\n\n")
+			}
+
+		case "unsupported":
+			md.WriteString(c.comment(formatUnsupportedBlock(block.ID)) + "\n\n")
+
+		case "template":
+			md.WriteString(c.comment(formatTemplateButton(block.ID)) + "\n\n")
+
+		case "child_database":
+			// Exported to CSV by the engine
+
+		default:
+			// No markdown for this block type, so it is dropped
+			c.stats.record(block.Type, false)
+			continue
 		}
+		c.stats.record(block.Type, true)
 	}
 
 	return strings.TrimSpace(md.String()), nil
 }
 
+// listSeparator goes between two lists of the same type that Notion keeps
+// apart, since markdown would otherwise read them as one list. Comments are
+// dropped on push.
+const listSeparator = "<!-- -->"
+
+func isListItem(blockType string) bool {
+	return blockType == "bulleted_list_item" || blockType == "numbered_list_item"
+}
+
 type tableTracker struct {
 	inTable   bool
 	rows      [][]string
@@ -223,17 +405,17 @@ func (c *converter) writeHeading(md *strings.Builder, block *notion.Block) {
 	switch block.Type {
 	case "heading_1":
 		if block.Heading1 != nil {
-			text = extractPlainTextFromRichText(block.Heading1.RichText)
+			text = c.richText(block.Heading1.RichText)
 			prefix = "# "
 		}
 	case "heading_2":
 		if block.Heading2 != nil {
-			text = extractPlainTextFromRichText(block.Heading2.RichText)
+			text = c.richText(block.Heading2.RichText)
 			prefix = "## "
 		}
 	case "heading_3":
 		if block.Heading3 != nil {
-			text = extractPlainTextFromRichText(block.Heading3.RichText)
+			text = c.richText(block.Heading3.RichText)
 			prefix = "### "
 		}
 	}
@@ -245,7 +427,7 @@ func (c *converter) writeHeading(md *strings.Builder, block *notion.Block) {
 
 func (c *converter) writeParagraph(md *strings.Builder, block *notion.Block) {
 	if block.Paragraph != nil {
-		text := extractPlainTextFromRichText(block.Paragraph.RichText)
+		text := c.richText(block.Paragraph.RichText)
 		if strings.TrimSpace(text) != "" {
 			md.WriteString(text + "\n\n")
 		}
@@ -254,15 +436,38 @@ func (c *converter) writeParagraph(md *strings.Builder, block *notion.Block) {
 
 func (c *converter) writeBulletedListItem(md *strings.Builder, block *notion.Block) {
 	if block.BulletedListItem != nil {
-		text := extractPlainTextFromRichText(block.BulletedListItem.RichText)
+		text := c.richText(block.BulletedListItem.RichText)
 		md.WriteString("- " + text + "\n")
+		c.writeListItemChildren(md, block.Children, "  ")
 	}
 }
 
 func (c *converter) writeNumberedListItem(md *strings.Builder, block *notion.Block) {
 	if block.NumberedListItem != nil {
-		text := extractPlainTextFromRichText(block.NumberedListItem.RichText)
+		text := c.richText(block.NumberedListItem.RichText)
 		md.WriteString("1. " + text + "\n")
+		c.writeListItemChildren(md, block.Children, "   ")
+	}
+}
+
+// writeListItemChildren writes a list item's nested blocks indented under it
+func (c *converter) writeListItemChildren(md *strings.Builder, children []notion.Block, indent string) {
+	content, err := c.BlocksToMarkdown(children)
+	if err != nil || content == "" {
+		return
+	}
+
+	// Anything but a nested list needs a blank line, or it would continue
+	// the item's own text
+	if !isListItem(children[0].Type) {
+		md.WriteString("\n")
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" {
+			md.WriteString("\n")
+		} else {
+			md.WriteString(indent + line + "\n")
+		}
 	}
 }
 
@@ -270,17 +475,51 @@ func (c *converter) writeCodeBlock(md *strings.Builder, block *notion.Block) {
 	if block.Code != nil {
 		code := extractPlainTextFromRichText(block.Code.RichText)
 		language := block.Code.Language
+		// Diagrams come back as a mermaid fence however Notion reports the
+		// language, so they render again on the next push
+		if isMermaid(language) {
+			language = mermaidLanguage
+		}
+		caption := extractPlainTextFromRichText(block.Code.Caption)
+		if language == "html" && caption == rawHTMLCaption {
+			md.WriteString(code + "\n\n")
+			return
+		}
+		if caption != "" {
+			md.WriteString(c.comment(formatCodeCaption(caption)) + "\n")
+		}
 		md.WriteString("```" + language + "\n" + code + "\n```\n\n")
 	}
 }
 
 func (c *converter) writeQuote(md *strings.Builder, block *notion.Block) {
 	if block.Quote != nil {
-		text := extractPlainTextFromRichText(block.Quote.RichText)
-		md.WriteString("> " + text + "\n\n")
+		c.writeBlockquote(md, c.richText(block.Quote.RichText), block.Children)
 	}
 }
 
+// writeBlockquote writes text followed by the rendered children as a single
+// blockquote
+func (c *converter) writeBlockquote(md *strings.Builder, text string, children []notion.Block) {
+	var paragraphs []string
+	if text != "" {
+		paragraphs = append(paragraphs, text)
+	}
+	if children, err := c.BlocksToMarkdown(children); err == nil && children != "" {
+		paragraphs = append(paragraphs, children)
+	}
+
+	// Prefix every line so child blocks stay inside the blockquote
+	for _, line := range strings.Split(strings.Join(paragraphs, "\n\n"), "\n") {
+		if line == "" {
+			md.WriteString(">\n")
+		} else {
+			md.WriteString("> " + line + "\n")
+		}
+	}
+	md.WriteString("\n")
+}
+
 func (c *converter) startTable(state *tableTracker, block *notion.Block) {
 	state.inTable = true
 	state.rows = [][]string{}
@@ -295,6 +534,9 @@ func (c *converter) processTableRow(state *tableTracker, block *notion.Block, in
 		var row []string
 		for _, cell := range block.TableRow.Cells {
 			cellText := extractPlainTextFromRichText(cell)
+			if c.flavor == FlavorMDX {
+				cellText = escapeJSX(cellText)
+			}
 			row = append(row, cellText)
 		}
 		state.rows = append(state.rows, row)
@@ -323,7 +565,7 @@ func (c *converter) writeMarkdownTable(md *strings.Builder, rows [][]string, has
 	for i, row := range rows {
 		md.WriteString("| ")
 		for j, cell := range row {
-			md.WriteString(cell)
+			md.WriteString(escapeMarkdownTableCell(cell))
 			if j < len(row)-1 {
 				md.WriteString(" | ")
 			}
@@ -359,11 +601,12 @@ func extractTextFromNode(node ast.Node, source []byte) string {
 		if entering {
 			switch n.Kind() {
 			case ast.KindText:
-				textNode := n.(*ast.Text)
-				buf.Write(textNode.Segment.Value(source))
+				buf.Write(textValue(n.(*ast.Text), source))
 			case ast.KindString:
 				stringNode := n.(*ast.String)
 				buf.Write(stringNode.Value)
+			case east.KindFootnoteLink:
+				buf.WriteString(footnoteMarker(n.(*east.FootnoteLink).Index))
 			}
 		}
 		return ast.WalkContinue, nil
@@ -372,106 +615,339 @@ func extractTextFromNode(node ast.Node, source []byte) string {
 	return buf.String()
 }
 
-func createHeadingBlock(level int, text string) map[string]interface{} {
-	blockType := fmt.Sprintf("heading_%d", level)
-	if level > 3 {
-		blockType = "heading_3"
+// textValue returns the literal text of a text node, resolving backslash
+// escapes such as \* to the character itself. Code span text is raw and
+// keeps its backslashes
+func textValue(node *ast.Text, source []byte) []byte {
+	value := node.Segment.Value(source)
+	if node.IsRaw() {
+		return value
 	}
+	return util.UnescapePunctuations(value)
+}
 
-	return map[string]interface{}{
-		"type": blockType,
-		blockType: map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": text,
-					},
-				},
+// Notion limits each rich text object to 2000 characters and each rich text
+// array to 100 objects
+const (
+	maxRichTextLength   = 2000
+	maxRichTextSegments = 100
+)
+
+// newRichText builds a rich_text array for plain content, splitting content
+// longer than Notion's limit into several text objects
+func newRichText(content string) []map[string]interface{} {
+	segments := splitText(content, maxRichTextLength)
+	richText := make([]map[string]interface{}, 0, len(segments))
+	for _, segment := range segments {
+		richText = append(richText, map[string]interface{}{
+			"type": "text",
+			"text": map[string]interface{}{
+				"content": segment,
 			},
-		},
+		})
 	}
+	return richText
 }
 
-func createParagraphBlock(text string) map[string]interface{} {
-	return map[string]interface{}{
-		"type": "paragraph",
-		"paragraph": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": text,
-					},
-				},
-			},
-		},
+// splitText splits s into chunks of at most limit characters without breaking
+// runes. Characters are counted in UTF-16 code units, as Notion counts them.
+func splitText(s string, limit int) []string {
+	var chunks []string
+	start, units := 0, 0
+	for i, r := range s {
+		n := runeLength(r)
+		if units+n > limit {
+			chunks = append(chunks, s[start:i])
+			start, units = i, 0
+		}
+		units += n
+	}
+	return append(chunks, s[start:])
+}
+
+// textLength returns the length of s in UTF-16 code units
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeLength(r)
+	}
+	return n
+}
+
+// runeLength returns the number of UTF-16 code units needed for r
+func runeLength(r rune) int {
+	if r > 0xFFFF {
+		return 2
+	}
+	return 1
+}
+
+func createHeadingBlock(level int, text string) map[string]interface{} {
+	// Markdown's h4 to h6 have no Notion equivalent
+	if level > 3 {
+		level = 3
 	}
+	return textBlock(fmt.Sprintf("heading_%d", level), newRichText(text))
+}
+
+func createParagraphBlock(text string) map[string]interface{} {
+	return textBlock("paragraph", newRichText(text))
 }
 
 func createCodeBlock(text, language string) map[string]interface{} {
-	// Notion requires a valid language, use "plain text" for empty languages
-	if language == "" {
-		language = "plain text"
+	return codeBlock(language, newRichText(text))
+}
+
+// createCodeBlocks creates one code block, or several sequential ones when
+// the text needs more rich text objects than a single block allows. Blocks are
+// split on line boundaries where possible.
+func createCodeBlocks(text, language string) []map[string]interface{} {
+	const maxBlockLength = maxRichTextLength * maxRichTextSegments
+
+	var blocks []map[string]interface{}
+	var chunk strings.Builder
+	chunkLength := 0
+	flush := func() {
+		if chunk.Len() > 0 {
+			blocks = append(blocks, createCodeBlock(strings.TrimSuffix(chunk.String(), "\n"), language))
+			chunk.Reset()
+			chunkLength = 0
+		}
 	}
 
-	// Validate language against Notion's supported languages
-	language = normalizeNotionLanguage(language)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for _, part := range splitText(line, maxBlockLength) {
+			length := textLength(part)
+			if chunkLength+length > maxBlockLength {
+				flush()
+			}
+			chunk.WriteString(part)
+			chunkLength += length
+		}
+	}
+	flush()
 
+	if len(blocks) == 0 {
+		blocks = append(blocks, createCodeBlock(text, language))
+	}
+	return blocks
+}
+
+// withCodeCaption sets caption on the first of a run of code blocks
+func withCodeCaption(blocks []map[string]interface{}, caption string) []map[string]interface{} {
+	if caption != "" && len(blocks) > 0 {
+		blocks[0]["code"].(map[string]interface{})["caption"] = newRichText(caption)
+	}
+	return blocks
+}
+
+// rawHTMLCaption marks html code blocks holding an HTML block the converter
+// kept rather than dropped. Pulls write their code back as raw HTML.
+const rawHTMLCaption = "Raw HTML"
+
+// createRawHTMLBlocks keeps an HTML block with no Notion equivalent, such
+// as an <iframe> embed, as html code blocks. Every block is marked, so HTML
+// split over several blocks comes back whole.
+func createRawHTMLBlocks(htmlBlock *ast.HTMLBlock, source []byte) []map[string]interface{} {
+	var html strings.Builder
+	for i := 0; i < htmlBlock.Lines().Len(); i++ {
+		line := htmlBlock.Lines().At(i)
+		html.Write(line.Value(source))
+	}
+	if htmlBlock.HasClosure() {
+		html.Write(htmlBlock.ClosureLine.Value(source))
+	}
+
+	blocks := createCodeBlocks(strings.TrimRight(html.String(), "\n"), "html")
+	for _, block := range blocks {
+		block["code"].(map[string]interface{})["caption"] = newRichText(rawHTMLCaption)
+	}
+	return blocks
+}
+
+// Code block captions round-trip as an HTML comment on the line before the
+// fence, e.g. <!-- caption: main.go -->, which markdown renderers hide
+const codeCaptionPrefix = "caption:"
+
+// formatCodeCaption renders a code block caption as its marker comment
+func formatCodeCaption(caption string) string {
+	caption = strings.Join(strings.Fields(caption), " ")
+	// A literal --> would end the comment early
+	caption = strings.ReplaceAll(caption, "-->", "--\u200b>")
+	return "<!-- " + codeCaptionPrefix + " " + caption + " -->"
+}
+
+// extractCodeCaption returns the caption held by n when it is a caption
+// comment directly followed by a code block
+func extractCodeCaption(n ast.Node, source []byte) (string, bool) {
+	htmlBlock, ok := n.(*ast.HTMLBlock)
+	if !ok {
+		return "", false
+	}
+
+	next := n.NextSibling()
+	if next == nil || (next.Kind() != ast.KindFencedCodeBlock && next.Kind() != ast.KindCodeBlock) {
+		return "", false
+	}
+
+	inner, ok := htmlComment(htmlBlock, source)
+	if !ok || !strings.HasPrefix(inner, codeCaptionPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(inner, codeCaptionPrefix)), true
+}
+
+// htmlComment returns the trimmed text inside an HTML block that is a single
+// comment
+func htmlComment(htmlBlock *ast.HTMLBlock, source []byte) (string, bool) {
+	var html strings.Builder
+	for i := 0; i < htmlBlock.Lines().Len(); i++ {
+		line := htmlBlock.Lines().At(i)
+		html.Write(line.Value(source))
+	}
+	if htmlBlock.HasClosure() {
+		html.Write(htmlBlock.ClosureLine.Value(source))
+	}
+
+	comment := strings.TrimSpace(html.String())
+	if !strings.HasPrefix(comment, "<!--") || !strings.HasSuffix(comment, "-->") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment, "<!--"), "-->")), true
+}
+
+// placeholderBlockTypes are Notion blocks with no markdown equivalent. Pulls
+// write them as <!-- notion:type --> comments that pushes turn back into the
+// block, so they survive a round trip
+var placeholderBlockTypes = map[string]bool{
+	"breadcrumb":        true,
+	"table_of_contents": true,
+}
+
+const placeholderPrefix = "notion:"
+
+func formatPlaceholder(blockType string) string {
+	return "<!-- " + placeholderPrefix + blockType + " -->"
+}
+
+// extractPlaceholderBlock returns the block for a placeholder comment written
+// by formatPlaceholder
+func extractPlaceholderBlock(htmlBlock *ast.HTMLBlock, source []byte) map[string]interface{} {
+	inner, ok := htmlComment(htmlBlock, source)
+	if !ok || !strings.HasPrefix(inner, placeholderPrefix) {
+		return nil
+	}
+
+	blockType := strings.TrimPrefix(inner, placeholderPrefix)
+	if !placeholderBlockTypes[blockType] {
+		return nil
+	}
 	return map[string]interface{}{
-		"type": "code",
-		"code": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": text,
-					},
-				},
-			},
-			"language": language,
-		},
+		"type":    blockType,
+		blockType: map[string]interface{}{},
 	}
 }
 
-func createCalloutBlock(text string) map[string]interface{} {
-	// Extract emoji if present at the beginning of the text
-	emoji := ""
-	content := text
-
-	// Simple check for emoji at start (could be enhanced)
-	if len(text) > 0 {
-		// Check if text starts with common callout indicators
-		if strings.HasPrefix(text, "💡 ") || strings.HasPrefix(text, "⚠️ ") ||
-			strings.HasPrefix(text, "❗ ") || strings.HasPrefix(text, "📝 ") {
-			emoji = text[:strings.Index(text, " ")]
-			content = text[len(emoji)+1:]
-		}
-	}
-
-	calloutBlock := map[string]interface{}{
-		"type": "callout",
-		"callout": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": content,
-					},
-				},
-			},
-			"color": "gray_background",
-		},
+// syncedReferenceType marks a synced block shown from another page. Pulls
+// write it as <!-- notion:synced_block <source id> --> and pushes skip it,
+// since the Notion client keeps the reference in place
+const syncedReferenceType = "synced_block"
+
+func formatSyncedReference(sourceID string) string {
+	return "<!-- " + placeholderPrefix + syncedReferenceType + " " + sourceID + " -->"
+}
+
+// unsupportedBlockType marks a block the Notion API doesn't expose. Pulls
+// write it as <!-- notion:unsupported <block id> --> so the file shows where
+// it is, and pushes skip it, since the Notion client leaves it in place
+const unsupportedBlockType = "unsupported"
+
+func formatUnsupportedBlock(blockID string) string {
+	return "<!-- " + placeholderPrefix + unsupportedBlockType + " " + blockID + " -->"
+}
+
+// templateButtonType marks a template button. Pulls write it as
+// <!-- notion:template <block id> --> instead of the template's content, and
+// pushes skip it, since the Notion client leaves it in place
+const templateButtonType = "template"
+
+func formatTemplateButton(blockID string) string {
+	return "<!-- " + placeholderPrefix + templateButtonType + " " + blockID + " -->"
+}
+
+// isKeptPlaceholder reports whether a comment stands for a block a push
+// leaves on the page: a synced reference, an unsupported block or a template
+// button
+func isKeptPlaceholder(htmlBlock *ast.HTMLBlock, source []byte) bool {
+	inner, ok := htmlComment(htmlBlock, source)
+	if !ok || !strings.HasPrefix(inner, placeholderPrefix) {
+		return false
+	}
+	fields := strings.Fields(strings.TrimPrefix(inner, placeholderPrefix))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case syncedReferenceType, unsupportedBlockType, templateButtonType:
+		return true
 	}
+	return false
+}
+
+// convertBlockquote converts a blockquote to a callout. Its first paragraph
+// is the callout's text and everything after it the callout's children. A
+// leading emoji becomes the callout's icon, and a GitHub alert marker such
+// as [!NOTE] sets the icon and color.
+func (c *converter) convertBlockquote(blockquote *ast.Blockquote, source []byte, mathBlocks []string) (map[string]interface{}, error) {
+	text := ""
+	rest := blockquote.FirstChild()
+	if paragraph, ok := rest.(*ast.Paragraph); ok {
+		text = extractTextFromNode(paragraph, source)
+		rest = paragraph.NextSibling()
+	}
+
+	block := createCalloutBlock(text)
+	children, err := c.convertNodes(rest, source, mathBlocks)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) > 0 {
+		block["callout"].(map[string]interface{})["children"] = children
+	}
+	return block, nil
+}
+
+// calloutAlerts are the GitHub alert markers a blockquote may start with,
+// with the icon and color of the callout each becomes
+var calloutAlerts = map[string]struct{ emoji, color string }{
+	"NOTE":      {"ℹ️", "blue_background"},
+	"TIP":       {"💡", "green_background"},
+	"IMPORTANT": {"❗", "purple_background"},
+	"WARNING":   {"⚠️", "yellow_background"},
+	"CAUTION":   {"🛑", "red_background"},
+}
+
+var calloutAlertPattern = regexp.MustCompile(`^\[!([A-Za-z]+)\]\s*`)
 
-	if emoji != "" {
-		calloutBlock["callout"].(map[string]interface{})["icon"] = map[string]interface{}{
-			"type":  "emoji",
-			"emoji": emoji,
+func createCalloutBlock(text string) map[string]interface{} {
+	color := ""
+	if match := calloutAlertPattern.FindStringSubmatch(text); match != nil {
+		if alert, ok := calloutAlerts[strings.ToUpper(match[1])]; ok {
+			text = strings.TrimPrefix(text, match[0])
+			if _, _, hasEmoji := splitLeadingEmoji(text); !hasEmoji {
+				text = alert.emoji + " " + text
+			}
+			color = alert.color
 		}
 	}
 
-	return calloutBlock
+	// A leading emoji becomes the callout's icon
+	emoji, content, _ := splitLeadingEmoji(text)
+	block := calloutBlock(emoji, newRichText(content))
+	if color != "" {
+		block["callout"].(map[string]interface{})["color"] = color
+	}
+	return block
 }
 
 func createDividerBlock() map[string]interface{} {
@@ -482,44 +958,15 @@ func createDividerBlock() map[string]interface{} {
 }
 
 func createImageBlock(url, caption string) map[string]interface{} {
-	imageBlock := map[string]interface{}{
-		"type": "image",
-		"image": map[string]interface{}{
-			"type": "external",
-			"external": map[string]interface{}{
-				"url": url,
-			},
-		},
-	}
-
+	var richText []map[string]interface{}
 	if caption != "" {
-		imageBlock["image"].(map[string]interface{})["caption"] = []map[string]interface{}{
-			{
-				"type": "text",
-				"text": map[string]interface{}{
-					"content": caption,
-				},
-			},
-		}
+		richText = newRichText(caption)
 	}
-
-	return imageBlock
+	return imageBlock(url, richText)
 }
 
 func createToggleBlock(summary string) map[string]interface{} {
-	return map[string]interface{}{
-		"type": "toggle",
-		"toggle": map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": summary,
-					},
-				},
-			},
-		},
-	}
+	return textBlock("toggle", newRichText(summary))
 }
 
 func createEquationBlock(expression string) map[string]interface{} {
@@ -531,6 +978,14 @@ func createEquationBlock(expression string) map[string]interface{} {
 	}
 }
 
+// mermaidLanguage is the code block language Notion renders as a diagram
+const mermaidLanguage = "mermaid"
+
+// isMermaid reports whether a code block language names a mermaid diagram
+func isMermaid(language string) bool {
+	return strings.EqualFold(strings.TrimSpace(language), mermaidLanguage)
+}
+
 func normalizeNotionLanguage(lang string) string {
 	// Map common language names to Notion's expected values
 	langMap := map[string]string{
@@ -599,20 +1054,7 @@ func (c *converter) convertListToBlocksWithDepth(list *ast.List, source []byte,
 			// Create indentation for nested lists by adding spaces
 			indent := strings.Repeat("  ", depth)
 
-			block := map[string]interface{}{
-				"type": blockType,
-				blockType: map[string]interface{}{
-					"rich_text": []map[string]interface{}{
-						{
-							"type": "text",
-							"text": map[string]interface{}{
-								"content": indent + text,
-							},
-						},
-					},
-				},
-			}
-			blocks = append(blocks, block)
+			blocks = append(blocks, textBlock(blockType, newRichText(indent+text)))
 
 			// Process nested lists
 			for nestedChild := listItem.FirstChild(); nestedChild != nil; nestedChild = nestedChild.NextSibling() {
@@ -643,11 +1085,12 @@ func (c *converter) extractListItemText(listItem *ast.ListItem, source []byte) s
 
 		switch n.Kind() {
 		case ast.KindText:
-			textNode := n.(*ast.Text)
-			text.Write(textNode.Segment.Value(source))
+			text.Write(textValue(n.(*ast.Text), source))
 		case ast.KindString:
 			stringNode := n.(*ast.String)
 			text.Write(stringNode.Value)
+		case east.KindFootnoteLink:
+			text.WriteString(footnoteMarker(n.(*east.FootnoteLink).Index))
 		}
 		return ast.WalkContinue, nil
 	})
@@ -665,6 +1108,239 @@ func extractPlainTextFromRichText(richTexts []notion.RichText) string {
 	return text.String()
 }
 
+// richText renders rich text as inline markdown in the converter's flavor
+// and link style
+func (c *converter) richText(richTexts []notion.RichText) string {
+	return extractMarkdownFromRichText(richTexts, c.flavor == FlavorMDX, c.link)
+}
+
+// link writes a markdown link in the converter's link style
+func (c *converter) link(text, url string) string {
+	if c.links == nil {
+		return "[" + text + "](" + url + ")"
+	}
+	return "[" + text + "][" + c.links.label(url) + "]"
+}
+
+// linkReferences numbers the URLs a page links to, in the order they first
+// appear, so every link to the same URL shares one reference definition
+type linkReferences struct {
+	urls   []string
+	labels map[string]string
+}
+
+// label returns the reference label for url, numbering it if it is new
+func (r *linkReferences) label(url string) string {
+	if label, ok := r.labels[url]; ok {
+		return label
+	}
+	if r.labels == nil {
+		r.labels = make(map[string]string)
+	}
+	r.urls = append(r.urls, url)
+	label := strconv.Itoa(len(r.urls))
+	r.labels[url] = label
+	return label
+}
+
+// appendTo ends md with a definition for every reference label used in it
+func (r *linkReferences) appendTo(md string) string {
+	if len(r.urls) == 0 {
+		return md
+	}
+
+	var out strings.Builder
+	out.WriteString(md + "\n\n")
+	for i, url := range r.urls {
+		if strings.ContainsAny(url, " <>") {
+			url = "<" + url + ">"
+		}
+		fmt.Fprintf(&out, "[%d]: %s\n", i+1, url)
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// extractMarkdownFromRichText renders rich text as inline markdown: inline
+// equations become $...$, annotated spans get code, bold, italic and
+// strikethrough markers, and linked spans become markdown links written by
+// link. mdx escapes the characters MDX reads as JSX too
+func extractMarkdownFromRichText(richTexts []notion.RichText, mdx bool, link func(text, url string) string) string {
+	var text strings.Builder
+
+	for _, rt := range richTexts {
+		atLineStart := text.Len() == 0 || strings.HasSuffix(text.String(), "\n")
+		text.WriteString(formatRichTextSegment(rt, atLineStart, mdx, link))
+	}
+
+	return text.String()
+}
+
+// formatRichTextSegment renders a single rich text object as inline markdown.
+// atLineStart says whether the segment begins a line
+func formatRichTextSegment(rt notion.RichText, atLineStart, mdx bool, link func(text, url string) string) string {
+	if rt.Type == "equation" && rt.Equation != nil {
+		return "$" + rt.Equation.Expression + "$"
+	}
+
+	content := rt.PlainText
+	switch {
+	case content == "":
+	case rt.Annotations != nil && rt.Annotations.Code:
+		content = formatInlineCode(content)
+	default:
+		content = escapeMarkdown(content, atLineStart, mdx)
+		if rt.Annotations != nil {
+			if rt.Annotations.Bold {
+				content = wrapEmphasis(content, "**")
+			}
+			if rt.Annotations.Italic {
+				content = wrapEmphasis(content, "*")
+			}
+			if rt.Annotations.Strikethrough {
+				content = wrapEmphasis(content, "~~")
+			}
+		}
+	}
+
+	if rt.Text != nil && rt.Text.Link != nil && rt.Text.Link.URL != "" && strings.TrimSpace(content) != "" {
+		content = link(content, rt.Text.Link.URL)
+	}
+
+	return content
+}
+
+// escapeMarkdown backslash-escapes characters in plain text that markdown
+// would otherwise read as syntax, so literal asterisks, backticks and the
+// like survive a pull and the next push. atLineStart says whether s begins
+// a line, where block markers such as # and - are significant. mdx also
+// escapes braces and every <, which MDX reads as expressions and JSX
+func escapeMarkdown(s string, atLineStart, mdx bool) string {
+	runes := []rune(s)
+	var out strings.Builder
+	lineStart := atLineStart
+
+	for i, r := range runes {
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		escape := false
+		switch r {
+		case '\\', '*', '`', '[':
+			escape = true
+		case '_':
+			// Underscores inside a word never start emphasis
+			escape = !isWordRune(prev) || !isWordRune(next)
+		case '<':
+			escape = mdx || unicode.IsLetter(next) || next == '/' || next == '!' || next == '?'
+		case '{', '}':
+			escape = mdx
+		}
+		if lineStart && !escape {
+			escape = isLineStartMarker(runes[i:])
+		}
+
+		if escape {
+			out.WriteRune('\\')
+		}
+		out.WriteRune(r)
+
+		switch {
+		case r == '\n':
+			lineStart = true
+		case r == ' ' || r == '\t':
+			// Indentation keeps the following marker at the line start
+		case lineStart && unicode.IsDigit(r) && !escape:
+			// An ordered list marker's delimiter follows its digits
+		default:
+			lineStart = false
+		}
+	}
+
+	return out.String()
+}
+
+// isLineStartMarker reports whether line, the rest of a line from its first
+// non-blank character, starts with markdown block syntax: a heading,
+// blockquote, list marker, or a run of - or = that makes a thematic break or
+// setext heading underline
+func isLineStartMarker(line []rune) bool {
+	end := len(line)
+	for i, r := range line {
+		if r == '\n' {
+			end = i
+			break
+		}
+	}
+	line = line[:end]
+	if len(line) == 0 {
+		return false
+	}
+
+	followedByBlank := len(line) == 1 || line[1] == ' ' || line[1] == '\t'
+	switch r := line[0]; {
+	case r == '#' || r == '>':
+		return true
+	case r == '-' || r == '+':
+		if followedByBlank {
+			return true
+		}
+		return r == '-' && strings.Trim(string(line), "- \t") == ""
+	case r == '=':
+		return strings.Trim(string(line), "= \t") == ""
+	case r == '.' || r == ')':
+		// Reached only after a run of digits at the line start
+		return followedByBlank
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wrapEmphasis wraps content in marker, keeping surrounding whitespace outside
+// the markers since markdown doesn't allow emphasis to start or end with it
+func wrapEmphasis(content, marker string) string {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return content
+	}
+
+	start := strings.Index(content, trimmed)
+	return content[:start] + marker + trimmed + marker + content[start+len(trimmed):]
+}
+
+// formatInlineCode wraps content in a backtick fence one longer than the
+// longest backtick run inside it, per the CommonMark code span rules
+func formatInlineCode(content string) string {
+	longestRun, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longestRun {
+				longestRun = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	fence := strings.Repeat("`", longestRun+1)
+
+	// A leading or trailing backtick would merge with the fence, so pad with
+	// a space which CommonMark strips back off
+	if strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") {
+		content = " " + content + " "
+	}
+
+	return fence + content + fence
+}
+
 func extractLanguageFromCodeBlock(_ *ast.CodeBlock, _ []byte) string {
 	// For indented code blocks, there's typically no language info
 	return ""
@@ -750,15 +1426,7 @@ func (c *converter) convertTableToBlocks(table *east.Table, source []byte) []map
 			var cells [][]map[string]interface{}
 			for cell := tableHeader.FirstChild(); cell != nil; cell = cell.NextSibling() {
 				if tableCell, ok := cell.(*east.TableCell); ok {
-					cellText := extractTextFromNode(tableCell, source)
-					cellRichText := []map[string]interface{}{
-						{
-							"type": "text",
-							"text": map[string]interface{}{
-								"content": strings.TrimSpace(cellText),
-							},
-						},
-					}
+					cellRichText := newRichText(tableCellText(tableCell, source))
 					cells = append(cells, cellRichText)
 				}
 			}
@@ -809,15 +1477,7 @@ func (c *converter) convertTableRow(row *east.TableRow, source []byte) map[strin
 	var cells [][]map[string]interface{}
 	for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
 		if tableCell, ok := cell.(*east.TableCell); ok {
-			cellText := extractTextFromNode(tableCell, source)
-			cellRichText := []map[string]interface{}{
-				{
-					"type": "text",
-					"text": map[string]interface{}{
-						"content": strings.TrimSpace(cellText),
-					},
-				},
-			}
+			cellRichText := newRichText(tableCellText(tableCell, source))
 			cells = append(cells, cellRichText)
 		}
 	}
@@ -830,6 +1490,47 @@ func (c *converter) convertTableRow(row *east.TableRow, source []byte) map[strin
 	}
 }
 
+// tableCellText returns the text of a table cell, turning <br> tags back
+// into the line breaks writeMarkdownTable replaced
+func tableCellText(cell *east.TableCell, source []byte) string {
+	var buf strings.Builder
+
+	_ = ast.Walk(cell, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			buf.Write(textValue(node, source))
+		case *ast.String:
+			buf.Write(node.Value)
+		case *east.FootnoteLink:
+			buf.WriteString(footnoteMarker(node.Index))
+		case *ast.RawHTML:
+			var tag strings.Builder
+			for i := 0; i < node.Segments.Len(); i++ {
+				segment := node.Segments.At(i)
+				tag.Write(segment.Value(source))
+			}
+			if isLineBreakTag(tag.String()) {
+				buf.WriteString("\n")
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(buf.String())
+}
+
+// isLineBreakTag reports whether tag is a <br> in any of its common spellings
+func isLineBreakTag(tag string) bool {
+	switch strings.ToLower(strings.ReplaceAll(tag, " ", "")) {
+	case "<br>", "<br/>":
+		return true
+	}
+	return false
+}
+
 func (c *converter) writeImage(md *strings.Builder, block *notion.Block) {
 	if block.Image != nil {
 		var url string
@@ -850,20 +1551,33 @@ func (c *converter) writeImage(md *strings.Builder, block *notion.Block) {
 
 func (c *converter) writeCallout(md *strings.Builder, block *notion.Block) {
 	if block.Callout != nil {
-		text := extractPlainTextFromRichText(block.Callout.RichText)
+		text := c.richText(block.Callout.RichText)
+		if c.flavor == FlavorMDX {
+			open := fmt.Sprintf("<Callout type=%q", mdxCalloutType(block.Callout.Color))
+			if block.Callout.Icon != nil && block.Callout.Icon.Emoji != "" {
+				open += fmt.Sprintf(" emoji=%q", block.Callout.Icon.Emoji)
+			}
+			c.writeMDXComponent(md, open+">", "</Callout>", text, block.Children)
+			return
+		}
+
 		icon := ""
 		if block.Callout.Icon != nil && block.Callout.Icon.Emoji != "" {
 			icon = block.Callout.Icon.Emoji + " "
 		}
 
 		// Convert callout to blockquote with icon
-		fmt.Fprintf(md, "> %s%s\n\n", icon, text)
+		c.writeBlockquote(md, icon+text, block.Children)
 	}
 }
 
 func (c *converter) writeToggle(md *strings.Builder, block *notion.Block) {
 	if block.Toggle != nil {
-		text := extractPlainTextFromRichText(block.Toggle.RichText)
+		text := c.richText(block.Toggle.RichText)
+		if c.flavor == FlavorMDX {
+			c.writeMDXComponent(md, "<Details>\n<summary>"+text+"</summary>", "</Details>", "", block.Children)
+			return
+		}
 		// Use HTML details/summary for toggle functionality
 		md.WriteString("<details>\n<summary>" + text + "</summary>\n\n")
 		// Note: Child blocks would be added here if we supported nested blocks
@@ -871,28 +1585,163 @@ func (c *converter) writeToggle(md *strings.Builder, block *notion.Block) {
 	}
 }
 
+// mdxCalloutTypes maps Notion callout colors to the types of the <Callout>
+// component docs sites such as Nextra provide
+var mdxCalloutTypes = map[string]string{
+	"blue":   "info",
+	"yellow": "warning",
+	"orange": "warning",
+	"red":    "error",
+}
+
+func mdxCalloutType(color string) string {
+	if calloutType, ok := mdxCalloutTypes[strings.TrimSuffix(color, "_background")]; ok {
+		return calloutType
+	}
+	return "default"
+}
+
+// writeMDXComponent writes text and the rendered children between a JSX
+// component's open and close tags. The blank lines around them make MDX
+// parse them as markdown rather than JSX text.
+func (c *converter) writeMDXComponent(md *strings.Builder, open, close, text string, children []notion.Block) {
+	md.WriteString(open + "\n\n")
+	if text != "" {
+		md.WriteString(text + "\n\n")
+	}
+	if children, err := c.BlocksToMarkdown(children); err == nil && children != "" {
+		md.WriteString(children + "\n\n")
+	}
+	md.WriteString(close + "\n\n")
+}
+
+// comment returns an HTML comment written by the converter as an MDX
+// comment under the mdx flavor, which has no HTML comments
+func (c *converter) comment(html string) string {
+	if c.flavor != FlavorMDX {
+		return html
+	}
+	inner := strings.TrimSuffix(strings.TrimPrefix(html, "<!--"), "-->")
+	return "{/*" + strings.ReplaceAll(inner, "*/", "*\u200b/") + "*/}"
+}
+
+// escapeJSX backslash-escapes the characters MDX reads as expressions and JSX
+func escapeJSX(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		if r == '{' || r == '}' || r == '<' {
+			out.WriteRune('\\')
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
 func (c *converter) writeBookmark(md *strings.Builder, block *notion.Block) {
 	if block.Bookmark != nil {
 		caption := extractPlainTextFromRichText(block.Bookmark.Caption)
 		if caption != "" {
-			fmt.Fprintf(md, "[%s](%s)\n\n", caption, block.Bookmark.URL)
+			md.WriteString(c.link(caption, block.Bookmark.URL) + "\n\n")
+		} else if c.flavor == FlavorMDX {
+			// MDX has no autolinks
+			md.WriteString(c.link(block.Bookmark.URL, block.Bookmark.URL) + "\n\n")
 		} else {
 			fmt.Fprintf(md, "<%s>\n\n", block.Bookmark.URL)
 		}
 	}
 }
 
+// writeFile links to the file of a file or pdf block, labelled with its
+// caption or else its name
+func (c *converter) writeFile(md *strings.Builder, block *notion.Block) {
+	file := block.File
+	if block.Type == "pdf" {
+		file = block.PDF
+	}
+	if file == nil || file.URL() == "" {
+		return
+	}
+
+	label := extractPlainTextFromRichText(file.Caption)
+	if label == "" {
+		label = file.Name
+	}
+	if label == "" {
+		label = urlFileName(file.URL())
+	}
+	md.WriteString(c.link(label, file.URL()) + "\n\n")
+}
+
+// writeChildPage links to a child page in Notion. Pulls replace child_page
+// blocks with links to the child's markdown file before conversion when
+// the file is known
+func (c *converter) writeChildPage(md *strings.Builder, block *notion.Block) {
+	if block.ChildPage == nil || block.ID == "" {
+		return
+	}
+	md.WriteString(c.link(block.ChildPage.Title, "https://www.notion.so/"+strings.ReplaceAll(block.ID, "-", "")) + "\n\n")
+}
+
 func (c *converter) writeEquation(md *strings.Builder, block *notion.Block) {
 	if block.Equation != nil {
-		fmt.Fprintf(md, "$$%s$$\n\n", block.Equation.Expression)
+		fmt.Fprintf(md, "$$%s$$\n\n", escapeMathDollars(block.Equation.Expression))
+	}
+}
+
+// escapeMathDollars escapes any unescaped dollar sign in a LaTeX expression so
+// it can't be mistaken for the closing $$ delimiter. \$ is LaTeX's own literal
+// dollar, so the escaped form renders the same in Notion.
+func escapeMathDollars(expression string) string {
+	var out strings.Builder
+	escaped := false
+
+	for _, r := range expression {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '$':
+			out.WriteRune('\\')
+		}
+		out.WriteRune(r)
 	}
+
+	// A trailing lone backslash would escape the closing delimiter
+	if escaped {
+		out.WriteRune(' ')
+	}
+
+	return out.String()
 }
 
-func (c *converter) extractImageFromParagraph(paragraph *ast.Paragraph, source []byte) map[string]interface{} {
+// findClosingMathDelimiter returns the index of the first $$ in s that isn't
+// preceded by an escaping backslash, or -1 if there is none
+func findClosingMathDelimiter(s string) int {
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '$' && s[i+1] == '$' {
+			return i
+		}
+	}
+	return -1
+}
+
+// extractImageFromParagraph returns the image block for a paragraph holding
+// only an image, and whether it does. Notion only takes images by URL, so
+// the block is nil for an image embedded as a data URI, e.g. by a pull with
+// --inline-images; FindUnsupportedFeatures reports those.
+func (c *converter) extractImageFromParagraph(paragraph *ast.Paragraph, source []byte) (map[string]interface{}, bool) {
 	// Check if paragraph contains only an image
 	if paragraph.ChildCount() == 1 {
 		if image, ok := paragraph.FirstChild().(*ast.Image); ok {
 			url := string(image.Destination)
+			if isDataURI(url) {
+				return nil, true
+			}
 			caption := string(image.Title)
 
 			// If no title, try to extract alt text
@@ -900,10 +1749,15 @@ func (c *converter) extractImageFromParagraph(paragraph *ast.Paragraph, source [
 				caption = extractTextFromNode(image, source)
 			}
 
-			return createImageBlock(url, caption)
+			return createImageBlock(url, caption), true
 		}
 	}
-	return nil
+	return nil, false
+}
+
+// isDataURI reports whether url embeds its content rather than pointing at it
+func isDataURI(url string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(url)), "data:")
 }
 
 func (c *converter) extractToggleFromHTML(htmlBlock *ast.HTMLBlock, source []byte) map[string]interface{} {
@@ -943,8 +1797,8 @@ func (c *converter) extractMathBlocks(content string) (string, []string) {
 			break
 		}
 
-		// Find the closing $$
-		end := strings.Index(result[start+2:], "$$")
+		// Find the closing $$, skipping escaped dollars inside the expression
+		end := findClosingMathDelimiter(result[start+2:])
 		if end == -1 {
 			break
 		}
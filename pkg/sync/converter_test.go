@@ -1,10 +1,17 @@
 package sync
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestConverter_MarkdownToBlocks(t *testing.T) {
@@ -472,6 +479,12 @@ func TestConverter_MarkdownToBlocks(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "image embedded as a data URI is dropped",
+			markdown: "![Logo](data:image/png;base64,iVBORw0KGgo=)",
+			want:     nil,
+			wantErr:  false,
+		},
 		{
 			name:     "blockquote as callout",
 			markdown: "> This is a callout",
@@ -702,7 +715,7 @@ func TestConverter_BlocksToMarkdown(t *testing.T) {
 					},
 				},
 			},
-			want:    "- First item\n1. Second item",
+			want:    "- First item\n\n1. Second item",
 			wantErr: false,
 		},
 		{
@@ -798,6 +811,27 @@ func TestConverter_BlocksToMarkdown(t *testing.T) {
 			want:    "> 💡 This is a callout",
 			wantErr: false,
 		},
+		{
+			name: "callout with bold text and inline equation",
+			blocks: []notion.Block{
+				{
+					Type: "callout",
+					Callout: &notion.CalloutBlock{
+						RichText: []notion.RichText{
+							{PlainText: "Energy is "},
+							{PlainText: "conserved ", Annotations: &notion.Annotations{Bold: true}},
+							{Type: "equation", PlainText: "E=mc^2", Equation: &notion.EquationBlock{Expression: "E=mc^2"}},
+						},
+						Icon: &notion.CalloutIcon{
+							Type:  "emoji",
+							Emoji: "💡",
+						},
+					},
+				},
+			},
+			want:    "> 💡 Energy is **conserved** $E=mc^2$",
+			wantErr: false,
+		},
 		{
 			name: "toggle block",
 			blocks: []notion.Block{
@@ -922,3 +956,1252 @@ func TestNormalizeNotionLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatInlineCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain code", "fmt.Println", "`fmt.Println`"},
+		{"single backtick", "a`b", "``a`b``"},
+		{"double backtick", "a``b", "```a``b```"},
+		{"leading backtick", "`x", "`` `x ``"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatInlineCode(tt.content); got != tt.want {
+				t.Errorf("formatInlineCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_SpecialCharactersRoundTrip(t *testing.T) {
+	converter := NewConverter()
+
+	t.Run("code span containing double backtick", func(t *testing.T) {
+		blocks := []notion.Block{
+			{
+				Type: "paragraph",
+				Paragraph: &notion.RichTextBlock{
+					RichText: []notion.RichText{
+						{PlainText: "Use "},
+						{PlainText: "a``b", Annotations: &notion.Annotations{Code: true}},
+						{PlainText: " here"},
+					},
+				},
+			},
+		}
+
+		md, err := converter.BlocksToMarkdown(blocks)
+		if err != nil {
+			t.Fatalf("BlocksToMarkdown() error = %v", err)
+		}
+		if want := "Use ```a``b``` here"; md != want {
+			t.Fatalf("BlocksToMarkdown() = %q, want %q", md, want)
+		}
+
+		pushed, err := converter.MarkdownToBlocks(md)
+		if err != nil {
+			t.Fatalf("MarkdownToBlocks() error = %v", err)
+		}
+		if len(pushed) != 1 || pushed[0]["type"] != "paragraph" {
+			t.Fatalf("expected a single paragraph block, got %v", pushed)
+		}
+	})
+
+	t.Run("literal markdown characters", func(t *testing.T) {
+		texts := []string{
+			"Use *stars*, a #hashtag and `ticks` literally",
+			"# not a heading",
+			"1. not a list, 2 * 3 = 6",
+			"- not a bullet",
+			`C:\path\file with [brackets] and <tags>`,
+			"snake_case stays, _underscores_ don't",
+		}
+
+		for _, want := range texts {
+			blocks := []notion.Block{{
+				Type:      "paragraph",
+				Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{{PlainText: want}}},
+			}}
+
+			md, err := converter.BlocksToMarkdown(blocks)
+			if err != nil {
+				t.Fatalf("BlocksToMarkdown() error = %v", err)
+			}
+
+			pushed, err := converter.MarkdownToBlocks(md)
+			if err != nil {
+				t.Fatalf("MarkdownToBlocks() error = %v", err)
+			}
+			if len(pushed) != 1 || pushed[0]["type"] != "paragraph" {
+				t.Fatalf("%q: expected a single paragraph block from %q, got %v", want, md, pushed)
+			}
+			if got := strings.Join(richTextSegments(t, pushed[0]), ""); got != want {
+				t.Errorf("round trip of %q via %q = %q", want, md, got)
+			}
+		}
+	})
+
+	t.Run("escapes in pushed markdown", func(t *testing.T) {
+		pushed, err := converter.MarkdownToBlocks("\\*not italic\\*, \\#1 and `a\\*b`\n")
+		if err != nil {
+			t.Fatalf("MarkdownToBlocks() error = %v", err)
+		}
+		if got, want := strings.Join(richTextSegments(t, pushed[0]), ""), "*not italic*, #1 and a\\*b"; got != want {
+			t.Errorf("paragraph text = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("equation containing literal dollar", func(t *testing.T) {
+		blocks := []notion.Block{
+			{
+				Type:     "equation",
+				Equation: &notion.EquationBlock{Expression: "x = 5$"},
+			},
+		}
+
+		md, err := converter.BlocksToMarkdown(blocks)
+		if err != nil {
+			t.Fatalf("BlocksToMarkdown() error = %v", err)
+		}
+		if want := `$$x = 5\$$$`; md != want {
+			t.Fatalf("BlocksToMarkdown() = %q, want %q", md, want)
+		}
+
+		pushed, err := converter.MarkdownToBlocks(md)
+		if err != nil {
+			t.Fatalf("MarkdownToBlocks() error = %v", err)
+		}
+		if len(pushed) != 1 || pushed[0]["type"] != "equation" {
+			t.Fatalf("expected a single equation block, got %v", pushed)
+		}
+		equation := pushed[0]["equation"].(map[string]interface{})
+		if got := equation["expression"]; got != `x = 5\$` {
+			t.Errorf("expression = %q, want %q", got, `x = 5\$`)
+		}
+	})
+}
+
+// richTextSegments returns the content of each rich text object in a block
+// after a JSON round trip, as it would be sent to the API
+func richTextSegments(t *testing.T, block map[string]interface{}) []string {
+	t.Helper()
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		t.Fatalf("failed to marshal block: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal block: %v", err)
+	}
+
+	blockType := decoded["type"].(string)
+	richText := decoded[blockType].(map[string]interface{})["rich_text"].([]interface{})
+
+	var segments []string
+	for _, rt := range richText {
+		segments = append(segments, rt.(map[string]interface{})["text"].(map[string]interface{})["content"].(string))
+	}
+	return segments
+}
+
+func TestConverter_LongParagraphSplitsRichText(t *testing.T) {
+	converter := NewConverter()
+	paragraph := strings.Repeat("abcde ", 833) + "ab" // 5000 characters
+
+	blocks, err := converter.MarkdownToBlocks(paragraph)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %d", len(blocks))
+	}
+
+	segments := richTextSegments(t, blocks[0])
+	if len(segments) != 3 {
+		t.Errorf("expected 3 segments, got %d", len(segments))
+	}
+	for i, segment := range segments {
+		if n := utf8.RuneCountInString(segment); n > maxRichTextLength {
+			t.Errorf("segment %d has %d characters, want at most %d", i, n, maxRichTextLength)
+		}
+	}
+	if joined := strings.Join(segments, ""); joined != paragraph {
+		t.Errorf("segments do not reassemble the paragraph")
+	}
+}
+
+func TestConverter_LongCodeBlockSplitsBlocks(t *testing.T) {
+	converter := NewConverter()
+
+	// One line longer than a single segment, then enough lines to exceed
+	// the rich text objects one block may hold
+	longLine := strings.Repeat("x", 4500)
+	lines := []string{longLine}
+	for i := 0; i < 2500; i++ {
+		lines = append(lines, fmt.Sprintf("line %04d %s", i, strings.Repeat("y", 90)))
+	}
+	code := strings.Join(lines, "\n")
+
+	blocks, err := converter.MarkdownToBlocks("```go\n" + code + "\n```\n")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 code blocks, got %d", len(blocks))
+	}
+
+	var parts []string
+	for i, block := range blocks {
+		if block["type"] != "code" {
+			t.Fatalf("block %d has type %v, want code", i, block["type"])
+		}
+		if lang := block["code"].(map[string]interface{})["language"]; lang != "go" {
+			t.Errorf("block %d has language %v, want go", i, lang)
+		}
+
+		segments := richTextSegments(t, block)
+		if len(segments) > maxRichTextSegments {
+			t.Errorf("block %d has %d segments, want at most %d", i, len(segments), maxRichTextSegments)
+		}
+		for j, segment := range segments {
+			if n := utf8.RuneCountInString(segment); n > maxRichTextLength {
+				t.Errorf("block %d segment %d has %d characters, want at most %d", i, j, n, maxRichTextLength)
+			}
+		}
+		parts = append(parts, strings.Join(segments, ""))
+	}
+
+	// Blocks are split on a line boundary
+	if joined := strings.Join(parts, "\n"); joined != code {
+		t.Errorf("code blocks do not reassemble the original code")
+	}
+}
+
+func TestSplitText_CountsUTF16Units(t *testing.T) {
+	// Each emoji takes two UTF-16 code units
+	chunks := splitText(strings.Repeat("😀", 1500), maxRichTextLength)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if n := utf8.RuneCountInString(chunks[0]); n != 1000 {
+		t.Errorf("first chunk has %d runes, want 1000", n)
+	}
+	if got := splitText("", maxRichTextLength); len(got) != 1 || got[0] != "" {
+		t.Errorf("splitText(\"\") = %q, want one empty chunk", got)
+	}
+}
+
+func TestConverter_CalloutRichTextFromAPI(t *testing.T) {
+	// A callout as returned by the Notion API, with a link and an inline equation
+	data := `{
+		"type": "callout",
+		"callout": {
+			"rich_text": [
+				{"type": "text", "text": {"content": "See "}, "plain_text": "See "},
+				{"type": "text", "text": {"content": "the docs", "link": {"url": "https://example.com"}},
+				 "annotations": {"italic": true}, "plain_text": "the docs"},
+				{"type": "text", "text": {"content": " for "}, "plain_text": " for "},
+				{"type": "equation", "equation": {"expression": "\\sum_i x_i"}, "plain_text": "\\sum_i x_i"}
+			],
+			"icon": {"type": "emoji", "emoji": "📝"}
+		}
+	}`
+
+	var block notion.Block
+	if err := json.Unmarshal([]byte(data), &block); err != nil {
+		t.Fatalf("failed to unmarshal block: %v", err)
+	}
+
+	got, err := NewConverter().BlocksToMarkdown([]notion.Block{block})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+
+	want := "> 📝 See [*the docs*](https://example.com) for $\\sum_i x_i$"
+	if strings.TrimSpace(got) != want {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapEmphasis(t *testing.T) {
+	tests := []struct {
+		content, marker, want string
+	}{
+		{"bold", "**", "**bold**"},
+		{" padded ", "**", " **padded** "},
+		{"   ", "*", "   "},
+		{"gone", "~~", "~~gone~~"},
+	}
+
+	for _, tt := range tests {
+		if got := wrapEmphasis(tt.content, tt.marker); got != tt.want {
+			t.Errorf("wrapEmphasis(%q, %q) = %q, want %q", tt.content, tt.marker, got, tt.want)
+		}
+	}
+}
+
+func TestConverter_CodeCaptionRoundTrip(t *testing.T) {
+	data := `{
+		"type": "code",
+		"code": {
+			"rich_text": [{"type": "text", "text": {"content": "package main"}, "plain_text": "package main"}],
+			"caption": [{"type": "text", "text": {"content": "main.go"}, "plain_text": "main.go"}],
+			"language": "go"
+		}
+	}`
+
+	var block notion.Block
+	if err := json.Unmarshal([]byte(data), &block); err != nil {
+		t.Fatalf("failed to unmarshal block: %v", err)
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown([]notion.Block{block})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+
+	wantMarkdown := "<!-- caption: main.go -->\n```go\npackage main\n```"
+	if strings.TrimSpace(md) != wantMarkdown {
+		t.Fatalf("BlocksToMarkdown() = %q, want %q", md, wantMarkdown)
+	}
+
+	blocks, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %d: %v", len(blocks), blocks)
+	}
+
+	code := blocks[0]["code"].(map[string]interface{})
+	if got := richTextContent(code["rich_text"]); got != "package main" {
+		t.Errorf("code = %q, want %q", got, "package main")
+	}
+	if got := richTextContent(code["caption"]); got != "main.go" {
+		t.Errorf("caption = %q, want %q", got, "main.go")
+	}
+}
+
+func TestConverter_CodeCaptionOnlyBeforeCode(t *testing.T) {
+	md := "<!-- caption: orphan -->\n\nJust a paragraph.\n\n```go\nx := 1\n```\n"
+
+	blocks, err := NewConverter().MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d: %v", len(blocks), blocks)
+	}
+
+	code := blocks[1]["code"].(map[string]interface{})
+	if _, ok := code["caption"]; ok {
+		t.Errorf("expected no caption on code block not preceded by a caption comment, got %v", code["caption"])
+	}
+}
+
+// richTextContent joins the text content of rich text built by newRichText
+func TestConverter_PlaceholderBlocksRoundTrip(t *testing.T) {
+	data := `[
+		{"type": "table_of_contents", "table_of_contents": {"color": "default"}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Intro"}, "plain_text": "Intro"}]}},
+		{"type": "breadcrumb", "breadcrumb": {}}
+	]`
+
+	var blocks []notion.Block
+	if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+		t.Fatalf("failed to unmarshal blocks: %v", err)
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+
+	wantMarkdown := "<!-- notion:table_of_contents -->\n\nIntro\n\n<!-- notion:breadcrumb -->"
+	if md != wantMarkdown {
+		t.Fatalf("BlocksToMarkdown() = %q, want %q", md, wantMarkdown)
+	}
+
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+
+	var types []string
+	for _, block := range pushed {
+		types = append(types, block["type"].(string))
+	}
+	if want := []string{"table_of_contents", "paragraph", "breadcrumb"}; strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("pushed block types = %v, want %v", types, want)
+	}
+	if _, ok := pushed[0]["table_of_contents"].(map[string]interface{}); !ok {
+		t.Errorf("table_of_contents block has no content object: %v", pushed[0])
+	}
+
+	// Other comments are still dropped
+	pushed, err = c.MarkdownToBlocks("<!-- notion:unknown_block -->\n")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 0 {
+		t.Errorf("expected unknown placeholder to be dropped, got %v", pushed)
+	}
+}
+
+func richTextContent(v interface{}) string {
+	var sb strings.Builder
+	items, _ := v.([]map[string]interface{})
+	for _, item := range items {
+		sb.WriteString(item["text"].(map[string]interface{})["content"].(string))
+	}
+	return sb.String()
+}
+
+func TestRegisterBlockWriter_OverridesCallout(t *testing.T) {
+	RegisterBlockWriter("callout", func(md *strings.Builder, block *notion.Block) bool {
+		if block.Callout == nil || block.Callout.Icon == nil || block.Callout.Icon.Emoji != "⚠️" {
+			return false
+		}
+		md.WriteString("{{< warning >}}" + extractPlainTextFromRichText(block.Callout.RichText) + "{{< /warning >}}\n\n")
+		return true
+	})
+	defer RegisterBlockWriter("callout", nil)
+
+	callout := func(emoji, text string) notion.Block {
+		return notion.Block{
+			Type: "callout",
+			Callout: &notion.CalloutBlock{
+				RichText: []notion.RichText{{PlainText: text}},
+				Icon:     &notion.CalloutIcon{Type: "emoji", Emoji: emoji},
+			},
+		}
+	}
+
+	c := NewConverter()
+	got, err := c.BlocksToMarkdown([]notion.Block{callout("⚠️", "Careful"), callout("💡", "Tip")})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+
+	// The custom writer handles the warning; the tip falls back to the default
+	want := "{{< warning >}}Careful{{< /warning >}}\n\n> 💡 Tip"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", got, want)
+	}
+
+	// Removing the writer restores the built-in output
+	RegisterBlockWriter("callout", nil)
+	got, err = c.BlocksToMarkdown([]notion.Block{callout("⚠️", "Careful")})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if got != "> ⚠️ Careful" {
+		t.Errorf("BlocksToMarkdown() after unregistering = %q, want %q", got, "> ⚠️ Careful")
+	}
+}
+
+func TestRegisterNodeConverter_OverridesThematicBreak(t *testing.T) {
+	RegisterNodeConverter(ast.KindThematicBreak, func(node ast.Node, source []byte) ([]map[string]interface{}, bool) {
+		return []map[string]interface{}{createParagraphBlock("* * *")}, true
+	})
+	defer RegisterNodeConverter(ast.KindThematicBreak, nil)
+
+	blocks, err := NewConverter().MarkdownToBlocks("Before\n\n---\n\nAfter\n")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(blocks))
+	}
+	if blocks[1]["type"] != "paragraph" {
+		t.Errorf("expected custom paragraph in place of the divider, got %v", blocks[1]["type"])
+	}
+}
+
+func TestConverter_TableCellEscapingRoundTrip(t *testing.T) {
+	cell := func(text string) []notion.RichText {
+		return []notion.RichText{{PlainText: text}}
+	}
+	blocks := []notion.Block{
+		{Type: "table", Table: &notion.TableBlock{TableWidth: 2, HasColumnHeader: true}},
+		{Type: "table_row", TableRow: &notion.TableRowBlock{Cells: [][]notion.RichText{cell("Expr"), cell("Notes")}}},
+		{Type: "table_row", TableRow: &notion.TableRowBlock{Cells: [][]notion.RichText{cell("a|b"), cell("first line\nsecond line")}}},
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if !strings.Contains(md, `| a\|b | first line<br>second line |`) {
+		t.Fatalf("expected escaped table row, got:\n%s", md)
+	}
+
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 3 {
+		t.Fatalf("expected a table and 2 rows, got %d blocks", len(pushed))
+	}
+	if width := pushed[0]["table"].(map[string]interface{})["table_width"]; width != 2 {
+		t.Errorf("table_width = %v, want 2", width)
+	}
+
+	cells := pushed[2]["table_row"].(map[string]interface{})["cells"].([][]map[string]interface{})
+	if len(cells) != 2 {
+		t.Fatalf("expected 2 cells, got %d", len(cells))
+	}
+	want := []string{"a|b", "first line\nsecond line"}
+	for i, w := range want {
+		got := cells[i][0]["text"].(map[string]interface{})["content"]
+		if got != w {
+			t.Errorf("cell %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestConverter_UnsupportedBlockRoundTrip(t *testing.T) {
+	data := `[
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Before"}, "plain_text": "Before"}]}},
+		{"id": "form-1", "type": "unsupported", "unsupported": {}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "After"}, "plain_text": "After"}]}}
+	]`
+
+	var blocks []notion.Block
+	if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+		t.Fatalf("failed to unmarshal blocks: %v", err)
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if want := "Before\n\n<!-- notion:unsupported form-1 -->\n\nAfter"; md != want {
+		t.Fatalf("BlocksToMarkdown() = %q, want %q", md, want)
+	}
+
+	// The block is left on the page, so pushing doesn't create anything for it
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 2 {
+		t.Fatalf("expected 2 paragraph blocks, got %v", pushed)
+	}
+	for _, block := range pushed {
+		if block["type"] != "paragraph" {
+			t.Errorf("unexpected %v block", block["type"])
+		}
+	}
+}
+
+func TestConverter_TemplateButtonRoundTrip(t *testing.T) {
+	data := `[
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Before"}, "plain_text": "Before"}]}},
+		{"id": "template-1", "type": "template", "has_children": true, "template": {"rich_text": [{"type": "text", "text": {"content": "Add a task"}, "plain_text": "Add a task"}]}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "After"}, "plain_text": "After"}]}}
+	]`
+
+	var blocks []notion.Block
+	if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+		t.Fatalf("failed to unmarshal blocks: %v", err)
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if want := "Before\n\n<!-- notion:template template-1 -->\n\nAfter"; md != want {
+		t.Fatalf("BlocksToMarkdown() = %q, want %q", md, want)
+	}
+
+	// The button is left on the page, so pushing doesn't create anything for it
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 2 {
+		t.Fatalf("expected 2 paragraph blocks, got %v", pushed)
+	}
+}
+
+func TestConverter_SyncedReferenceRoundTrip(t *testing.T) {
+	data := `[
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "Before"}, "plain_text": "Before"}]}},
+		{"id": "ref-1", "type": "synced_block", "has_children": true, "synced_block": {"synced_from": {"type": "block_id", "block_id": "original-1"}}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"type": "text", "text": {"content": "After"}, "plain_text": "After"}]}}
+	]`
+
+	var blocks []notion.Block
+	if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+		t.Fatalf("failed to unmarshal blocks: %v", err)
+	}
+
+	c := NewConverter()
+	md, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if want := "Before\n\n<!-- notion:synced_block original-1 -->\n\nAfter"; md != want {
+		t.Fatalf("BlocksToMarkdown() = %q, want %q", md, want)
+	}
+
+	// The reference is left on the page, so pushing doesn't create anything for it
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 2 {
+		t.Fatalf("expected 2 paragraph blocks, got %v", pushed)
+	}
+	for _, block := range pushed {
+		if block["type"] != "paragraph" {
+			t.Errorf("unexpected %v block", block["type"])
+		}
+	}
+}
+
+func TestConverter_QuoteWithChildParagraphs(t *testing.T) {
+	paragraph := func(text string) notion.Block {
+		return notion.Block{
+			Type:      "paragraph",
+			Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{{PlainText: text}}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		quote notion.Block
+		want  string
+	}{
+		{
+			name: "two child paragraphs",
+			quote: notion.Block{
+				Type:     "quote",
+				Quote:    &notion.RichTextBlock{},
+				Children: []notion.Block{paragraph("First paragraph"), paragraph("Second paragraph")},
+			},
+			want: "> First paragraph\n>\n> Second paragraph",
+		},
+		{
+			name: "text and child paragraph",
+			quote: notion.Block{
+				Type:     "quote",
+				Quote:    &notion.RichTextBlock{RichText: []notion.RichText{{PlainText: "Opening\nline two"}}},
+				Children: []notion.Block{paragraph("Continued")},
+			},
+			want: "> Opening\n> line two\n>\n> Continued",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := NewConverter().BlocksToMarkdown([]notion.Block{tt.quote, paragraph("After")})
+			if err != nil {
+				t.Fatalf("BlocksToMarkdown() error = %v", err)
+			}
+			if want := tt.want + "\n\nAfter"; md != want {
+				t.Errorf("BlocksToMarkdown() = %q, want %q", md, want)
+			}
+		})
+	}
+}
+
+func TestConverter_NestedBlocksInListsAndCallouts(t *testing.T) {
+	text := func(s string) *notion.RichTextBlock {
+		return &notion.RichTextBlock{RichText: []notion.RichText{{PlainText: s}}}
+	}
+	code := notion.Block{
+		Type: "code",
+		Code: &notion.CodeBlock{RichText: []notion.RichText{{PlainText: "go build ./..."}}, Language: "bash"},
+	}
+
+	tests := []struct {
+		name   string
+		blocks []notion.Block
+		want   string
+	}{
+		{
+			name: "code block under a bulleted item",
+			blocks: []notion.Block{
+				{Type: "bulleted_list_item", BulletedListItem: text("Build it"), Children: []notion.Block{code}},
+				{Type: "bulleted_list_item", BulletedListItem: text("Ship it")},
+			},
+			want: "- Build it\n\n  ```bash\n  go build ./...\n  ```\n- Ship it",
+		},
+		{
+			name: "nested list under a numbered item",
+			blocks: []notion.Block{
+				{Type: "numbered_list_item", NumberedListItem: text("Step"), Children: []notion.Block{
+					{Type: "bulleted_list_item", BulletedListItem: text("Detail")},
+				}},
+			},
+			want: "1. Step\n   - Detail",
+		},
+		{
+			name: "divider and code in a callout",
+			blocks: []notion.Block{
+				{
+					Type:     "callout",
+					Callout:  &notion.CalloutBlock{RichText: []notion.RichText{{PlainText: "Note"}}, Icon: &notion.CalloutIcon{Emoji: "💡"}},
+					Children: []notion.Block{{Type: "divider"}, code},
+				},
+			},
+			want: "> 💡 Note\n>\n> ---\n>\n> ```bash\n> go build ./...\n> ```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := NewConverter().BlocksToMarkdown(tt.blocks)
+			if err != nil {
+				t.Fatalf("BlocksToMarkdown() error = %v", err)
+			}
+			if md != tt.want {
+				t.Errorf("BlocksToMarkdown() = %q, want %q", md, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_MermaidRoundTrip(t *testing.T) {
+	c := NewConverter()
+	source := "graph TD\n    A[Start] --> B{Done?}\n    B -->|yes| C[End]"
+
+	blocks, err := c.MarkdownToBlocks("```Mermaid\n" + source + "\n```")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %d: %v", len(blocks), blocks)
+	}
+	code := blocks[0]["code"].(map[string]interface{})
+	if code["language"] != "mermaid" {
+		t.Fatalf("pushed language = %v, want mermaid", code["language"])
+	}
+
+	// Pull the block back the way the API returns it
+	segments := richTextSegments(t, blocks[0])
+	pulled := notion.Block{
+		Type: "code",
+		Code: &notion.CodeBlock{
+			RichText: []notion.RichText{{Type: "text", PlainText: strings.Join(segments, "")}},
+			Language: "Mermaid",
+		},
+	}
+
+	md, err := c.BlocksToMarkdown([]notion.Block{pulled})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	want := "```mermaid\n" + source + "\n```"
+	if strings.TrimSpace(md) != want {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", md, want)
+	}
+}
+
+func TestConverter_SeparateListsRoundTrip(t *testing.T) {
+	item := func(blockType, text string) notion.Block {
+		block := notion.Block{Type: blockType}
+		richText := &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: text}}}
+		if blockType == "bulleted_list_item" {
+			block.BulletedListItem = richText
+		} else {
+			block.NumberedListItem = richText
+		}
+		return block
+	}
+	paragraph := func(text string) notion.Block {
+		return notion.Block{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: text}}}}
+	}
+
+	tests := []struct {
+		name      string
+		blocks    []notion.Block
+		wantTypes []string
+	}{
+		{
+			name: "lists separated by a paragraph",
+			blocks: []notion.Block{
+				item("bulleted_list_item", "a"), item("bulleted_list_item", "b"),
+				paragraph("Between"),
+				item("bulleted_list_item", "c"),
+			},
+			wantTypes: []string{"bulleted_list_item", "bulleted_list_item", "paragraph", "bulleted_list_item"},
+		},
+		{
+			name: "lists separated by an empty paragraph",
+			blocks: []notion.Block{
+				item("numbered_list_item", "one"),
+				paragraph(""),
+				item("numbered_list_item", "two"),
+			},
+			wantTypes: []string{"numbered_list_item", "numbered_list_item"},
+		},
+	}
+
+	c := NewConverter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := c.BlocksToMarkdown(tt.blocks)
+			if err != nil {
+				t.Fatalf("BlocksToMarkdown() error = %v", err)
+			}
+
+			// Parse the markdown as goldmark does on push and count lists
+			source := []byte(md)
+			doc := goldmark.New().Parser().Parse(text.NewReader(source))
+			lists := 0
+			for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+				if n.Kind() == ast.KindList {
+					lists++
+				}
+			}
+			if lists != 2 {
+				t.Errorf("markdown %q has %d lists, want 2", md, lists)
+			}
+
+			blocks, err := c.MarkdownToBlocks(md)
+			if err != nil {
+				t.Fatalf("MarkdownToBlocks() error = %v", err)
+			}
+			var types []string
+			for _, block := range blocks {
+				types = append(types, block["type"].(string))
+			}
+			if strings.Join(types, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("pushed block types = %v, want %v (markdown %q)", types, tt.wantTypes, md)
+			}
+		})
+	}
+}
+
+func TestConverter_FootnotesRoundTrip(t *testing.T) {
+	md := "Intro with a note[^1] and another[^2].\n\n[^1]: First note.\n[^2]: Second note."
+
+	c := NewConverter()
+	pushed, err := c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	var types []string
+	for _, block := range pushed {
+		types = append(types, block["type"].(string))
+	}
+	wantTypes := "paragraph,heading_2,numbered_list_item,numbered_list_item"
+	if strings.Join(types, ",") != wantTypes {
+		t.Fatalf("pushed block types = %v, want %s", types, wantTypes)
+	}
+	paragraph := richTextContent(pushed[0]["paragraph"].(map[string]interface{})["rich_text"])
+	if paragraph != "Intro with a note¹ and another²." {
+		t.Errorf("pushed paragraph = %q", paragraph)
+	}
+
+	pulled := pulledTextBlocks(pushed)
+	got, err := c.BlocksToMarkdown(pulled)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if strings.TrimSpace(got) != md {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", got, md)
+	}
+
+	// A Footnotes list nothing refers to is left as it is
+	got, err = c.BlocksToMarkdown(pulled[1:])
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if strings.Contains(got, "[^") || !strings.Contains(got, "## Footnotes") {
+		t.Errorf("BlocksToMarkdown() = %q, want a plain heading and list", got)
+	}
+}
+
+func TestConverter_FootnotesKeepSuperscripts(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+	}{
+		{
+			name: "superscript before the markers",
+			md:   "Energy is mc² here[^1] and there[^2].\n\n[^1]: First note.\n[^2]: Second note.",
+		},
+		{
+			// Numbers of footnotes already referenced would be markers
+			name: "superscript after the markers",
+			md:   "A note[^1] and another[^2], then x³ ¹ alone.\n\n[^1]: First note.\n[^2]: Second note.",
+		},
+		{
+			name: "footnote referenced again",
+			md:   "A note[^1], another[^2] and the first again[^1].\n\n[^1]: First note.\n[^2]: Second note.",
+		},
+	}
+
+	c := NewConverter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pushed, err := c.MarkdownToBlocks(tt.md)
+			if err != nil {
+				t.Fatalf("MarkdownToBlocks() error = %v", err)
+			}
+			got, err := c.BlocksToMarkdown(pulledTextBlocks(pushed))
+			if err != nil {
+				t.Fatalf("BlocksToMarkdown() error = %v", err)
+			}
+			if strings.TrimSpace(got) != tt.md {
+				t.Errorf("BlocksToMarkdown() = %q, want %q", got, tt.md)
+			}
+		})
+	}
+
+	// Superscripts in inline code are never markers
+	blocks := []notion.Block{
+		{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{
+			{Type: "text", PlainText: "x¹", Annotations: &notion.Annotations{Code: true}},
+			{Type: "text", PlainText: " and a note¹"},
+		}}},
+		{Type: "heading_2", Heading2: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: "Footnotes"}}}},
+		{Type: "numbered_list_item", NumberedListItem: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: "Note."}}}},
+	}
+	got, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if want := "`x¹` and a note[^1]\n\n[^1]: Note."; strings.TrimSpace(got) != want {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestConverter_FootnoteFormatting(t *testing.T) {
+	md := "A note[^1].\n\n[^1]: See **the spec** and `go vet`, [here](https://example.com).\n\n    Second *paragraph*."
+
+	pushed, err := NewConverter().MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	item := pushed[len(pushed)-1]["numbered_list_item"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+
+	type span struct {
+		content     string
+		annotations interface{}
+		link        interface{}
+	}
+	var got []span
+	for _, object := range item {
+		text := object["text"].(map[string]interface{})
+		got = append(got, span{text["content"].(string), object["annotations"], text["link"]})
+	}
+	want := []span{
+		{"See ", nil, nil},
+		{"the spec", map[string]interface{}{"bold": true}, nil},
+		{" and ", nil, nil},
+		{"go vet", map[string]interface{}{"code": true}, nil},
+		{", ", nil, nil},
+		{"here", nil, map[string]interface{}{"url": "https://example.com"}},
+		{". Second ", nil, nil},
+		{"paragraph", map[string]interface{}{"italic": true}, nil},
+		{".", nil, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("footnote rich text = %+v, want %+v", got, want)
+	}
+}
+
+// pulledTextBlocks turns pushed text blocks into blocks as Notion returns
+// them on pull
+func pulledTextBlocks(pushed []map[string]interface{}) []notion.Block {
+	var blocks []notion.Block
+	for _, block := range pushed {
+		blockType := block["type"].(string)
+		richText := &notion.RichTextBlock{RichText: []notion.RichText{
+			{Type: "text", PlainText: richTextContent(block[blockType].(map[string]interface{})["rich_text"])},
+		}}
+		pulled := notion.Block{Type: blockType}
+		switch blockType {
+		case "paragraph":
+			pulled.Paragraph = richText
+		case "heading_2":
+			pulled.Heading2 = richText
+		case "numbered_list_item":
+			pulled.NumberedListItem = richText
+		}
+		blocks = append(blocks, pulled)
+	}
+	return blocks
+}
+
+func TestConverter_PreserveHTMLRoundTrip(t *testing.T) {
+	iframe := `<iframe src="https://www.youtube.com/embed/abc" width="560" height="315"></iframe>`
+	md := "Intro\n\n" + iframe + "\n\nOutro\n"
+
+	blocks, err := NewConverter().MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected the HTML block to be dropped by default, got %d blocks: %v", len(blocks), blocks)
+	}
+
+	c := NewConverterWithOptions(ConverterOptions{PreserveHTML: true})
+	blocks, err = c.MarkdownToBlocks(md)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(blocks) != 3 || blocks[1]["type"] != "code" {
+		t.Fatalf("expected the HTML block to be kept as a code block, got %v", blocks)
+	}
+	code := blocks[1]["code"].(map[string]interface{})
+	if code["language"] != "html" {
+		t.Errorf("language = %v, want html", code["language"])
+	}
+	if got := richTextContent(code["rich_text"]); got != iframe {
+		t.Errorf("code = %q, want %q", got, iframe)
+	}
+
+	// Pulling the block writes the HTML back as it was
+	data, err := json.Marshal(map[string]interface{}{
+		"type": "code",
+		"code": map[string]interface{}{
+			"rich_text": []map[string]interface{}{{"type": "text", "plain_text": iframe}},
+			"caption":   []map[string]interface{}{{"type": "text", "plain_text": rawHTMLCaption}},
+			"language":  "html",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal block: %v", err)
+	}
+	var pulled notion.Block
+	if err := json.Unmarshal(data, &pulled); err != nil {
+		t.Fatalf("failed to unmarshal block: %v", err)
+	}
+	got, err := NewConverter().BlocksToMarkdown([]notion.Block{pulled})
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if strings.TrimSpace(got) != iframe {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", got, iframe)
+	}
+}
+
+func TestConverter_MDXFlavor(t *testing.T) {
+	text := func(s string) []notion.RichText {
+		return []notion.RichText{{Type: "text", PlainText: s}}
+	}
+	blocks := []notion.Block{
+		{Type: "callout", Callout: &notion.CalloutBlock{
+			RichText: text("Rotate keys {yearly}"),
+			Icon:     &notion.CalloutIcon{Type: "emoji", Emoji: "⚠️"},
+			Color:    "yellow_background",
+		}, Children: []notion.Block{
+			{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: text("See a < b")}},
+		}},
+		{Type: "toggle", Toggle: &notion.ToggleBlock{RichText: text("More details")}},
+		{Type: "table_of_contents"},
+	}
+
+	got, err := NewConverterWithOptions(ConverterOptions{Flavor: " MDX "}).BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	want := "<Callout type=\"warning\" emoji=\"⚠️\">\n\n" +
+		"Rotate keys \\{yearly\\}\n\n" +
+		"See a \\< b\n\n" +
+		"</Callout>\n\n" +
+		"<Details>\n<summary>More details</summary>\n\n</Details>\n\n" +
+		"{/* notion:table_of_contents */}"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The default flavor is unchanged
+	got, err = NewConverter().BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	want = "> ⚠️ Rotate keys {yearly}\n>\n> See a < b\n\n" +
+		"<details>\n<summary>More details</summary>\n\n</details>\n\n" +
+		"<!-- notion:table_of_contents -->"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestConverter_ReferenceLinks(t *testing.T) {
+	link := func(s, url string) notion.RichText {
+		return notion.RichText{Type: "text", PlainText: s, Text: &notion.TextContent{Content: s, Link: &notion.Link{URL: url}}}
+	}
+	text := func(s string) notion.RichText {
+		return notion.RichText{Type: "text", PlainText: s}
+	}
+	docs := "https://example.com/docs/getting-started"
+	blocks := []notion.Block{
+		{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{
+			text("Read the "), link("guide", docs), text(" or the "), link("changelog", "https://example.com/changelog"), text("."),
+		}}},
+		{Type: "bulleted_list_item", BulletedListItem: &notion.RichTextBlock{RichText: []notion.RichText{
+			link("Getting started", docs),
+		}}},
+	}
+
+	got, err := NewConverterWithOptions(ConverterOptions{LinkStyle: LinkStyleReference}).BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	// Both links to the docs share one definition
+	want := "Read the [guide][1] or the [changelog][2].\n\n" +
+		"- [Getting started][1]\n\n" +
+		"[1]: " + docs + "\n" +
+		"[2]: https://example.com/changelog"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Links stay inline by default
+	got, err = NewConverter().BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	want = "Read the [guide](" + docs + ") or the [changelog](https://example.com/changelog).\n\n" +
+		"- [Getting started](" + docs + ")"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestConverter_ReferenceLinksInNestedBlocks(t *testing.T) {
+	link := func(s, url string) []notion.RichText {
+		return []notion.RichText{{Type: "text", PlainText: s, Text: &notion.TextContent{Content: s, Link: &notion.Link{URL: url}}}}
+	}
+	blocks := []notion.Block{
+		{Type: "callout", Callout: &notion.CalloutBlock{RichText: link("Status", "https://status.example.com")}, Children: []notion.Block{
+			{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: link("Status page", "https://status.example.com")}},
+		}},
+		{Type: "bookmark", Bookmark: &notion.BookmarkBlock{URL: "https://example.com/a b", Caption: []notion.RichText{{Type: "text", PlainText: "Spec"}}}},
+	}
+
+	c := NewConverterWithOptions(ConverterOptions{LinkStyle: LinkStyleReference})
+	got, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	// Definitions are listed once, at the end of the page rather than the
+	// callout, and URLs with spaces are wrapped in angle brackets
+	want := "> [Status][1]\n>\n> [Status page][1]\n\n" +
+		"[Spec][2]\n\n" +
+		"[1]: https://status.example.com\n" +
+		"[2]: <https://example.com/a b>"
+	if got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Each page is numbered from 1 again
+	got, err = c.BlocksToMarkdown(blocks[1:])
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if want := "[Spec][1]\n\n[1]: <https://example.com/a b>"; got != want {
+		t.Errorf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestConverter_CalloutChildrenRoundTrip(t *testing.T) {
+	text := func(s string) []notion.RichText {
+		return []notion.RichText{{Type: "text", PlainText: s}}
+	}
+	blocks := []notion.Block{
+		{Type: "callout", Callout: &notion.CalloutBlock{
+			RichText: text("Before you deploy"),
+			Icon:     &notion.CalloutIcon{Type: "emoji", Emoji: "💡"},
+		}, Children: []notion.Block{
+			{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: text("Check these first:")}},
+			{Type: "bulleted_list_item", BulletedListItem: &notion.RichTextBlock{RichText: text("Tests pass")}},
+			{Type: "bulleted_list_item", BulletedListItem: &notion.RichTextBlock{RichText: text("Changelog updated")}},
+		}},
+	}
+
+	c := NewConverter()
+	got, err := c.BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	want := "> 💡 Before you deploy\n>\n> Check these first:\n>\n> - Tests pass\n> - Changelog updated"
+	if got != want {
+		t.Fatalf("BlocksToMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	pushed, err := c.MarkdownToBlocks(got)
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 1 || pushed[0]["type"] != "callout" {
+		t.Fatalf("expected a single callout, got %v", pushed)
+	}
+	callout := pushed[0]["callout"].(map[string]interface{})
+	if got := richTextContent(callout["rich_text"]); got != "Before you deploy" {
+		t.Errorf("callout text = %q, want %q", got, "Before you deploy")
+	}
+	if icon := callout["icon"].(map[string]interface{}); icon["emoji"] != "💡" {
+		t.Errorf("callout icon = %v, want 💡", icon)
+	}
+
+	children, _ := callout["children"].([]map[string]interface{})
+	wantChildren := []struct{ blockType, text string }{
+		{"paragraph", "Check these first:"},
+		{"bulleted_list_item", "Tests pass"},
+		{"bulleted_list_item", "Changelog updated"},
+	}
+	if len(children) != len(wantChildren) {
+		t.Fatalf("expected %d callout children, got %v", len(wantChildren), children)
+	}
+	for i, want := range wantChildren {
+		blockType := children[i]["type"].(string)
+		content := richTextContent(children[i][blockType].(map[string]interface{})["rich_text"])
+		if blockType != want.blockType || content != want.text {
+			t.Errorf("child %d = %s %q, want %s %q", i, blockType, content, want.blockType, want.text)
+		}
+	}
+}
+
+func TestConverter_AlertToCallout(t *testing.T) {
+	pushed, err := NewConverter().MarkdownToBlocks("> [!WARNING]\n> Rotate the keys\n>\n> - Staging\n> - Production\n")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	if len(pushed) != 1 || pushed[0]["type"] != "callout" {
+		t.Fatalf("expected a single callout, got %v", pushed)
+	}
+
+	callout := pushed[0]["callout"].(map[string]interface{})
+	if got := richTextContent(callout["rich_text"]); got != "Rotate the keys" {
+		t.Errorf("callout text = %q, want %q", got, "Rotate the keys")
+	}
+	if icon := callout["icon"].(map[string]interface{}); icon["emoji"] != "⚠️" {
+		t.Errorf("callout icon = %v, want ⚠️", icon)
+	}
+	if callout["color"] != "yellow_background" {
+		t.Errorf("callout color = %v, want yellow_background", callout["color"])
+	}
+	if children, _ := callout["children"].([]map[string]interface{}); len(children) != 2 {
+		t.Errorf("expected the list items as callout children, got %v", callout["children"])
+	}
+
+	// Unknown markers are left as text
+	pushed, err = NewConverter().MarkdownToBlocks("> [!SHRUG] Whatever\n")
+	if err != nil {
+		t.Fatalf("MarkdownToBlocks() error = %v", err)
+	}
+	callout = pushed[0]["callout"].(map[string]interface{})
+	if got := richTextContent(callout["rich_text"]); got != "[!SHRUG] Whatever" {
+		t.Errorf("callout text = %q, want the marker kept", got)
+	}
+}
@@ -3,24 +3,40 @@ package sync
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/concurrent/batch"
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
 )
 
+// Supported database export formats
+const (
+	ExportFormatCSV      = "csv"
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "md"
+)
+
 // DatabaseSync interface for syncing between Notion databases and CSV files
 type DatabaseSync interface {
 	SyncNotionDatabaseToCSV(ctx context.Context, databaseID, csvPath string) error
+	ExportNotionDatabase(ctx context.Context, databaseID, outputPath, format string) error
+	ExportNotionDatabaseToCSV(ctx context.Context, databaseID, csvPath string, opts CSVExportOptions) error
 	SyncCSVToNotionDatabase(ctx context.Context, csvPath, databaseID string) error
+	ImportCSVToNotionDatabase(ctx context.Context, csvPath, databaseID string, opts CSVImportOptions) (*CSVImportResult, error)
 	CreateDatabaseFromCSV(ctx context.Context, csvPath, parentPageID string) (*notion.Database, error)
 }
 
 type databaseSync struct {
-	client notion.Client
+	client   notion.Client
+	warnings io.Writer // Destination for non-fatal warnings; stdout when nil
 }
 
 // NewDatabaseSync creates a new DatabaseSync instance
@@ -30,44 +46,69 @@ func NewDatabaseSync(client notion.Client) DatabaseSync {
 	}
 }
 
+// warnf writes a non-fatal warning
+func (ds *databaseSync) warnf(format string, args ...interface{}) {
+	w := ds.warnings
+	if w == nil {
+		w = os.Stdout
+	}
+	_, _ = fmt.Fprintf(w, format, args...)
+}
+
 // SyncNotionDatabaseToCSV exports a Notion database to a CSV file
 func (ds *databaseSync) SyncNotionDatabaseToCSV(ctx context.Context, databaseID, csvPath string) error {
-	// Get database schema
-	database, err := ds.client.GetDatabase(ctx, databaseID)
-	if err != nil {
-		return fmt.Errorf("failed to get database: %w", err)
+	return ds.ExportNotionDatabase(ctx, databaseID, csvPath, ExportFormatCSV)
+}
+
+// ExportNotionDatabase exports a Notion database to outputPath as CSV, JSON or a markdown table
+func (ds *databaseSync) ExportNotionDatabase(ctx context.Context, databaseID, outputPath, format string) error {
+	var write func(w io.Writer, header []string, rows []notion.DatabaseRow) error
+	switch format {
+	case ExportFormatCSV:
+		return ds.streamDatabaseToCSV(ctx, databaseID, outputPath)
+	case ExportFormatJSON:
+		write = ds.writeJSON
+	case ExportFormatMarkdown:
+		write = ds.writeMarkdownTable
+	default:
+		return fmt.Errorf("unsupported export format: %s (expected csv, json or md)", format)
 	}
 
-	// Query all rows
-	queryResp, err := ds.client.QueryDatabase(ctx, databaseID, &notion.DatabaseQueryRequest{
-		PageSize: intPtr(100), // Notion's max page size
-	})
+	database, rows, err := ds.fetchDatabase(ctx, databaseID)
 	if err != nil {
-		return fmt.Errorf("failed to query database: %w", err)
+		return err
 	}
 
-	// Create CSV file
-	file, err := os.Create(csvPath)
+	file, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+		return fmt.Errorf("failed to create %s file: %w", format, err)
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
 			// Log error but don't fail the operation
-			fmt.Printf("Warning: failed to close CSV file: %v\n", err)
+			ds.warnf("Warning: failed to close %s file: %v\n", format, err)
 		}
 	}()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	return write(file, ds.buildCSVHeader(database.Properties), rows)
+}
 
-	// Write header row
-	header := ds.buildCSVHeader(database.Properties)
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
+// fetchDatabase retrieves a database schema and all of its rows
+func (ds *databaseSync) fetchDatabase(ctx context.Context, databaseID string) (*notion.Database, []notion.DatabaseRow, error) {
+	// Get database schema
+	database, err := ds.client.GetDatabase(ctx, databaseID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get database: %w", err)
+	}
+
+	// Query all rows
+	queryResp, err := ds.client.QueryDatabase(ctx, databaseID, &notion.DatabaseQueryRequest{
+		PageSize: intPtr(100), // Notion's max page size
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query database: %w", err)
 	}
 
-	// Write data rows
 	allRows := queryResp.Results
 
 	// Handle pagination
@@ -77,67 +118,484 @@ func (ds *databaseSync) SyncNotionDatabaseToCSV(ctx context.Context, databaseID,
 			PageSize:    intPtr(100),
 		})
 		if err != nil {
-			return fmt.Errorf("failed to query database (pagination): %w", err)
+			return nil, nil, fmt.Errorf("failed to query database (pagination): %w", err)
 		}
 		allRows = append(allRows, queryResp.Results...)
 	}
 
-	for _, row := range allRows {
-		csvRow := ds.convertRowToCSV(row, header)
-		if err := writer.Write(csvRow); err != nil {
+	for i := range allRows {
+		if err := expandTruncatedProperties(ctx, ds.client, &allRows[i]); err != nil {
+			ds.warnf("Warning: %v; exporting the values returned so far\n", err)
+		}
+		if err := resolvePeopleNames(ctx, ds.client, &allRows[i]); err != nil {
+			ds.warnf("Warning: %v; exporting user IDs instead\n", err)
+		}
+	}
+
+	return database, allRows, nil
+}
+
+// expandTruncatedProperties replaces property values Notion cut short in
+// the page object, such as relations with more than 25 pages, with the
+// complete values from the page property endpoint
+func expandTruncatedProperties(ctx context.Context, client notion.Client, row *notion.DatabaseRow) error {
+	for name, prop := range row.Properties {
+		if !prop.HasMore || prop.ID == "" {
+			continue
+		}
+
+		full, err := client.GetPageProperty(ctx, row.ID, prop.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get all values of property %q for row %s: %w", name, row.ID, err)
+		}
+		if full.Type == "" {
+			full.Type = prop.Type
+		}
+		full.ID = prop.ID
+		row.Properties[name] = *full
+	}
+	return nil
+}
+
+// streamDatabaseToCSV writes each database row to csvPath as it arrives
+// rather than collecting them first, so memory use stays flat however many
+// rows the database has. A failed export removes the partial file
+func (ds *databaseSync) streamDatabaseToCSV(ctx context.Context, databaseID, csvPath string) error {
+	database, err := ds.client.GetDatabase(ctx, databaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get database: %w", err)
+	}
+
+	file, err := os.Create(csvPath)
+	if err != nil {
+		return fmt.Errorf("failed to create csv file: %w", err)
+	}
+
+	// Cancelling stops the stream's producer if writing fails part way
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := ds.client.StreamDatabaseRows(streamCtx, databaseID)
+	writeErr := ds.writeCSVStream(streamCtx, file, ds.buildCSVHeader(database.Properties), stream)
+	if err := file.Close(); err != nil && writeErr == nil {
+		writeErr = fmt.Errorf("failed to close csv file: %w", err)
+	}
+	if writeErr != nil {
+		if err := os.Remove(csvPath); err != nil && !os.IsNotExist(err) {
+			ds.warnf("Warning: failed to remove partial csv file: %v\n", err)
+		}
+		return writeErr
+	}
+	return nil
+}
+
+// csvExportCursorSuffix names the sidecar file next to a CSV in which
+// resumable exports record how far they got
+const csvExportCursorSuffix = ".cursor"
+
+// CSVExportOptions controls how a database is exported to CSV
+type CSVExportOptions struct {
+	// Resume saves a checkpoint after each page of rows so an interrupted
+	// export picks up where it stopped, appending to the CSV it left behind.
+	// Without a checkpoint the export starts from the beginning.
+	Resume bool
+	// After is a Notion cursor to start from instead, appending to the
+	// existing CSV as it is
+	After string
+}
+
+// csvExportCheckpoint is the content of a resumable export's sidecar file
+type csvExportCheckpoint struct {
+	DatabaseID string `json:"database_id"`
+	NextCursor string `json:"next_cursor"`
+	Offset     int64  `json:"offset"` // CSV size once the rows before NextCursor were written
+}
+
+// ExportNotionDatabaseToCSV exports a Notion database to a CSV file, one
+// page of rows at a time. Unless opts asks to resume or start from a cursor
+// it behaves like SyncNotionDatabaseToCSV.
+func (ds *databaseSync) ExportNotionDatabaseToCSV(ctx context.Context, databaseID, csvPath string, opts CSVExportOptions) error {
+	if !opts.Resume && opts.After == "" {
+		return ds.streamDatabaseToCSV(ctx, databaseID, csvPath)
+	}
+
+	database, err := ds.client.GetDatabase(ctx, databaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get database: %w", err)
+	}
+	header := ds.buildCSVHeader(database.Properties)
+
+	cursorPath := csvPath + csvExportCursorSuffix
+	start := opts.After
+	offset := int64(-1) // Keep the whole CSV unless a checkpoint says otherwise
+	if start == "" {
+		checkpoint, err := readCSVExportCheckpoint(cursorPath)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			if checkpoint.DatabaseID != databaseID {
+				return fmt.Errorf("%s is from an export of database %s, not %s", cursorPath, checkpoint.DatabaseID, databaseID)
+			}
+			start, offset = checkpoint.NextCursor, checkpoint.Offset
+		}
+	}
+
+	var file *os.File
+	if start == "" {
+		file, err = os.Create(csvPath)
+	} else {
+		file, err = os.OpenFile(csvPath, os.O_RDWR|os.O_CREATE, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open csv file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			ds.warnf("Warning: failed to close csv file: %v\n", err)
+		}
+	}()
+
+	// Drop rows written after the last checkpoint, which the resumed
+	// export writes again
+	if offset >= 0 {
+		if err := file.Truncate(offset); err != nil {
+			return fmt.Errorf("failed to truncate csv file: %w", err)
+		}
+	}
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek csv file: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+	if size == 0 {
+		if err := writer.Write(header); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+
+	request := &notion.DatabaseQueryRequest{PageSize: intPtr(100)}
+	if start != "" {
+		request.StartCursor = &start
+	}
+	for {
+		resp, err := ds.client.QueryDatabase(ctx, databaseID, request)
+		if err != nil {
+			return fmt.Errorf("failed to query database: %w", err)
+		}
+
+		for _, row := range resp.Results {
+			if err := expandTruncatedProperties(ctx, ds.client, &row); err != nil {
+				ds.warnf("Warning: %v; exporting the values returned so far\n", err)
+			}
+			if err := resolvePeopleNames(ctx, ds.client, &row); err != nil {
+				ds.warnf("Warning: %v; exporting user IDs instead\n", err)
+			}
+			if err := writer.Write(ds.convertRowToCSV(row, header)); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to flush CSV: %w", err)
+		}
+
+		if !resp.HasMore || resp.NextCursor == nil {
+			break
+		}
+
+		// The rows must be on disk before the checkpoint that skips them
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync csv file: %w", err)
+		}
+		size, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to seek csv file: %w", err)
+		}
+		checkpoint := csvExportCheckpoint{DatabaseID: databaseID, NextCursor: *resp.NextCursor, Offset: size}
+		if err := writeCSVExportCheckpoint(cursorPath, checkpoint); err != nil {
+			return err
+		}
+
+		request = &notion.DatabaseQueryRequest{StartCursor: resp.NextCursor, PageSize: intPtr(100)}
+	}
+
+	if err := os.Remove(cursorPath); err != nil && !os.IsNotExist(err) {
+		ds.warnf("Warning: failed to remove %s: %v\n", cursorPath, err)
+	}
+	return nil
+}
+
+// readCSVExportCheckpoint reads a resumable export's sidecar file, returning
+// nil when there is none
+func readCSVExportCheckpoint(path string) (*csvExportCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export checkpoint: %w", err)
+	}
+
+	var checkpoint csvExportCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse export checkpoint %s: %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// writeCSVExportCheckpoint replaces a resumable export's sidecar file, so
+// an interruption never leaves half a checkpoint behind
+func writeCSVExportCheckpoint(path string, checkpoint csvExportCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode export checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write export checkpoint: %w", err)
+	}
+	return nil
+}
+
+// writeCSVStream writes a header row followed by every row from stream
+func (ds *databaseSync) writeCSVStream(ctx context.Context, w io.Writer, header []string, stream *notion.DatabaseRowStream) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for row := range stream.Rows() {
+		if err := expandTruncatedProperties(ctx, ds.client, &row); err != nil {
+			ds.warnf("Warning: %v; exporting the values returned so far\n", err)
+		}
+		if err := resolvePeopleNames(ctx, ds.client, &row); err != nil {
+			ds.warnf("Warning: %v; exporting user IDs instead\n", err)
+		}
+		if err := writer.Write(ds.convertRowToCSV(row, header)); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
 		}
 	}
 
+	// The stream closes its rows after reporting a failure
+	if err, ok := <-stream.Errors(); ok && err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("database export cancelled: %w", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes rows as a JSON array of objects keyed by property name,
+// keeping numbers, checkboxes and multi-selects as native JSON types
+func (ds *databaseSync) writeJSON(w io.Writer, header []string, rows []notion.DatabaseRow) error {
+	records := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]interface{}, len(header))
+		for _, columnName := range header {
+			if prop, exists := row.Properties[columnName]; exists {
+				record[columnName] = ds.propertyValueToJSON(prop)
+			} else {
+				record[columnName] = nil
+			}
+		}
+		records = append(records, record)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
 	return nil
 }
 
-// SyncCSVToNotionDatabase imports a CSV file to an existing Notion database
+// writeMarkdownTable writes rows as a GitHub-flavored markdown table
+func (ds *databaseSync) writeMarkdownTable(w io.Writer, header []string, rows []notion.DatabaseRow) error {
+	var sb strings.Builder
+
+	escaped := make([]string, len(header))
+	separators := make([]string, len(header))
+	for i, name := range header {
+		escaped[i] = escapeMarkdownTableCell(name)
+		separators[i] = "---"
+	}
+	sb.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+	sb.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+
+	for _, row := range rows {
+		cells := ds.convertRowToCSV(row, header)
+		for i, cell := range cells {
+			cells[i] = escapeMarkdownTableCell(cell)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write markdown table: %w", err)
+	}
+	return nil
+}
+
+// escapeMarkdownTableCell keeps a value on one line and stops pipes from splitting the cell
+func escapeMarkdownTableCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+// DefaultCSVImportWorkers bounds how many rows a CSV import creates at once.
+// Every worker shares the client's rate limiter, so more mostly adds queueing
+const DefaultCSVImportWorkers = 4
+
+// CSVImportOptions controls how CSV rows are created
+type CSVImportOptions struct {
+	Workers         int  // Concurrent row creations; DefaultCSVImportWorkers when not positive
+	ContinueOnError bool // Keep importing after a row fails instead of stopping
+	// SkipUnknownColumns drops CSV columns the database has no property for
+	// instead of failing the import before any row is created
+	SkipUnknownColumns bool
+}
+
+// CSVRowError is a failure importing a single CSV row
+type CSVRowError struct {
+	Row int // Line in the CSV file, counting the header as row 1
+	Err error
+}
+
+func (e *CSVRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVImportResult reports the outcome of a CSV import
+type CSVImportResult struct {
+	Created        int
+	Errors         []*CSVRowError // Sorted by row
+	MissingColumns []string       // Database properties the CSV has no column for, left empty
+	UnknownColumns []string       // CSV columns the database has no property for
+}
+
+// SyncCSVToNotionDatabase imports a CSV file to an existing Notion database,
+// stopping at the first row that fails
 func (ds *databaseSync) SyncCSVToNotionDatabase(ctx context.Context, csvPath, databaseID string) error {
+	result, err := ds.ImportCSVToNotionDatabase(ctx, csvPath, databaseID, CSVImportOptions{})
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return result.Errors[0]
+	}
+	return nil
+}
+
+// ImportCSVToNotionDatabase creates a database row for every CSV record using
+// a bounded pool of workers. The CSV is checked against the database schema
+// first: unknown columns fail the import unless opts.SkipUnknownColumns is
+// set, and values that don't fit their column's type are all reported
+// before any row is created. Rows that can't be converted or created are
+// reported by their CSV row number; unless opts.ContinueOnError is set, the
+// first failure stops rows that haven't started yet from being created
+func (ds *databaseSync) ImportCSVToNotionDatabase(ctx context.Context, csvPath, databaseID string, opts CSVImportOptions) (*CSVImportResult, error) {
 	// Read CSV file
 	file, err := os.Open(csvPath)
 	if err != nil {
-		return fmt.Errorf("failed to open CSV file: %w", err)
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Warning: failed to close CSV file: %v\n", err)
+			ds.warnf("Warning: failed to close CSV file: %v\n", err)
 		}
 	}()
 
 	reader := csv.NewReader(file)
 	records, err := reader.ReadAll()
 	if err != nil {
-		return fmt.Errorf("failed to read CSV: %w", err)
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
 
 	if len(records) < 2 {
-		return fmt.Errorf("CSV file must have at least a header row and one data row")
+		return nil, fmt.Errorf("CSV file must have at least a header row and one data row")
 	}
 
 	// Get database schema
 	database, err := ds.client.GetDatabase(ctx, databaseID)
 	if err != nil {
-		return fmt.Errorf("failed to get database: %w", err)
+		return nil, fmt.Errorf("failed to get database: %w", err)
 	}
 
 	header := records[0]
-	dataRows := records[1:]
+	result := &CSVImportResult{}
 
-	// Convert and insert rows
-	for i, record := range dataRows {
+	result.MissingColumns, result.UnknownColumns = compareCSVColumns(header, database.Properties)
+	if len(result.UnknownColumns) > 0 && !opts.SkipUnknownColumns {
+		return result, fmt.Errorf("CSV columns not in the database: %s", strings.Join(result.UnknownColumns, ", "))
+	}
+
+	// Convert every row up front so bad values stop the import before
+	// anything is created
+	type rowJob struct {
+		row        int
+		properties map[string]notion.PropertyValue
+	}
+	var jobs []rowJob
+	for i, record := range records[1:] {
+		row := i + 2
 		properties, err := ds.convertCSVRowToProperties(record, header, database.Properties)
 		if err != nil {
-			return fmt.Errorf("failed to convert CSV row %d: %w", i+2, err)
+			result.Errors = append(result.Errors, &CSVRowError{Row: row, Err: fmt.Errorf("failed to convert CSV row: %w", err)})
+			continue
 		}
+		jobs = append(jobs, rowJob{row: row, properties: properties})
+	}
+	if len(result.Errors) > 0 && !opts.ContinueOnError {
+		return result, nil
+	}
 
-		_, err = ds.client.CreateDatabaseRow(ctx, databaseID, properties)
-		if err != nil {
-			return fmt.Errorf("failed to create database row %d: %w", i+2, err)
+	workerCount := opts.Workers
+	if workerCount <= 0 {
+		workerCount = DefaultCSVImportWorkers
+	}
+
+	processor := batch.Processor{Workers: workerCount, StopOnError: !opts.ContinueOnError}
+	errs := processor.Run(ctx, len(jobs), func(ctx context.Context, i int) error {
+		_, err := ds.client.CreateDatabaseRow(ctx, databaseID, jobs[i].properties)
+		return err
+	})
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			result.Created++
+		case errors.Is(err, batch.ErrNotRun):
+			// Left out or cut short by another row's failure rather than failing itself
+		default:
+			result.Errors = append(result.Errors, &CSVRowError{Row: jobs[i].row, Err: fmt.Errorf("failed to create database row: %w", err)})
 		}
 	}
 
-	return nil
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("CSV import cancelled: %w", ctx.Err())
+	}
+	return result, nil
 }
 
 // CreateDatabaseFromCSV creates a new Notion database from a CSV file structure
@@ -149,7 +607,7 @@ func (ds *databaseSync) CreateDatabaseFromCSV(ctx context.Context, csvPath, pare
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			fmt.Printf("Warning: failed to close CSV file: %v\n", err)
+			ds.warnf("Warning: failed to close CSV file: %v\n", err)
 		}
 	}()
 
@@ -165,14 +623,8 @@ func (ds *databaseSync) CreateDatabaseFromCSV(ctx context.Context, csvPath, pare
 
 	header := records[0]
 
-	// Analyze data types if we have data rows
-	var sampleData []string
-	if len(records) > 1 {
-		sampleData = records[1]
-	}
-
 	// Create database schema
-	properties := ds.inferPropertiesFromCSV(header, sampleData)
+	properties := ds.inferPropertiesFromCSV(header, records[1:])
 
 	// Create database
 	createReq := &notion.CreateDatabaseRequest{
@@ -218,11 +670,20 @@ func (ds *databaseSync) CreateDatabaseFromCSV(ctx context.Context, csvPath, pare
 
 // Helper functions
 
+// buildCSVHeader lists the title column first and the rest by name, so
+// exports of the same database always have the same column order
 func (ds *databaseSync) buildCSVHeader(properties map[string]notion.Property) []string {
-	var header []string
+	header := make([]string, 0, len(properties))
 	for name := range properties {
 		header = append(header, name)
 	}
+	sort.Slice(header, func(i, j int) bool {
+		iTitle, jTitle := properties[header[i]].Type == "title", properties[header[j]].Type == "title"
+		if iTitle != jTitle {
+			return iTitle
+		}
+		return header[i] < header[j]
+	})
 	return header
 }
 
@@ -278,10 +739,87 @@ func (ds *databaseSync) propertyValueToString(prop notion.PropertyValue) string
 		if prop.PhoneNumber != nil {
 			return *prop.PhoneNumber
 		}
+	case "relation":
+		return strings.Join(relationIDs(prop.Relation), ", ")
+	case "people":
+		return strings.Join(peopleNames(prop.People), ", ")
+	case "rollup":
+		return ds.rollupToString(prop.Rollup)
+	}
+	return ""
+}
+
+// relationIDs returns the IDs of the related pages
+func relationIDs(relations []notion.RelationValue) []string {
+	ids := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		ids = append(ids, relation.ID)
+	}
+	return ids
+}
+
+// rollupToString formats a rollup's computed number, date or array value
+func (ds *databaseSync) rollupToString(rollup *notion.RollupValue) string {
+	if rollup == nil {
+		return ""
+	}
+
+	switch rollup.Type {
+	case "number":
+		if rollup.Number != nil {
+			return strconv.FormatFloat(*rollup.Number, 'f', -1, 64)
+		}
+	case "date":
+		if rollup.Date != nil && rollup.Date.Start != nil {
+			return rollup.Date.Start.Format("2006-01-02")
+		}
+	case "array":
+		var values []string
+		for _, item := range rollup.Array {
+			if value := ds.propertyValueToString(item); value != "" {
+				values = append(values, value)
+			}
+		}
+		return strings.Join(values, ", ")
 	}
 	return ""
 }
 
+// propertyValueToJSON converts a property value to its natural JSON type,
+// falling back to the CSV string form for text-like properties
+func (ds *databaseSync) propertyValueToJSON(prop notion.PropertyValue) interface{} {
+	switch prop.Type {
+	case "number":
+		if prop.Number != nil {
+			return *prop.Number
+		}
+		return nil
+	case "checkbox":
+		if prop.Checkbox != nil {
+			return *prop.Checkbox
+		}
+		return false
+	case "multi_select":
+		names := make([]string, 0, len(prop.MultiSelect))
+		for _, option := range prop.MultiSelect {
+			names = append(names, option.Name)
+		}
+		return names
+	case "relation":
+		return relationIDs(prop.Relation)
+	case "people":
+		return peopleNames(prop.People)
+	case "select", "date", "url", "email", "phone_number":
+		// Empty optional values become null rather than ""
+		if value := ds.propertyValueToString(prop); value != "" {
+			return value
+		}
+		return nil
+	default:
+		return ds.propertyValueToString(prop)
+	}
+}
+
 func (ds *databaseSync) richTextToString(richTexts []notion.RichText) string {
 	var text strings.Builder
 	for _, rt := range richTexts {
@@ -290,6 +828,26 @@ func (ds *databaseSync) richTextToString(richTexts []notion.RichText) string {
 	return text.String()
 }
 
+// compareCSVColumns returns, sorted, the schema's properties the CSV header
+// lacks and the header's columns the schema lacks
+func compareCSVColumns(header []string, schema map[string]notion.Property) (missing, unknown []string) {
+	columns := make(map[string]bool, len(header))
+	for _, column := range header {
+		columns[column] = true
+		if _, ok := schema[column]; !ok {
+			unknown = append(unknown, column)
+		}
+	}
+	for name := range schema {
+		if !columns[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unknown)
+	return missing, unknown
+}
+
 func (ds *databaseSync) convertCSVRowToProperties(record, header []string, schema map[string]notion.Property) (map[string]notion.PropertyValue, error) {
 	properties := make(map[string]notion.PropertyValue)
 
@@ -358,7 +916,7 @@ func (ds *databaseSync) stringToPropertyValue(value, propType string) (notion.Pr
 		return notion.PropertyValue{
 			Type: "select",
 			Select: &notion.SelectOption{
-				Name: value,
+				Name: strings.TrimSpace(value),
 			},
 		}, nil
 	case "multi_select":
@@ -432,18 +990,28 @@ func (ds *databaseSync) stringToPropertyValue(value, propType string) (notion.Pr
 	}
 }
 
-func (ds *databaseSync) inferPropertiesFromCSV(header, sampleData []string) map[string]notion.Property {
+// maxInferredSelectOptions is the most distinct values a text column may
+// have to be created as a select or multi-select
+const maxInferredSelectOptions = 25
+
+func (ds *databaseSync) inferPropertiesFromCSV(header []string, rows [][]string) map[string]notion.Property {
 	properties := make(map[string]notion.Property)
 
 	for i, columnName := range header {
-		var propType string
+		var values []string
+		for _, row := range rows {
+			if i < len(row) {
+				values = append(values, row[i])
+			}
+		}
 
-		// Analyze sample data to infer type
-		if i < len(sampleData) && sampleData[i] != "" {
-			value := sampleData[i]
-			propType = ds.inferPropertyType(value)
-		} else {
-			propType = "rich_text" // Default type
+		// Infer the type from the first value, falling back to text
+		propType := "rich_text"
+		for _, value := range values {
+			if value != "" {
+				propType = ds.inferPropertyType(value)
+				break
+			}
 		}
 
 		// First column is typically the title, override any inferred type
@@ -451,6 +1019,13 @@ func (ds *databaseSync) inferPropertiesFromCSV(header, sampleData []string) map[
 			propType = "title"
 		}
 
+		var options []notion.SelectOption
+		if propType == "rich_text" {
+			if selectType, selectOptions := inferSelectOptions(values); selectType != "" {
+				propType, options = selectType, selectOptions
+			}
+		}
+
 		var prop notion.Property
 		prop.Type = propType
 
@@ -462,6 +1037,10 @@ func (ds *databaseSync) inferPropertiesFromCSV(header, sampleData []string) map[
 			prop.Text = &notion.TextProperty{}
 		case "number":
 			prop.Number = &notion.NumberProperty{}
+		case "select":
+			prop.Select = &notion.SelectProperty{Options: options}
+		case "multi_select":
+			prop.MultiSelect = &notion.MultiSelectProperty{Options: options}
 		case "checkbox":
 			prop.Checkbox = &notion.CheckboxProperty{}
 		case "url":
@@ -480,6 +1059,56 @@ func (ds *databaseSync) inferPropertiesFromCSV(header, sampleData []string) map[
 	return properties
 }
 
+// inferSelectOptions decides whether a text column's values are picked from
+// a short list, returning "select" or "multi_select" and the options in the
+// order they first appear, or "" when the column is free text. Values with
+// commas are taken as multi-select lists, since select options can't contain
+// commas. Each option must be used at least twice on average.
+func inferSelectOptions(values []string) (string, []notion.SelectOption) {
+	propType := "select"
+	for _, value := range values {
+		if strings.Contains(value, ",") {
+			propType = "multi_select"
+			break
+		}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	used := 0
+	for _, value := range values {
+		parts := []string{value}
+		if propType == "multi_select" {
+			parts = strings.Split(value, ",")
+		}
+		for _, part := range parts {
+			name := strings.TrimSpace(part)
+			if name == "" {
+				continue
+			}
+			if len(name) > 100 {
+				// Longer than Notion allows for an option name
+				return "", nil
+			}
+			used++
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) == 0 || len(names) > maxInferredSelectOptions || len(names)*2 > used {
+		return "", nil
+	}
+
+	options := make([]notion.SelectOption, len(names))
+	for i, name := range names {
+		options[i] = notion.SelectOption{Name: name}
+	}
+	return propType, options
+}
+
 func (ds *databaseSync) inferPropertyType(value string) string {
 	// Try to infer type from value
 	if _, err := strconv.ParseFloat(value, 64); err == nil {
@@ -0,0 +1,579 @@
+package sync
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMixedTypeDatabaseSync returns a DatabaseSync backed by a database with
+// title, number, checkbox, select and multi-select columns
+func newMixedTypeDatabaseSync() DatabaseSync {
+	price := 19.5
+	qty := 3.0
+	inStock := true
+	outOfStock := false
+
+	mockNotion := &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{
+				ID: databaseID,
+				Properties: map[string]notion.Property{
+					"Name":     {Type: "title"},
+					"Price":    {Type: "number"},
+					"Quantity": {Type: "number"},
+					"In Stock": {Type: "checkbox"},
+					"Category": {Type: "select"},
+					"Tags":     {Type: "multi_select"},
+				},
+			}, nil
+		},
+		queryDatabaseFunc: func(ctx context.Context, databaseID string, request *notion.DatabaseQueryRequest) (*notion.DatabaseQueryResponse, error) {
+			return &notion.DatabaseQueryResponse{
+				Results: []notion.DatabaseRow{
+					{
+						ID: "row-1",
+						Properties: map[string]notion.PropertyValue{
+							"Name":     {Type: "title", Title: []notion.RichText{{PlainText: "Widget | Large"}}},
+							"Price":    {Type: "number", Number: &price},
+							"Quantity": {Type: "number", Number: &qty},
+							"In Stock": {Type: "checkbox", Checkbox: &inStock},
+							"Category": {Type: "select", Select: &notion.SelectOption{Name: "Hardware"}},
+							"Tags":     {Type: "multi_select", MultiSelect: []notion.SelectOption{{Name: "new"}, {Name: "sale"}}},
+						},
+					},
+					{
+						ID: "row-2",
+						Properties: map[string]notion.PropertyValue{
+							"Name":     {Type: "title", Title: []notion.RichText{{PlainText: "Gadget"}}},
+							"Price":    {Type: "number"},
+							"In Stock": {Type: "checkbox", Checkbox: &outOfStock},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	return NewDatabaseSync(mockNotion)
+}
+
+func TestDatabaseSync_ExportNotionDatabase_CSV(t *testing.T) {
+	ds := newMixedTypeDatabaseSync()
+	outputPath := filepath.Join(t.TempDir(), "db.csv")
+
+	err := ds.ExportNotionDatabase(context.Background(), "db-id", outputPath, ExportFormatCSV)
+	require.NoError(t, err)
+
+	file, err := os.Open(outputPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	header := records[0]
+	row := make(map[string]string)
+	for i, name := range header {
+		row[name] = records[1][i]
+	}
+	assert.Equal(t, "Widget | Large", row["Name"])
+	assert.Equal(t, "19.5", row["Price"])
+	assert.Equal(t, "3", row["Quantity"])
+	assert.Equal(t, "true", row["In Stock"])
+	assert.Equal(t, "Hardware", row["Category"])
+	assert.Equal(t, "new, sale", row["Tags"])
+}
+
+func TestDatabaseSync_ExportNotionDatabase_JSON(t *testing.T) {
+	ds := newMixedTypeDatabaseSync()
+	outputPath := filepath.Join(t.TempDir(), "db.json")
+
+	err := ds.ExportNotionDatabase(context.Background(), "db-id", outputPath, ExportFormatJSON)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &records))
+	require.Len(t, records, 2)
+
+	first := records[0]
+	assert.Equal(t, "Widget | Large", first["Name"])
+	assert.IsType(t, float64(0), first["Price"], "numbers must stay numeric")
+	assert.Equal(t, 19.5, first["Price"])
+	assert.Equal(t, float64(3), first["Quantity"])
+	assert.Equal(t, true, first["In Stock"])
+	assert.Equal(t, "Hardware", first["Category"])
+	assert.Equal(t, []interface{}{"new", "sale"}, first["Tags"])
+
+	second := records[1]
+	assert.Nil(t, second["Price"])
+	assert.Nil(t, second["Quantity"])
+	assert.Equal(t, false, second["In Stock"])
+	assert.Nil(t, second["Category"])
+}
+
+func TestDatabaseSync_ExportNotionDatabase_Markdown(t *testing.T) {
+	ds := newMixedTypeDatabaseSync()
+	outputPath := filepath.Join(t.TempDir(), "db.md")
+
+	err := ds.ExportNotionDatabase(context.Background(), "db-id", outputPath, ExportFormatMarkdown)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "| --- | --- | --- | --- | --- | --- |", lines[1])
+	assert.Contains(t, lines[2], `| Widget \| Large |`)
+	assert.Contains(t, lines[2], "| 19.5 |")
+	assert.Contains(t, lines[2], "| true |")
+	assert.Contains(t, lines[3], "| Gadget |")
+}
+
+func TestDatabaseSync_ExportNotionDatabase_UnsupportedFormat(t *testing.T) {
+	ds := newMixedTypeDatabaseSync()
+	outputPath := filepath.Join(t.TempDir(), "db.xml")
+
+	err := ds.ExportNotionDatabase(context.Background(), "db-id", outputPath, "xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported export format")
+
+	_, statErr := os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(statErr), "no file should be created for an unsupported format")
+}
+
+func TestDatabaseSync_ExportNotionDatabase_ExpandsTruncatedRelations(t *testing.T) {
+	// Notion includes at most 25 related pages in the page object
+	var truncated, all []notion.RelationValue
+	var wantIDs []string
+	for i := 0; i < 40; i++ {
+		relation := notion.RelationValue{ID: fmt.Sprintf("related-%d", i)}
+		if i < 25 {
+			truncated = append(truncated, relation)
+		}
+		all = append(all, relation)
+		wantIDs = append(wantIDs, relation.ID)
+	}
+
+	var requested []string
+	mockNotion := &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{
+				ID: databaseID,
+				Properties: map[string]notion.Property{
+					"Name":  {Type: "title"},
+					"Links": {Type: "relation"},
+				},
+			}, nil
+		},
+		queryDatabaseFunc: func(ctx context.Context, databaseID string, request *notion.DatabaseQueryRequest) (*notion.DatabaseQueryResponse, error) {
+			return &notion.DatabaseQueryResponse{
+				Results: []notion.DatabaseRow{
+					{
+						ID: "row-1",
+						Properties: map[string]notion.PropertyValue{
+							"Name":  {Type: "title", Title: []notion.RichText{{PlainText: "Hub"}}},
+							"Links": {ID: "rel%3A", Type: "relation", Relation: truncated, HasMore: true},
+						},
+					},
+					{
+						ID: "row-2",
+						Properties: map[string]notion.PropertyValue{
+							"Name":  {Type: "title", Title: []notion.RichText{{PlainText: "Leaf"}}},
+							"Links": {ID: "rel%3A", Type: "relation", Relation: truncated[:1]},
+						},
+					},
+				},
+			}, nil
+		},
+		getPagePropertyFunc: func(ctx context.Context, pageID, propertyID string) (*notion.PropertyValue, error) {
+			requested = append(requested, pageID+"/"+propertyID)
+			return &notion.PropertyValue{Type: "relation", Relation: all}, nil
+		},
+	}
+
+	ds := NewDatabaseSync(mockNotion)
+	outputPath := filepath.Join(t.TempDir(), "db.json")
+	require.NoError(t, ds.ExportNotionDatabase(context.Background(), "db-id", outputPath, ExportFormatJSON))
+
+	// Only the truncated property is fetched again
+	assert.Equal(t, []string{"row-1/rel%3A"}, requested)
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &rows))
+	require.Len(t, rows, 2)
+
+	var links []string
+	for _, id := range rows[0]["Links"].([]interface{}) {
+		links = append(links, id.(string))
+	}
+	assert.Equal(t, wantIDs, links)
+	assert.Len(t, rows[1]["Links"], 1)
+}
+
+// writeItemsCSV writes a CSV with a Name column holding Item 1 to Item n
+func writeItemsCSV(t *testing.T, n int) string {
+	t.Helper()
+	var sb strings.Builder
+	sb.WriteString("Name\n")
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "Item %d\n", i)
+	}
+	csvPath := filepath.Join(t.TempDir(), "items.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte(sb.String()), 0644))
+	return csvPath
+}
+
+func TestDatabaseSync_ImportCSVToNotionDatabase_Concurrent(t *testing.T) {
+	failing := map[string]bool{"Item 7": true, "Item 33": true}
+
+	var mu gosync.Mutex
+	created := make(map[string]bool)
+	inFlight, maxInFlight := 0, 0
+
+	mockNotion := &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{ID: databaseID, Properties: map[string]notion.Property{
+				"Name": {Type: "title"},
+			}}, nil
+		},
+		createDatabaseRowFunc: func(ctx context.Context, databaseID string, properties map[string]notion.PropertyValue) (*notion.DatabaseRow, error) {
+			name := properties["Name"].Title[0].PlainText
+
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+
+			time.Sleep(2 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			inFlight--
+			if failing[name] {
+				return nil, fmt.Errorf("validation failed for %s", name)
+			}
+			created[name] = true
+			return &notion.DatabaseRow{ID: "row-" + name}, nil
+		},
+	}
+
+	ds := NewDatabaseSync(mockNotion)
+	result, err := ds.ImportCSVToNotionDatabase(context.Background(), writeItemsCSV(t, 50), "db-id",
+		CSVImportOptions{Workers: 8, ContinueOnError: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 48, result.Created)
+	assert.Len(t, created, 48)
+	assert.Greater(t, maxInFlight, 1, "rows should be created concurrently")
+	assert.LessOrEqual(t, maxInFlight, 8)
+
+	// Item N sits on CSV row N+1, below the header
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, 8, result.Errors[0].Row)
+	assert.Contains(t, result.Errors[0].Error(), "Item 7")
+	assert.Equal(t, 34, result.Errors[1].Row)
+	assert.Contains(t, result.Errors[1].Error(), "Item 33")
+}
+
+func TestDatabaseSync_SyncCSVToNotionDatabase_StopsAtFirstFailure(t *testing.T) {
+	var mu gosync.Mutex
+	var created []string
+
+	mockNotion := &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{ID: databaseID, Properties: map[string]notion.Property{
+				"Name": {Type: "title"},
+			}}, nil
+		},
+		createDatabaseRowFunc: func(ctx context.Context, databaseID string, properties map[string]notion.PropertyValue) (*notion.DatabaseRow, error) {
+			name := properties["Name"].Title[0].PlainText
+			if name == "Item 3" {
+				return nil, fmt.Errorf("validation failed")
+			}
+			mu.Lock()
+			created = append(created, name)
+			mu.Unlock()
+			return &notion.DatabaseRow{ID: "row-" + name}, nil
+		},
+	}
+
+	ds := NewDatabaseSync(mockNotion)
+	result, err := ds.ImportCSVToNotionDatabase(context.Background(), writeItemsCSV(t, 10), "db-id",
+		CSVImportOptions{Workers: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Item 1", "Item 2"}, created)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 4, result.Errors[0].Row)
+
+	err = ds.SyncCSVToNotionDatabase(context.Background(), writeItemsCSV(t, 10), "db-id")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 4")
+}
+
+// schemaClient serves a database with Name, Count and Done columns and
+// records the rows created in it
+func schemaClient(created *[]string) *mockNotionClient {
+	return &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{ID: databaseID, Properties: map[string]notion.Property{
+				"Name":  {Type: "title"},
+				"Count": {Type: "number"},
+				"Done":  {Type: "checkbox"},
+			}}, nil
+		},
+		createDatabaseRowFunc: func(ctx context.Context, databaseID string, properties map[string]notion.PropertyValue) (*notion.DatabaseRow, error) {
+			*created = append(*created, properties["Name"].Title[0].PlainText)
+			return &notion.DatabaseRow{ID: "row"}, nil
+		},
+	}
+}
+
+func TestDatabaseSync_ImportCSVToNotionDatabase_UnknownColumn(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "items.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("Name,Count,Owner\nFirst,1,ana\nSecond,2,ben\n"), 0644))
+
+	var created []string
+	ds := NewDatabaseSync(schemaClient(&created))
+
+	result, err := ds.ImportCSVToNotionDatabase(context.Background(), csvPath, "db-id", CSVImportOptions{Workers: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Owner")
+	assert.Empty(t, created, "no row is created when the header doesn't match")
+	assert.Equal(t, []string{"Owner"}, result.UnknownColumns)
+	assert.Equal(t, []string{"Done"}, result.MissingColumns)
+
+	result, err = ds.ImportCSVToNotionDatabase(context.Background(), csvPath, "db-id",
+		CSVImportOptions{Workers: 1, SkipUnknownColumns: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, []string{"First", "Second"}, created)
+}
+
+func TestDatabaseSync_ImportCSVToNotionDatabase_TypeMismatch(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "items.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("Name,Count,Done\nFirst,1,true\nSecond,two,false\nThird,3,maybe\n"), 0644))
+
+	var created []string
+	ds := NewDatabaseSync(schemaClient(&created))
+
+	result, err := ds.ImportCSVToNotionDatabase(context.Background(), csvPath, "db-id", CSVImportOptions{Workers: 1})
+	require.NoError(t, err)
+	assert.Empty(t, created, "bad values are found before any row is created")
+	assert.Zero(t, result.Created)
+
+	// Every bad row is reported, not just the first
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	assert.Contains(t, result.Errors[0].Error(), "invalid number: two")
+	assert.Equal(t, 4, result.Errors[1].Row)
+	assert.Contains(t, result.Errors[1].Error(), "invalid boolean: maybe")
+}
+
+// pagedDatabaseClient serves a database with a title and number column whose
+// rows are split over several query pages, optionally failing one page
+func pagedDatabaseClient(pages, rowsPerPage, failPage int) *mockNotionClient {
+	return &mockNotionClient{
+		getDatabaseFunc: func(ctx context.Context, databaseID string) (*notion.Database, error) {
+			return &notion.Database{ID: databaseID, Properties: map[string]notion.Property{
+				"Name":  {Type: "title"},
+				"Index": {Type: "number"},
+			}}, nil
+		},
+		queryDatabaseFunc: func(ctx context.Context, databaseID string, request *notion.DatabaseQueryRequest) (*notion.DatabaseQueryResponse, error) {
+			page := 0
+			if request.StartCursor != nil {
+				page, _ = strconv.Atoi(*request.StartCursor)
+			}
+			if page == failPage {
+				return nil, fmt.Errorf("query failed on page %d", page)
+			}
+
+			resp := &notion.DatabaseQueryResponse{}
+			for i := 0; i < rowsPerPage; i++ {
+				index := float64(page*rowsPerPage + i)
+				resp.Results = append(resp.Results, notion.DatabaseRow{
+					ID: fmt.Sprintf("row-%d", int(index)),
+					Properties: map[string]notion.PropertyValue{
+						"Name":  {Type: "title", Title: []notion.RichText{{PlainText: fmt.Sprintf("Row %d", int(index))}}},
+						"Index": {Type: "number", Number: &index},
+					},
+				})
+			}
+			if page < pages-1 {
+				next := strconv.Itoa(page + 1)
+				resp.HasMore = true
+				resp.NextCursor = &next
+			}
+			return resp, nil
+		},
+	}
+}
+
+func TestDatabaseSync_SyncNotionDatabaseToCSV_StreamsPages(t *testing.T) {
+	ds := NewDatabaseSync(pagedDatabaseClient(3, 40, -1))
+	csvPath := filepath.Join(t.TempDir(), "db.csv")
+
+	require.NoError(t, ds.SyncNotionDatabaseToCSV(context.Background(), "db-id", csvPath))
+
+	file, err := os.Open(csvPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 121)
+
+	// Title column first, then the rest by name
+	assert.Equal(t, []string{"Name", "Index"}, records[0])
+	for i, record := range records[1:] {
+		assert.Equal(t, []string{fmt.Sprintf("Row %d", i), strconv.Itoa(i)}, record)
+	}
+}
+
+func TestDatabaseSync_SyncNotionDatabaseToCSV_RemovesPartialExport(t *testing.T) {
+	ds := NewDatabaseSync(pagedDatabaseClient(3, 40, 2))
+	csvPath := filepath.Join(t.TempDir(), "db.csv")
+
+	err := ds.SyncNotionDatabaseToCSV(context.Background(), "db-id", csvPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query failed on page 2")
+	assert.NoFileExists(t, csvPath)
+}
+
+func TestDatabaseSync_ExportNotionDatabaseToCSV_Resume(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "db.csv")
+	cursorPath := csvPath + csvExportCursorSuffix
+	opts := CSVExportOptions{Resume: true}
+
+	// The first run is interrupted on the third page and keeps what it wrote
+	err := NewDatabaseSync(pagedDatabaseClient(4, 40, 2)).ExportNotionDatabaseToCSV(context.Background(), "db-id", csvPath, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query failed on page 2")
+	require.FileExists(t, cursorPath)
+
+	checkpoint, err := readCSVExportCheckpoint(cursorPath)
+	require.NoError(t, err)
+	assert.Equal(t, "2", checkpoint.NextCursor)
+
+	// Rows written after the last checkpoint are dropped when resuming
+	file, err := os.OpenFile(csvPath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString("Row 80,80\nRow 8")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// A checkpoint from another database is refused
+	err = NewDatabaseSync(pagedDatabaseClient(4, 40, -1)).ExportNotionDatabaseToCSV(context.Background(), "other-db", csvPath, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not other-db")
+
+	var queried []string
+	client := pagedDatabaseClient(4, 40, -1)
+	query := client.queryDatabaseFunc
+	client.queryDatabaseFunc = func(ctx context.Context, databaseID string, request *notion.DatabaseQueryRequest) (*notion.DatabaseQueryResponse, error) {
+		cursor := ""
+		if request.StartCursor != nil {
+			cursor = *request.StartCursor
+		}
+		queried = append(queried, cursor)
+		return query(ctx, databaseID, request)
+	}
+	require.NoError(t, NewDatabaseSync(client).ExportNotionDatabaseToCSV(context.Background(), "db-id", csvPath, opts))
+	assert.Equal(t, []string{"2", "3"}, queried, "pages already exported are not fetched again")
+	assert.NoFileExists(t, cursorPath)
+
+	file, err = os.Open(csvPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 161)
+	assert.Equal(t, []string{"Name", "Index"}, records[0])
+	for i, record := range records[1:] {
+		assert.Equal(t, []string{fmt.Sprintf("Row %d", i), strconv.Itoa(i)}, record)
+	}
+}
+
+func TestDatabaseSync_ExportNotionDatabaseToCSV_After(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "db.csv")
+	ds := NewDatabaseSync(pagedDatabaseClient(3, 40, -1))
+
+	require.NoError(t, ds.ExportNotionDatabaseToCSV(context.Background(), "db-id", csvPath, CSVExportOptions{After: "2"}))
+
+	file, err := os.Open(csvPath)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 41, "a new file still gets a header")
+	assert.Equal(t, []string{"Row 80", "80"}, records[1])
+}
+
+func TestDatabaseSync_CreateDatabaseFromCSV_InfersSelectOptions(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "tasks.csv")
+	content := "Name,Priority,Tags,Notes\n" +
+		"Write docs,High,\"docs, writing\",Needs review\n" +
+		"Fix bug,Low,code,\n" +
+		"Plan sprint,Medium,\"planning, docs\",Next week\n" +
+		"Release,High,code,Tag and publish\n" +
+		"Triage,Low,\"planning, code\",Every Monday\n" +
+		"Retro,Medium,planning,\n"
+	require.NoError(t, os.WriteFile(csvPath, []byte(content), 0644))
+
+	var request *notion.CreateDatabaseRequest
+	var rows []map[string]notion.PropertyValue
+	client := &mockNotionClient{
+		createDatabaseFunc: func(ctx context.Context, req *notion.CreateDatabaseRequest) (*notion.Database, error) {
+			request = req
+			return &notion.Database{ID: "new-database-id", Properties: req.Properties}, nil
+		},
+		createDatabaseRowFunc: func(ctx context.Context, databaseID string, properties map[string]notion.PropertyValue) (*notion.DatabaseRow, error) {
+			rows = append(rows, properties)
+			return &notion.DatabaseRow{ID: fmt.Sprintf("row-%d", len(rows))}, nil
+		},
+	}
+
+	_, err := NewDatabaseSync(client).CreateDatabaseFromCSV(context.Background(), csvPath, "parent-id")
+	require.NoError(t, err)
+	require.NotNil(t, request)
+
+	priority := request.Properties["Priority"]
+	assert.Equal(t, "select", priority.Type)
+	require.NotNil(t, priority.Select)
+	assert.Equal(t, []notion.SelectOption{{Name: "High"}, {Name: "Low"}, {Name: "Medium"}}, priority.Select.Options)
+
+	tags := request.Properties["Tags"]
+	assert.Equal(t, "multi_select", tags.Type)
+	require.NotNil(t, tags.MultiSelect)
+	assert.Equal(t, []notion.SelectOption{{Name: "docs"}, {Name: "writing"}, {Name: "code"}, {Name: "planning"}}, tags.MultiSelect.Options)
+
+	assert.Equal(t, "rich_text", request.Properties["Notes"].Type, "mostly distinct values stay text")
+
+	require.Len(t, rows, 6)
+	assert.Equal(t, "High", rows[0]["Priority"].Select.Name)
+	assert.Len(t, rows[0]["Tags"].MultiSelect, 2)
+}
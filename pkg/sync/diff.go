@@ -0,0 +1,242 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// ANSI colors used for diff output
+const (
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+	colorReset = "\033[0m"
+)
+
+// NormalizeContent normalizes line endings and trailing whitespace so that
+// local and remote markdown only differ where their content does
+func NormalizeContent(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// DiffFile returns a unified diff of what syncing filePath in the given
+// direction would change: for push, Notion's content is replaced by the local
+// file; for pull, the local file is replaced by Notion's content. Only read
+// calls are made against Notion. An empty string means there are no changes.
+func (e *engine) DiffFile(ctx context.Context, filePath, direction string) (string, error) {
+	doc, err := e.parser.ParseFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse markdown file: %w", err)
+	}
+
+	frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract frontmatter: %w", err)
+	}
+
+	// The title the file would be pushed under
+	title := frontmatter.Title
+	if title == "" {
+		title = e.getTitleFromFilename(filePath)
+	}
+
+	// A file without a page would be created from scratch on push
+	remoteContent := ""
+	remoteName := "notion:(new page)"
+	if frontmatter.NotionID != "" {
+		blocks, err := e.notion.GetPageBlocks(ctx, frontmatter.NotionID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get page blocks: %w", err)
+		}
+		e.resolveUserMentions(ctx, blocks)
+		e.inlineImages(ctx, blocks)
+
+		remoteContent, err = e.converter.BlocksToMarkdown(blocks)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert blocks to markdown: %w", err)
+		}
+		remoteContent = e.normalizePulled(remoteContent)
+		remoteContent = e.restoreTitleHeading(remoteContent, title)
+		remoteName = "notion:" + frontmatter.NotionID
+	}
+
+	switch direction {
+	case "push":
+		return UnifiedDiff(remoteName, filePath, remoteContent, doc.Content), nil
+	case "pull":
+		if frontmatter.NotionID == "" {
+			return "", fmt.Errorf("no notion_id found in frontmatter - cannot pull without page ID")
+		}
+		return UnifiedDiff(filePath, remoteName, doc.Content, remoteContent), nil
+	default:
+		return "", fmt.Errorf("unsupported diff direction: %s (expected push or pull)", direction)
+	}
+}
+
+// diffLine is a single line of a line-level diff
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// UnifiedDiff returns a unified diff turning from into to. Both sides are
+// normalized first; identical content yields an empty string.
+func UnifiedDiff(fromName, toName, from, to string) string {
+	from = NormalizeContent(from)
+	to = NormalizeContent(to)
+	if from == to {
+		return ""
+	}
+
+	lines := diffLines(from, to)
+
+	var sb strings.Builder
+	sb.WriteString("--- " + fromName + "\n")
+	sb.WriteString("+++ " + toName + "\n")
+
+	for _, h := range buildHunks(lines) {
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", h.oldStart, h.oldCount, h.newStart, h.newCount)
+		for _, line := range lines[h.first:h.last] {
+			sb.WriteString(string(line.op) + line.text + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// ColorizeDiff adds ANSI colors to the lines of a unified diff
+func ColorizeDiff(diff string) string {
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "-"):
+			text = colorRed + text + colorReset
+		case strings.HasPrefix(text, "+"):
+			text = colorGreen + text + colorReset
+		case strings.HasPrefix(text, "@@"):
+			text = colorCyan + text + colorReset
+		}
+		lines[i] = text + line[len(strings.TrimSuffix(line, "\n")):]
+	}
+	return strings.Join(lines, "")
+}
+
+// diffLines computes a line-level diff between two texts
+func diffLines(from, to string) []diffLine {
+	dmp := diffmatchpatch.New()
+	fromChars, toChars, lineArray := dmp.DiffLinesToChars(from+"\n", to+"\n")
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(fromChars, toChars, false), lineArray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			lines = append(lines, diffLine{op: op, text: strings.TrimSuffix(text, "\n")})
+		}
+	}
+	return lines
+}
+
+// hunk is a range of diff lines [first, last) with its unified header counts
+type hunk struct {
+	first, last        int
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+// buildHunks groups changed lines, with surrounding context, into hunks
+func buildHunks(lines []diffLine) []hunk {
+	var hunks []hunk
+
+	for i := 0; i < len(lines); i++ {
+		if lines[i].op == ' ' {
+			continue
+		}
+
+		first := i - diffContextLines
+		if first < 0 {
+			first = 0
+		}
+
+		// Extend over changes separated by no more than twice the context
+		last := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				last = j
+			} else if j-last > 2*diffContextLines {
+				break
+			}
+		}
+		end := last + diffContextLines + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		hunks = append(hunks, hunk{first: first, last: end})
+		i = end - 1
+	}
+
+	// Compute line numbers for each hunk header
+	oldLine, newLine, next := 1, 1, 0
+	for i, line := range lines {
+		if next < len(hunks) && i == hunks[next].first {
+			hunks[next].oldStart, hunks[next].newStart = oldLine, newLine
+		}
+		if next < len(hunks) && i >= hunks[next].first && i < hunks[next].last {
+			if line.op != '+' {
+				hunks[next].oldCount++
+			}
+			if line.op != '-' {
+				hunks[next].newCount++
+			}
+		}
+		if line.op != '+' {
+			oldLine++
+		}
+		if line.op != '-' {
+			newLine++
+		}
+		if next < len(hunks) && i == hunks[next].last-1 {
+			next++
+		}
+	}
+
+	// An empty side refers to the line before the hunk
+	for i := range hunks {
+		if hunks[i].oldCount == 0 {
+			hunks[i].oldStart--
+		}
+		if hunks[i].newCount == 0 {
+			hunks[i].newStart--
+		}
+	}
+
+	return hunks
+}
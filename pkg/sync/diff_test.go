@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeContent(t *testing.T) {
+	assert.Equal(t, "# Title\n\nBody", NormalizeContent("# Title  \r\n\r\nBody\t\n\n"))
+	assert.False(t, HasConflict("line\r\n", "line"))
+	assert.True(t, HasConflict("line one", "line two"))
+}
+
+func TestUnifiedDiff_OneLineChange(t *testing.T) {
+	from := "# Title\n\nline 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8"
+	to := strings.Replace(from, "line 5", "line five", 1)
+
+	expected := "--- a.md\n" +
+		"+++ b.md\n" +
+		"@@ -4,7 +4,7 @@\n" +
+		" line 2\n" +
+		" line 3\n" +
+		" line 4\n" +
+		"-line 5\n" +
+		"+line five\n" +
+		" line 6\n" +
+		" line 7\n" +
+		" line 8\n"
+	assert.Equal(t, expected, UnifiedDiff("a.md", "b.md", from, to))
+
+	assert.Empty(t, UnifiedDiff("a.md", "b.md", from, from+"\n"))
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	from := strings.Join(lines, "\n")
+	lines[0] = "first"
+	lines[19] = "last"
+	to := strings.Join(lines, "\n")
+
+	diff := UnifiedDiff("a", "b", from, to)
+	assert.Contains(t, diff, "@@ -1,4 +1,4 @@\n")
+	assert.Contains(t, diff, "@@ -17,4 +17,4 @@\n")
+}
+
+func TestColorizeDiff(t *testing.T) {
+	colored := ColorizeDiff("--- a\n+++ b\n@@ -1 +1 @@\n-old\n+new\n same\n")
+	assert.Contains(t, colored, colorRed+"-old"+colorReset+"\n")
+	assert.Contains(t, colored, colorGreen+"+new"+colorReset+"\n")
+	assert.Contains(t, colored, colorCyan+"@@ -1 +1 @@"+colorReset+"\n")
+	assert.Contains(t, colored, "\n same\n")
+}
+
+func TestEngine_DiffFile(t *testing.T) {
+	e, mockNotion, _, mockConverter := createTestEngine(t)
+	e.parser = markdown.NewParser()
+
+	filePath := filepath.Join(e.config.Directories.MarkdownRoot, "page.md")
+	content := "---\nnotion_id: page-id\n---\n# Title\n\nUnchanged\nLocal edit\n"
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		assert.Equal(t, "page-id", pageID)
+		return []notion.Block{}, nil
+	}
+	mockConverter.blocksToMarkdownFunc = func(blocks []notion.Block) (string, error) {
+		return "# Title\n\nUnchanged\nRemote text\n", nil
+	}
+
+	push, err := e.DiffFile(context.Background(), filePath, "push")
+	require.NoError(t, err)
+	assert.Equal(t, "--- notion:page-id\n"+
+		"+++ "+filePath+"\n"+
+		"@@ -1,4 +1,4 @@\n"+
+		" # Title\n"+
+		" \n"+
+		" Unchanged\n"+
+		"-Remote text\n"+
+		"+Local edit\n", push)
+
+	pull, err := e.DiffFile(context.Background(), filePath, "pull")
+	require.NoError(t, err)
+	assert.Contains(t, pull, "--- "+filePath+"\n+++ notion:page-id\n")
+	assert.Contains(t, pull, "-Local edit\n+Remote text\n")
+
+	_, err = e.DiffFile(context.Background(), filePath, "bidirectional")
+	assert.Error(t, err)
+}
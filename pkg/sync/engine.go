@@ -2,12 +2,18 @@ package sync
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	gosync "sync"
 	"time"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/concurrent/batch"
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
 	"github.com/byvfx/go-notion-md-sync/pkg/notion"
@@ -19,6 +25,8 @@ type Engine interface {
 	SyncNotionToFile(ctx context.Context, pageID, filePath string) error
 	SyncAll(ctx context.Context, direction string) error
 	SyncSpecificFile(ctx context.Context, filename, direction string) error
+	SyncPageSubtree(ctx context.Context, pageID, direction string) error
+	DiffFile(ctx context.Context, filePath, direction string) (string, error)
 }
 
 type engine struct {
@@ -27,39 +35,87 @@ type engine struct {
 	parser           markdown.Parser
 	converter        Converter
 	conflictResolver *ConflictResolver
-	workerCount      int // Configurable worker count
+	workerCount      int                    // Configurable worker count
+	workerScaling    string                 // How concurrent pulls size their workers: WorkerScalingStatic or WorkerScalingAdaptive
+	fileNames        *util.FileNameRegistry // Keeps sanitized page/database names unique
+	progress         ProgressFunc           // Optional; replaces per-page output when set
+	logger           *util.Logger           // Optional; the default logger when nil
+	maxDepth         int                    // Levels of sub-pages a pull descends; negative is unlimited
+	flatten          bool                   // Pull every page straight into the markdown root
+	inlineImageMax   int64                  // Pull images up to this many bytes as data URIs; 0 keeps links
+	assets           *assetStore            // Optional; saves Notion-hosted files on pull when set
+	includeArchived  bool                   // Pull the content of archived pages too
+	orderedOutput    bool                   // Print concurrent pull status a page at a time, in page order
+	prePush          []Transform            // Applied to a file's body before it is converted for Notion
+	pruneMode        string                 // Directories a pull removes afterwards: PruneEmptyDirs, PruneStaleDirs or none
+	detectRenames    bool                   // Move the files of renamed or moved pages instead of pulling them as new files
+	postPull         []Transform            // Applied to a pulled body before it is written
+
+	ignoreOnce gosync.Once
+	ignore     *util.IgnoreMatcher // excluded_patterns and .notionignore, loaded on first use
 }
 
-func NewEngine(cfg *config.Config) Engine {
-	// Create the appropriate client based on configuration
-	var client notion.Client
-	if cfg.Performance.UseMultiClient {
-		// Use multi-client approach for maximum throughput
-		client = notion.NewBatchClient(cfg.Notion.Token, cfg.Performance.ClientCount)
-	} else {
-		// Use standard client (proven best performance)
-		client = notion.NewClient(cfg.Notion.Token)
+// log returns the logger all engine output goes through
+func (e *engine) log() *util.Logger {
+	if e.logger != nil {
+		return e.logger
 	}
+	return util.GetDefaultLogger()
+}
 
+func NewEngine(cfg *config.Config) Engine {
 	return &engine{
 		config:           cfg,
-		notion:           client,
-		parser:           markdown.NewParser(),
-		converter:        NewConverter(),
+		notion:           newNotionClient(cfg),
+		parser:           newParser(cfg),
+		converter:        NewConverterWithOptions(converterOptions(cfg)),
 		conflictResolver: NewConflictResolver(cfg.Sync.ConflictResolution),
 		workerCount:      cfg.Performance.Workers, // Use configured worker count
+		workerScaling:    cfg.Performance.WorkerScaling,
+		fileNames:        util.NewFileNameRegistry(),
+		maxDepth:         notion.UnlimitedDepth,
 	}
 }
 
+// converterOptions returns the converter settings in cfg
+func converterOptions(cfg *config.Config) ConverterOptions {
+	return ConverterOptions{PreserveHTML: cfg.Sync.PreserveHTML, Flavor: cfg.Sync.Flavor, LinkStyle: cfg.Sync.LinkStyle}
+}
+
+// newParser returns a markdown parser that writes files as cfg says
+func newParser(cfg *config.Config) markdown.Parser {
+	return markdown.NewParser(markdown.WithLineEnding(cfg.Sync.LineEndings))
+}
+
+// newNotionClient creates the appropriate client based on configuration
+func newNotionClient(cfg *config.Config, opts ...notion.ClientOption) notion.Client {
+	opts = append([]notion.ClientOption{
+		notion.WithCircuitBreaker(cfg.Performance.CircuitBreakerThreshold, cfg.Performance.CircuitBreakerCooldown),
+		notion.WithDeleteWorkers(cfg.Performance.DeleteWorkers),
+		notion.WithMaxPages(cfg.Sync.MaxPages),
+	}, opts...)
+
+	if cfg.Performance.UseMultiClient {
+		// Use multi-client approach for maximum throughput; all sub-clients
+		// share one global rate limiter so they can't exceed Notion's limit
+		return notion.NewBatchClientWithRateLimit(cfg.Notion.Token, cfg.Performance.ClientCount, cfg.Performance.RequestsPerSecond, opts...)
+	}
+
+	// Use standard client (proven best performance)
+	return notion.NewClient(cfg.Notion.Token, opts...)
+}
+
 // NewEngineWithWorkers creates an engine with a specific worker count
 func NewEngineWithWorkers(cfg *config.Config, workers int) Engine {
 	return &engine{
 		config:           cfg,
 		notion:           notion.NewClient(cfg.Notion.Token),
-		parser:           markdown.NewParser(),
-		converter:        NewConverter(),
+		parser:           newParser(cfg),
+		converter:        NewConverterWithOptions(converterOptions(cfg)),
 		conflictResolver: NewConflictResolver(cfg.Sync.ConflictResolution),
 		workerCount:      workers,
+		fileNames:        util.NewFileNameRegistry(),
+		maxDepth:         notion.UnlimitedDepth,
 	}
 }
 
@@ -68,14 +124,24 @@ func NewEngineWithClient(cfg *config.Config, client notion.Client) Engine {
 	return &engine{
 		config:           cfg,
 		notion:           client,
-		parser:           markdown.NewParser(),
-		converter:        NewConverter(),
+		parser:           newParser(cfg),
+		converter:        NewConverterWithOptions(converterOptions(cfg)),
 		conflictResolver: NewConflictResolver(cfg.Sync.ConflictResolution),
 		workerCount:      0,
+		workerScaling:    cfg.Performance.WorkerScaling,
+		fileNames:        util.NewFileNameRegistry(),
+		maxDepth:         notion.UnlimitedDepth,
 	}
 }
 
 func (e *engine) SyncFileToNotion(ctx context.Context, filePath string) error {
+	return withOperationTimeout(ctx, e.config.Timeouts.PageUpdate, "timeouts.page_update", func(ctx context.Context) error {
+		return e.pushFile(ctx, filePath)
+	})
+}
+
+// pushFile creates or updates the page for filePath
+func (e *engine) pushFile(ctx context.Context, filePath string) error {
 	// Parse markdown file
 	doc, err := e.parser.ParseFile(filePath)
 	if err != nil {
@@ -88,15 +154,20 @@ func (e *engine) SyncFileToNotion(ctx context.Context, filePath string) error {
 		return fmt.Errorf("failed to extract frontmatter: %w", err)
 	}
 
-	// Skip if sync is disabled
+	if frontmatter.SyncAction != "" {
+		return e.runSyncAction(ctx, filePath, doc, frontmatter)
+	}
+
+	// A disabled file freezes its page: nothing is written to Notion
 	if !frontmatter.SyncEnabled {
+		if frontmatter.NotionID != "" {
+			e.statusf("  Sync disabled, leaving page %s unchanged: %s\n", frontmatter.NotionID, filePath)
+		}
 		return nil
 	}
 
-	// Convert markdown to Notion blocks
-	blocks, err := e.converter.MarkdownToBlocks(doc.Content)
-	if err != nil {
-		return fmt.Errorf("failed to convert markdown to blocks: %w", err)
+	if frontmatter.Archived {
+		return e.archivePage(ctx, filePath, frontmatter)
 	}
 
 	// Determine title
@@ -105,43 +176,273 @@ func (e *engine) SyncFileToNotion(ctx context.Context, filePath string) error {
 		title = e.getTitleFromFilename(filePath)
 	}
 
-	// Create or update page
+	// Leave the page alone if nothing changed since it was last synced
+	properties := propertyValues(doc.Metadata, frontmatter.Properties)
+	hash := e.fileContentHash(filePath, doc, frontmatter)
+	if frontmatter.NotionID != "" && frontmatter.ContentHash == hash {
+		e.statusf("  Unchanged since last sync: %s\n", filePath)
+		return nil
+	}
+
+	// Transforms change what is pushed, not the file, so the hash above
+	// stays that of the file
+	body, err := applyTransforms(ctx, e.prePush, filePath, doc.Content)
+	if err != nil {
+		return err
+	}
+
+	// Surface content that would otherwise silently disappear
+	if err := e.checkUnsupportedFeatures(filePath, body); err != nil {
+		return err
+	}
+
+	// Convert markdown to Notion blocks
+	blocks, err := e.converter.MarkdownToBlocks(body)
+	if err != nil {
+		return fmt.Errorf("failed to convert markdown to blocks: %w", err)
+	}
+	blocks = e.stripTitleHeading(blocks, title)
+
+	// A page deleted or archived in Notion can't be updated; push the file
+	// as a new page or stop, as configured
+	var page *notion.Page
 	if frontmatter.NotionID != "" {
+		page, err = e.livePage(ctx, frontmatter.NotionID)
+		if err != nil {
+			return err
+		}
+		if page != nil && page.IsTemplate && !e.config.Sync.OverwriteTemplates {
+			return fmt.Errorf("%s: notion_id %s points at a database template; "+
+				"push with --force or set sync.overwrite_templates to replace its content",
+				filePath, frontmatter.NotionID)
+		}
+		if page == nil {
+			if util.NormalizeOption(e.config.Sync.OrphanedPages) != OrphanedPagesRecreate {
+				return fmt.Errorf("%s: notion_id %s points at a page that was deleted or archived in Notion; "+
+					"restore the page, remove notion_id from the frontmatter, or set sync.orphaned_pages to %q to push it as a new page",
+					filePath, frontmatter.NotionID, OrphanedPagesRecreate)
+			}
+			e.log().Warning("Page %s for %s was deleted or archived in Notion; creating a new page", frontmatter.NotionID, filePath)
+			frontmatter.NotionID = ""
+		}
+	}
+
+	// Create or update page
+	if frontmatter.NotionID != "" && frontmatter.SyncMode == markdown.SyncModeAppend {
+		// Append to existing page without clearing its content
+		err = e.notion.AppendPageBlocks(ctx, frontmatter.NotionID, blocks)
+	} else if frontmatter.NotionID != "" {
 		// Update existing page
-		err = e.updateNotionPage(ctx, frontmatter.NotionID, title, blocks)
+		err = e.updateNotionPage(ctx, page, title, blocks)
 	} else {
-		// Create new page
-		pageID, err := e.createNotionPage(ctx, title, blocks)
+		if len(blocks) == 0 {
+			switch util.NormalizeOption(e.config.Sync.EmptyPages) {
+			case EmptyPagesSkip:
+				e.statusf("  Skipped %s: no content to push, so no page was created\n", filePath)
+				return nil
+			case EmptyPagesPlaceholder:
+				blocks = []map[string]interface{}{createParagraphBlock(emptyPagePlaceholder)}
+			}
+		}
+
+		// Create new page under the frontmatter's parent, if any
+		parentID, err := e.resolveParentID(filePath, frontmatter.NotionParent)
 		if err != nil {
 			return err
 		}
 
-		// Update frontmatter with new page ID
+		pageID, err := e.createNotionPage(ctx, parentID, title, blocks)
+		if err != nil {
+			return err
+		}
+
+		// Record the new page in the file's frontmatter, keeping the rest
 		frontmatter.NotionID = pageID
-		frontmatter.UpdatedAt = &time.Time{}
-		*frontmatter.UpdatedAt = time.Now()
+		doc.Metadata["notion_id"] = pageID
+		doc.Metadata["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Sync page properties edited in the frontmatter
+	if len(properties) > 0 {
+		if err := e.pushProperties(ctx, frontmatter.NotionID, properties); err != nil {
+			return err
+		}
+	}
+
+	// Record what was pushed so the next push of the same content is skipped
+	doc.Metadata["content_hash"] = hash
+	doc.Metadata["body_hash"] = bodyHash(doc.Content)
+	return e.parser.CreateMarkdownWithFrontmatter(filePath, doc.Metadata, doc.Content)
+}
+
+// archivePage archives the page of a file marked archived: true, unless
+// Notion already has it archived
+func (e *engine) archivePage(ctx context.Context, filePath string, frontmatter *markdown.FrontmatterFields) error {
+	if frontmatter.NotionID == "" {
+		e.statusf("  Archived, not creating a page: %s\n", filePath)
+		return nil
+	}
+
+	gone, err := e.pageIsGone(ctx, frontmatter.NotionID)
+	if err != nil {
+		return err
+	}
+	if gone {
+		return nil
+	}
+
+	if err := e.removePage(ctx, frontmatter.NotionID); err != nil {
+		return fmt.Errorf("failed to archive page for %s: %w", filePath, err)
+	}
+	e.statusf("  Archived page %s for %s\n", frontmatter.NotionID, filePath)
+	return nil
+}
+
+// DeletePagesTrash makes pushes move pages they remove to the trash rather
+// than archive them
+const DeletePagesTrash = "trash"
+
+// removePage archives a page or moves it to the trash, following
+// sync.delete_pages
+func (e *engine) removePage(ctx context.Context, pageID string) error {
+	if util.NormalizeOption(e.config.Sync.DeletePages) == DeletePagesTrash {
+		return e.notion.TrashPage(ctx, pageID)
+	}
+	return e.notion.DeletePage(ctx, pageID)
+}
+
+// runSyncAction carries out the sync_action set in a file's frontmatter. The
+// action is cleared afterwards and the file disabled, so it runs once.
+func (e *engine) runSyncAction(ctx context.Context, filePath string, doc *markdown.Document, frontmatter *markdown.FrontmatterFields) error {
+	if frontmatter.SyncAction != markdown.SyncActionArchive {
+		return fmt.Errorf("%s: unknown sync_action %q (supported: %s)", filePath, frontmatter.SyncAction, markdown.SyncActionArchive)
+	}
+
+	if frontmatter.NotionID == "" {
+		e.statusf("  No Notion page to archive for %s\n", filePath)
+	} else {
+		if err := e.removePage(ctx, frontmatter.NotionID); err != nil {
+			return fmt.Errorf("failed to archive page for %s: %w", filePath, err)
+		}
+		e.statusf("  Archived page %s for %s\n", frontmatter.NotionID, filePath)
+	}
+
+	delete(doc.Metadata, "sync_action")
+	doc.Metadata["sync_enabled"] = false
+	return e.parser.CreateMarkdownWithFrontmatter(filePath, doc.Metadata, doc.Content)
+}
+
+// OrphanedPagesRecreate makes pushes create a new page for a file whose
+// notion_id points at a deleted or archived page, instead of failing
+const OrphanedPagesRecreate = "recreate"
+
+// Values of sync.empty_pages other than the default, create, which creates a
+// blank page for a file with no content
+const (
+	EmptyPagesSkip        = "skip"        // Don't create a page until the file has content
+	EmptyPagesPlaceholder = "placeholder" // Create the page with emptyPagePlaceholder as its content
+)
+
+// emptyPagePlaceholder is the text of pages created for empty files under
+// sync.empty_pages: placeholder
+const emptyPagePlaceholder = "This page is empty."
 
-		// Write back to file
-		return e.parser.CreateMarkdownWithFrontmatter(
-			filePath,
-			frontmatter.ToMetadata(),
-			doc.Content,
-		)
+// pageIsGone reports whether pageID was deleted, archived or moved to the
+// trash in Notion
+func (e *engine) pageIsGone(ctx context.Context, pageID string) (bool, error) {
+	page, err := e.livePage(ctx, pageID)
+	return page == nil && err == nil, err
+}
+
+// livePage fetches pageID, or returns nil when it was deleted, archived or
+// moved to the trash in Notion
+func (e *engine) livePage(ctx context.Context, pageID string) (*notion.Page, error) {
+	page, err := e.notion.GetPage(ctx, pageID)
+	if errors.Is(err, notion.ErrPageNotFound) {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check Notion page %s: %w", pageID, err)
+	}
+	if page.Archived || page.InTrash {
+		return nil, nil
+	}
+	return page, nil
+}
 
-	return err
+// bodyHash identifies the synced state of a page's normalized body alone
+func bodyHash(content string) string {
+	sum := sha256.Sum256([]byte(NormalizeContent(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// fileContentHash returns the contentHash of a markdown file, as compared
+// with the content_hash recorded when it was last synced
+func (e *engine) fileContentHash(filePath string, doc *markdown.Document, frontmatter *markdown.FrontmatterFields) string {
+	title := frontmatter.Title
+	if title == "" {
+		title = e.getTitleFromFilename(filePath)
+	}
+	return contentHash(title, doc.Content, propertyValues(doc.Metadata, frontmatter.Properties))
+}
+
+// contentHash identifies the synced state of a page: its title, normalized
+// body and frontmatter properties
+func contentHash(title, content string, properties map[string]interface{}) string {
+	h := sha256.New()
+	h.Write([]byte(title + "\n" + NormalizeContent(content) + "\n"))
+	if len(properties) > 0 {
+		// Map keys are marshalled in sorted order, so this is stable
+		if data, err := json.Marshal(properties); err == nil {
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 func (e *engine) SyncNotionToFile(ctx context.Context, pageID, filePath string) error {
+	return e.pullPage(ctx, pageID, filePath, nil)
+}
+
+// pullPage writes pageID to filePath. pagePaths maps the IDs of pages pulled
+// alongside it to their files, so child pages can be linked locally
+func (e *engine) pullPage(ctx context.Context, pageID, filePath string, pagePaths map[string]string) error {
+	return withOperationTimeout(ctx, e.config.Timeouts.PageFetch, "timeouts.page_fetch", func(ctx context.Context) error {
+		return e.pullPageFile(ctx, pageID, filePath, pagePaths)
+	})
+}
+
+func (e *engine) pullPageFile(ctx context.Context, pageID, filePath string, pagePaths map[string]string) error {
 	// Get page from Notion
 	page, err := e.notion.GetPage(ctx, pageID)
 	if err != nil {
 		return fmt.Errorf("failed to get Notion page: %w", err)
 	}
+	return e.writePulledPage(ctx, page, filePath, pagePaths)
+}
+
+// writePulledPage converts page to markdown and writes it to filePath with
+// its frontmatter. Every pull, streaming or not, writes pages through it.
+func (e *engine) writePulledPage(ctx context.Context, page *notion.Page, filePath string, pagePaths map[string]string) error {
+	pageID := page.ID
 
 	// Extract and display page title
 	title := e.extractTitleFromPage(page)
-	fmt.Printf("  Page title: %s\n", title)
+	e.pageStatusf(ctx, "  Page title: %s\n", title)
+
+	archived := page.Archived || page.InTrash
+	if archived && !e.includeArchived {
+		return e.markArchived(ctx, filePath, title)
+	}
+
+	// A template's content would turn into a real page once pushed back
+	if page.IsTemplate {
+		e.pageStatusf(ctx, "  Skipped template page: %s\n", title)
+		return nil
+	}
 
 	// Get page blocks
 	blocks, err := e.notion.GetPageBlocks(ctx, pageID)
@@ -153,36 +454,91 @@ func (e *engine) SyncNotionToFile(ctx context.Context, pageID, filePath string)
 	databaseRefs, err := e.exportChildDatabases(ctx, pageID, filePath, title)
 	if err != nil {
 		// Log warning but don't fail the page sync
-		util.WithError(err, "Failed to export databases for page %s", pageID)
+		e.log().WithError(err, "Failed to export databases for page %s", pageID)
 	}
 
-	// Convert blocks to markdown
+	content, err := e.renderPulled(ctx, blocks, filePath, title, pagePaths, databaseRefs)
+	if err != nil {
+		return err
+	}
+
+	// Create frontmatter, with timestamps taken from Notion rather than the
+	// time of the pull so they only move when the page changes
+	frontmatter := &markdown.FrontmatterFields{
+		Title:       title,
+		NotionID:    pageID,
+		CreatedAt:   markdown.Timestamp(page.CreatedTime),
+		UpdatedAt:   markdown.Timestamp(page.LastEditedTime),
+		Properties:  propertyKeys(e.config.Mapping.Properties).frontmatter(extractTaskProperties(page)),
+		SyncEnabled: true,
+		Archived:    archived,
+	}
+	if e.config.Sync.StoreNotionURL {
+		frontmatter.NotionURL = page.URL
+	}
+
+	// Keep frontmatter the sync doesn't manage from any earlier version of
+	// the file
+	metadata := frontmatter.ToMetadata()
+	if _, err := os.Stat(filePath); err == nil {
+		if existing, err := e.parser.ParseFile(filePath); err != nil {
+			e.log().Warning("Failed to read existing frontmatter of %s, overwriting it: %v", filePath, err)
+		} else {
+			metadata = markdown.MergePulledMetadata(existing.Metadata, metadata)
+		}
+	}
+
+	// Hash what a push would see, kept keys such as tags included, so an
+	// unedited file isn't pushed again
+	metadata["content_hash"] = contentHash(title, content, propertyValues(metadata, frontmatter.Properties))
+	metadata["body_hash"] = bodyHash(content)
+
+	// Write markdown file
+	return e.parser.CreateMarkdownWithFrontmatter(filePath, metadata, content)
+}
+
+// renderPulled converts a page's blocks to the body a pull writes to
+// filePath. Conflict detection renders the remote side the same way, so the
+// body_hash a pull records matches it until the page changes.
+func (e *engine) renderPulled(ctx context.Context, blocks []notion.Block, filePath, title string, pagePaths map[string]string, databaseRefs []DatabaseReference) (string, error) {
+	e.resolveUserMentions(ctx, blocks)
+	e.inlineImages(ctx, blocks)
+	e.downloadAssets(ctx, blocks, filePath)
+	blocks = linkChildPages(blocks, filePath, pagePaths)
 	content, err := e.converter.BlocksToMarkdown(blocks)
 	if err != nil {
-		return fmt.Errorf("failed to convert blocks to markdown: %w", err)
+		return "", fmt.Errorf("failed to convert blocks to markdown: %w", err)
 	}
+	content = e.normalizePulled(content)
+	content = e.restoreTitleHeading(content, title)
 
 	// Add database references to content if any databases were exported
 	if len(databaseRefs) > 0 {
 		content = e.addDatabaseReferences(content, databaseRefs)
 	}
 
-	// Create frontmatter
-	frontmatter := &markdown.FrontmatterFields{
-		Title:       title,
-		NotionID:    pageID,
-		CreatedAt:   &page.CreatedTime,
-		UpdatedAt:   &time.Time{},
-		SyncEnabled: true,
+	return applyTransforms(ctx, e.postPull, filePath, content)
+}
+
+// markArchived records in filePath's frontmatter that its page was archived,
+// leaving the content as it was. Archived pages with no file are skipped.
+func (e *engine) markArchived(ctx context.Context, filePath, title string) error {
+	if _, err := os.Stat(filePath); err != nil {
+		e.pageStatusf(ctx, "  Skipped archived page: %s\n", title)
+		return nil
 	}
-	*frontmatter.UpdatedAt = time.Now()
 
-	// Write markdown file
-	return e.parser.CreateMarkdownWithFrontmatter(
-		filePath,
-		frontmatter.ToMetadata(),
-		content,
-	)
+	doc, err := e.parser.ParseFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse markdown file: %w", err)
+	}
+	if archived, _ := doc.Metadata["archived"].(bool); archived {
+		return nil
+	}
+
+	e.pageStatusf(ctx, "  Page was archived, marking %s\n", filePath)
+	doc.Metadata["archived"] = true
+	return e.parser.CreateMarkdownWithFrontmatter(filePath, doc.Metadata, doc.Content)
 }
 
 func (e *engine) SyncAll(ctx context.Context, direction string) error {
@@ -199,21 +555,41 @@ func (e *engine) SyncAll(ctx context.Context, direction string) error {
 }
 
 func (e *engine) syncAllMarkdownToNotion(ctx context.Context) error {
-	return filepath.Walk(e.config.Directories.MarkdownRoot, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	files, err := e.markdownFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		if err := e.SyncFileToNotion(ctx, path); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		if !strings.HasSuffix(path, ".md") {
-			return nil
+// markdownFiles lists the files a push syncs, in document order
+func (e *engine) markdownFiles() ([]string, error) {
+	var files []string
+	err := filepath.Walk(e.config.Directories.MarkdownRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
 		}
-
-		if e.isExcluded(path) {
-			return nil
+		if info.IsDir() {
+			return e.skipExcludedDir(path)
 		}
 
-		return e.SyncFileToNotion(ctx, path)
+		if strings.HasSuffix(path, ".md") && e.shouldSyncFile(path) {
+			files = append(files, path)
+		}
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	SortByDocumentOrder("", files)
+	return files, nil
 }
 
 func (e *engine) syncAllNotionToMarkdown(ctx context.Context) error {
@@ -223,23 +599,60 @@ func (e *engine) syncAllNotionToMarkdown(ctx context.Context) error {
 	}
 
 	// Use original implementation for smaller workspaces
-	// Get the parent page itself first
-	parentPage, err := e.notion.GetPage(ctx, e.config.Notion.ParentPageID)
+	return e.pullPageTree(ctx, e.config.Notion.ParentPageID)
+}
+
+// SyncPageSubtree syncs a single page and all of its descendants, building
+// the file tree rooted at that page instead of the configured parent page
+func (e *engine) SyncPageSubtree(ctx context.Context, pageID, direction string) error {
+	switch direction {
+	case "pull":
+		return e.pullPageTree(ctx, pageID)
+	default:
+		return fmt.Errorf("unsupported sync direction for page subtree: %s (only pull is supported)", direction)
+	}
+}
+
+// withPageLimitHint says how to get past the page limit when err is a
+// *notion.PageLimitError
+func withPageLimitHint(err error) error {
+	if !errors.As(err, new(*notion.PageLimitError)) {
+		return err
+	}
+	return fmt.Errorf("%w; pull a narrower page with --page or notion.parent_page_id, "+
+		"or raise the limit with --max-pages or sync.max_pages (0 for no limit)", err)
+}
+
+// pullPageTree pulls rootID and its descendants into a directory tree rooted
+// at the root page's title
+func (e *engine) pullPageTree(ctx context.Context, rootID string) error {
+	// Get the root page itself first
+	rootPage, err := e.notion.GetPage(ctx, rootID)
 	if err != nil {
+		// Databases can't be pulled as a page tree; say so instead of
+		// surfacing Notion's generic validation error
+		if _, dbErr := e.notion.GetDatabase(ctx, rootID); dbErr == nil {
+			return fmt.Errorf("%s is a database, not a page; use 'database export' instead", rootID)
+		}
 		return fmt.Errorf("failed to get parent page: %w", err)
 	}
 
 	// Get all descendant pages (including nested sub-pages)
-	descendantPages, err := e.notion.GetAllDescendantPages(ctx, e.config.Notion.ParentPageID)
-	if err != nil {
-		return fmt.Errorf("failed to get descendant pages: %w", err)
+	descendantPages, err := e.notion.GetAllDescendantPages(ctx, rootID, e.maxDepth)
+	var restricted *notion.RestrictedPagesError
+	if err != nil && !errors.As(err, &restricted) {
+		return fmt.Errorf("failed to get descendant pages: %w", withPageLimitHint(err))
+	}
+	var skipped []string
+	if restricted != nil {
+		skipped = restricted.PageIDs
 	}
 
-	// Combine parent page with descendants
-	pages := append([]notion.Page{*parentPage}, descendantPages...)
+	// Combine root page with descendants
+	pages := append([]notion.Page{*rootPage}, descendantPages...)
 
-	fmt.Printf("Found %d pages under parent %s (including parent and sub-pages)\n", len(pages), e.config.Notion.ParentPageID)
-	fmt.Println()
+	e.printf("Found %d pages under parent %s (including parent and sub-pages)\n", len(pages), rootID)
+	e.printf("\n")
 
 	// Build a map of page IDs to their parent IDs for path construction
 	pageParentMap := make(map[string]string)
@@ -249,12 +662,41 @@ func (e *engine) syncAllNotionToMarkdown(ctx context.Context) error {
 		}
 	}
 
+	// Work out every page's file up front so parents can link to their
+	// children while the pages are pulled concurrently
+	titles := make([]string, len(pages))
+	pagePaths := make(map[string]string, len(pages))
+	for i := range pages {
+		titles[i] = e.extractTitleFromPage(&pages[i])
+		pagePaths[pages[i].ID] = e.buildFilePathForPage(&pages[i], titles[i], rootID, pageParentMap, pages)
+	}
+
+	// Move the files of pages whose title or parent changed to their new
+	// paths, so they're updated rather than pulled again beside the old ones
+	if e.detectRenames {
+		local := e.localNotionFiles()
+		for _, page := range pages {
+			e.renamePulledFile(local, page.ID, pagePaths[page.ID])
+		}
+	}
+
 	// Use concurrent processing for better performance
-	return e.syncPagesConcurrently(ctx, pages, pageParentMap)
+	if err := e.syncPagesConcurrently(ctx, pages, titles, pagePaths, skipped); err != nil {
+		return err
+	}
+
+	// Clean up directories left behind by renamed or removed pages
+	if !e.flatten {
+		e.pruneDirs(ctx, filepath.Dir(pagePaths[rootID]), pagePaths)
+	}
+	return nil
 }
 
-// syncPagesConcurrently processes multiple pages concurrently using simple goroutines
-func (e *engine) syncPagesConcurrently(ctx context.Context, pages []notion.Page, pageParentMap map[string]string) error {
+// syncPagesConcurrently pulls pages, with the given titles, into pagePaths
+// using a pool of goroutines. skipped lists pages already passed over for
+// missing access; pages Notion denies access to while pulling join them in
+// the summary instead of counting as failures.
+func (e *engine) syncPagesConcurrently(ctx context.Context, pages []notion.Page, titles []string, pagePaths map[string]string, skipped []string) error {
 	// Configure concurrency based on page count or custom setting
 	workerCount := e.workerCount
 	if workerCount == 0 {
@@ -269,54 +711,109 @@ func (e *engine) syncPagesConcurrently(ctx context.Context, pages []notion.Page,
 	}
 
 	// Cap at 50 workers to avoid overwhelming the API
-	if workerCount > 50 {
-		workerCount = 50
+	if workerCount > maxPullWorkers {
+		workerCount = maxPullWorkers
 	}
 
-	fmt.Printf("🚀 Using concurrent processing with %d workers for %d pages\n", workerCount, len(pages))
+	// Adaptive scaling starts a few workers and lets the controller decide
+	// how many run, up to the configured count or the cap
+	var controller *workerController
+	if util.NormalizeOption(e.workerScaling) == WorkerScalingAdaptive {
+		workerCount = maxPullWorkers
+		if e.workerCount > 0 && e.workerCount < workerCount {
+			workerCount = e.workerCount
+		}
+		if len(pages) < workerCount {
+			workerCount = len(pages)
+		}
+		controller = newWorkerController(workerCount)
+		e.printf("🚀 Using adaptive concurrent processing with up to %d workers for %d pages\n", workerCount, len(pages))
+	} else {
+		e.printf("🚀 Using concurrent processing with %d workers for %d pages\n", workerCount, len(pages))
+	}
+
+	// Collect results as they complete. Progress events go out in completion
+	// order; buffered status output is printed once every earlier page has
+	// finished.
+	results := make([]syncResult, len(pages))
+	finished := make([]bool, len(pages))
+	next, completed, failed := 0, 0, 0
+	processor := batch.Processor{
+		Workers: workerCount,
+		OnDone: func(i int, _ error) {
+			result := &results[i]
+			if errors.Is(result.err, notion.ErrForbidden) {
+				skipped = append(skipped, result.pageID)
+				result.err = nil
+				result.skipped = true
+			}
+			finished[i] = true
+			completed++
+			if result.err != nil {
+				failed++
+			}
 
-	// Create channels for work distribution
-	pageJobs := make(chan pageJob, len(pages))
-	results := make(chan syncResult, len(pages))
+			e.reportProgress(ProgressEvent{
+				PageID:    result.pageID,
+				Title:     result.title,
+				Index:     result.index,
+				Completed: completed,
+				Failed:    failed,
+				Skipped:   len(skipped),
+				Total:     len(pages),
+				Err:       result.err,
+			})
 
-	// Start workers
-	for i := 0; i < workerCount; i++ {
-		go e.syncWorker(ctx, pageJobs, results)
+			for ; next < len(finished) && finished[next]; next++ {
+				if results[next].output != "" {
+					e.printf("%s", results[next].output)
+				}
+			}
+		},
 	}
+	errs := processor.Run(ctx, len(pages), func(ctx context.Context, i int) error {
+		results[i] = e.pullJob(ctx, pageJob{
+			page:      pages[i],
+			title:     titles[i],
+			filePath:  pagePaths[pages[i].ID],
+			index:     i,
+			total:     len(pages),
+			pagePaths: pagePaths,
+		}, controller)
+		return results[i].err
+	})
 
-	// Send jobs to workers
-	for i, page := range pages {
-		title := e.extractTitleFromPage(&page)
-		filePath := e.buildFilePathForPage(&page, title, pageParentMap, pages)
-
-		pageJobs <- pageJob{
-			page:     page,
-			title:    title,
-			filePath: filePath,
-			index:    i + 1,
-			total:    len(pages),
+	// Pages never started once the pull was cancelled
+	for i, done := range finished {
+		if !done {
+			results[i] = syncResult{pageID: pages[i].ID, title: titles[i], index: i, err: errs[i]}
 		}
 	}
-	close(pageJobs)
 
-	// Collect results
 	var errors []string
-	successCount := 0
-	for i := 0; i < len(pages); i++ {
-		result := <-results
+	for _, result := range results {
 		if result.err != nil {
 			errors = append(errors, fmt.Sprintf("Page %s: %v", result.pageID, result.err))
-		} else {
-			successCount++
 		}
 	}
 
-	fmt.Printf("\n🎉 Concurrent sync complete! %d/%d pages successful\n", successCount, len(pages))
+	pulled := 0
+	for _, result := range results {
+		if result.err == nil && !result.skipped {
+			pulled++
+		}
+	}
+	e.printf("\n🎉 Concurrent sync complete! %d/%d pages successful\n", pulled, len(pages))
+	if controller != nil {
+		e.printf("📈 Adaptive scaling peaked at %d workers and finished at %d\n", controller.peak, controller.current())
+	}
+
+	e.warnSkipped(skipped)
 
 	if len(errors) > 0 {
-		util.ErrorMsg("%d pages failed", len(errors))
+		e.log().ErrorMsg("%d pages failed", len(errors))
 		for _, errMsg := range errors {
-			util.Error("  - %s", errMsg)
+			e.log().Error("  - %s", errMsg)
 		}
 		return fmt.Errorf("%d pages failed to sync", len(errors))
 	}
@@ -324,55 +821,104 @@ func (e *engine) syncPagesConcurrently(ctx context.Context, pages []notion.Page,
 	return nil
 }
 
+// warnSkipped lists the pages a pull skipped for missing access
+func (e *engine) warnSkipped(skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+	e.log().Warning("%d pages skipped due to missing access; share them with the integration to pull them:", len(skipped))
+	for _, pageID := range skipped {
+		e.log().Warning("  - %s", pageID)
+	}
+}
+
 // pageJob represents a page sync job
 type pageJob struct {
 	page     notion.Page
 	title    string
 	filePath string
-	index    int
+	index    int // Position in the pull, from 0
 	total    int
+
+	// pagePaths maps every page in the pull to its file
+	pagePaths map[string]string
 }
 
 // syncResult represents the result of a sync operation
 type syncResult struct {
-	pageID string
-	err    error
+	pageID  string
+	title   string
+	index   int
+	err     error
+	skipped bool   // Notion denied access to the page
+	output  string // Status lines held back for ordered output
 }
 
-// syncWorker processes page sync jobs concurrently
-func (e *engine) syncWorker(ctx context.Context, jobs <-chan pageJob, results chan<- syncResult) {
-	for job := range jobs {
-		result := syncResult{pageID: job.page.ID}
+// pullJob pulls one page. With a controller, it first waits for a slot.
+func (e *engine) pullJob(ctx context.Context, job pageJob, controller *workerController) syncResult {
+	if controller == nil {
+		return e.syncJob(ctx, job)
+	}
 
-		// Print progress
-		fmt.Printf("[%d/%d] Pulling page: %s\n", job.index, job.total, job.title)
-		fmt.Printf("  Notion ID: %s\n", job.page.ID)
-		fmt.Printf("  Saving to: %s\n", job.filePath)
+	started, err := controller.acquire(ctx)
+	if err != nil {
+		return syncResult{pageID: job.page.ID, title: job.title, index: job.index, err: err}
+	}
+	result := e.syncJob(ctx, job)
+	controller.release(started, result.err)
+	return result
+}
 
-		// Create parent directory if needed
-		dir := filepath.Dir(job.filePath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			result.err = fmt.Errorf("failed to create directory %s: %w", dir, err)
-			results <- result
-			continue
-		}
+// syncJob pulls one page. With ordered output its status lines are returned
+// in the result instead of printed, so they don't interleave with other
+// workers'.
+func (e *engine) syncJob(ctx context.Context, job pageJob) (result syncResult) {
+	result = syncResult{pageID: job.page.ID, title: job.title, index: job.index}
+	if e.orderedOutput {
+		output := &strings.Builder{}
+		ctx = context.WithValue(ctx, pageStatusKey{}, output)
+		defer func() { result.output = output.String() }()
+	}
 
-		// Sync the page
-		if err := e.SyncNotionToFile(ctx, job.page.ID, job.filePath); err != nil {
-			result.err = fmt.Errorf("failed to sync page %s: %w", job.page.ID, err)
-		} else {
-			fmt.Printf("  ✓ Successfully pulled %s\n", job.title)
-		}
+	// Print progress
+	e.pageStatusf(ctx, "[%d/%d] Pulling page: %s\n", job.index+1, job.total, job.title)
+	e.pageStatusf(ctx, "  Notion ID: %s\n", job.page.ID)
+	e.pageStatusf(ctx, "  Saving to: %s\n", job.filePath)
 
-		results <- result
+	// Create parent directory if needed
+	dir := filepath.Dir(job.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		result.err = fmt.Errorf("failed to create directory %s: %w", dir, err)
+		return result
 	}
+
+	// Sync the page
+	if err := e.pullPage(ctx, job.page.ID, job.filePath, job.pagePaths); err != nil {
+		result.err = fmt.Errorf("failed to sync page %s: %w", job.page.ID, err)
+	} else {
+		e.pageStatusf(ctx, "  ✓ Successfully pulled %s\n", job.title)
+	}
+
+	return result
 }
 
 func (e *engine) syncBidirectional(ctx context.Context) error {
 	// Get all descendant pages from Notion (including sub-pages)
-	pages, err := e.notion.GetAllDescendantPages(ctx, e.config.Notion.ParentPageID)
-	if err != nil {
-		return fmt.Errorf("failed to get descendant pages: %w", err)
+	pages, err := e.notion.GetAllDescendantPages(ctx, e.config.Notion.ParentPageID, notion.UnlimitedDepth)
+	var restricted *notion.RestrictedPagesError
+	if errors.As(err, &restricted) {
+		e.log().Warning("%v", restricted)
+	} else if err != nil {
+		return fmt.Errorf("failed to get descendant pages: %w", withPageLimitHint(err))
+	}
+
+	// Child pages link to the files they were pulled to, as in tree pulls
+	local := e.localNotionFiles()
+	pagePaths := make(map[string]string, len(local))
+	for _, page := range pages {
+		if path, ok := local[comparableID(page.ID)]; ok {
+			pagePaths[page.ID] = path
+		}
 	}
 
 	// Check each file for conflicts
@@ -380,12 +926,15 @@ func (e *engine) syncBidirectional(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if info.IsDir() {
+			return e.skipExcludedDir(path)
+		}
 
-		if !strings.HasSuffix(path, ".md") || e.isExcluded(path) {
+		if !strings.HasSuffix(path, ".md") || !e.shouldSyncFile(path) {
 			return nil
 		}
 
-		return e.syncFileWithConflictDetection(ctx, path, pages)
+		return e.syncFileWithConflictDetection(ctx, path, pages, pagePaths)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to sync markdown files: %w", err)
@@ -398,7 +947,7 @@ func (e *engine) syncBidirectional(ctx context.Context) error {
 
 		// Check if file exists
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			if err := e.SyncNotionToFile(ctx, page.ID, filePath); err != nil {
+			if err := e.pullPage(ctx, page.ID, filePath, pagePaths); err != nil {
 				return fmt.Errorf("failed to sync page %s: %w", page.ID, err)
 			}
 		}
@@ -407,7 +956,7 @@ func (e *engine) syncBidirectional(ctx context.Context) error {
 	return nil
 }
 
-func (e *engine) syncFileWithConflictDetection(ctx context.Context, filePath string, notionPages []notion.Page) error {
+func (e *engine) syncFileWithConflictDetection(ctx context.Context, filePath string, notionPages []notion.Page, pagePaths map[string]string) error {
 	// Parse local markdown file
 	doc, err := e.parser.ParseFile(filePath)
 	if err != nil {
@@ -420,7 +969,10 @@ func (e *engine) syncFileWithConflictDetection(ctx context.Context, filePath str
 		return fmt.Errorf("failed to extract frontmatter: %w", err)
 	}
 
-	// Skip if sync is disabled
+	// Frozen files are left alone; sync actions are carried out by the push
+	if frontmatter.SyncAction != "" {
+		return e.SyncFileToNotion(ctx, filePath)
+	}
 	if !frontmatter.SyncEnabled {
 		return nil
 	}
@@ -440,57 +992,98 @@ func (e *engine) syncFileWithConflictDetection(ctx context.Context, filePath str
 	}
 
 	if notionPage == nil {
-		// Page doesn't exist in Notion anymore, create new one
+		// Page isn't under the parent anymore; the push checks whether it
+		// was deleted and recreates it or stops according to sync.orphaned_pages
 		return e.SyncFileToNotion(ctx, filePath)
 	}
 
-	// Get remote content from Notion
+	// Get remote content from Notion, rendered as a pull would write it
 	blocks, err := e.notion.GetPageBlocks(ctx, frontmatter.NotionID)
 	if err != nil {
 		return fmt.Errorf("failed to get page blocks: %w", err)
 	}
-
-	remoteContent, err := e.converter.BlocksToMarkdown(blocks)
+	title := e.extractTitleFromPage(notionPage)
+	databaseRefs := e.databaseReferences(ctx, blocks, filePath, title)
+	remoteContent, err := e.renderPulled(ctx, blocks, filePath, title, pagePaths, databaseRefs)
 	if err != nil {
-		return fmt.Errorf("failed to convert blocks to markdown: %w", err)
+		return err
 	}
 
-	// Check for conflicts
-	if HasConflict(doc.Content, remoteContent) {
-		// Resolve conflict
-		resolvedContent, err := e.conflictResolver.ResolveConflict(doc.Content, remoteContent, filePath)
-		if err != nil {
-			// User chose to skip or there was an error
-			fmt.Printf("Skipping file %s: %v\n", filePath, err)
-			return nil
-		}
+	// No conflict, sync normally (push local to Notion)
+	if !HasConflict(doc.Content, remoteContent) {
+		return e.SyncFileToNotion(ctx, filePath)
+	}
 
-		// Determine which direction to sync based on resolved content
-		if resolvedContent == doc.Content {
-			// Local version chosen, push to Notion
+	// The body as of the last sync shows which side changed it. A body
+	// changed on one side only isn't a conflict, even when the frontmatter
+	// changed locally. Appends always leave the bodies different.
+	if frontmatter.BodyHash != "" && frontmatter.SyncMode != markdown.SyncModeAppend {
+		localChanged := bodyHash(doc.Content) != frontmatter.BodyHash
+		remoteChanged := bodyHash(remoteContent) != frontmatter.BodyHash
+		switch {
+		case localChanged && !remoteChanged:
 			return e.SyncFileToNotion(ctx, filePath)
-		} else {
-			// Remote version chosen, pull from Notion
-			return e.SyncNotionToFile(ctx, frontmatter.NotionID, filePath)
+		case !localChanged && remoteChanged:
+			return e.mergeRemoteBody(ctx, filePath, doc, frontmatter, pagePaths)
+		}
+	}
+
+	conflict := Conflict{
+		FilePath:       filePath,
+		LocalContent:   doc.Content,
+		RemoteContent:  remoteContent,
+		RemoteModified: notionPage.LastEditedTime,
+	}
+	if info, err := os.Stat(filePath); err == nil {
+		conflict.LocalModified = info.ModTime()
+	}
+
+	resolution, err := e.conflictResolver.Resolve(conflict)
+	e.logConflict(newConflictLogEntry(conflict, e.conflictResolver.strategy, resolution, err))
+	if err != nil {
+		// User chose to skip or there was an error
+		e.printf("Skipping file %s: %v\n", filePath, err)
+		return nil
+	}
+
+	if resolution == KeepRemote {
+		return e.pullPage(ctx, frontmatter.NotionID, filePath, pagePaths)
+	}
+	return e.SyncFileToNotion(ctx, filePath)
+}
+
+// mergeRemoteBody syncs a file whose body changed only in Notion: properties
+// edited in the frontmatter are pushed, then the page is pulled, keeping
+// the frontmatter a pull doesn't manage
+func (e *engine) mergeRemoteBody(ctx context.Context, filePath string, doc *markdown.Document, frontmatter *markdown.FrontmatterFields, pagePaths map[string]string) error {
+	if properties := propertyValues(doc.Metadata, frontmatter.Properties); len(properties) > 0 {
+		if err := e.pushProperties(ctx, frontmatter.NotionID, properties); err != nil {
+			return err
 		}
-	} else {
-		// No conflict, sync normally (push local to Notion)
-		return e.SyncFileToNotion(ctx, filePath)
 	}
+
+	e.statusf("  Merging %s: pushing its frontmatter and pulling the page's new content\n", filePath)
+	return e.pullPage(ctx, frontmatter.NotionID, filePath, pagePaths)
 }
 
 // Helper functions
 
 // buildFilePathForPage constructs the file path for a page, including nested directory structure
-func (e *engine) buildFilePathForPage(page *notion.Page, title string, pageParentMap map[string]string, allPages []notion.Page) string {
-	// Special handling for the parent page itself
-	if page.ID == e.config.Notion.ParentPageID {
+// below the root page rootID
+func (e *engine) buildFilePathForPage(page *notion.Page, title, rootID string, pageParentMap map[string]string, allPages []notion.Page) string {
+	if e.flatten {
+		return e.buildFlatFilePath(page, title, allPages)
+	}
+
+	// Special handling for the root page itself
+	if page.ID == rootID {
 		// Parent page gets its own directory with its markdown file inside
-		return filepath.Join(e.config.Directories.MarkdownRoot, title, title+".md")
+		safeTitle := e.fileNames.Sanitize(e.config.Directories.MarkdownRoot, title)
+		return filepath.Join(e.config.Directories.MarkdownRoot, safeTitle, safeTitle+".md")
 	}
 
-	// Build the path from root to this page
-	var pathParts []string
+	// Collect the titles of the pages from the root down to this one
+	titles := []string{title}
 
 	// Traverse up the parent chain
 	currentPageID := page.ID
@@ -498,14 +1091,14 @@ func (e *engine) buildFilePathForPage(page *notion.Page, title string, pageParen
 
 	for {
 		parentID, hasParent := pageParentMap[currentPageID]
-		if !hasParent || parentID == e.config.Notion.ParentPageID {
+		if !hasParent || parentID == rootID {
 			// Reached the root parent or no parent found
 			break
 		}
 
 		// Safety check to prevent infinite loops
 		if visited[currentPageID] {
-			util.Warning("Cycle detected in page hierarchy for page %s", currentPageID)
+			e.log().Warning("Cycle detected in page hierarchy for page %s", currentPageID)
 			break
 		}
 		visited[currentPageID] = true
@@ -514,11 +1107,8 @@ func (e *engine) buildFilePathForPage(page *notion.Page, title string, pageParen
 		parentFound := false
 		for _, p := range allPages {
 			if p.ID == parentID {
-				parentTitle := e.extractTitleFromPage(&p)
-				// Sanitize the parent title to prevent path traversal
-				parentTitle = util.SanitizeFileName(parentTitle)
 				// Add parent title as directory
-				pathParts = append([]string{parentTitle}, pathParts...)
+				titles = append([]string{e.extractTitleFromPage(&p)}, titles...)
 				currentPageID = parentID
 				parentFound = true
 				break
@@ -527,53 +1117,130 @@ func (e *engine) buildFilePathForPage(page *notion.Page, title string, pageParen
 
 		// If we couldn't find the parent page, break to avoid infinite loop
 		if !parentFound {
-			util.Warning("Parent page %s not found in page list", parentID)
+			e.log().Warning("Parent page %s not found in page list", parentID)
 			break
 		}
 	}
 
 	// Add the parent page directory at the beginning
 	for _, p := range allPages {
-		if p.ID == e.config.Notion.ParentPageID {
-			parentTitle := e.extractTitleFromPage(&p)
-			// Sanitize the parent title
-			parentTitle = util.SanitizeFileName(parentTitle)
-			pathParts = append([]string{parentTitle}, pathParts...)
+		if p.ID == rootID {
+			titles = append([]string{e.extractTitleFromPage(&p)}, titles...)
 			break
 		}
 	}
 
-	// Sanitize the current page title
-	safeTitle := util.SanitizeFileName(title)
+	// Sanitize each title within the directory it names, so only pages in
+	// the same directory need distinct names
+	var pathParts []string
+	dir := e.config.Directories.MarkdownRoot
+	for _, t := range titles {
+		name := e.fileNames.Sanitize(dir, t)
+		pathParts = append(pathParts, name)
+		dir = filepath.Join(dir, name)
+	}
 
-	// Add the current page as a directory and then the filename
-	pathParts = append(pathParts, safeTitle)
+	// The current page gets a directory of its own with its file inside
+	safeTitle := pathParts[len(pathParts)-1]
 	pathParts = append(pathParts, safeTitle+".md")
 
 	// Construct the full path securely
 	fullPath, err := util.SecureJoin(e.config.Directories.MarkdownRoot, pathParts...)
 	if err != nil {
 		// If path traversal is detected, fall back to a safe default
-		util.Warning("Potential path traversal detected for page %s, using safe path", page.ID)
+		e.log().Warning("Potential path traversal detected for page %s, using safe path", page.ID)
 		fullPath = filepath.Join(e.config.Directories.MarkdownRoot, safeTitle, safeTitle+".md")
 	}
 	return fullPath
 }
 
-func (e *engine) createNotionPage(ctx context.Context, title string, blocks []map[string]interface{}) (string, error) {
-	properties := map[string]interface{}{
-		"title": map[string]interface{}{
-			"title": []notion.RichText{
-				{
-					Type:      "text",
-					Text:      &notion.TextContent{Content: title},
-					PlainText: title,
-				},
-			},
-		},
+// buildFlatFilePath puts a page straight in the markdown root as
+// <title>.md. Pages whose titles would share a file name get a short page ID
+// appended, so every page in allPages ends up with a file of its own
+// whatever order they are pulled in.
+func (e *engine) buildFlatFilePath(page *notion.Page, title string, allPages []notion.Page) string {
+	name := util.SanitizeFileName(title)
+
+	for i := range allPages {
+		other := &allPages[i]
+		if other.ID != page.ID && strings.EqualFold(util.SanitizeFileName(e.extractTitleFromPage(other)), name) {
+			name += "_" + shortPageID(page.ID)
+			break
+		}
+	}
+
+	return filepath.Join(e.config.Directories.MarkdownRoot, name+".md")
+}
+
+// shortPageID returns the first 8 characters of a page ID, enough to tell
+// apart pages that share a title
+func shortPageID(id string) string {
+	id = comparableID(id)
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// resolveParentID returns the page new pages from filePath are created under.
+// parent comes from the notion_parent frontmatter field and is either a page
+// ID or URL, or a path to another markdown file, relative to filePath's
+// directory or the markdown root, whose notion_id is used. An empty parent
+// means the configured parent page.
+func (e *engine) resolveParentID(filePath, parent string) (string, error) {
+	parent = strings.TrimSpace(parent)
+	if parent == "" {
+		return e.config.Notion.ParentPageID, nil
+	}
+
+	isFile := strings.HasSuffix(strings.ToLower(parent), ".md") ||
+		strings.HasSuffix(strings.ToLower(parent), ".markdown")
+	if !isFile {
+		id, err := util.ParseNotionID(parent)
+		if err == nil {
+			return id, nil
+		}
+		// Anything without a separator can only have been meant as an ID
+		if !strings.ContainsAny(parent, `/\`) {
+			return "", fmt.Errorf("invalid notion_parent %q: %w", parent, err)
+		}
 	}
 
-	page, err := e.notion.CreatePage(ctx, e.config.Notion.ParentPageID, properties)
+	candidates := []string{parent}
+	if !filepath.IsAbs(parent) {
+		candidates = []string{
+			filepath.Join(filepath.Dir(filePath), parent),
+			filepath.Join(e.config.Directories.MarkdownRoot, parent),
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err != nil {
+			continue
+		}
+
+		doc, err := e.parser.ParseFile(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse notion_parent file %s: %w", candidate, err)
+		}
+		frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+		if err != nil {
+			return "", fmt.Errorf("failed to extract frontmatter from %s: %w", candidate, err)
+		}
+		if frontmatter.NotionID == "" {
+			return "", fmt.Errorf("notion_parent file %s has no notion_id; push it first", candidate)
+		}
+		return frontmatter.NotionID, nil
+	}
+
+	return "", fmt.Errorf("notion_parent file not found: %s", parent)
+}
+
+func (e *engine) createNotionPage(ctx context.Context, parentID, title string, blocks []map[string]interface{}) (string, error) {
+	// Pages created under a parent page always key their title as "title"
+	properties := buildTitleProperties("title", title)
+
+	page, err := e.notion.CreatePage(ctx, parentID, properties)
 	if err != nil {
 		return "", fmt.Errorf("failed to create page: %w", err)
 	}
@@ -582,17 +1249,46 @@ func (e *engine) createNotionPage(ctx context.Context, title string, blocks []ma
 	if len(blocks) > 0 {
 		if err := e.notion.UpdatePageBlocks(ctx, page.ID, blocks); err != nil {
 			// Log the error but don't fail - the page was created successfully
-			fmt.Printf("Warning: failed to update page blocks: %v\n", err)
+			e.printf("Warning: failed to update page blocks: %v\n", err)
 		}
 	}
 
 	return page.ID, nil
 }
 
-func (e *engine) updateNotionPage(ctx context.Context, pageID, title string, blocks []map[string]interface{}) error {
+func (e *engine) updateNotionPage(ctx context.Context, page *notion.Page, title string, blocks []map[string]interface{}) error {
+	// Retitle the page through its own title property, which database
+	// pages name after the column
+	if title != page.Title() {
+		key := page.TitlePropertyName()
+		if key == "" {
+			key = "title"
+		}
+		if err := e.notion.UpdatePageProperties(ctx, page.ID, buildTitleProperties(key, title)); err != nil {
+			return fmt.Errorf("failed to update page title: %w", err)
+		}
+	}
+
 	// Use the original slower but safer method for updates to preserve page IDs
 	// The delete-and-recreate approach would change page IDs and break links
-	return e.notion.UpdatePageBlocks(ctx, pageID, blocks)
+	return e.notion.UpdatePageBlocks(ctx, page.ID, blocks)
+}
+
+// buildTitleProperties builds a properties payload that sets the title
+// property named key, which is "title" for regular pages but matches the
+// column name for database pages
+func buildTitleProperties(key, title string) map[string]interface{} {
+	return map[string]interface{}{
+		key: map[string]interface{}{
+			"title": []notion.RichText{
+				{
+					Type:      "text",
+					Text:      &notion.TextContent{Content: title},
+					PlainText: title,
+				},
+			},
+		},
+	}
 }
 
 func (e *engine) getTitleFromFilename(filePath string) string {
@@ -601,27 +1297,73 @@ func (e *engine) getTitleFromFilename(filePath string) string {
 }
 
 func (e *engine) extractTitleFromPage(page *notion.Page) string {
-	if titleProp, ok := page.Properties["title"]; ok {
-		if titleData, ok := titleProp.(map[string]interface{}); ok {
-			if titleList, ok := titleData["title"].([]interface{}); ok && len(titleList) > 0 {
-				if titleItem, ok := titleList[0].(map[string]interface{}); ok {
-					if plainText, ok := titleItem["plain_text"].(string); ok {
-						return plainText
-					}
-				}
-			}
-		}
+	if title := page.Title(); title != "" {
+		return title
 	}
 	return "Untitled"
 }
 
+// shouldSyncFile reports whether a local file passes the include and
+// exclude patterns
+func (e *engine) shouldSyncFile(path string) bool {
+	return e.isIncluded(path) && !e.isExcluded(path)
+}
+
+// isIncluded reports whether path, relative to the markdown root, matches
+// an include pattern. Everything is included when none are configured.
+func (e *engine) isIncluded(path string) bool {
+	patterns := e.config.Directories.IncludedPatterns
+	if len(patterns) == 0 {
+		return true
+	}
+
+	return util.MatchAnyGlob(patterns, e.relPath(path))
+}
+
+// isExcluded reports whether excluded_patterns or the markdown root's
+// .notionignore leave path out, matching gitignore-style against the path
+// relative to the root
 func (e *engine) isExcluded(path string) bool {
 	for _, pattern := range e.config.Directories.ExcludedPatterns {
+		// Patterns written out as full paths keep working
 		if matched, _ := filepath.Match(pattern, path); matched {
 			return true
 		}
 	}
-	return false
+
+	return e.ignoreMatcher().Ignored(e.relPath(path), false)
+}
+
+// skipExcludedDir returns filepath.SkipDir for a directory the exclusion
+// rules leave out, so walks don't descend into trees such as node_modules
+func (e *engine) skipExcludedDir(path string) error {
+	if e.ignoreMatcher().Ignored(e.relPath(path), true) {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// relPath returns path relative to the markdown root, or path itself when
+// it can't be made relative
+func (e *engine) relPath(path string) string {
+	rel, err := filepath.Rel(e.config.Directories.MarkdownRoot, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// ignoreMatcher loads the exclusion rules once per engine. An unreadable
+// .notionignore is reported and the config patterns are used alone.
+func (e *engine) ignoreMatcher() *util.IgnoreMatcher {
+	e.ignoreOnce.Do(func() {
+		var err error
+		e.ignore, err = util.LoadIgnoreMatcher(e.config.Directories.MarkdownRoot, e.config.Directories.ExcludedPatterns)
+		if err != nil {
+			e.log().WithError(err, "Ignoring %s", util.IgnoreFileName)
+		}
+	})
+	return e.ignore
 }
 
 func (e *engine) SyncSpecificFile(ctx context.Context, filename, direction string) error {
@@ -636,6 +1378,14 @@ func (e *engine) SyncSpecificFile(ctx context.Context, filename, direction strin
 }
 
 func (e *engine) syncSpecificNotionToMarkdown(ctx context.Context, filename string) error {
+	// With rename detection a file's notion_id finds its page, even after
+	// the page's title changed; title matching is the fallback
+	if e.detectRenames {
+		if found, err := e.syncRenamedNotionToMarkdown(ctx, filepath.Join(e.config.Directories.MarkdownRoot, filename)); found {
+			return err
+		}
+	}
+
 	// Get all child pages from Notion
 	pages, err := e.notion.GetChildPages(ctx, e.config.Notion.ParentPageID)
 	if err != nil {
@@ -662,9 +1412,9 @@ func (e *engine) syncSpecificNotionToMarkdown(ctx context.Context, filename stri
 	filePath := filepath.Join(e.config.Directories.MarkdownRoot, filename)
 
 	// Sync this specific page
-	fmt.Printf("Pulling page: %s\n", e.extractTitleFromPage(targetPage))
-	fmt.Printf("  Notion ID: %s\n", targetPage.ID)
-	fmt.Printf("  Saving to: %s\n", filePath)
+	e.printf("Pulling page: %s\n", e.extractTitleFromPage(targetPage))
+	e.printf("  Notion ID: %s\n", targetPage.ID)
+	e.printf("  Saving to: %s\n", filePath)
 
 	if err := e.SyncNotionToFile(ctx, targetPage.ID, filePath); err != nil {
 		return fmt.Errorf("failed to sync page %s: %w", targetPage.ID, err)
@@ -677,13 +1427,26 @@ func (e *engine) syncSpecificNotionToMarkdown(ctx context.Context, filename stri
 type DatabaseReference struct {
 	DatabaseID string
 	Title      string
-	CSVPath    string
+	CSVPath    string // Relative to the page's markdown file, with forward slashes
+}
+
+// maxDatabaseExportWorkers bounds how many of a page's databases export at
+// once; requests still go through the shared rate-limited client
+const maxDatabaseExportWorkers = 4
+
+// databaseExport is one child database of a page being exported
+type databaseExport struct {
+	databaseID string
+	index      int // 1-based position among the page's child databases
+	title      string
+	hasTitle   bool
+	csvPath    string
+	linkPath   string
+	err        error
 }
 
 // exportChildDatabases finds and exports all child databases of a page
 func (e *engine) exportChildDatabases(ctx context.Context, pageID, filePath, pageTitle string) ([]DatabaseReference, error) {
-	var databaseRefs []DatabaseReference
-
 	// Get child databases - we need to check if the Notion API provides child database blocks
 	// For now, we'll look for database blocks in the page content
 	blocks, err := e.notion.GetPageBlocks(ctx, pageID)
@@ -691,7 +1454,71 @@ func (e *engine) exportChildDatabases(ctx context.Context, pageID, filePath, pag
 		return nil, fmt.Errorf("failed to get page blocks: %w", err)
 	}
 
+	exports := e.childDatabases(blocks, true)
+	if len(exports) == 0 {
+		return nil, nil
+	}
+
+	baseDir, err := e.databaseExportDir(filePath)
+	if err != nil {
+		e.printf("  Warning: Failed to export databases for %s: %v\n", pageTitle, err)
+		return nil, nil
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		e.printf("  Warning: Failed to create database directory %s: %v\n", baseDir, err)
+		return nil, nil
+	}
+	e.nameDatabaseExports(ctx, exports, baseDir, filePath, pageTitle)
+
+	// Export database to CSV
+	forEachDatabase(exports, func(export *databaseExport) {
+		e.statusf("  Exporting database '%s' to: %s\n", export.title, export.csvPath)
+
+		dbSync := &databaseSync{client: e.notion, warnings: e.log().Writer()}
+		export.err = dbSync.SyncNotionDatabaseToCSV(ctx, export.databaseID, export.csvPath)
+	})
+
+	var databaseRefs []DatabaseReference
+	for _, export := range exports {
+		if export.err != nil {
+			e.printf("  Warning: Failed to export database %s: %v\n", export.databaseID, export.err)
+			continue
+		}
+		databaseRefs = append(databaseRefs, export.reference())
+	}
+
+	if len(databaseRefs) > 0 {
+		e.statusf("  Exported %d database(s)\n", len(databaseRefs))
+	}
+
+	return databaseRefs, nil
+}
+
+// databaseReferences returns the references a pull would add for the child
+// databases in blocks, without exporting them
+func (e *engine) databaseReferences(ctx context.Context, blocks []notion.Block, filePath, pageTitle string) []DatabaseReference {
+	exports := e.childDatabases(blocks, false)
+	if len(exports) == 0 {
+		return nil
+	}
+	baseDir, err := e.databaseExportDir(filePath)
+	if err != nil {
+		return nil
+	}
+	e.nameDatabaseExports(ctx, exports, baseDir, filePath, pageTitle)
+
+	databaseRefs := make([]DatabaseReference, len(exports))
+	for i, export := range exports {
+		databaseRefs[i] = export.reference()
+	}
+	return databaseRefs
+}
+
+// childDatabases returns an export for each child database block, in page
+// order. report prints each one found.
+func (e *engine) childDatabases(blocks []notion.Block, report bool) []*databaseExport {
 	databaseCount := 0
+	var exports []*databaseExport
 
 	// Check blocks for child_database type
 	for _, block := range blocks {
@@ -699,7 +1526,9 @@ func (e *engine) exportChildDatabases(ctx context.Context, pageID, filePath, pag
 			databaseCount++
 
 			// Debug: print block structure
-			fmt.Printf("  Debug: Found child_database block, ID: %s\n", block.ID)
+			if report {
+				e.statusf("  Debug: Found child_database block, ID: %s\n", block.ID)
+			}
 
 			// Try to extract database ID - it might be the block ID itself
 			databaseID := block.ID
@@ -710,58 +1539,74 @@ func (e *engine) exportChildDatabases(ctx context.Context, pageID, filePath, pag
 			}
 
 			if databaseID == "" {
-				fmt.Printf("  Warning: Found child_database block but couldn't extract database ID\n")
-				continue
-			}
-
-			// Get database title and create better CSV filename
-			baseDir := filepath.Dir(filePath)
-			dbTitle := fmt.Sprintf("Database %d", databaseCount)
-			var csvFileName string
-
-			if database, err := e.notion.GetDatabase(ctx, databaseID); err == nil {
-				if len(database.Title) > 0 && database.Title[0].PlainText != "" {
-					dbTitle = database.Title[0].PlainText
-					// Use the database title as the CSV filename
-					sanitizedDbTitle := e.sanitizeFilename(dbTitle)
-					csvFileName = fmt.Sprintf("%s.csv", sanitizedDbTitle)
-				} else {
-					// Fallback to page name with counter if database has no title
-					sanitizedTitle := e.sanitizeFilename(pageTitle)
-					csvFileName = fmt.Sprintf("%s_db%d.csv", sanitizedTitle, databaseCount)
+				if report {
+					e.printf("  Warning: Found child_database block but couldn't extract database ID\n")
 				}
-			} else {
-				// Fallback if we can't get database info
-				fmt.Printf("  Warning: Could not get database info for %s: %v\n", databaseID, err)
-				sanitizedTitle := e.sanitizeFilename(pageTitle)
-				csvFileName = fmt.Sprintf("%s_db%d.csv", sanitizedTitle, databaseCount)
+				continue
 			}
 
-			csvPath := filepath.Join(baseDir, csvFileName)
+			exports = append(exports, &databaseExport{databaseID: databaseID, index: databaseCount})
+		}
+	}
+	return exports
+}
 
-			// Export database to CSV
-			fmt.Printf("  Exporting database '%s' to: %s\n", dbTitle, csvFileName)
+// nameDatabaseExports looks up each database's title and works out the CSV
+// file it's exported to in baseDir and the link to it from filePath
+func (e *engine) nameDatabaseExports(ctx context.Context, exports []*databaseExport, baseDir, filePath, pageTitle string) {
+	// Look up titles concurrently
+	forEachDatabase(exports, func(export *databaseExport) {
+		export.title = fmt.Sprintf("Database %d", export.index)
+		database, err := e.notion.GetDatabase(ctx, export.databaseID)
+		if err != nil {
+			// Fallback if we can't get database info
+			e.printf("  Warning: Could not get database info for %s: %v\n", export.databaseID, err)
+			return
+		}
+		if len(database.Title) > 0 && database.Title[0].PlainText != "" {
+			export.title = database.Title[0].PlainText
+			export.hasTitle = true
+		}
+	})
 
-			// Create database sync instance and export
-			dbSync := NewDatabaseSync(e.notion)
-			if err := dbSync.SyncNotionDatabaseToCSV(ctx, databaseID, csvPath); err != nil {
-				fmt.Printf("  Warning: Failed to export database %s: %v\n", databaseID, err)
-				continue
-			}
+	// Name files in page order so colliding titles always resolve the same way
+	for _, export := range exports {
+		var csvFileName string
+		if export.hasTitle {
+			// Use the database title as the CSV filename
+			csvFileName = fmt.Sprintf("%s.csv", e.databaseFileName(baseDir, export.title))
+		} else {
+			// Fallback to page name with counter if database has no title
+			csvFileName = fmt.Sprintf("%s_db%d.csv", e.databaseFileName(baseDir, pageTitle), export.index)
+		}
+		export.csvPath = filepath.Join(baseDir, csvFileName)
 
-			databaseRefs = append(databaseRefs, DatabaseReference{
-				DatabaseID: databaseID,
-				Title:      dbTitle,
-				CSVPath:    csvFileName, // Store relative path for markdown reference
-			})
+		// Link to the export relative to the page file
+		linkPath, err := filepath.Rel(filepath.Dir(filePath), export.csvPath)
+		if err != nil {
+			linkPath = export.csvPath
 		}
+		export.linkPath = linkPath
 	}
+}
 
-	if len(databaseRefs) > 0 {
-		fmt.Printf("  Exported %d database(s)\n", len(databaseRefs))
+// reference returns the reference a page links to the export with
+func (export *databaseExport) reference() DatabaseReference {
+	return DatabaseReference{
+		DatabaseID: export.databaseID,
+		Title:      export.title,
+		CSVPath:    filepath.ToSlash(export.linkPath),
 	}
+}
 
-	return databaseRefs, nil
+// forEachDatabase runs fn over exports with a bounded pool of workers and
+// returns once every call has finished
+func forEachDatabase(exports []*databaseExport, fn func(export *databaseExport)) {
+	processor := batch.Processor{Workers: maxDatabaseExportWorkers}
+	processor.Run(context.Background(), len(exports), func(_ context.Context, i int) error {
+		fn(exports[i])
+		return nil
+	})
 }
 
 // addDatabaseReferences adds database references to the markdown content
@@ -774,35 +1619,87 @@ func (e *engine) addDatabaseReferences(content string, databaseRefs []DatabaseRe
 	content += "\n\n## Databases\n\n"
 
 	for _, ref := range databaseRefs {
-		content += fmt.Sprintf("- [%s](./%s)\n", ref.Title, ref.CSVPath)
+		link := ref.CSVPath
+		if !strings.HasPrefix(link, "../") && !filepath.IsAbs(link) {
+			link = "./" + link
+		}
+		content += fmt.Sprintf("- [%s](%s)\n", ref.Title, link)
 	}
 
 	return content
 }
 
-// sanitizeFilename removes characters that are invalid in filenames
-func (e *engine) sanitizeFilename(filename string) string {
-	// Replace common problematic characters
-	filename = strings.ReplaceAll(filename, "/", "_")
-	filename = strings.ReplaceAll(filename, "\\", "_")
-	filename = strings.ReplaceAll(filename, ":", "_")
-	filename = strings.ReplaceAll(filename, "*", "_")
-	filename = strings.ReplaceAll(filename, "?", "_")
-	filename = strings.ReplaceAll(filename, "\"", "_")
-	filename = strings.ReplaceAll(filename, "<", "_")
-	filename = strings.ReplaceAll(filename, ">", "_")
-	filename = strings.ReplaceAll(filename, "|", "_")
-	filename = strings.ReplaceAll(filename, " ", "_")
+// linkChildPages replaces child_page blocks whose page is in pagePaths with
+// a paragraph linking to the child's file, relative to the parent's
+// directory, so the link stays where the child page sits in the body
+func linkChildPages(blocks []notion.Block, filePath string, pagePaths map[string]string) []notion.Block {
+	if len(pagePaths) == 0 {
+		return blocks
+	}
 
-	// Remove any leading/trailing dots or spaces
-	filename = strings.Trim(filename, ". ")
+	linked := make([]notion.Block, len(blocks))
+	for i, block := range blocks {
+		linked[i] = block
+		if block.Type != "child_page" || block.ChildPage == nil {
+			continue
+		}
+		childPath, ok := pagePaths[block.ID]
+		if !ok {
+			continue
+		}
+		link, err := relativeLink(filePath, childPath)
+		if err != nil {
+			continue
+		}
 
-	// Ensure it's not empty
-	if filename == "" {
-		filename = "untitled"
+		linked[i] = notion.Block{
+			ID:   block.ID,
+			Type: "paragraph",
+			Paragraph: &notion.RichTextBlock{
+				RichText: []notion.RichText{{
+					Type:      "text",
+					PlainText: block.ChildPage.Title,
+					Text: &notion.TextContent{
+						Content: block.ChildPage.Title,
+						Link:    &notion.Link{URL: link},
+					},
+				}},
+			},
+		}
 	}
+	return linked
+}
+
+// databaseExportDir returns the directory a page's database exports go in:
+// next to the page file by default, or under the configured databases
+// directory, mirroring the page's location below the markdown root
+func (e *engine) databaseExportDir(filePath string) (string, error) {
+	pageDir := filepath.Dir(filePath)
 
-	return filename
+	databasesDir := e.config.Directories.DatabasesDir
+	if databasesDir == "" {
+		return pageDir, nil
+	}
+
+	root := e.config.Directories.MarkdownRoot
+	if !filepath.IsAbs(databasesDir) {
+		databasesDir = filepath.Join(root, databasesDir)
+	}
+
+	relDir, err := filepath.Rel(root, pageDir)
+	if err != nil || relDir == ".." || strings.HasPrefix(relDir, ".."+string(filepath.Separator)) {
+		// Pages outside the markdown root go at the top of the databases directory
+		return databasesDir, nil
+	}
+
+	return util.SecureJoin(databasesDir, relDir)
+}
+
+// databaseFileName returns a link-friendly file name in dir for an exported
+// database, using underscores for spaces so markdown references don't need
+// escaping
+func (e *engine) databaseFileName(dir, name string) string {
+	return strings.ReplaceAll(e.fileNames.Sanitize(dir, name), " ", "_")
 }
 
 // shouldUseStreaming determines if we should use streaming based on workspace size
@@ -821,7 +1718,7 @@ func (e *engine) shouldUseStreaming(ctx context.Context) bool {
 
 // syncAllNotionToMarkdownStreaming uses streaming to handle large workspaces
 func (e *engine) syncAllNotionToMarkdownStreaming(ctx context.Context) error {
-	fmt.Println("🌊 Using streaming mode for large workspace")
+	e.printf("🌊 Using streaming mode for large workspace\n")
 
 	// Get parent page first
 	parentPage, err := e.notion.GetPage(ctx, e.config.Notion.ParentPageID)
@@ -833,46 +1730,81 @@ func (e *engine) syncAllNotionToMarkdownStreaming(ctx context.Context) error {
 	parentTitle := e.extractTitleFromPage(parentPage)
 	parentPath := e.buildFilePathForPageStreaming(*parentPage, parentTitle)
 
-	util.Progress("Processing parent page: %s", parentTitle)
+	var local map[string]string
+	if e.detectRenames {
+		local = e.localNotionFiles()
+		e.renamePulledFile(local, parentPage.ID, parentPath)
+	}
+
+	e.log().Progress("Processing parent page: %s", parentTitle)
 	if err := e.syncNotionPageToFile(ctx, *parentPage, parentPath); err != nil {
-		util.WithError(err, "Failed to sync parent page")
+		e.log().WithError(err, "Failed to sync parent page")
 	}
 
 	processedCount := 0
 	errorCount := 0
+	var skipped []string
 
 	// Stream and process descendant pages
-	stream := e.notion.StreamDescendantPages(ctx, e.config.Notion.ParentPageID)
+	stream := e.notion.StreamDescendantPages(ctx, e.config.Notion.ParentPageID, e.maxDepth)
 
 	for {
 		select {
 		case page, ok := <-stream.Pages():
 			if !ok {
-				fmt.Printf("\n🎉 Streaming sync complete! %d/%d pages successful\n", processedCount-errorCount, processedCount+1) // +1 for parent
+				// The walk's last error is sent just before the stream closes
+				for err := range stream.Errors() {
+					if err := e.streamError(err, &errorCount, &skipped); err != nil {
+						return err
+					}
+				}
+				e.printf("\n🎉 Streaming sync complete! %d/%d pages successful\n", processedCount-errorCount, processedCount+1) // +1 for parent
+				e.warnSkipped(skipped)
 				return nil
 			}
 
 			processedCount++
 			title := e.extractTitleFromPage(&page)
 			filePath := e.buildFilePathForPageStreaming(page, title)
+			if e.detectRenames {
+				e.renamePulledFile(local, page.ID, filePath)
+			}
 
-			util.Progress("[%d] Processing page: %s", processedCount, title)
+			if e.progress == nil {
+				e.log().Progress("[%d] Processing page: %s", processedCount, title)
+			}
 
-			if err := e.syncNotionPageToFile(ctx, page, filePath); err != nil {
+			err := e.syncNotionPageToFile(ctx, page, filePath)
+			if err != nil {
 				errorCount++
-				util.ErrorMsg("Error: %v", err)
-			} else {
-				util.Success("Successfully synced: %s", title)
+				if e.progress == nil {
+					e.log().ErrorMsg("Error: %v", err)
+				}
+			} else if e.progress == nil {
+				e.log().Success("Successfully synced: %s", title)
 			}
 
+			e.reportProgress(ProgressEvent{
+				PageID:    page.ID,
+				Title:     title,
+				Index:     processedCount - 1,
+				Completed: processedCount,
+				Failed:    errorCount,
+				Err:       err,
+			})
+
 			// Progress indicator for large operations
-			if processedCount%50 == 0 {
-				util.Progress("\n--- Progress: %d pages processed ---", processedCount)
+			if e.progress == nil && processedCount%50 == 0 {
+				e.log().Progress("\n--- Progress: %d pages processed ---", processedCount)
 			}
 
-		case err := <-stream.Errors():
-			errorCount++
-			util.Warning("Streaming error: %v", err)
+		case err, ok := <-stream.Errors():
+			if !ok {
+				continue
+			}
+			if err := e.streamError(err, &errorCount, &skipped); err != nil {
+				return err
+			}
 
 		case <-ctx.Done():
 			return fmt.Errorf("sync cancelled: %w", ctx.Err())
@@ -880,48 +1812,47 @@ func (e *engine) syncAllNotionToMarkdownStreaming(ctx context.Context) error {
 	}
 }
 
+// streamError handles an error from a page stream: restricted pages are
+// added to skipped, a page limit stops the pull, and anything else is
+// counted in errorCount
+func (e *engine) streamError(err error, errorCount *int, skipped *[]string) error {
+	var restricted *notion.RestrictedPagesError
+	switch {
+	case errors.As(err, &restricted):
+		*skipped = append(*skipped, restricted.PageIDs...)
+	case errors.As(err, new(*notion.PageLimitError)):
+		return withPageLimitHint(err)
+	default:
+		*errorCount++
+		e.log().Warning("Streaming error: %v", err)
+	}
+	return nil
+}
+
 // buildFilePathForPageStreaming builds file path without needing all pages in memory
 func (e *engine) buildFilePathForPageStreaming(page notion.Page, title string) string {
 	// For streaming, we use a simpler path construction
 	// This avoids needing to keep all pages in memory to build the hierarchy
-	safeTitle := util.SanitizeFileName(title)
+	safeTitle := e.fileNames.Sanitize(e.config.Directories.MarkdownRoot, title)
+	if e.flatten {
+		return filepath.Join(e.config.Directories.MarkdownRoot, safeTitle+".md")
+	}
 
 	// Create a simple path: markdown_root/page_title/page_title.md
 	fullPath, err := util.SecureJoin(e.config.Directories.MarkdownRoot, safeTitle, safeTitle+".md")
 	if err != nil {
 		// Fallback to safe path
-		util.Warning("Path construction failed for %s, using fallback", page.ID)
+		e.log().Warning("Path construction failed for %s, using fallback", page.ID)
 		fullPath = filepath.Join(e.config.Directories.MarkdownRoot, safeTitle, safeTitle+".md")
 	}
 
 	return fullPath
 }
 
-// syncNotionPageToFile syncs a single Notion page to a markdown file
+// syncNotionPageToFile syncs a single Notion page found by a streaming pull
+// to a markdown file
 func (e *engine) syncNotionPageToFile(ctx context.Context, page notion.Page, filePath string) error {
-	// Get page blocks
-	blocks, err := e.notion.GetPageBlocks(ctx, page.ID)
-	if err != nil {
-		return fmt.Errorf("failed to get page blocks: %w", err)
-	}
-
-	// Convert to markdown
-	converter := NewConverter()
-	markdown, err := converter.BlocksToMarkdown(blocks)
-	if err != nil {
-		return fmt.Errorf("failed to convert blocks to markdown: %w", err)
-	}
-
-	// Ensure directory exists
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Write to file
-	if err := os.WriteFile(filePath, []byte(markdown), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return withOperationTimeout(ctx, e.config.Timeouts.PageFetch, "timeouts.page_fetch", func(ctx context.Context) error {
+		return e.writePulledPage(ctx, &page, filePath, nil)
+	})
 }
@@ -0,0 +1,55 @@
+package sync_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+)
+
+// ExampleSyncer embeds the sync engine in another program. The only output
+// is what the example prints itself.
+func ExampleSyncer() {
+	// Stand-in for the Notion API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pages/page-id":
+			_, _ = w.Write([]byte(`{"id": "page-id", "properties": {"title": {"type": "title", "title": [{"plain_text": "Notes"}]}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"results": [{"id": "b1", "type": "paragraph", "paragraph": {"rich_text": [{"plain_text": "Hello"}]}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "syncer-example")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cfg := &config.Config{}
+	cfg.Notion.Token = "secret-token"
+	cfg.Directories.MarkdownRoot = dir
+
+	syncer, err := sync.NewSyncer(cfg, sync.WithClientOptions(notion.WithBaseURL(server.URL)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	result, err := syncer.PullFile(context.Background(), "page-id", filepath.Join(dir, "notes.md"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("pulled %d page(s), %d failed\n", result.Succeeded(), result.Failed())
+	// Output: pulled 1 page(s), 0 failed
+}
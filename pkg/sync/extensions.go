@@ -0,0 +1,73 @@
+package sync
+
+import (
+	"strings"
+	gosync "sync"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/yuin/goldmark/ast"
+)
+
+// BlockWriterFunc renders a Notion block as markdown. Returning false leaves
+// the block to the built-in handling for its type.
+type BlockWriterFunc func(md *strings.Builder, block *notion.Block) bool
+
+// NodeConverterFunc converts a goldmark AST node into Notion blocks.
+// Returning false leaves the node to the built-in handling for its kind.
+type NodeConverterFunc func(node ast.Node, source []byte) ([]map[string]interface{}, bool)
+
+// Custom converters are consulted before the built-in ones, which remain the
+// default for everything not registered here
+var (
+	extensionsMu   gosync.RWMutex
+	blockWriters   = make(map[string]BlockWriterFunc)
+	nodeConverters = make(map[ast.NodeKind]NodeConverterFunc)
+)
+
+// RegisterBlockWriter sets a custom writer for a Notion block type such as
+// "callout" when converting to markdown. A nil fn removes it again.
+func RegisterBlockWriter(blockType string, fn BlockWriterFunc) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	if fn == nil {
+		delete(blockWriters, blockType)
+		return
+	}
+	blockWriters[blockType] = fn
+}
+
+// RegisterNodeConverter sets a custom converter for a markdown node kind
+// such as ast.KindBlockquote when converting to Notion blocks. A nil fn
+// removes it again.
+func RegisterNodeConverter(kind ast.NodeKind, fn NodeConverterFunc) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	if fn == nil {
+		delete(nodeConverters, kind)
+		return
+	}
+	nodeConverters[kind] = fn
+}
+
+// writeCustomBlock renders block with its registered writer, reporting
+// whether one handled it
+func writeCustomBlock(md *strings.Builder, block *notion.Block) bool {
+	extensionsMu.RLock()
+	fn := blockWriters[block.Type]
+	extensionsMu.RUnlock()
+
+	return fn != nil && fn(md, block)
+}
+
+// convertCustomNode converts node with its registered converter, reporting
+// whether one handled it
+func convertCustomNode(node ast.Node, source []byte) ([]map[string]interface{}, bool) {
+	extensionsMu.RLock()
+	fn := nodeConverters[node.Kind()]
+	extensionsMu.RUnlock()
+
+	if fn == nil {
+		return nil, false
+	}
+	return fn(node, source)
+}
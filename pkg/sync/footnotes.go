@@ -0,0 +1,284 @@
+package sync
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// footnotesHeading titles the section pushes collect footnotes into, as
+// Notion has no footnotes
+const footnotesHeading = "Footnotes"
+
+// superscriptDigits are the digits of footnote markers, ⁰ to ⁹
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// footnoteMarkerPattern matches a footnote marker in pulled markdown
+var footnoteMarkerPattern = regexp.MustCompile("[⁰¹²³⁴⁵⁶⁷⁸⁹]+")
+
+// footnoteMarker returns the superscript number pushes write for a
+// reference to footnote index, ¹ for the first footnote referenced. Markers
+// don't link to their footnotes in Notion: block IDs only exist once the
+// page is written, and a marker can't point at a block anyway.
+func footnoteMarker(index int) string {
+	digits := []rune(strconv.Itoa(index))
+	for i, d := range digits {
+		digits[i] = superscriptDigits[d-'0']
+	}
+	return string(digits)
+}
+
+// parseFootnoteMarker returns the footnote index a marker stands for
+func parseFootnoteMarker(marker string) int {
+	index := 0
+	for _, r := range marker {
+		for digit, s := range superscriptDigits {
+			if r == s {
+				index = index*10 + digit
+			}
+		}
+	}
+	return index
+}
+
+// convertFootnoteList turns a document's footnote definitions into the
+// Footnotes heading and a numbered list item per footnote, keeping their
+// inline formatting. A footnote's paragraphs are joined into one item.
+func convertFootnoteList(list *east.FootnoteList, source []byte) []map[string]interface{} {
+	blocks := []map[string]interface{}{createHeadingBlock(2, footnotesHeading)}
+	for footnote := list.FirstChild(); footnote != nil; footnote = footnote.NextSibling() {
+		var spans []RichText
+		for child := footnote.FirstChild(); child != nil; child = child.NextSibling() {
+			paragraph := trimSpans(inlineSpans(child, source, RichText{}, nil))
+			if len(paragraph) == 0 {
+				continue
+			}
+			if len(spans) > 0 {
+				spans = appendSpan(spans, RichText{Content: " "})
+			}
+			for _, span := range paragraph {
+				spans = appendSpan(spans, span)
+			}
+		}
+		blocks = append(blocks, textBlock("numbered_list_item", richTextObjects(spans...)))
+	}
+	return blocks
+}
+
+// inlineSpans appends the text of node's inline children to spans, styled
+// with their emphasis, code and links on top of style
+func inlineSpans(node ast.Node, source []byte, style RichText, spans []RichText) []RichText {
+	for n := node.FirstChild(); n != nil; n = n.NextSibling() {
+		styled := style
+		switch n := n.(type) {
+		case *ast.Text:
+			styled.Content = string(textValue(n, source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				styled.Content += " "
+			}
+			spans = appendSpan(spans, styled)
+		case *ast.String:
+			styled.Content = string(n.Value)
+			spans = appendSpan(spans, styled)
+		case *east.FootnoteLink:
+			styled.Content = footnoteMarker(n.Index)
+			spans = appendSpan(spans, styled)
+		case *ast.CodeSpan:
+			styled.Code = true
+			styled.Content = extractTextFromNode(n, source)
+			spans = appendSpan(spans, styled)
+		case *ast.Emphasis:
+			if n.Level >= 2 {
+				styled.Bold = true
+			} else {
+				styled.Italic = true
+			}
+			spans = inlineSpans(n, source, styled, spans)
+		case *ast.Link:
+			styled.Link = string(n.Destination)
+			spans = inlineSpans(n, source, styled, spans)
+		default:
+			spans = inlineSpans(n, source, styled, spans)
+		}
+	}
+	return spans
+}
+
+// appendSpan appends span, merging it into the last span when both are
+// styled the same
+func appendSpan(spans []RichText, span RichText) []RichText {
+	if span.Content == "" {
+		return spans
+	}
+	if last := len(spans) - 1; last >= 0 {
+		merged := spans[last]
+		merged.Content = span.Content
+		if merged == span {
+			spans[last].Content += span.Content
+			return spans
+		}
+	}
+	return append(spans, span)
+}
+
+// trimSpans trims the whitespace around the text of spans, dropping spans
+// left empty
+func trimSpans(spans []RichText) []RichText {
+	for len(spans) > 0 {
+		spans[0].Content = strings.TrimLeftFunc(spans[0].Content, unicode.IsSpace)
+		if spans[0].Content != "" {
+			break
+		}
+		spans = spans[1:]
+	}
+	for len(spans) > 0 {
+		last := len(spans) - 1
+		spans[last].Content = strings.TrimRightFunc(spans[last].Content, unicode.IsSpace)
+		if spans[last].Content != "" {
+			break
+		}
+		spans = spans[:last]
+	}
+	return spans
+}
+
+// splitFootnotes separates a trailing Footnotes section, a heading of that
+// name followed only by numbered list items, from the blocks before it
+func splitFootnotes(blocks []notion.Block) (body, footnotes []notion.Block) {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := &blocks[i]
+		if block.Type == "numbered_list_item" && block.NumberedListItem != nil {
+			continue
+		}
+		if i < len(blocks)-1 && block.Type == "heading_2" && block.Heading2 != nil &&
+			strings.TrimSpace(extractPlainTextFromRichText(block.Heading2.RichText)) == footnotesHeading {
+			return blocks[:i], blocks[i:]
+		}
+		break
+	}
+	return blocks, nil
+}
+
+// restoreFootnotes turns the markers in md back into footnote references,
+// numbered as pushes number them so labels such as [^note] come back as
+// numbers, and appends a definition for each item of the Footnotes section. It reports
+// false, leaving md alone, unless every footnote is referenced: the section
+// may just be a list someone titled Footnotes.
+//
+// A superscript number in the text may be a marker or just a superscript,
+// as in mc². Only numbers directly after text, outside code, count, and
+// only in the order pushes number footnotes: the first ¹, then the first ²
+// after it, and so on, though a footnote referenced again repeats its
+// number. Anything else is left as written.
+func (c *converter) restoreFootnotes(md string, footnotes []notion.Block) (string, bool) {
+	items := footnotes[1:]
+
+	next := 1 // The footnote whose marker comes next
+	lines := strings.Split(md, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if inCode {
+			continue
+		}
+		spans := codeSpans(line)
+		var out strings.Builder
+		last := 0
+		for _, loc := range footnoteMarkerPattern.FindAllStringIndex(line, -1) {
+			start, end := loc[0], loc[1]
+			if start == 0 || isSpaceBefore(line, start) || inSpans(spans, start) {
+				continue
+			}
+			index := parseFootnoteMarker(line[start:end])
+			if index < 1 || index > next || index > len(items) {
+				continue
+			}
+			out.WriteString(line[last:start] + "[^" + strconv.Itoa(index) + "]")
+			last = end
+			if index == next {
+				next++
+			}
+		}
+		lines[i] = out.String() + line[last:]
+	}
+	if next <= len(items) {
+		return md, false
+	}
+
+	var out strings.Builder
+	out.WriteString(strings.Join(lines, "\n") + "\n\n")
+	for i, item := range items {
+		out.WriteString("[^" + strconv.Itoa(i+1) + "]: " + c.richText(item.NumberedListItem.RichText) + "\n")
+		c.stats.record(item.Type, true)
+	}
+	c.stats.record(footnotes[0].Type, true)
+	return strings.TrimSuffix(out.String(), "\n"), true
+}
+
+// isSpaceBefore reports whether the character before text[i] is whitespace
+func isSpaceBefore(text string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return unicode.IsSpace(r)
+}
+
+// codeSpans returns the start and end of each inline code span in line
+func codeSpans(line string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		ticks := backtickRun(line, i)
+
+		// A span closes at the next run of the same number of backticks
+		end := -1
+		for j := i + ticks; j < len(line); {
+			if line[j] != '`' {
+				j++
+				continue
+			}
+			run := backtickRun(line, j)
+			if run == ticks {
+				end = j + run
+				break
+			}
+			j += run
+		}
+		if end < 0 {
+			// An unmatched run is literal backticks
+			i += ticks
+			continue
+		}
+		spans = append(spans, [2]int{i, end})
+		i = end
+	}
+	return spans
+}
+
+// backtickRun returns how many backticks start at line[i]
+func backtickRun(line string, i int) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// inSpans reports whether offset i falls inside one of spans
+func inSpans(spans [][2]int, i int) bool {
+	for _, span := range spans {
+		if i >= span[0] && i < span[1] {
+			return true
+		}
+	}
+	return false
+}
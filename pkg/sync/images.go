@@ -0,0 +1,101 @@
+package sync
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+// DefaultInlineImageMax is the largest image, in bytes, pulls inline when
+// no other limit is given
+const DefaultInlineImageMax = 1 << 20
+
+// assetFetchTimeout bounds downloading a single image or file
+const assetFetchTimeout = 30 * time.Second
+
+// inlineImages replaces the URLs of image blocks with base64 data URIs, so
+// the pulled markdown doesn't depend on Notion's expiring file links or on
+// external hosts. Images larger than e.inlineImageMax, or that can't be
+// fetched, keep their URL.
+func (e *engine) inlineImages(ctx context.Context, blocks []notion.Block) {
+	if e.inlineImageMax <= 0 {
+		return
+	}
+
+	client := &http.Client{Timeout: assetFetchTimeout}
+	for i := range blocks {
+		block := &blocks[i]
+		if block.Image != nil {
+			var url *string
+			switch {
+			case block.Image.File != nil:
+				url = &block.Image.File.URL
+			case block.Image.External != nil:
+				url = &block.Image.External.URL
+			}
+
+			if url != nil && *url != "" && !strings.HasPrefix(*url, "data:") {
+				dataURI, err := fetchImageDataURI(ctx, client, *url, e.inlineImageMax)
+				if err != nil {
+					e.log().Warning("Keeping image link %s: %v", *url, err)
+				} else {
+					*url = dataURI
+				}
+			}
+		}
+		e.inlineImages(ctx, block.Children)
+	}
+}
+
+// fetchImageDataURI downloads an image of at most maxBytes and encodes it as
+// a data URI
+func fetchImageDataURI(ctx context.Context, client *http.Client, url string, maxBytes int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return "", fmt.Errorf("image is %d bytes, over the %d byte inline limit", resp.ContentLength, maxBytes)
+	}
+
+	// Read one byte past the limit to tell when a response without a
+	// Content-Length is too large
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return "", fmt.Errorf("image is over the %d byte inline limit", maxBytes)
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.TrimSpace(mediaType)
+	if !strings.HasPrefix(mediaType, "image/") {
+		// Storage hosts often send application/octet-stream
+		mediaType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("not an image (%s)", mediaType)
+	}
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
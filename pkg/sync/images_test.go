@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_InlineImages(t *testing.T) {
+	// The PNG signature is enough for content sniffing
+	small := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 16)...)
+	large := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 4096)...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/small.png":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(small)
+		case "/large.png":
+			_, _ = w.Write(large)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	image := func(url, caption string) notion.Block {
+		return notion.Block{Type: "image", Image: &notion.ImageBlock{
+			Type: "file",
+			File: &notion.InternalFile{URL: url},
+			Caption: []notion.RichText{
+				{Type: "text", PlainText: caption, Text: &notion.TextContent{Content: caption}},
+			},
+		}}
+	}
+
+	e, mockNotion, mockParser, _ := createTestEngine(t)
+	e.converter = NewConverter()
+	e.inlineImageMax = 1024
+
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		return []notion.Block{
+			image(server.URL+"/small.png", "Small"),
+			image(server.URL+"/large.png", "Large"),
+			image(server.URL+"/missing.png", "Missing"),
+		}, nil
+	}
+	var content string
+	mockParser.createMarkdownWithFrontmatterFunc = func(filePath string, metadata map[string]interface{}, c string) error {
+		content = c
+		return nil
+	}
+
+	filePath := filepath.Join(e.config.Directories.MarkdownRoot, "page.md")
+	require.NoError(t, e.SyncNotionToFile(context.Background(), "page-id", filePath))
+
+	assert.Contains(t, content, "![Small](data:image/png;base64,"+base64.StdEncoding.EncodeToString(small)+")")
+	assert.Contains(t, content, "![Large]("+server.URL+"/large.png)", "images over the limit stay links")
+	assert.Contains(t, content, "![Missing]("+server.URL+"/missing.png)", "images that can't be fetched stay links")
+}
+
+func TestEngine_InlineImages_DisabledByDefault(t *testing.T) {
+	e, _, _, _ := createTestEngine(t)
+
+	blocks := []notion.Block{{Type: "image", Image: &notion.ImageBlock{
+		Type:     "external",
+		External: &notion.ExternalFile{URL: "http://127.0.0.1:0/never-fetched.png"},
+	}}}
+	e.inlineImages(context.Background(), blocks)
+	assert.Equal(t, "http://127.0.0.1:0/never-fetched.png", blocks[0].Image.External.URL)
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+)
+
+// SortByDocumentOrder sorts markdown files, given relative to dir, into the
+// order their pages should be created in. Notion lists child pages in the
+// order they were created, so creating them in this order keeps a folder of
+// numbered chapters in sequence. Files are grouped by directory. Within a
+// directory, files with an order frontmatter field or a name starting with a
+// number (01-intro.md) come first, by that number, then the rest by name.
+func SortByDocumentOrder(dir string, files []string) {
+	parser := markdown.NewParser()
+	type sortKey struct {
+		dir     string
+		name    string
+		order   float64
+		ordered bool
+	}
+	keys := make(map[string]sortKey, len(files))
+	for _, file := range files {
+		key := sortKey{dir: filepath.Dir(file), name: filepath.Base(file)}
+		key.order, key.ordered = documentOrder(parser, filepath.Join(dir, file))
+		keys[file] = key
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := keys[files[i]], keys[files[j]]
+		switch {
+		case a.dir != b.dir:
+			return a.dir < b.dir
+		case a.ordered != b.ordered:
+			return a.ordered
+		case a.ordered && a.order != b.order:
+			return a.order < b.order
+		default:
+			return a.name < b.name
+		}
+	})
+}
+
+// documentOrder returns a file's position among its siblings: the order
+// frontmatter field, else the number its name starts with
+func documentOrder(parser markdown.Parser, path string) (float64, bool) {
+	// Files that can't be parsed fail when they're pushed; here they just
+	// sort by name
+	if doc, err := parser.ParseFile(path); err == nil {
+		if frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata); err == nil && frontmatter.Order != nil {
+			return *frontmatter.Order, true
+		}
+	}
+
+	name := filepath.Base(path)
+	digits := len(name) - len(strings.TrimLeft(name, "0123456789"))
+	if digits == 0 {
+		return 0, false
+	}
+	order, err := strconv.ParseFloat(name[:digits], 64)
+	return order, err == nil
+}
+
+// SplitNewFiles separates files, given relative to dir, that don't have a
+// Notion page yet from those that do, keeping their order. New files must
+// be pushed one at a time for their pages to be created in that order.
+func SplitNewFiles(dir string, files []string) (newFiles, existing []string) {
+	parser := markdown.NewParser()
+	for _, file := range files {
+		doc, err := parser.ParseFile(filepath.Join(dir, file))
+		if err == nil {
+			if frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata); err == nil && frontmatter.NotionID != "" {
+				existing = append(existing, file)
+				continue
+			}
+		}
+		newFiles = append(newFiles, file)
+	}
+	return newFiles, existing
+}
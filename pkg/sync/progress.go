@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ProgressEvent reports the outcome of one page during a bulk sync
+type ProgressEvent struct {
+	PageID    string
+	Title     string
+	Index     int // The page's position in the sync, from 0
+	Completed int // Pages finished so far, including failures
+	Failed    int
+	Skipped   int // Pages passed over because Notion denied access to them
+	Total     int // 0 when the total isn't known up front (streaming mode)
+	Err       error
+}
+
+// ProgressFunc receives progress events. It is always called from a single
+// goroutine for a given sync run.
+type ProgressFunc func(ProgressEvent)
+
+// ProgressReporter is implemented by engines that can report per-page
+// progress. When a ProgressFunc is set the engine stops printing its own
+// per-page status lines so callers can render progress themselves.
+type ProgressReporter interface {
+	SetProgressFunc(fn ProgressFunc)
+}
+
+// SetProgressFunc registers fn to receive progress events; nil restores the
+// default per-page output
+func (e *engine) SetProgressFunc(fn ProgressFunc) {
+	e.progress = fn
+}
+
+// reportProgress forwards an event to the registered ProgressFunc, if any
+func (e *engine) reportProgress(event ProgressEvent) {
+	if e.progress != nil {
+		e.progress(event)
+	}
+}
+
+// printf writes engine output through the logger
+func (e *engine) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(e.log().Writer(), format, args...)
+}
+
+// statusf prints a per-page status line unless a ProgressFunc has taken
+// over progress output
+func (e *engine) statusf(format string, args ...interface{}) {
+	if e.progress == nil {
+		e.printf(format, args...)
+	}
+}
+
+// pageStatusKey holds the buffer a page's status lines are collected in
+// while it is pulled with ordered output
+type pageStatusKey struct{}
+
+// pageStatusf prints a status line for the page being pulled with ctx, or
+// holds it back until the page is done when output is ordered
+func (e *engine) pageStatusf(ctx context.Context, format string, args ...interface{}) {
+	if output, ok := ctx.Value(pageStatusKey{}).(*strings.Builder); ok {
+		if e.progress == nil {
+			_, _ = fmt.Fprintf(output, format, args...)
+		}
+		return
+	}
+	e.statusf(format, args...)
+}
@@ -0,0 +1,452 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+// Page property types mirrored in the frontmatter "properties" map so task
+// state can be edited from markdown and synced both ways. Other property
+// types are only set by pushes.
+const (
+	propertyTypeStatus   = "status"
+	propertyTypeCheckbox = "checkbox"
+)
+
+// extractTaskProperties returns the page's status and checkbox properties as
+// frontmatter values: status by option name, checkbox as a bool
+func extractTaskProperties(page *notion.Page) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for name, raw := range page.Properties {
+		prop, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch prop["type"] {
+		case propertyTypeStatus:
+			// An unset status comes back as null
+			status, _ := prop["status"].(map[string]interface{})
+			statusName, _ := status["name"].(string)
+			values[name] = statusName
+		case propertyTypeCheckbox:
+			checked, _ := prop["checkbox"].(bool)
+			values[name] = checked
+		}
+	}
+
+	return values
+}
+
+// propertyKeys maps Notion property names to the frontmatter keys they're
+// written under, from mapping.properties. Viper lowercases map keys, so names
+// match case-insensitively.
+type propertyKeys map[string]string
+
+// key returns the frontmatter key for a Notion property: its mapped key, or
+// else its name slugified
+func (k propertyKeys) key(name string) string {
+	for mapped, key := range k {
+		if strings.EqualFold(mapped, name) {
+			return key
+		}
+	}
+	return slugifyPropertyName(name)
+}
+
+// frontmatter rekeys property values by frontmatter key instead of Notion
+// property name. Properties whose keys collide, such as "Due Date" and
+// due_date, are each written under their own name instead so neither is lost.
+func (k propertyKeys) frontmatter(values map[string]interface{}) map[string]interface{} {
+	names := make(map[string][]string, len(values))
+	for name := range values {
+		key := k.key(name)
+		names[key] = append(names[key], name)
+	}
+
+	keyed := make(map[string]interface{}, len(values))
+	for key, shared := range names {
+		if len(shared) == 1 {
+			keyed[key] = values[shared[0]]
+			continue
+		}
+		for _, name := range shared {
+			keyed[name] = values[name]
+		}
+	}
+	return keyed
+}
+
+// slugifyPropertyName lowercases a property name and joins its words with
+// underscores, dropping emoji and punctuation: "Status 🚦" becomes status.
+// Names with no letters or digits are kept as they are.
+func slugifyPropertyName(name string) string {
+	var slug strings.Builder
+	pending := false
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pending = slug.Len() > 0
+			continue
+		}
+		if pending {
+			slug.WriteByte('_')
+			pending = false
+		}
+		slug.WriteRune(unicode.ToLower(r))
+	}
+	if slug.Len() == 0 {
+		return name
+	}
+	return slug.String()
+}
+
+// propertyValues returns the frontmatter values a push writes to page
+// properties: the properties map, plus any top-level key the sync doesn't use
+// itself, such as tags. A properties entry wins over a top-level key of the
+// same name.
+func propertyValues(metadata, properties map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(properties))
+	for name, value := range properties {
+		values[name] = value
+	}
+
+	for key, value := range metadata {
+		if isSyncField(key) {
+			continue
+		}
+		shadowed := false
+		for name := range properties {
+			if strings.EqualFold(name, key) {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func isSyncField(key string) bool {
+	for _, field := range markdown.SyncFields {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+// pageProperty finds the page property a frontmatter value sets: an exact
+// name match, else the property pulls write under that key, else one
+// differing only in case, since YAML keys are usually lowercase and Notion
+// property names capitalized
+func pageProperty(page *notion.Page, keys propertyKeys, name string) (string, map[string]interface{}, bool) {
+	if raw, ok := page.Properties[name].(map[string]interface{}); ok {
+		return name, raw, true
+	}
+	for _, matches := range []func(propName string) bool{
+		func(propName string) bool { return keys.key(propName) == name },
+		func(propName string) bool { return strings.EqualFold(propName, name) },
+	} {
+		for propName, prop := range page.Properties {
+			if raw, ok := prop.(map[string]interface{}); ok && matches(propName) {
+				return propName, raw, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// buildPropertyUpdates compares frontmatter values against the page's
+// current properties and returns Notion property payloads for the ones that
+// changed. Values without a matching property, or whose property has a type
+// that can't be set from frontmatter (title, relation, formula, ...), are
+// ignored. Values are keyed as in the frontmatter, matched to properties
+// through keys.
+func buildPropertyUpdates(page *notion.Page, keys propertyKeys, values map[string]interface{}) (map[string]interface{}, error) {
+	current := extractTaskProperties(page)
+	updates := make(map[string]interface{})
+
+	for key, value := range values {
+		name, raw, ok := pageProperty(page, keys, key)
+		if !ok {
+			continue
+		}
+
+		switch raw["type"] {
+		case propertyTypeStatus:
+			statusName, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("property %q is a status and needs a string value, got %T", name, value)
+			}
+			if statusName == current[name] {
+				continue
+			}
+			if statusName == "" {
+				updates[name] = map[string]interface{}{"status": nil}
+			} else {
+				// Status options are set by name, never through the select payload
+				updates[name] = map[string]interface{}{
+					"status": map[string]interface{}{"name": statusName},
+				}
+			}
+		case propertyTypeCheckbox:
+			checked, err := parseCheckboxValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", name, err)
+			}
+			if checked == current[name] {
+				continue
+			}
+			updates[name] = map[string]interface{}{"checkbox": checked}
+		case "multi_select":
+			names, err := parseMultiSelectValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", name, err)
+			}
+			if equalStrings(names, multiSelectNames(raw)) {
+				continue
+			}
+			options := make([]map[string]interface{}, 0, len(names))
+			for _, option := range names {
+				options = append(options, map[string]interface{}{"name": option})
+			}
+			updates[name] = map[string]interface{}{"multi_select": options}
+		case "select", "rich_text", "number", "url", "email", "phone_number", "date":
+			update, changed, err := buildScalarPropertyUpdate(raw, value)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: %w", name, err)
+			}
+			if changed {
+				updates[name] = update
+			}
+		}
+	}
+
+	return updates, nil
+}
+
+// buildScalarPropertyUpdate builds the payload setting a single-valued
+// property, and reports whether it differs from the current value. Empty
+// values clear the property.
+func buildScalarPropertyUpdate(raw map[string]interface{}, value interface{}) (map[string]interface{}, bool, error) {
+	propType := raw["type"].(string)
+
+	if propType == "number" {
+		number, err := parseNumberValue(value)
+		if err != nil {
+			return nil, false, err
+		}
+		currentNumber, _ := raw["number"].(float64)
+		if number == nil && raw["number"] == nil || number != nil && raw["number"] != nil && *number == currentNumber {
+			return nil, false, nil
+		}
+		if number == nil {
+			return map[string]interface{}{"number": nil}, true, nil
+		}
+		return map[string]interface{}{"number": *number}, true, nil
+	}
+
+	text, err := parseTextValue(value)
+	if err != nil {
+		return nil, false, err
+	}
+	if text == scalarPropertyText(raw) {
+		return nil, false, nil
+	}
+
+	var payload interface{}
+	switch {
+	case propType == "rich_text":
+		payload = newRichText(text)
+	case text == "":
+		payload = nil
+	case propType == "select":
+		payload = map[string]interface{}{"name": text}
+	case propType == "date":
+		payload = map[string]interface{}{"start": text}
+	default:
+		payload = text
+	}
+	return map[string]interface{}{propType: payload}, true, nil
+}
+
+// scalarPropertyText returns a select, text, URL, email, phone number or
+// date property's current value as the text frontmatter would hold
+func scalarPropertyText(raw map[string]interface{}) string {
+	switch propType := raw["type"].(string); propType {
+	case "select":
+		option, _ := raw["select"].(map[string]interface{})
+		name, _ := option["name"].(string)
+		return name
+	case "date":
+		date, _ := raw["date"].(map[string]interface{})
+		start, _ := date["start"].(string)
+		return start
+	case "rich_text":
+		var text strings.Builder
+		segments, _ := raw["rich_text"].([]interface{})
+		for _, segment := range segments {
+			if object, ok := segment.(map[string]interface{}); ok {
+				plainText, _ := object["plain_text"].(string)
+				text.WriteString(plainText)
+			}
+		}
+		return text.String()
+	default:
+		value, _ := raw[propType].(string)
+		return value
+	}
+}
+
+// multiSelectNames returns the names of a multi-select property's options
+func multiSelectNames(raw map[string]interface{}) []string {
+	options, _ := raw["multi_select"].([]interface{})
+	names := make([]string, 0, len(options))
+	for _, option := range options {
+		if object, ok := option.(map[string]interface{}); ok {
+			name, _ := object["name"].(string)
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseMultiSelectValue accepts a YAML list of option names, or a single
+// name
+func parseMultiSelectValue(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			name, err := parseTextValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("multi-select options must be names: %w", err)
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	case []string:
+		return v, nil
+	default:
+		name, err := parseTextValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("multi-select needs a list of option names, got %T", value)
+		}
+		if name == "" {
+			return nil, nil
+		}
+		return []string{name}, nil
+	}
+}
+
+// parseTextValue accepts strings, and the numbers, booleans and dates YAML
+// decodes unquoted scalars as
+func parseTextValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format("2006-01-02"), nil
+		}
+		return v.Format(time.RFC3339), nil
+	default:
+		return "", fmt.Errorf("needs a single value, got %T", value)
+	}
+}
+
+// parseNumberValue accepts YAML numbers and numeric strings. Null or an empty
+// string yields nil, which clears the property.
+func parseNumberValue(value interface{}) (*float64, error) {
+	var number float64
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case uint64:
+		number = float64(v)
+	case float64:
+		number = v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number needs a numeric value, got %q", v)
+		}
+		number = parsed
+	default:
+		return nil, fmt.Errorf("number needs a numeric value, got %T", value)
+	}
+	return &number, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCheckboxValue accepts YAML booleans as well as "true"/"false" strings
+func parseCheckboxValue(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		checked, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("checkbox needs a boolean value, got %q", v)
+		}
+		return checked, nil
+	default:
+		return false, fmt.Errorf("checkbox needs a boolean value, got %T", value)
+	}
+}
+
+// pushProperties updates the page's properties to match the frontmatter
+// values, skipping the request when nothing changed
+func (e *engine) pushProperties(ctx context.Context, pageID string, values map[string]interface{}) error {
+	page, err := e.notion.GetPage(ctx, pageID)
+	if err != nil {
+		return fmt.Errorf("failed to get Notion page: %w", err)
+	}
+
+	updates, err := buildPropertyUpdates(page, e.config.Mapping.Properties, values)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := e.notion.UpdatePageProperties(ctx, pageID, updates); err != nil {
+		return fmt.Errorf("failed to update page properties: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+)
+
+// Directory pruning after a pull, set with WithPruneDirs
+const (
+	// PruneEmptyDirs removes directories a pull leaves empty, such as the
+	// directory of a page that was renamed
+	PruneEmptyDirs = "empty"
+	// PruneStaleDirs also removes directories holding nothing but markdown
+	// files of pages deleted or archived in Notion, as long as the files
+	// weren't edited since they were last synced
+	PruneStaleDirs = "stale"
+)
+
+// pruneDirs removes the directories below root that the pull left empty or,
+// with PruneStaleDirs, stale. pulled maps the pulled pages to their files.
+// A directory holding anything else is kept: other files, markdown files
+// without a notion_id, hidden or excluded entries. root itself is never
+// removed, and failures are only reported, since the pull itself worked.
+func (e *engine) pruneDirs(ctx context.Context, root string, pulled map[string]string) {
+	if e.pruneMode == "" {
+		return
+	}
+
+	// A pull limited in depth leaves out pages that still exist
+	stale := e.pruneMode == PruneStaleDirs && e.maxDepth < 0
+	if e.pruneMode == PruneStaleDirs && !stale {
+		e.log().Warning("Only removing empty directories: --max-depth leaves out pages that still exist")
+	}
+
+	pulledIDs := make(map[string]bool, len(pulled))
+	for id := range pulled {
+		pulledIDs[comparableID(id)] = true
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		e.log().Warning("Failed to read %s to remove empty directories: %v", root, err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && e.prunable(filepath.Join(root, entry.Name()), true) {
+			e.pruneDir(ctx, filepath.Join(root, entry.Name()), pulledIDs, stale)
+		}
+	}
+}
+
+// pruneDir removes dir if nothing in it needs keeping and reports whether it
+// was removed
+func (e *engine) pruneDir(ctx context.Context, dir string, pulledIDs map[string]bool, stale bool) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		e.log().Warning("Failed to read %s: %v", dir, err)
+		return false
+	}
+
+	keep := false
+	var staleFiles []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case !e.prunable(path, entry.IsDir()):
+			keep = true
+		case entry.IsDir():
+			if !e.pruneDir(ctx, path, pulledIDs, stale) {
+				keep = true
+			}
+		case stale && e.isStalePage(ctx, path, pulledIDs):
+			staleFiles = append(staleFiles, path)
+		default:
+			keep = true
+		}
+	}
+	if keep {
+		return false
+	}
+
+	for _, file := range staleFiles {
+		if err := os.Remove(file); err != nil {
+			e.log().Warning("Failed to remove %s: %v", file, err)
+			return false
+		}
+	}
+	if err := os.Remove(dir); err != nil {
+		e.log().Warning("Failed to remove %s: %v", dir, err)
+		return false
+	}
+	if len(staleFiles) > 0 {
+		e.printf("🧹 Removed directory of pages no longer in Notion: %s\n", dir)
+	} else {
+		e.printf("🧹 Removed empty directory: %s\n", dir)
+	}
+	return true
+}
+
+// prunable reports whether an entry may be removed at all: hidden entries
+// such as .git and excluded paths never are
+func (e *engine) prunable(path string, isDir bool) bool {
+	return !strings.HasPrefix(filepath.Base(path), ".") && !e.ignoreMatcher().Ignored(e.relPath(path), isDir)
+}
+
+// isStalePage reports whether path is a markdown file of a page that wasn't
+// pulled because it was deleted or archived in Notion. Pages missing from
+// the pull for other reasons, such as being moved or restricted, aren't
+// stale, nor are files without a notion_id, which were written by hand, or
+// files edited since they were last synced.
+func (e *engine) isStalePage(ctx context.Context, path string, pulledIDs map[string]bool) bool {
+	if !strings.HasSuffix(path, ".md") {
+		return false
+	}
+	doc, err := e.parser.ParseFile(path)
+	if err != nil {
+		return false
+	}
+	frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+	if err != nil || frontmatter.NotionID == "" || pulledIDs[comparableID(frontmatter.NotionID)] {
+		return false
+	}
+	if frontmatter.ContentHash != e.fileContentHash(path, doc, frontmatter) {
+		e.log().Warning("Keeping %s: it has changes that weren't synced", path)
+		return false
+	}
+
+	gone, err := e.pageIsGone(ctx, frontmatter.NotionID)
+	if err != nil {
+		e.log().Warning("Keeping %s: %v", path, err)
+		return false
+	}
+	return gone
+}
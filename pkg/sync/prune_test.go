@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPruneTestSyncer returns a Syncer pulling a Root page with one child,
+// Live, into a Root directory that already holds leftovers from earlier
+// pulls and files the user added
+func newPruneTestSyncer(t *testing.T, mode string) (*Syncer, string) {
+	t.Helper()
+
+	// synced returns a file as last synced with a page, edited by edit
+	synced := func(pageID, title, body, edit string) string {
+		return "---\nnotion_id: " + pageID + "\ncontent_hash: " + contentHash(title, body, nil) + "\n---\n" + body + edit
+	}
+
+	cfg := newSyncerTestConfig(t)
+	root := filepath.Join(cfg.Directories.MarkdownRoot, "Root")
+	files := map[string]string{
+		"Keep/notes.txt":   "not markdown",
+		"Gone/Gone.md":     synced("0000aaaa-0000-0000-0000-000000000000", "Gone", "Deleted in Notion\n", ""),
+		"Edited/Edited.md": synced("2222cccc-0000-0000-0000-000000000000", "Edited", "Deleted in Notion\n", "Edited since\n"),
+		"Moved/Moved.md":   synced("3333dddd-0000-0000-0000-000000000000", "Moved", "Moved out of the tree\n", ""),
+		"Mine/draft.md":    "A file never pushed\n",
+		"Live/old-id.md":   "---\nnotion_id: 1111BBBB000000000000000000000000\n---\nLive page, dashless ID\n",
+		".hidden/.keep":    "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	for _, dir := range []string{"Empty/Nested", "Renamed/Old"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+
+	titled := func(id, title, parentID string) notion.Page {
+		page := notion.Page{ID: id, Properties: map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":  "title",
+				"title": []interface{}{map[string]interface{}{"plain_text": title}},
+			},
+		}}
+		if parentID != "" {
+			page.Parent = notion.Parent{Type: "page_id", PageID: parentID}
+		}
+		return page
+	}
+	pages := map[string]notion.Page{
+		"root-id":                              titled("root-id", "Root", ""),
+		"1111bbbb-0000-0000-0000-000000000000": titled("1111bbbb-0000-0000-0000-000000000000", "Live", "root-id"),
+		"3333dddd-0000-0000-0000-000000000000": titled("3333dddd-0000-0000-0000-000000000000", "Moved", "elsewhere-id"),
+	}
+
+	mockNotion := &mockNotionClient{}
+	mockNotion.getPageFunc = func(ctx context.Context, pageID string) (*notion.Page, error) {
+		page, ok := pages[pageID]
+		if !ok {
+			return nil, &notion.NotionAPIError{Code: http.StatusNotFound, Message: "Could not find page"}
+		}
+		return &page, nil
+	}
+	mockNotion.getAllDescendantPagesFunc = func(ctx context.Context, parentID string) ([]notion.Page, error) {
+		return []notion.Page{pages["1111bbbb-0000-0000-0000-000000000000"]}, nil
+	}
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithPruneDirs(mode))
+	require.NoError(t, err)
+	return syncer, root
+}
+
+func TestSyncer_PruneEmptyDirs(t *testing.T) {
+	syncer, root := newPruneTestSyncer(t, PruneEmptyDirs)
+
+	_, err := syncer.PullPage(context.Background(), "root-id")
+	require.NoError(t, err)
+
+	// Directories left empty are removed, however deeply nested
+	assert.NoDirExists(t, filepath.Join(root, "Empty"))
+	assert.NoDirExists(t, filepath.Join(root, "Renamed"))
+
+	// Anything holding files is kept, as are hidden directories
+	assert.FileExists(t, filepath.Join(root, "Keep", "notes.txt"))
+	assert.FileExists(t, filepath.Join(root, "Gone", "Gone.md"))
+	assert.FileExists(t, filepath.Join(root, "Mine", "draft.md"))
+	assert.DirExists(t, filepath.Join(root, ".hidden"))
+	assert.FileExists(t, filepath.Join(root, "Root.md"))
+	assert.FileExists(t, filepath.Join(root, "Live", "Live.md"))
+}
+
+func TestSyncer_PruneStaleDirs(t *testing.T) {
+	syncer, root := newPruneTestSyncer(t, PruneStaleDirs)
+
+	_, err := syncer.PullPage(context.Background(), "root-id")
+	require.NoError(t, err)
+
+	// Only the directory of the page that is gone from Notion goes
+	assert.NoDirExists(t, filepath.Join(root, "Gone"))
+	assert.NoDirExists(t, filepath.Join(root, "Empty"))
+	assert.FileExists(t, filepath.Join(root, "Edited", "Edited.md"), "local changes weren't pushed")
+	assert.FileExists(t, filepath.Join(root, "Moved", "Moved.md"), "the page still exists elsewhere")
+	assert.FileExists(t, filepath.Join(root, "Keep", "notes.txt"))
+	assert.FileExists(t, filepath.Join(root, "Mine", "draft.md"))
+	assert.FileExists(t, filepath.Join(root, "Live", "old-id.md"), "notion_id matches a pulled page without dashes")
+	assert.FileExists(t, filepath.Join(root, "Root.md"))
+}
+
+func TestNewSyncer_InvalidPruneMode(t *testing.T) {
+	_, err := NewSyncer(newSyncerTestConfig(t), WithPruneDirs("everything"))
+	assert.Error(t, err)
+}
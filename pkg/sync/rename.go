@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+)
+
+// localNotionFiles maps the notion_id of every markdown file under the
+// markdown root to the file, so a pull can find a page's file wherever it
+// was written last. Files that can't be parsed are left out.
+func (e *engine) localNotionFiles() map[string]string {
+	files, err := e.markdownFiles()
+	if err != nil {
+		e.log().Warning("Rename detection is off for this pull: %v", err)
+		return nil
+	}
+
+	local := make(map[string]string, len(files))
+	for _, path := range files {
+		if pageID := e.fileNotionID(path); pageID != "" {
+			local[comparableID(pageID)] = path
+		}
+	}
+	return local
+}
+
+// fileNotionID returns the notion_id of a markdown file, or "" when it has
+// none or can't be read
+func (e *engine) fileNotionID(path string) string {
+	doc, err := e.parser.ParseFile(path)
+	if err != nil {
+		return ""
+	}
+	frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+	if err != nil {
+		return ""
+	}
+	return frontmatter.NotionID
+}
+
+// renamePulledFile moves the file last pulled for pageID, as found in local,
+// to filePath when the page's title or place in the tree has changed, so the
+// pull updates it in place rather than writing a second copy. A file already
+// at filePath is never overwritten. Directories the move leaves empty are
+// removed.
+func (e *engine) renamePulledFile(local map[string]string, pageID, filePath string) {
+	current, ok := local[comparableID(pageID)]
+	if !ok || filepath.Clean(current) == filepath.Clean(filePath) {
+		return
+	}
+	if _, err := os.Stat(filePath); err == nil {
+		e.log().Warning("Not renaming %s to %s: the file already exists", current, filePath)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		e.log().Warning("Failed to rename %s: %v", current, err)
+		return
+	}
+	if err := os.Rename(current, filePath); err != nil {
+		e.log().Warning("Failed to rename %s: %v", current, err)
+		return
+	}
+	local[comparableID(pageID)] = filePath
+	e.statusf("  Renamed %s to %s\n", current, filePath)
+
+	root := filepath.Clean(e.config.Directories.MarkdownRoot)
+	for dir := filepath.Dir(current); dir != root && dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		// Remove only fails once a directory holds something else
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+}
+
+// syncRenamedNotionToMarkdown pulls the page a file's notion_id points at,
+// renaming the file first if the page's title changed. It reports false when
+// the file has no notion_id, so the page must be found by title instead.
+func (e *engine) syncRenamedNotionToMarkdown(ctx context.Context, filePath string) (bool, error) {
+	pageID := e.fileNotionID(filePath)
+	if pageID == "" {
+		return false, nil
+	}
+
+	page, err := e.notion.GetPage(ctx, pageID)
+	if err != nil {
+		return true, fmt.Errorf("failed to get page %s of %s: %w", pageID, filePath, err)
+	}
+	title := e.extractTitleFromPage(page)
+	dir := filepath.Dir(filePath)
+	target := filepath.Join(dir, e.fileNames.Sanitize(dir, title)+".md")
+	e.renamePulledFile(map[string]string{comparableID(pageID): filePath}, pageID, target)
+
+	e.printf("Pulling page: %s\n", title)
+	e.printf("  Notion ID: %s\n", page.ID)
+	e.printf("  Saving to: %s\n", target)
+
+	if err := e.SyncNotionToFile(ctx, page.ID, target); err != nil {
+		return true, fmt.Errorf("failed to sync page %s: %w", page.ID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRenamedPageClient serves a Root page with one child, child-id, now
+// titled "New Title"
+func newRenamedPageClient() *mockNotionClient {
+	titled := func(id, title, parentID string) notion.Page {
+		page := notion.Page{ID: id, Properties: map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":  "title",
+				"title": []interface{}{map[string]interface{}{"plain_text": title}},
+			},
+		}}
+		if parentID != "" {
+			page.Parent = notion.Parent{Type: "page_id", PageID: parentID}
+		}
+		return page
+	}
+	pages := map[string]notion.Page{
+		"root-id":  titled("root-id", "Root", ""),
+		"child-id": titled("child-id", "New Title", "root-id"),
+	}
+
+	mockNotion := &mockNotionClient{}
+	mockNotion.getPageFunc = func(ctx context.Context, pageID string) (*notion.Page, error) {
+		page := pages[pageID]
+		return &page, nil
+	}
+	mockNotion.getAllDescendantPagesFunc = func(ctx context.Context, parentID string) ([]notion.Page, error) {
+		return []notion.Page{pages["child-id"]}, nil
+	}
+	return mockNotion
+}
+
+// writePulledFile writes a file as an earlier pull of child-id left it
+func writePulledFile(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	content := "---\ntitle: Old Title\nnotion_id: child-id\n---\n\nOld content\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestSyncer_RenameDetection_RenamesFileOfRetitledPage(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	root := filepath.Join(cfg.Directories.MarkdownRoot, "Root")
+	oldPath := filepath.Join(root, "Old Title", "Old Title.md")
+	writePulledFile(t, oldPath)
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(newRenamedPageClient()), WithRenameDetection())
+	require.NoError(t, err)
+
+	_, err = syncer.PullPage(context.Background(), "root-id")
+	require.NoError(t, err)
+
+	newPath := filepath.Join(root, "New Title", "New Title.md")
+	assert.FileExists(t, newPath)
+	assert.NoFileExists(t, oldPath)
+	assert.NoDirExists(t, filepath.Dir(oldPath), "the directory the rename left empty is removed")
+
+	doc, err := syncer.Engine().(*engine).parser.ParseFile(newPath)
+	require.NoError(t, err)
+	assert.Equal(t, "New Title", doc.Metadata["title"])
+	assert.Equal(t, "child-id", doc.Metadata["notion_id"])
+}
+
+func TestSyncer_WithoutRenameDetection_LeavesOldFile(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	root := filepath.Join(cfg.Directories.MarkdownRoot, "Root")
+	oldPath := filepath.Join(root, "Old Title", "Old Title.md")
+	writePulledFile(t, oldPath)
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(newRenamedPageClient()))
+	require.NoError(t, err)
+
+	_, err = syncer.PullPage(context.Background(), "root-id")
+	require.NoError(t, err)
+
+	// The page ends up in two files
+	assert.FileExists(t, filepath.Join(root, "New Title", "New Title.md"))
+	assert.FileExists(t, oldPath)
+}
+
+func TestSyncer_RenameDetection_KeepsExistingTarget(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	root := filepath.Join(cfg.Directories.MarkdownRoot, "Root")
+	oldPath := filepath.Join(root, "Old Title", "Old Title.md")
+	writePulledFile(t, oldPath)
+	newPath := filepath.Join(root, "New Title", "New Title.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(newPath), 0755))
+	require.NoError(t, os.WriteFile(newPath, []byte("# Someone else's file\n"), 0644))
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(newRenamedPageClient()), WithRenameDetection())
+	require.NoError(t, err)
+
+	_, err = syncer.PullPage(context.Background(), "root-id")
+	require.NoError(t, err)
+
+	// Nothing is moved over a file already there
+	assert.FileExists(t, oldPath)
+}
+
+func TestEngine_SyncSpecificFile_FindsRenamedPageByNotionID(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	oldPath := filepath.Join(cfg.Directories.MarkdownRoot, "Old Title.md")
+	writePulledFile(t, oldPath)
+
+	mockNotion := newRenamedPageClient()
+	mockNotion.getChildPagesFunc = func(ctx context.Context, parentID string) ([]notion.Page, error) {
+		t.Error("the page should be found by notion_id, not by title")
+		return nil, nil
+	}
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithRenameDetection())
+	require.NoError(t, err)
+
+	require.NoError(t, syncer.Engine().SyncSpecificFile(context.Background(), "Old Title.md", "pull"))
+
+	assert.NoFileExists(t, oldPath)
+	assert.FileExists(t, filepath.Join(cfg.Directories.MarkdownRoot, "New Title.md"))
+}
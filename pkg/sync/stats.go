@@ -0,0 +1,90 @@
+package sync
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	gosync "sync"
+)
+
+// ConversionStats tallies the Notion blocks converted to markdown by block
+// type, separating the types the converter had to drop. It is safe for
+// concurrent use, since pulls convert pages in parallel.
+type ConversionStats struct {
+	mu          gosync.Mutex
+	converted   map[string]int
+	unsupported map[string]int
+}
+
+// NewConversionStats returns an empty tally
+func NewConversionStats() *ConversionStats {
+	return &ConversionStats{
+		converted:   make(map[string]int),
+		unsupported: make(map[string]int),
+	}
+}
+
+// record counts one block. A nil tally records nothing, so converters
+// without one pay only for the nil check.
+func (s *ConversionStats) record(blockType string, supported bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if supported {
+		s.converted[blockType]++
+	} else {
+		s.unsupported[blockType]++
+	}
+}
+
+// Converted returns the number of blocks converted, by block type
+func (s *ConversionStats) Converted() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyCounts(s.converted)
+}
+
+// Unsupported returns the number of blocks dropped because the converter has
+// no markdown for their type, by block type
+func (s *ConversionStats) Unsupported() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyCounts(s.unsupported)
+}
+
+// WriteReport prints the tally, one block type per line
+func (s *ConversionStats) WriteReport(w io.Writer) {
+	converted, unsupported := s.Converted(), s.Unsupported()
+
+	fmt.Fprintln(w, "Conversion report:")
+	if len(converted) == 0 && len(unsupported) == 0 {
+		fmt.Fprintln(w, "  no blocks converted")
+		return
+	}
+	writeCounts(w, converted)
+	if len(unsupported) > 0 {
+		fmt.Fprintln(w, "Unsupported (dropped):")
+		writeCounts(w, unsupported)
+	}
+}
+
+func writeCounts(w io.Writer, counts map[string]int) {
+	types := make([]string, 0, len(counts))
+	for blockType := range counts {
+		types = append(types, blockType)
+	}
+	sort.Strings(types)
+	for _, blockType := range types {
+		fmt.Fprintf(w, "  %-20s %d\n", blockType, counts[blockType])
+	}
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	out := make(map[string]int, len(counts))
+	for k, v := range counts {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+func TestConverter_ConversionStats(t *testing.T) {
+	data := `[
+		{"type": "heading_1", "heading_1": {"rich_text": [{"plain_text": "Title"}]}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"plain_text": "One"}]}},
+		{"type": "paragraph", "paragraph": {"rich_text": [{"plain_text": "Two"}]}},
+		{"type": "divider", "divider": {}},
+		{"type": "column_list", "column_list": {}},
+		{"type": "video", "video": {}},
+		{"type": "video", "video": {}}
+	]`
+	var blocks []notion.Block
+	if err := json.Unmarshal([]byte(data), &blocks); err != nil {
+		t.Fatalf("failed to unmarshal blocks: %v", err)
+	}
+
+	stats := NewConversionStats()
+	if _, err := NewConverterWithStats(stats).BlocksToMarkdown(blocks); err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+
+	wantConverted := map[string]int{"heading_1": 1, "paragraph": 2, "divider": 1}
+	if got := stats.Converted(); !reflect.DeepEqual(got, wantConverted) {
+		t.Errorf("Converted() = %v, want %v", got, wantConverted)
+	}
+	wantUnsupported := map[string]int{"column_list": 1, "video": 2}
+	if got := stats.Unsupported(); !reflect.DeepEqual(got, wantUnsupported) {
+		t.Errorf("Unsupported() = %v, want %v", got, wantUnsupported)
+	}
+
+	var report strings.Builder
+	stats.WriteReport(&report)
+	for _, want := range []string{"paragraph", "Unsupported (dropped):", "video"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, report.String())
+		}
+	}
+}
+
+func TestConverter_WithoutStats(t *testing.T) {
+	blocks := []notion.Block{{Type: "video"}, {Type: "divider"}}
+	md, err := NewConverter().BlocksToMarkdown(blocks)
+	if err != nil {
+		t.Fatalf("BlocksToMarkdown() error = %v", err)
+	}
+	if md != "---" {
+		t.Errorf("BlocksToMarkdown() = %q, want %q", md, "---")
+	}
+}
@@ -66,6 +66,13 @@ func (sds *StreamingDatabaseSync) ExportToCSVStreaming(ctx context.Context, data
 				return nil
 			}
 
+			if err := expandTruncatedProperties(ctx, sds.client, &row); err != nil {
+				util.WithError(err, "Exporting the property values returned so far")
+			}
+			if err := resolvePeopleNames(ctx, sds.client, &row); err != nil {
+				util.WithError(err, "Exporting user IDs instead")
+			}
+
 			// Convert row to CSV format and write immediately
 			csvRow := sds.buildCSVRow(row, database.Properties)
 			if err := writer.Write(csvRow); err != nil {
@@ -103,8 +110,8 @@ func (sds *StreamingDatabaseSync) buildCSVHeader(properties map[string]notion.Pr
 	var header []string
 	header = append(header, "ID", "Title") // Standard fields first
 
-	for name := range properties {
-		if name != "title" && name != "Title" { // Avoid duplicates
+	for name, prop := range properties {
+		if prop.Type != "title" && name != "title" && name != "Title" { // Avoid duplicates
 			header = append(header, name)
 		}
 	}
@@ -118,14 +125,16 @@ func (sds *StreamingDatabaseSync) buildCSVRow(dbRow notion.DatabaseRow, properti
 
 	// Extract title from property value
 	title := "Untitled"
-	if titleProp, ok := dbRow.Properties["title"]; ok {
-		title = sds.extractPropertyValueFromPropertyValue(titleProp)
+	if titleProp, ok := dbRow.Properties[dbRow.TitlePropertyName()]; ok {
+		if value := sds.extractPropertyValueFromPropertyValue(titleProp); value != "" {
+			title = value
+		}
 	}
 	row = append(row, title)
 
 	// Add other properties in the same order as header
-	for name := range properties {
-		if name != "title" && name != "Title" {
+	for name, prop := range properties {
+		if prop.Type != "title" && name != "title" && name != "Title" {
 			value := sds.extractPropertyValueFromPropertyValue(dbRow.Properties[name])
 			row = append(row, value)
 		}
@@ -170,6 +179,8 @@ func (sds *StreamingDatabaseSync) extractPropertyValueFromPropertyValue(property
 		if property.Date != nil && property.Date.Start != nil {
 			return property.Date.Start.Format("2006-01-02")
 		}
+	case "people":
+		return strings.Join(peopleNames(property.People), ", ")
 		// Add more property types as needed
 	}
 
@@ -192,7 +203,7 @@ func NewStreamingPageProcessor(engine Engine) *StreamingPageProcessor {
 func (spp *StreamingPageProcessor) ProcessPagesStreaming(ctx context.Context, parentID string, processor func(page notion.Page) error) error {
 	// Use the streaming client to get pages
 	if client, ok := spp.engine.(*engine); ok {
-		stream := client.notion.StreamDescendantPages(ctx, parentID)
+		stream := client.notion.StreamDescendantPages(ctx, parentID, client.maxDepth)
 
 		processedCount := 0
 		errorCount := 0
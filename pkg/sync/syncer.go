@@ -0,0 +1,384 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+)
+
+// Syncer is the entry point for using the sync engine as a library. It never
+// writes to stdout on its own: status output and warnings go to the logger
+// passed with WithLogger, and are discarded otherwise. Per-page outcomes are
+// returned as a Result.
+//
+// The "diff" conflict resolution strategy is interactive and reads from
+// stdin, so embedders running bidirectional syncs should configure one of
+// the non-interactive strategies. A Syncer is not safe for concurrent use.
+type Syncer struct {
+	engine *engine
+}
+
+// Result summarizes the pages or files handled by one Syncer call
+type Result struct {
+	Pages []PageResult
+}
+
+// PageResult is the outcome for a single page or file
+type PageResult struct {
+	PageID   string
+	Title    string
+	FilePath string
+	Err      error
+}
+
+// Succeeded returns the number of pages synced without error
+func (r *Result) Succeeded() int {
+	return len(r.Pages) - r.Failed()
+}
+
+// Failed returns the number of pages that failed to sync
+func (r *Result) Failed() int {
+	failed := 0
+	for _, page := range r.Pages {
+		if page.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// SyncerOption customizes a Syncer
+type SyncerOption func(*syncerOptions)
+
+type syncerOptions struct {
+	logger        *util.Logger
+	client        notion.Client
+	clientOptions []notion.ClientOption
+	maxDepth      int
+	flatten       bool
+	inlineImages  int64
+	assetsDir     string
+	assetsBaseURL string
+	archived      bool
+	orderedOutput bool
+	pruneMode     string
+	detectRenames bool
+	prePush       []Transform
+	postPull      []Transform
+	stats         *ConversionStats
+	noPrompts     bool
+}
+
+// WithLogger sends the Syncer's status output and warnings to logger
+func WithLogger(logger *util.Logger) SyncerOption {
+	return func(o *syncerOptions) {
+		o.logger = logger
+	}
+}
+
+// WithNotionClient uses client instead of one built from the config. The
+// client's own warning output is left as the caller configured it.
+func WithNotionClient(client notion.Client) SyncerOption {
+	return func(o *syncerOptions) {
+		o.client = client
+	}
+}
+
+// WithClientOptions customizes the Notion client built from the config, e.g.
+// to point it at a proxy with notion.WithBaseURL
+func WithClientOptions(opts ...notion.ClientOption) SyncerOption {
+	return func(o *syncerOptions) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// WithMaxDepth limits how many levels of sub-pages a pull descends below the
+// page it starts from: 0 pulls only that page, 1 adds its direct children.
+// The default, notion.UnlimitedDepth, pulls the whole tree.
+func WithMaxDepth(depth int) SyncerOption {
+	return func(o *syncerOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithFlatten makes pulls write every page straight into the markdown root
+// instead of a directory per page. Pages sharing a title get a short page ID
+// appended to their file name.
+func WithFlatten(flatten bool) SyncerOption {
+	return func(o *syncerOptions) {
+		o.flatten = flatten
+	}
+}
+
+// WithInlineImages makes pulls embed images of up to maxBytes in the
+// markdown as base64 data URIs, so files stay readable when moved or when
+// Notion's file links expire. Larger images keep their link. maxBytes <= 0
+// leaves every image linked.
+func WithInlineImages(maxBytes int64) SyncerOption {
+	return func(o *syncerOptions) {
+		o.inlineImages = maxBytes
+	}
+}
+
+// WithAssetDownloads makes pulls save the images, files and PDFs Notion
+// hosts, whose links expire, into dir and link to the saved copies. dir is
+// relative to the markdown root; empty means DefaultAssetsDir.
+func WithAssetDownloads(dir string) SyncerOption {
+	return func(o *syncerOptions) {
+		if dir == "" {
+			dir = DefaultAssetsDir
+		}
+		o.assetsDir = dir
+	}
+}
+
+// WithAssetsBaseURL makes pulls that download assets link to them under
+// baseURL, e.g. https://cdn.example.com/assets, instead of by a relative
+// path, for sites that publish the assets directory elsewhere. Saved files
+// are named by their content hash, which both the file and its URL use.
+// Asset downloads are turned on with DefaultAssetsDir unless
+// WithAssetDownloads chose a directory.
+func WithAssetsBaseURL(baseURL string) SyncerOption {
+	return func(o *syncerOptions) {
+		o.assetsBaseURL = baseURL
+	}
+}
+
+// WithArchivedPages makes pulls write the content of archived and trashed
+// pages. By default they only mark an existing file archived: true.
+func WithArchivedPages(include bool) SyncerOption {
+	return func(o *syncerOptions) {
+		o.archived = include
+	}
+}
+
+// WithOrderedOutput makes concurrent pulls print each page's status lines
+// together and in page order, instead of interleaved as workers get to them.
+// Lines for a page are held back until every page before it has finished.
+// Status lines are only printed by engine calls with no ProgressFunc set.
+func WithOrderedOutput(ordered bool) SyncerOption {
+	return func(o *syncerOptions) {
+		o.orderedOutput = ordered
+	}
+}
+
+// WithPrePushTransforms runs each file's markdown body through transforms,
+// in order, before it is converted and pushed. The file itself is left as
+// it was.
+func WithPrePushTransforms(transforms ...Transform) SyncerOption {
+	return func(o *syncerOptions) {
+		o.prePush = append(o.prePush, transforms...)
+	}
+}
+
+// WithPostPullTransforms runs each pulled page's markdown body through
+// transforms, in order, before the file is written
+func WithPostPullTransforms(transforms ...Transform) SyncerOption {
+	return func(o *syncerOptions) {
+		o.postPull = append(o.postPull, transforms...)
+	}
+}
+
+// WithPruneDirs makes pulls of a page tree clean up the tree's directory
+// afterwards: PruneEmptyDirs removes directories left empty, such as those
+// of renamed pages, and PruneStaleDirs also removes directories holding only
+// unedited markdown files of pages deleted or archived in Notion. Directories
+// with any other files are kept. Empty leaves directories alone.
+func WithPruneDirs(mode string) SyncerOption {
+	return func(o *syncerOptions) {
+		o.pruneMode = mode
+	}
+}
+
+// WithRenameDetection makes pulls find a page's file by its notion_id. When
+// the page's title or parent changed since the last pull, the file is moved
+// to its new path and updated there instead of the page being pulled again
+// as a new file beside the old one. Pulling a single file by name also looks
+// its page up by notion_id first, falling back to the title.
+func WithRenameDetection() SyncerOption {
+	return func(o *syncerOptions) {
+		o.detectRenames = true
+	}
+}
+
+// WithNonInteractiveConflicts makes bidirectional syncs skip conflicts that
+// need the user's choice, with ErrConflictNeedsChoice, rather than prompt for
+// it. For runs nobody can answer, such as --json output read by a script.
+func WithNonInteractiveConflicts() SyncerOption {
+	return func(o *syncerOptions) {
+		o.noPrompts = true
+	}
+}
+
+// WithConversionStats tallies the blocks converted during pulls in stats
+func WithConversionStats(stats *ConversionStats) SyncerOption {
+	return func(o *syncerOptions) {
+		o.stats = stats
+	}
+}
+
+// NewSyncer creates a Syncer from a loaded configuration
+func NewSyncer(cfg *config.Config, opts ...SyncerOption) (*Syncer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	options := syncerOptions{
+		logger:   util.NewLogger(util.INFO, io.Discard),
+		maxDepth: notion.UnlimitedDepth,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	switch options.pruneMode {
+	case "", PruneEmptyDirs, PruneStaleDirs:
+	default:
+		return nil, fmt.Errorf("invalid prune mode %q (valid: %s, %s)", options.pruneMode, PruneEmptyDirs, PruneStaleDirs)
+	}
+	if options.assetsBaseURL != "" {
+		if err := util.ValidateURL(options.assetsBaseURL); err != nil {
+			return nil, fmt.Errorf("invalid assets base URL: %w", err)
+		}
+		if options.assetsDir == "" {
+			options.assetsDir = DefaultAssetsDir
+		}
+	}
+
+	client := options.client
+	if client == nil {
+		clientOptions := append([]notion.ClientOption{notion.WithWarningWriter(options.logger.Writer())}, options.clientOptions...)
+		client = newNotionClient(cfg, clientOptions...)
+	}
+
+	e := NewEngineWithClient(cfg, client).(*engine)
+	e.workerCount = cfg.Performance.Workers
+	e.logger = options.logger
+	e.maxDepth = options.maxDepth
+	e.flatten = options.flatten
+	e.inlineImageMax = options.inlineImages
+	e.includeArchived = options.archived
+	e.orderedOutput = options.orderedOutput
+	e.pruneMode = options.pruneMode
+	e.detectRenames = options.detectRenames
+	e.prePush = options.prePush
+	e.postPull = options.postPull
+	if dir := options.assetsDir; dir != "" {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cfg.Directories.MarkdownRoot, dir)
+		}
+		e.assets = newAssetStore(dir)
+		e.assets.baseURL = options.assetsBaseURL
+	}
+	if options.stats != nil {
+		converterOpts := converterOptions(cfg)
+		converterOpts.Stats = options.stats
+		e.converter = NewConverterWithOptions(converterOpts)
+	}
+	e.conflictResolver.out = options.logger.Writer()
+	e.conflictResolver.nonInteractive = options.noPrompts
+
+	return &Syncer{engine: e}, nil
+}
+
+// Engine returns the underlying engine, which shares the Syncer's logger
+func (s *Syncer) Engine() Engine {
+	return s.engine
+}
+
+// Client returns the Notion client the Syncer's engine uses
+func (s *Syncer) Client() notion.Client {
+	return s.engine.notion
+}
+
+// Pull pulls every page under the configured parent page
+func (s *Syncer) Pull(ctx context.Context) (*Result, error) {
+	return s.collect(func() error {
+		return s.engine.SyncAll(ctx, "pull")
+	})
+}
+
+// PullPage pulls a single page and all of its descendants
+func (s *Syncer) PullPage(ctx context.Context, pageID string) (*Result, error) {
+	return s.collect(func() error {
+		return s.engine.SyncPageSubtree(ctx, pageID, "pull")
+	})
+}
+
+// PullFile pulls one page into filePath
+func (s *Syncer) PullFile(ctx context.Context, pageID, filePath string) (*Result, error) {
+	err := s.engine.SyncNotionToFile(ctx, pageID, filePath)
+	return &Result{Pages: []PageResult{{PageID: pageID, FilePath: filePath, Err: err}}}, err
+}
+
+// Push pushes every markdown file under the configured markdown root. A file
+// that fails doesn't stop the others; its error is recorded in the Result.
+func (s *Syncer) Push(ctx context.Context) (*Result, error) {
+	result := &Result{}
+
+	files, err := s.engine.markdownFiles()
+	if err != nil {
+		return result, fmt.Errorf("failed to walk markdown root: %w", err)
+	}
+
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("push cancelled: %w", err)
+		}
+		result.Pages = append(result.Pages, PageResult{
+			FilePath: path,
+			Err:      s.engine.SyncFileToNotion(ctx, path),
+		})
+	}
+
+	return result, nil
+}
+
+// PushFile pushes one markdown file
+func (s *Syncer) PushFile(ctx context.Context, filePath string) (*Result, error) {
+	err := s.engine.SyncFileToNotion(ctx, filePath)
+	return &Result{Pages: []PageResult{{FilePath: filePath, Err: err}}}, err
+}
+
+// Diff returns a unified diff of what syncing filePath in direction would
+// change, without syncing anything
+func (s *Syncer) Diff(ctx context.Context, filePath, direction string) (string, error) {
+	return s.engine.DiffFile(ctx, filePath, direction)
+}
+
+// collect runs a bulk sync, gathering per-page progress events into a Result
+// in page order, whatever order the pages finished in. Any ProgressFunc set
+// on the engine still receives the events as they happen.
+func (s *Syncer) collect(run func() error) (*Result, error) {
+	var events []ProgressEvent
+
+	previous := s.engine.progress
+	s.engine.progress = func(event ProgressEvent) {
+		events = append(events, event)
+		if previous != nil {
+			previous(event)
+		}
+	}
+	defer func() { s.engine.progress = previous }()
+
+	err := run()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Index < events[j].Index
+	})
+	result := &Result{Pages: make([]PageResult, 0, len(events))}
+	for _, event := range events {
+		result.Pages = append(result.Pages, PageResult{
+			PageID: event.PageID,
+			Title:  event.Title,
+			Err:    event.Err,
+		})
+	}
+	return result, err
+}
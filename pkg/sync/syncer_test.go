@@ -0,0 +1,294 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	gosync "sync"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeNotionServer serves a single page with one paragraph and one block
+// whose children can't be fetched, which makes the client emit a warning
+func newFakeNotionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pages/page-id":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "page-id",
+				"properties": map[string]interface{}{
+					"title": map[string]interface{}{
+						"type":  "title",
+						"title": []interface{}{map[string]interface{}{"plain_text": "Embedded"}},
+					},
+				},
+			})
+		case "/blocks/page-id/children":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"results": []interface{}{
+					map[string]interface{}{
+						"id":   "paragraph-id",
+						"type": "paragraph",
+						"paragraph": map[string]interface{}{
+							"rich_text": []interface{}{map[string]interface{}{"type": "text", "plain_text": "Hello from Notion"}},
+						},
+					},
+					map[string]interface{}{
+						"id":           "toggle-id",
+						"type":         "toggle",
+						"has_children": true,
+						"toggle":       map[string]interface{}{"rich_text": []interface{}{}},
+					},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("unavailable"))
+		}
+	}))
+}
+
+// captureStdout returns everything written to os.Stdout while fn runs
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+
+	require.NoError(t, w.Close())
+	return <-done
+}
+
+func newSyncerTestConfig(t *testing.T) *config.Config {
+	cfg := &config.Config{}
+	cfg.Notion.Token = "test-token"
+	cfg.Notion.ParentPageID = "page-id"
+	cfg.Directories.MarkdownRoot = t.TempDir()
+	cfg.Sync.ConflictResolution = "notion_wins"
+	return cfg
+}
+
+func TestSyncer_NoStdoutOutput(t *testing.T) {
+	server := newFakeNotionServer(t)
+	defer server.Close()
+
+	cfg := newSyncerTestConfig(t)
+	filePath := filepath.Join(cfg.Directories.MarkdownRoot, "embedded.md")
+
+	var result *Result
+	var err error
+	output := captureStdout(t, func() {
+		var syncer *Syncer
+		syncer, err = NewSyncer(cfg, WithClientOptions(notion.WithBaseURL(server.URL)))
+		require.NoError(t, err)
+		result, err = syncer.PullFile(context.Background(), "page-id", filePath)
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, output)
+	assert.Equal(t, 1, result.Succeeded())
+	assert.Equal(t, 0, result.Failed())
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Hello from Notion")
+}
+
+func TestSyncer_WithLogger(t *testing.T) {
+	server := newFakeNotionServer(t)
+	defer server.Close()
+
+	cfg := newSyncerTestConfig(t)
+	var buf bytes.Buffer
+	syncer, err := NewSyncer(cfg, WithClientOptions(notion.WithBaseURL(server.URL)), WithLogger(util.NewLogger(util.INFO, &buf)))
+	require.NoError(t, err)
+
+	filePath := filepath.Join(cfg.Directories.MarkdownRoot, "embedded.md")
+	_, err = syncer.PullFile(context.Background(), "page-id", filePath)
+	require.NoError(t, err)
+
+	// Status output and client warnings go to the logger instead of stdout
+	assert.Contains(t, buf.String(), "Page title: Embedded")
+	assert.Contains(t, buf.String(), "Warning: failed to get child blocks for toggle-id")
+}
+
+func TestSyncer_Push(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	mockNotion := &mockNotionClient{}
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion))
+	require.NoError(t, err)
+
+	good := filepath.Join(cfg.Directories.MarkdownRoot, "good.md")
+	bad := filepath.Join(cfg.Directories.MarkdownRoot, "bad.md")
+	require.NoError(t, os.WriteFile(good, []byte("---\nnotion_id: good-id\n---\n# Good\n"), 0644))
+	require.NoError(t, os.WriteFile(bad, []byte("---\nnotion_id: bad-id\n---\n# Bad\n"), 0644))
+
+	mockNotion.updatePageFunc = func(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+		if pageID == "bad-id" {
+			return assert.AnError
+		}
+		return nil
+	}
+
+	result, err := syncer.Push(context.Background())
+	require.NoError(t, err)
+
+	// A failing file is recorded without stopping the others
+	require.Len(t, result.Pages, 2)
+	assert.Equal(t, 1, result.Succeeded())
+	assert.Equal(t, 1, result.Failed())
+	for _, page := range result.Pages {
+		if page.FilePath == bad {
+			assert.Error(t, page.Err)
+		} else {
+			assert.NoError(t, page.Err)
+		}
+	}
+}
+
+// newReversedPullClient serves a root page with the given children, each of
+// which has its blocks fetched only once wait returns for the page after it,
+// so the pages finish in reverse order
+func newReversedPullClient(children []string, wait func(nextID string)) *mockNotionClient {
+	ids := append([]string{"root-id"}, children...)
+	mockNotion := &mockNotionClient{}
+	mockNotion.getPageFunc = func(ctx context.Context, pageID string) (*notion.Page, error) {
+		return &notion.Page{ID: pageID, Properties: map[string]interface{}{
+			"title": map[string]interface{}{
+				"type":  "title",
+				"title": []interface{}{map[string]interface{}{"plain_text": "Page " + pageID}},
+			},
+		}}, nil
+	}
+	mockNotion.getAllDescendantPagesFunc = func(ctx context.Context, parentID string) ([]notion.Page, error) {
+		var pages []notion.Page
+		for _, id := range children {
+			page, _ := mockNotion.getPageFunc(ctx, id)
+			page.Parent = notion.Parent{Type: "page_id", PageID: parentID}
+			pages = append(pages, *page)
+		}
+		return pages, nil
+	}
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		for i, id := range ids[:len(ids)-1] {
+			if id == pageID {
+				wait(ids[i+1])
+			}
+		}
+		if pageID == "child-2" {
+			return nil, assert.AnError
+		}
+		return nil, nil
+	}
+	return mockNotion
+}
+
+func TestSyncer_PullPageResultsInPageOrder(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	ids := []string{"root-id", "child-1", "child-2", "child-3"}
+
+	// Each page waits until the next one has been reported
+	reported := make(map[string]chan struct{})
+	for _, id := range ids {
+		reported[id] = make(chan struct{})
+	}
+	mockNotion := newReversedPullClient(ids[1:], func(nextID string) {
+		<-reported[nextID]
+	})
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion))
+	require.NoError(t, err)
+
+	var completed []string
+	syncer.Engine().(ProgressReporter).SetProgressFunc(func(event ProgressEvent) {
+		completed = append(completed, event.PageID)
+		close(reported[event.PageID])
+	})
+
+	result, err := syncer.PullPage(context.Background(), "root-id")
+	require.Error(t, err)
+	assert.Equal(t, []string{"child-3", "child-2", "child-1", "root-id"}, completed)
+
+	// Results come back in page order, not completion order
+	require.Len(t, result.Pages, len(ids))
+	for i, page := range result.Pages {
+		assert.Equal(t, ids[i], page.PageID)
+		assert.Equal(t, "Page "+ids[i], page.Title)
+		assert.Equal(t, page.PageID == "child-2", page.Err != nil)
+	}
+	assert.Equal(t, 3, result.Succeeded())
+	assert.Equal(t, 1, result.Failed())
+}
+
+func TestSyncer_WithOrderedOutput(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	ids := []string{"root-id", "child-1", "child-2"}
+
+	// Each page waits until the next one has fetched its blocks
+	fetched := make(map[string]chan struct{})
+	once := make(map[string]*gosync.Once)
+	for _, id := range ids {
+		fetched[id] = make(chan struct{})
+		once[id] = &gosync.Once{}
+	}
+	mockNotion := newReversedPullClient(ids[1:], func(nextID string) {
+		<-fetched[nextID]
+	})
+	getPageBlocks := mockNotion.getPageBlocksFunc
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		defer once[pageID].Do(func() { close(fetched[pageID]) })
+		return getPageBlocks(ctx, pageID)
+	}
+
+	var buf bytes.Buffer
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithOrderedOutput(true), WithLogger(util.NewLogger(util.INFO, &buf)))
+	require.NoError(t, err)
+
+	// Status lines are printed when no ProgressFunc takes over
+	err = syncer.Engine().SyncPageSubtree(context.Background(), "root-id", "pull")
+	require.Error(t, err)
+
+	// Each page's lines are printed together, in page order
+	blocks := regexp.MustCompile(`(?m)^\[\d+/\d+\] `).Split(buf.String(), -1)[1:]
+	require.Len(t, blocks, len(ids))
+	for i, id := range ids {
+		assert.True(t, strings.HasPrefix(blocks[i], "Pulling page: Page "+id+"\n"), blocks[i])
+		assert.Contains(t, blocks[i], "  Notion ID: "+id+"\n")
+		assert.Contains(t, blocks[i], "  Page title: Page "+id+"\n")
+	}
+	assert.Contains(t, blocks[0], "✓ Successfully pulled Page root-id")
+	assert.NotContains(t, blocks[2], "✓ Successfully pulled")
+}
+
+func TestNewSyncer_RequiresConfig(t *testing.T) {
+	_, err := NewSyncer(nil)
+	assert.Error(t, err)
+}
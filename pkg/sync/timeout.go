@@ -0,0 +1,27 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// withOperationTimeout runs op with a context bounded by timeout, on top of
+// any deadline ctx already has, so an operation made of many requests fails
+// predictably instead of stalling the run. A zero timeout adds none. Running
+// out of time is reported against setting, the config key that sets timeout.
+func withOperationTimeout(ctx context.Context, timeout time.Duration, setting string, op func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return op(ctx)
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := op(opCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(opCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s (%s): %w", timeout, setting, err)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowRequests simulates an operation made of many requests, each quick on
+// its own, that together take far longer than the operation's deadline
+func slowRequests(ctx context.Context, requests int) error {
+	for i := 0; i < requests; i++ {
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func TestEngine_OperationTimeouts(t *testing.T) {
+	t.Run("page fetch", func(t *testing.T) {
+		e, mockNotion, _, _ := createTestEngine(t)
+		e.config.Timeouts.PageFetch = 50 * time.Millisecond
+
+		mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+			if err := slowRequests(ctx, 100); err != nil {
+				return nil, err
+			}
+			return []notion.Block{}, nil
+		}
+
+		start := time.Now()
+		err := e.SyncNotionToFile(context.Background(), "slow-page", filepath.Join(e.config.Directories.MarkdownRoot, "slow.md"))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "timeouts.page_fetch")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("page update", func(t *testing.T) {
+		e, mockNotion, _, _ := createTestEngine(t)
+		e.parser = markdown.NewParser()
+		e.converter = NewConverter()
+		e.config.Timeouts.PageUpdate = 50 * time.Millisecond
+
+		mockNotion.updatePageFunc = func(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+			return slowRequests(ctx, 100)
+		}
+
+		filePath := filepath.Join(e.config.Directories.MarkdownRoot, "slow.md")
+		require.NoError(t, os.WriteFile(filePath, []byte("---\nnotion_id: slow-page\n---\n\nBody\n"), 0644))
+
+		err := e.SyncFileToNotion(context.Background(), filePath)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "timeouts.page_update")
+	})
+
+	t.Run("operations within the deadline succeed", func(t *testing.T) {
+		e, mockNotion, _, _ := createTestEngine(t)
+		e.config.Timeouts.PageFetch = time.Second
+
+		mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+			if err := slowRequests(ctx, 3); err != nil {
+				return nil, err
+			}
+			return []notion.Block{}, nil
+		}
+
+		err := e.SyncNotionToFile(context.Background(), "page", filepath.Join(e.config.Directories.MarkdownRoot, "page.md"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("cancelling the run is not reported as an operation timeout", func(t *testing.T) {
+		e, mockNotion, _, _ := createTestEngine(t)
+		e.config.Timeouts.PageFetch = time.Minute
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+			cancel()
+			return nil, slowRequests(ctx, 100)
+		}
+
+		err := e.SyncNotionToFile(ctx, "page", filepath.Join(e.config.Directories.MarkdownRoot, "page.md"))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotContains(t, err.Error(), "timeouts.page_fetch")
+	})
+}
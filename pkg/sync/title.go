@@ -0,0 +1,45 @@
+package sync
+
+import (
+	"strings"
+)
+
+// stripTitleHeading drops the first block when it is a heading_1 whose text
+// is the page title, which Notion would otherwise show twice. It only applies
+// with sync.strip_title_heading.
+func (e *engine) stripTitleHeading(blocks []map[string]interface{}, title string) []map[string]interface{} {
+	if !e.config.Sync.StripTitleHeading || len(blocks) == 0 || blocks[0]["type"] != "heading_1" {
+		return blocks
+	}
+
+	heading, _ := blocks[0]["heading_1"].(map[string]interface{})
+	richText, _ := heading["rich_text"].([]map[string]interface{})
+	var text strings.Builder
+	for _, object := range richText {
+		if content, ok := object["text"].(map[string]interface{}); ok {
+			text.WriteString(content["content"].(string))
+		}
+	}
+
+	if strings.TrimSpace(text.String()) != strings.TrimSpace(title) {
+		return blocks
+	}
+	return blocks[1:]
+}
+
+// restoreTitleHeading puts back the heading stripTitleHeading left out on
+// push, unless the pulled content already starts with it, so files keep it
+func (e *engine) restoreTitleHeading(content, title string) string {
+	if !e.config.Sync.StripTitleHeading || title == "" {
+		return content
+	}
+
+	heading := "# " + title
+	if firstLine, _, _ := strings.Cut(content, "\n"); strings.TrimSpace(firstLine) == heading {
+		return content
+	}
+	if strings.TrimSpace(content) == "" {
+		return heading + "\n"
+	}
+	return heading + "\n\n" + content
+}
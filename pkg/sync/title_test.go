@@ -0,0 +1,87 @@
+package sync
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_StripTitleHeadingRoundTrip(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(map[bool]string{false: "disabled", true: "enabled"}[enabled], func(t *testing.T) {
+			e, mockNotion, _, _ := createTestEngine(t)
+			e.parser = markdown.NewParser()
+			e.converter = NewConverter()
+			e.config.Sync.StripTitleHeading = enabled
+
+			var pushed []map[string]interface{}
+			mockNotion.updatePageFunc = func(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+				pushed = blocks
+				return nil
+			}
+
+			filePath := filepath.Join(e.config.Directories.MarkdownRoot, "notes.md")
+			require.NoError(t, e.parser.CreateMarkdownWithFrontmatter(filePath,
+				map[string]interface{}{"title": "Release Notes"}, "# Release Notes\n\nBody text."))
+			require.NoError(t, e.SyncFileToNotion(context.Background(), filePath))
+
+			require.NotEmpty(t, pushed)
+			if enabled {
+				require.Len(t, pushed, 1)
+				assert.Equal(t, "paragraph", pushed[0]["type"])
+			} else {
+				assert.Equal(t, "heading_1", pushed[0]["type"])
+			}
+
+			// Pull the page back as Notion stores it
+			mockNotion.getPageFunc = func(ctx context.Context, pageID string) (*notion.Page, error) {
+				return &notion.Page{
+					ID: pageID,
+					Properties: map[string]interface{}{
+						"title": map[string]interface{}{
+							"title": []interface{}{map[string]interface{}{"plain_text": "Release Notes"}},
+						},
+					},
+				}, nil
+			}
+			mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+				blocks := []notion.Block{{
+					Type:      "paragraph",
+					Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: "Body text."}}},
+				}}
+				if !enabled {
+					blocks = append([]notion.Block{{
+						Type:     "heading_1",
+						Heading1: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: "Release Notes"}}},
+					}}, blocks...)
+				}
+				return blocks, nil
+			}
+			require.NoError(t, e.SyncNotionToFile(context.Background(), "new-page-id", filePath))
+
+			doc, err := e.parser.ParseFile(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, "# Release Notes\n\nBody text.", strings.TrimSpace(doc.Content))
+		})
+	}
+}
+
+func TestEngine_StripTitleHeading_OnlyMatchingTitle(t *testing.T) {
+	e, _, _, _ := createTestEngine(t)
+	e.config.Sync.StripTitleHeading = true
+
+	blocks := []map[string]interface{}{createHeadingBlock(1, "Introduction"), createParagraphBlock("Text")}
+	assert.Len(t, e.stripTitleHeading(blocks, "Release Notes"), 2, "a different heading is content")
+
+	blocks = []map[string]interface{}{createHeadingBlock(2, "Release Notes")}
+	assert.Len(t, e.stripTitleHeading(blocks, "Release Notes"), 1, "only heading_1 repeats the title")
+
+	assert.Equal(t, "# Release Notes\n\nText", e.restoreTitleHeading("# Release Notes\n\nText", "Release Notes"),
+		"a heading that is already there isn't added twice")
+}
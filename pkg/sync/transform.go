@@ -0,0 +1,33 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transform rewrites a file's markdown body on its way between the file and
+// Notion, e.g. to strip internal-only sections or add a banner. It sees the
+// body without frontmatter. An error aborts the sync of that file.
+type Transform interface {
+	Transform(ctx context.Context, filePath, content string) (string, error)
+}
+
+// TransformFunc adapts an ordinary function to the Transform interface
+type TransformFunc func(ctx context.Context, filePath, content string) (string, error)
+
+// Transform calls f
+func (f TransformFunc) Transform(ctx context.Context, filePath, content string) (string, error) {
+	return f(ctx, filePath, content)
+}
+
+// applyTransforms runs content through transforms in order
+func applyTransforms(ctx context.Context, transforms []Transform, filePath, content string) (string, error) {
+	for i, transform := range transforms {
+		transformed, err := transform.Transform(ctx, filePath, content)
+		if err != nil {
+			return "", fmt.Errorf("transform %d of %d failed for %s: %w", i+1, len(transforms), filePath, err)
+		}
+		content = transformed
+	}
+	return content, nil
+}
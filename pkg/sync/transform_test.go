@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var upperCase = TransformFunc(func(ctx context.Context, filePath, content string) (string, error) {
+	return strings.ToUpper(content), nil
+})
+
+func TestSyncer_PrePushTransforms(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	mockNotion := &mockNotionClient{}
+
+	var pushed []map[string]interface{}
+	mockNotion.updatePageFunc = func(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+		pushed = blocks
+		return nil
+	}
+
+	var seen []string
+	banner := TransformFunc(func(ctx context.Context, filePath, content string) (string, error) {
+		seen = append(seen, content)
+		return "Internal draft\n\n" + content, nil
+	})
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithPrePushTransforms(upperCase, banner))
+	require.NoError(t, err)
+
+	filePath := filepath.Join(cfg.Directories.MarkdownRoot, "page.md")
+	require.NoError(t, os.WriteFile(filePath, []byte("---\nnotion_id: page-id\n---\nHello world\n"), 0644))
+
+	_, err = syncer.PushFile(context.Background(), filePath)
+	require.NoError(t, err)
+
+	// Transforms run in order, on the body without frontmatter, before
+	// conversion
+	assert.Equal(t, []string{"HELLO WORLD\n"}, seen)
+	require.Len(t, pushed, 2)
+	assert.Equal(t, "Internal draft", richTextContent(pushed[0]["paragraph"].(map[string]interface{})["rich_text"]))
+	assert.Equal(t, "HELLO WORLD", richTextContent(pushed[1]["paragraph"].(map[string]interface{})["rich_text"]))
+
+	// The file keeps its own content
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Hello world")
+	assert.NotContains(t, string(data), "HELLO WORLD")
+}
+
+func TestSyncer_PrePushTransformError(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	mockNotion := &mockNotionClient{}
+	mockNotion.updatePageFunc = func(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+		t.Fatal("nothing should be pushed when a transform fails")
+		return nil
+	}
+
+	lint := TransformFunc(func(ctx context.Context, filePath, content string) (string, error) {
+		return "", errors.New("TODO left in text")
+	})
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithPrePushTransforms(lint))
+	require.NoError(t, err)
+
+	filePath := filepath.Join(cfg.Directories.MarkdownRoot, "page.md")
+	require.NoError(t, os.WriteFile(filePath, []byte("---\nnotion_id: page-id\n---\nTODO\n"), 0644))
+
+	_, err = syncer.PushFile(context.Background(), filePath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TODO left in text")
+}
+
+func TestSyncer_PostPullTransforms(t *testing.T) {
+	cfg := newSyncerTestConfig(t)
+	mockNotion := &mockNotionClient{}
+	mockNotion.getPageBlocksFunc = func(ctx context.Context, pageID string) ([]notion.Block, error) {
+		return []notion.Block{
+			{Type: "paragraph", Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{{Type: "text", PlainText: "From Notion"}}}},
+		}, nil
+	}
+
+	syncer, err := NewSyncer(cfg, WithNotionClient(mockNotion), WithPostPullTransforms(upperCase))
+	require.NoError(t, err)
+
+	filePath := filepath.Join(cfg.Directories.MarkdownRoot, "page.md")
+	_, err = syncer.PullFile(context.Background(), "page-id", filePath)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "FROM NOTION")
+}
@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// typographyReplacer swaps the punctuation Notion substitutes as you type
+// for the plain characters it was typed as
+var typographyReplacer = strings.NewReplacer(
+	"\u2018", "'", // ‘
+	"\u2019", "'", // ’
+	"\u201A", "'", // ‚
+	"\u201B", "'", // ‛
+	"\u201C", `"`, // “
+	"\u201D", `"`, // ”
+	"\u201E", `"`, // „
+	"\u201F", `"`, // ‟
+	"\u2013", "-", // en dash
+	"\u2014", "--", // em dash
+	"\u2026", "...", // ellipsis
+	"\u00A0", " ", // non-breaking space
+	"\u202F", " ", // narrow non-breaking space
+)
+
+// normalizeTypography replaces smart quotes, dashes, ellipses and
+// non-breaking spaces with their ASCII equivalents. Code is left as written,
+// fenced and inline.
+func normalizeTypography(s string) string {
+	lines := strings.Split(s, "\n")
+	fence := "" // The marker of the fenced code block the line is in, if any
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if fence = codeFence(trimmed); fence != "" {
+			continue
+		}
+
+		var out strings.Builder
+		last := 0
+		for _, span := range codeSpans(line) {
+			out.WriteString(typographyReplacer.Replace(line[last:span[0]]))
+			out.WriteString(line[span[0]:span[1]])
+			last = span[1]
+		}
+		out.WriteString(typographyReplacer.Replace(line[last:]))
+		lines[i] = out.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeFence returns the run of backticks or tildes that opens a fenced code
+// block on line, or "" when line doesn't open one
+func codeFence(line string) string {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return ""
+	}
+	n := 0
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return line[:n]
+}
+
+// normalizePulled applies sync.normalize_typography to content pulled from
+// Notion, so typographic punctuation doesn't show up as a change
+func (e *engine) normalizePulled(content string) string {
+	if !e.config.Sync.NormalizeTypography {
+		return content
+	}
+	return normalizeTypography(content)
+}
+
+// splitLeadingEmoji splits text that starts with an emoji and a space into
+// the emoji and the rest. The emoji is a whole grapheme cluster, so flags,
+// skin tones and ZWJ sequences stay intact.
+func splitLeadingEmoji(text string) (emoji, rest string, ok bool) {
+	cluster, rest, _, _ := uniseg.FirstGraphemeClusterInString(text, -1)
+	if cluster == "" || !isEmoji(cluster) || !strings.HasPrefix(rest, " ") {
+		return "", text, false
+	}
+	return cluster, rest[1:], true
+}
+
+// isEmoji reports whether a grapheme cluster is an emoji rather than a
+// letter, digit or ordinary symbol such as ©
+func isEmoji(cluster string) bool {
+	for _, r := range cluster {
+		switch {
+		case r == 0xFE0F: // Emoji presentation selector, e.g. ⚠️
+			return true
+		case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, flags and the like
+			return true
+		case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+			return true
+		}
+	}
+	return false
+}
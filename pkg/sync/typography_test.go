@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"testing"
+)
+
+func TestNormalizeTypography(t *testing.T) {
+	in := "“It’s fine” — mostly… pages 1–3, done"
+	want := `"It's fine" -- mostly... pages 1-3, done`
+	if got := normalizeTypography(in); got != want {
+		t.Errorf("normalizeTypography() = %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeTypography_SkipsCode(t *testing.T) {
+	in := "“Quoted” and `“kept”`\n\n```go\ns := “kept” // — too\n```\n\n````\n```\n“kept”\n````\nAfter — code"
+	want := "\"Quoted\" and `“kept”`\n\n```go\ns := “kept” // — too\n```\n\n````\n```\n“kept”\n````\nAfter -- code"
+	if got := normalizeTypography(in); got != want {
+		t.Errorf("normalizeTypography() = %q, want %q", got, want)
+	}
+}
+
+func TestEngine_NormalizePulled(t *testing.T) {
+	e, _, _, _ := createTestEngine(t)
+	content := "‘quoted’"
+
+	if got := e.normalizePulled(content); got != content {
+		t.Errorf("content changed with normalization off: %q", got)
+	}
+
+	e.config.Sync.NormalizeTypography = true
+	if got := e.normalizePulled(content); got != "'quoted'" {
+		t.Errorf("normalizePulled() = %q, want %q", got, "'quoted'")
+	}
+}
+
+func TestCreateCalloutBlock_LeadingEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantIcon string
+		wantText string
+	}{
+		{"single code point", "💡 Tip", "💡", "Tip"},
+		{"variation selector", "⚠️ Careful", "⚠️", "Careful"},
+		{"flag", "🇯🇵 Tokyo office", "🇯🇵", "Tokyo office"},
+		{"skin tone", "👍🏽 Approved", "👍🏽", "Approved"},
+		{"zwj sequence", "👩‍💻 Owner", "👩‍💻", "Owner"},
+		{"no emoji", "Plain quote", "", "Plain quote"},
+		{"emoji without space", "🇯🇵Tokyo", "", "🇯🇵Tokyo"},
+		{"ordinary symbol", "© 2024 Example", "", "© 2024 Example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callout := createCalloutBlock(tt.text)["callout"].(map[string]interface{})
+
+			icon := ""
+			if i, ok := callout["icon"].(map[string]interface{}); ok {
+				icon = i["emoji"].(string)
+			}
+			if icon != tt.wantIcon {
+				t.Errorf("icon = %q, want %q", icon, tt.wantIcon)
+			}
+
+			text := callout["rich_text"].([]map[string]interface{})[0]["text"].(map[string]interface{})["content"]
+			if text != tt.wantText {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
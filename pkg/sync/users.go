@@ -0,0 +1,104 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+// resolveUserMentions rewrites the text of @-mentions of people to their
+// names, looked up in the workspace's user directory when the API left the
+// name out. Mentions that can't be resolved keep the text Notion sent.
+func (e *engine) resolveUserMentions(ctx context.Context, blocks []notion.Block) {
+	failed := make(map[string]bool)
+	forEachRichText(blocks, func(rt *notion.RichText) {
+		if rt.Type != "mention" || rt.Mention == nil || rt.Mention.Type != "user" || rt.Mention.User == nil {
+			return
+		}
+
+		user := rt.Mention.User
+		if user.Name == "" && !failed[user.ID] {
+			resolved, err := e.notion.GetUser(ctx, user.ID)
+			if err != nil {
+				failed[user.ID] = true
+				e.log().WithError(err, "Failed to look up mentioned user %s", user.ID)
+				return
+			}
+			user.Name = resolved.Name
+		}
+		if user.Name != "" {
+			rt.PlainText = "@" + user.Name
+		}
+	})
+}
+
+// forEachRichText calls fn for every rich text object in blocks and their
+// children
+func forEachRichText(blocks []notion.Block, fn func(rt *notion.RichText)) {
+	each := func(richTexts []notion.RichText) {
+		for i := range richTexts {
+			fn(&richTexts[i])
+		}
+	}
+
+	for i := range blocks {
+		block := &blocks[i]
+		for _, rtb := range []*notion.RichTextBlock{
+			block.Paragraph, block.Heading1, block.Heading2, block.Heading3,
+			block.BulletedListItem, block.NumberedListItem, block.Quote,
+		} {
+			if rtb != nil {
+				each(rtb.RichText)
+			}
+		}
+		if block.Callout != nil {
+			each(block.Callout.RichText)
+		}
+		if block.Toggle != nil {
+			each(block.Toggle.RichText)
+		}
+		if block.TableRow != nil {
+			for _, cell := range block.TableRow.Cells {
+				each(cell)
+			}
+		}
+		forEachRichText(block.Children, fn)
+	}
+}
+
+// resolvePeopleNames fills in the names of people in a row's people
+// properties, which the API returns as bare user IDs when the integration
+// can't read user information
+func resolvePeopleNames(ctx context.Context, client notion.Client, row *notion.DatabaseRow) error {
+	for name, prop := range row.Properties {
+		if prop.Type != "people" {
+			continue
+		}
+		for i := range prop.People {
+			if prop.People[i].Name != "" {
+				continue
+			}
+			user, err := client.GetUser(ctx, prop.People[i].ID)
+			if err != nil {
+				return fmt.Errorf("failed to resolve people in property %q for row %s: %w", name, row.ID, err)
+			}
+			prop.People[i].Name = user.Name
+		}
+	}
+	return nil
+}
+
+// peopleNames returns the names of people, falling back to the user ID for
+// anyone without one
+func peopleNames(people []notion.User) []string {
+	names := make([]string, 0, len(people))
+	for _, user := range people {
+		if user.Name != "" {
+			names = append(names, user.Name)
+		} else {
+			names = append(names, user.ID)
+		}
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_ResolveUserMentions(t *testing.T) {
+	e, mockNotion, _, _ := createTestEngine(t)
+	e.converter = NewConverter()
+	mockNotion.listUsersFunc = func(ctx context.Context) ([]notion.User, error) {
+		return []notion.User{{ID: "user-1", Object: "user", Type: "person", Name: "Ada Lovelace"}}, nil
+	}
+
+	blocks := []notion.Block{
+		{
+			Type: "paragraph",
+			Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{
+				{Type: "text", PlainText: "Owner: "},
+				{Type: "mention", PlainText: "@Anonymous", Mention: &notion.Mention{Type: "user", User: &notion.User{ID: "user-1", Object: "user"}}},
+				{Type: "text", PlainText: ", reviewer: "},
+				{Type: "mention", PlainText: "@Unknown", Mention: &notion.Mention{Type: "user", User: &notion.User{ID: "user-2", Object: "user"}}},
+			}},
+		},
+	}
+
+	e.resolveUserMentions(context.Background(), blocks)
+	markdown, err := e.converter.BlocksToMarkdown(blocks)
+	require.NoError(t, err)
+
+	// Unknown users keep the text Notion sent
+	assert.Equal(t, "Owner: @Ada Lovelace, reviewer: @Unknown", markdown)
+}
+
+func TestEngine_ResolveUserMentions_DirectoryUnavailable(t *testing.T) {
+	e, mockNotion, _, _ := createTestEngine(t)
+	e.converter = NewConverter()
+	mockNotion.listUsersFunc = func(ctx context.Context) ([]notion.User, error) {
+		return nil, errors.New("insufficient permissions")
+	}
+
+	blocks := []notion.Block{
+		{
+			Type: "paragraph",
+			Paragraph: &notion.RichTextBlock{RichText: []notion.RichText{
+				{Type: "text", PlainText: "Owner: "},
+				{Type: "mention", PlainText: "@Ada", Mention: &notion.Mention{Type: "user", User: &notion.User{ID: "user-1", Object: "user"}}},
+			}},
+		},
+	}
+
+	e.resolveUserMentions(context.Background(), blocks)
+	markdown, err := e.converter.BlocksToMarkdown(blocks)
+	require.NoError(t, err)
+	assert.Equal(t, "Owner: @Ada", markdown)
+}
+
+func TestDatabaseSync_PeoplePropertyUsesNames(t *testing.T) {
+	mockNotion := &mockNotionClient{
+		listUsersFunc: func(ctx context.Context) ([]notion.User, error) {
+			return []notion.User{{ID: "user-1", Name: "Ada Lovelace"}, {ID: "user-2", Name: "Grace Hopper"}}, nil
+		},
+	}
+	ds := NewDatabaseSync(mockNotion).(*databaseSync)
+
+	row := notion.DatabaseRow{
+		ID: "row-1",
+		Properties: map[string]notion.PropertyValue{
+			"Assignees": {Type: "people", People: []notion.User{{ID: "user-1"}, {ID: "user-2"}}},
+		},
+	}
+	require.NoError(t, resolvePeopleNames(context.Background(), mockNotion, &row))
+
+	assert.Equal(t, "Ada Lovelace, Grace Hopper", ds.propertyValueToString(row.Properties["Assignees"]))
+}
@@ -0,0 +1,215 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// UnsupportedFeature is a markdown construct the converter drops on push
+type UnsupportedFeature struct {
+	Kind string // Human readable name, e.g. "definition list"
+	Line int    // 1-based line within the markdown content
+}
+
+func (f UnsupportedFeature) String() string {
+	return fmt.Sprintf("line %d: %s", f.Line, f.Kind)
+}
+
+// rawHTMLFeature is the kind reported for HTML blocks
+const rawHTMLFeature = "raw HTML"
+
+// FindUnsupportedFeatures walks the markdown AST and reports constructs
+// that have no Notion equivalent. It parses with the footnote and
+// definition list extensions enabled so those constructs are recognised
+// rather than read as plain paragraphs. Footnotes are pushed as a
+// Footnotes section, so only what they contain is checked.
+func FindUnsupportedFeatures(content string) []UnsupportedFeature {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.Table, extension.Footnote, extension.DefinitionList),
+	)
+	source := []byte(content)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var features []UnsupportedFeature
+	report := func(kind string, n ast.Node) {
+		features = append(features, UnsupportedFeature{Kind: kind, Line: nodeLine(n, source)})
+	}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n.Kind() {
+		case east.KindDefinitionList:
+			report("definition list", n)
+			return ast.WalkSkipChildren, nil
+
+		case ast.KindHTMLBlock:
+			// Comments are hidden anyway, and carry code block captions
+			htmlBlock := n.(*ast.HTMLBlock)
+			if htmlBlock.HTMLBlockType != ast.HTMLBlockType2 && !isToggleHTML(htmlBlock, source) {
+				report(rawHTMLFeature, n)
+			}
+			return ast.WalkSkipChildren, nil
+
+		case ast.KindRawHTML:
+			report("inline HTML", n)
+
+		case ast.KindImage:
+			if isDataURI(string(n.(*ast.Image).Destination)) {
+				report("image embedded as a data URI", n)
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return features
+}
+
+// checkUnsupportedFeatures warns about markdown the converter would drop,
+// or refuses the push when strict markdown checking is enabled
+func (e *engine) checkUnsupportedFeatures(filePath, content string) error {
+	// Pushing would drop or mangle MDX, so refuse it whatever the settings
+	if mdx := findMDX(content); len(mdx) > 0 {
+		descriptions := make([]string, len(mdx))
+		for i, feature := range mdx {
+			descriptions[i] = feature.String()
+		}
+		return fmt.Errorf("%s was pulled as MDX, which can't be pushed (%s); pull it with the commonmark flavor to edit it", filePath, strings.Join(descriptions, ", "))
+	}
+
+	var features []UnsupportedFeature
+	for _, feature := range FindUnsupportedFeatures(content) {
+		// Kept as html code blocks instead
+		if e.config.Sync.PreserveHTML && feature.Kind == rawHTMLFeature {
+			continue
+		}
+		features = append(features, feature)
+	}
+	if len(features) == 0 {
+		return nil
+	}
+
+	if e.config.Sync.StrictMarkdown {
+		descriptions := make([]string, len(features))
+		for i, feature := range features {
+			descriptions[i] = feature.String()
+		}
+		return fmt.Errorf("markdown uses features Notion does not support: %s", strings.Join(descriptions, ", "))
+	}
+
+	for _, feature := range features {
+		e.log().Warning("%s:%d: %s is not supported by Notion and will be dropped", filePath, feature.Line, feature.Kind)
+	}
+	return nil
+}
+
+// mdxTags are the components the mdx flavor pulls callouts and toggles as
+var mdxTags = []string{"<Callout", "</Callout>", "<Details>", "</Details>"}
+
+// findMDX reports the components and comments the mdx flavor writes, which
+// the converter can't read back. Code blocks are left alone.
+func findMDX(content string) []UnsupportedFeature {
+	md := goldmark.New(goldmark.WithExtensions(extension.Table, extension.Footnote))
+	source := []byte(content)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var features []UnsupportedFeature
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() != ast.TypeBlock || n.Lines().Len() == 0 {
+			return ast.WalkContinue, nil
+		}
+
+		first := n.Lines().At(0)
+		line := strings.TrimSpace(string(first.Value(source)))
+		switch n.Kind() {
+		case ast.KindHTMLBlock:
+			for _, tag := range mdxTags {
+				if strings.HasPrefix(line, tag) {
+					features = append(features, UnsupportedFeature{Kind: "MDX component " + tag, Line: nodeLine(n, source)})
+					break
+				}
+			}
+		case ast.KindParagraph:
+			if strings.HasPrefix(line, "{/*") {
+				features = append(features, UnsupportedFeature{Kind: "MDX comment", Line: nodeLine(n, source)})
+			}
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return features
+}
+
+// isToggleHTML reports whether an HTML block is part of a details/summary
+// toggle, which the converter turns into a Notion toggle block
+func isToggleHTML(block *ast.HTMLBlock, source []byte) bool {
+	var html strings.Builder
+	for i := 0; i < block.Lines().Len(); i++ {
+		line := block.Lines().At(i)
+		html.Write(line.Value(source))
+	}
+
+	trimmed := strings.TrimSpace(html.String())
+	for _, tag := range []string{"<details", "</details", "<summary", "</summary"} {
+		if strings.HasPrefix(trimmed, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeLine returns the 1-based source line a node starts on, or 0 when
+// the node carries no position
+func nodeLine(n ast.Node, source []byte) int {
+	start := nodeStart(n)
+	if start < 0 {
+		return 0
+	}
+	return bytes.Count(source[:start], []byte("\n")) + 1
+}
+
+// nodeStart returns the source offset a node starts at, or -1
+func nodeStart(n ast.Node) int {
+	switch node := n.(type) {
+	case *ast.Text:
+		return node.Segment.Start
+	case *ast.RawHTML:
+		if node.Segments.Len() > 0 {
+			return node.Segments.At(0).Start
+		}
+	}
+
+	if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+		return n.Lines().At(0).Start
+	}
+
+	// Inline nodes without positions sit right after the text before them
+	if n.Type() == ast.TypeInline {
+		for prev := n.PreviousSibling(); prev != nil; prev = prev.PreviousSibling() {
+			if t, ok := prev.(*ast.Text); ok {
+				return t.Segment.Stop
+			}
+		}
+		if n.Parent() != nil {
+			return nodeStart(n.Parent())
+		}
+		return -1
+	}
+
+	// Container blocks such as definition lists start at their first child
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if start := nodeStart(c); start >= 0 {
+			return start
+		}
+	}
+	return -1
+}
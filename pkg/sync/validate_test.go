@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnsupportedFeatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []UnsupportedFeature
+	}{
+		{
+			name:    "definition list",
+			content: "Intro paragraph.\n\nTerm\n: Definition of the term\n",
+			want:    []UnsupportedFeature{{Kind: "definition list", Line: 3}},
+		},
+		{
+			name:    "raw HTML block",
+			content: "# Title\n\n<div class=\"note\">\nHello\n</div>\n",
+			want:    []UnsupportedFeature{{Kind: "raw HTML", Line: 3}},
+		},
+		{
+			name:    "inline HTML",
+			content: "Press <kbd>Ctrl</kbd> to continue.\n",
+			want: []UnsupportedFeature{
+				{Kind: "inline HTML", Line: 1},
+				{Kind: "inline HTML", Line: 1},
+			},
+		},
+		{
+			name:    "image embedded as a data URI",
+			content: "# Title\n\n![Logo](data:image/png;base64,iVBORw0KGgo=)\n\n![Remote](https://example.com/a.png)\n",
+			want:    []UnsupportedFeature{{Kind: "image embedded as a data URI", Line: 3}},
+		},
+		{
+			name:    "footnotes are supported",
+			content: "# Notes\n\nFirst line\nsecond line with a note[^1].\n\n[^1]: The footnote.\n",
+		},
+		{
+			name:    "details toggle is supported",
+			content: "<details>\n<summary>More</summary>\n\nHidden text\n\n</details>\n",
+		},
+		{
+			name:    "supported markdown",
+			content: "# Title\n\n- item\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n```go\nfmt.Println()\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FindUnsupportedFeatures(tt.content))
+		})
+	}
+}
+
+func TestEngine_SyncFileToNotion_UnsupportedMarkdown(t *testing.T) {
+	content := "# Glossary\n\nSee the note[^1].\n\nTerm\n: Definition\n\n[^1]: The note.\n"
+
+	setup := func(t *testing.T) (*engine, *mockNotionClient, string, *bytes.Buffer) {
+		e, mockNotion, _, mockConverter := createTestEngine(t)
+		e.parser = markdown.NewParser()
+		mockConverter.markdownToBlocksFunc = func(content string) ([]map[string]interface{}, error) {
+			return nil, nil
+		}
+
+		var output bytes.Buffer
+		e.logger = util.NewLogger(util.INFO, &output)
+
+		filePath := filepath.Join(e.config.Directories.MarkdownRoot, "glossary.md")
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+		return e, mockNotion, filePath, &output
+	}
+
+	t.Run("warns and pushes", func(t *testing.T) {
+		e, mockNotion, filePath, output := setup(t)
+		created := false
+		mockNotion.createPageFunc = func(ctx context.Context, parentID string, properties map[string]interface{}) (*notion.Page, error) {
+			created = true
+			return &notion.Page{ID: "new-page-id"}, nil
+		}
+
+		require.NoError(t, e.SyncFileToNotion(context.Background(), filePath))
+		assert.True(t, created)
+		assert.NotContains(t, output.String(), "footnote")
+		assert.Contains(t, output.String(), filePath+":5: definition list is not supported by Notion")
+	})
+
+	t.Run("strict fails before pushing", func(t *testing.T) {
+		e, mockNotion, filePath, _ := setup(t)
+		e.config.Sync.StrictMarkdown = true
+		mockNotion.createPageFunc = func(ctx context.Context, parentID string, properties map[string]interface{}) (*notion.Page, error) {
+			t.Fatal("page should not be created in strict mode")
+			return nil, nil
+		}
+
+		err := e.SyncFileToNotion(context.Background(), filePath)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "footnote")
+		assert.Contains(t, err.Error(), "line 5: definition list")
+	})
+}
+
+func TestEngine_SyncFileToNotion_MDX(t *testing.T) {
+	e, mockNotion, _, mockConverter := createTestEngine(t)
+	e.parser = markdown.NewParser()
+	mockConverter.markdownToBlocksFunc = func(content string) ([]map[string]interface{}, error) {
+		return nil, nil
+	}
+	created := false
+	mockNotion.createPageFunc = func(ctx context.Context, parentID string, properties map[string]interface{}) (*notion.Page, error) {
+		created = true
+		return &notion.Page{ID: "new-page-id"}, nil
+	}
+
+	write := func(name, content string) string {
+		filePath := filepath.Join(e.config.Directories.MarkdownRoot, name)
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+		return filePath
+	}
+
+	pulled := "# Keys\n\n<Callout type=\"warning\">\n\nRotate keys\n\n</Callout>\n\n" +
+		"<Details>\n<summary>More</summary>\n\n</Details>\n\n{/* notion:table_of_contents */}\n"
+	err := e.SyncFileToNotion(context.Background(), write("keys.md", pulled))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be pushed")
+	assert.Contains(t, err.Error(), "line 3: MDX component <Callout")
+	assert.Contains(t, err.Error(), "line 9: MDX component <Details>")
+	assert.Contains(t, err.Error(), "line 14: MDX comment")
+	assert.False(t, created)
+
+	// The same text in a code block is just code
+	documented := "# Components\n\n```mdx\n<Callout type=\"info\">\n\n{/* note */}\n\n</Callout>\n```\n"
+	require.NoError(t, e.SyncFileToNotion(context.Background(), write("components.md", documented)))
+	assert.True(t, created)
+}
@@ -0,0 +1,141 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+)
+
+// LinkStatus is what became of the page a file's notion_id points at
+type LinkStatus string
+
+const (
+	LinkOK          LinkStatus = "ok"
+	LinkMissing     LinkStatus = "missing"      // Deleted, or no longer shared with the integration
+	LinkArchived    LinkStatus = "archived"     // Archived or in the trash
+	LinkOutsideTree LinkStatus = "outside tree" // Exists, but not under the parent page
+	LinkUnchecked   LinkStatus = "unchecked"    // The check itself failed; see Err
+)
+
+// LinkCheck is the verification result for one file
+type LinkCheck struct {
+	FilePath string
+	NotionID string
+	Status   LinkStatus
+	Err      error // Set when Status is LinkUnchecked
+}
+
+// Broken reports whether the file's notion_id needs attention
+func (c LinkCheck) Broken() bool {
+	return c.Status != LinkOK
+}
+
+// VerifyLinks checks that the notion_id of every markdown file under the
+// markdown root still points at a live page under the parent page. It only
+// reads from Notion. Results are sorted by file path.
+func (s *Syncer) VerifyLinks(ctx context.Context) ([]LinkCheck, error) {
+	return s.engine.verifyLinks(ctx)
+}
+
+func (e *engine) verifyLinks(ctx context.Context) ([]LinkCheck, error) {
+	var checks []LinkCheck
+	err := filepath.Walk(e.config.Directories.MarkdownRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return e.skipExcludedDir(path)
+		}
+		if !strings.HasSuffix(path, ".md") || !e.shouldSyncFile(path) {
+			return nil
+		}
+
+		doc, err := e.parser.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to extract frontmatter from %s: %w", path, err)
+		}
+		if frontmatter.NotionID != "" {
+			checks = append(checks, LinkCheck{FilePath: path, NotionID: frontmatter.NotionID})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk markdown root: %w", err)
+	}
+	if len(checks) == 0 {
+		return checks, nil
+	}
+
+	// One walk of the tree tells which pages are still under the parent
+	parentID := e.config.Notion.ParentPageID
+	descendants, err := e.notion.GetAllDescendantPages(ctx, parentID, notion.UnlimitedDepth)
+	var restricted *notion.RestrictedPagesError
+	if err != nil && !errors.As(err, &restricted) {
+		return nil, fmt.Errorf("failed to list pages under %s: %w", parentID, err)
+	}
+	inTree := map[string]bool{comparableID(parentID): true}
+	if restricted != nil {
+		// Pages the integration can't read are still under the parent
+		for _, pageID := range restricted.PageIDs {
+			inTree[comparableID(pageID)] = true
+		}
+	}
+	for _, page := range descendants {
+		inTree[comparableID(page.ID)] = true
+	}
+
+	// Fetch the pages in as few requests as the API allows. Pages the batch
+	// leaves out no longer exist, or aren't shared with the integration
+	ids := make([]string, len(checks))
+	for i, check := range checks {
+		ids[i] = check.NotionID
+	}
+	pages, err := e.notion.GetPagesMetadata(ctx, ids)
+	var failed *notion.PageMetadataError
+	if err != nil && !errors.As(err, &failed) {
+		return nil, fmt.Errorf("failed to look up pages: %w", err)
+	}
+
+	for i := range checks {
+		check := &checks[i]
+		if page, ok := pages[check.NotionID]; ok {
+			check.Status = classifyPage(page, check.NotionID, inTree)
+		} else if failed != nil && failed.Errs[check.NotionID] != nil {
+			check.Status, check.Err = LinkUnchecked, failed.Errs[check.NotionID]
+		} else {
+			check.Status = LinkMissing
+		}
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].FilePath < checks[j].FilePath
+	})
+	return checks, nil
+}
+
+// classifyPage says what became of a page that still exists
+func classifyPage(page *notion.Page, pageID string, inTree map[string]bool) LinkStatus {
+	switch {
+	case page.Archived || page.InTrash:
+		return LinkArchived
+	case !inTree[comparableID(pageID)]:
+		return LinkOutsideTree
+	}
+	return LinkOK
+}
+
+// comparableID normalizes a page ID with or without dashes for comparison
+func comparableID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
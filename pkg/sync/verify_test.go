@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_VerifyLinks(t *testing.T) {
+	e, mockNotion, _, _ := createTestEngine(t)
+	e.parser = markdown.NewParser()
+
+	files := map[string]string{
+		"ok.md":       "11111111-1111-1111-1111-111111111111",
+		"undashed.md": "22222222222222222222222222222222",
+		"deleted.md":  "33333333-3333-3333-3333-333333333333",
+		"archived.md": "44444444-4444-4444-4444-444444444444",
+		"moved.md":    "55555555-5555-5555-5555-555555555555",
+		"flaky.md":    "66666666-6666-6666-6666-666666666666",
+	}
+	root := e.config.Directories.MarkdownRoot
+	for name, id := range files {
+		require.NoError(t, e.parser.CreateMarkdownWithFrontmatter(filepath.Join(root, name),
+			map[string]interface{}{"title": name, "notion_id": id}, "Content"))
+	}
+	require.NoError(t, e.parser.CreateMarkdownWithFrontmatter(filepath.Join(root, "new.md"),
+		map[string]interface{}{"title": "Never pushed"}, "Content"))
+
+	mockNotion.getAllDescendantPagesFunc = func(ctx context.Context, parentID string) ([]notion.Page, error) {
+		assert.Equal(t, "parent-id", parentID)
+		return []notion.Page{
+			{ID: "11111111-1111-1111-1111-111111111111"},
+			{ID: "22222222-2222-2222-2222-222222222222"},
+			{ID: "44444444-4444-4444-4444-444444444444"},
+		}, nil
+	}
+	fetches := make(map[string]int)
+	mockNotion.getPageFunc = func(ctx context.Context, pageID string) (*notion.Page, error) {
+		fetches[pageID]++
+		switch pageID {
+		case files["deleted.md"]:
+			return nil, &notion.NotionAPIError{Code: 404, Message: "Could not find page"}
+		case files["archived.md"]:
+			return &notion.Page{ID: pageID, Archived: true}, nil
+		case files["flaky.md"]:
+			return nil, errors.New("connection reset")
+		}
+		return &notion.Page{ID: pageID}, nil
+	}
+
+	checks, err := e.verifyLinks(context.Background())
+	require.NoError(t, err)
+
+	// Pages the batch leaves out or fails on aren't fetched again
+	for name, id := range files {
+		assert.Equal(t, 1, fetches[id], name)
+	}
+
+	got := make(map[string]LinkStatus)
+	for _, check := range checks {
+		got[filepath.Base(check.FilePath)] = check.Status
+	}
+	assert.Equal(t, map[string]LinkStatus{
+		"archived.md": LinkArchived,
+		"deleted.md":  LinkMissing,
+		"flaky.md":    LinkUnchecked,
+		"moved.md":    LinkOutsideTree,
+		"ok.md":       LinkOK,
+		"undashed.md": LinkOK,
+	}, got)
+
+	for _, check := range checks {
+		assert.Equal(t, check.Status != LinkOK, check.Broken(), check.FilePath)
+		if check.Status == LinkUnchecked {
+			assert.ErrorContains(t, check.Err, "connection reset")
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	gosync "sync"
 	"time"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
@@ -30,11 +31,75 @@ type CommandExecutor struct {
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 
-	// Progress tracking
+	// Progress tracking, written by the sync goroutine and read by the
+	// view; guarded by mu
+	mu                 gosync.Mutex
 	currentOperation   string
 	operationStartTime time.Time
 	lastProgressMsg    string
+	lastErr            error
 	isRunning          bool
+	pendingProgress    []CommandProgressMsg
+}
+
+// executorState is a snapshot of an executor's progress
+type executorState struct {
+	operation string
+	startTime time.Time
+	message   string
+	err       error
+	running   bool
+}
+
+// state returns a snapshot of the executor's progress
+func (ce *CommandExecutor) state() executorState {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	return executorState{
+		operation: ce.currentOperation,
+		startTime: ce.operationStartTime,
+		message:   ce.lastProgressMsg,
+		err:       ce.lastErr,
+		running:   ce.isRunning,
+	}
+}
+
+// drainProgress returns the progress messages queued since the last call
+func (ce *CommandExecutor) drainProgress() []CommandProgressMsg {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	msgs := ce.pendingProgress
+	ce.pendingProgress = nil
+	return msgs
+}
+
+// handleProgressEvent queues an engine progress event for the view
+func (ce *CommandExecutor) handleProgressEvent(event sync.ProgressEvent) {
+	phase := PhaseCompleted
+	if event.Err != nil {
+		phase = PhaseFailed
+	}
+
+	progress := 0.0
+	if event.Total > 0 {
+		progress = float64(event.Completed) / float64(event.Total)
+	}
+
+	file := event.Title
+	if file == "" {
+		file = event.PageID
+	}
+
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	ce.lastProgressMsg = fmt.Sprintf("%s: %d pages processed", ce.currentOperation, event.Completed)
+	ce.pendingProgress = append(ce.pendingProgress, CommandProgressMsg{
+		Command:     ce.currentOperation,
+		CurrentFile: file,
+		Phase:       phase,
+		Err:         event.Err,
+		Progress:    progress,
+	})
 }
 
 // NewCommandExecutor creates a new command executor
@@ -42,12 +107,19 @@ func NewCommandExecutor(cfg *config.Config) (*CommandExecutor, error) {
 	engine := sync.NewEngine(cfg)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	return &CommandExecutor{
+	ce := &CommandExecutor{
 		config:     cfg,
 		syncEngine: engine,
 		ctx:        ctx,
 		cancelFunc: cancel,
-	}, nil
+	}
+
+	// Follow per-page progress through typed events rather than output
+	if reporter, ok := engine.(sync.ProgressReporter); ok {
+		reporter.SetProgressFunc(ce.handleProgressEvent)
+	}
+
+	return ce, nil
 }
 
 // Close cleans up the executor resources
@@ -95,14 +167,24 @@ func (ce *CommandExecutor) executePull(_ []string) tea.Cmd {
 	return tea.Batch(
 		// Start the operation
 		func() tea.Msg {
+			startTime := time.Now()
+
+			ce.mu.Lock()
 			ce.currentOperation = string(CommandPull)
-			ce.operationStartTime = time.Now()
+			ce.operationStartTime = startTime
+			ce.lastProgressMsg = ""
+			ce.lastErr = nil
 			ce.isRunning = true
+			ce.mu.Unlock()
 
 			// Start the sync operation in background
 			go func() {
-				defer func() { ce.isRunning = false }()
 				err := ce.syncEngine.SyncAll(ce.ctx, "pull")
+
+				ce.mu.Lock()
+				defer ce.mu.Unlock()
+				ce.isRunning = false
+				ce.lastErr = err
 				if err != nil {
 					ce.lastProgressMsg = fmt.Sprintf("Error: %v", err)
 				} else {
@@ -112,7 +194,7 @@ func (ce *CommandExecutor) executePull(_ []string) tea.Cmd {
 
 			return CommandStartMsg{
 				Command:   string(CommandPull),
-				StartTime: ce.operationStartTime,
+				StartTime: startTime,
 			}
 		},
 		// Start progress polling
@@ -258,10 +340,38 @@ type CommandStartMsg struct {
 	StartTime time.Time
 }
 
+// SyncPhase is the stage a file has reached during a command
+type SyncPhase string
+
+const (
+	PhasePulling   SyncPhase = "pulling"
+	PhasePushing   SyncPhase = "pushing"
+	PhaseCompleted SyncPhase = "completed"
+	PhaseFailed    SyncPhase = "failed"
+)
+
+// Status returns the label shown for the phase in the sync status pane
+func (p SyncPhase) Status() string {
+	switch p {
+	case PhasePulling:
+		return "Pulling from Notion"
+	case PhasePushing:
+		return "Pushing to Notion"
+	case PhaseCompleted:
+		return "Completed"
+	case PhaseFailed:
+		return "Failed"
+	default:
+		return "Processing"
+	}
+}
+
 // CommandProgressMsg provides progress updates
 type CommandProgressMsg struct {
 	Command     string
 	CurrentFile string
+	Phase       SyncPhase // Stage CurrentFile has reached; empty when unknown
+	Err         error     // Set when Phase is PhaseFailed
 	Message     string
 	Progress    float64 // 0.0 to 1.0
 }
@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -273,6 +276,14 @@ func (m ConfigInputModel) saveConfig() tea.Cmd {
 			return ConfigErrorMsg{Error: fmt.Errorf("parent page ID cannot be empty")}
 		}
 
+		// Only write credentials Notion accepts
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		pageID, _, err := verifyCredentials(ctx, token, pageID)
+		if err != nil {
+			return ConfigErrorMsg{Error: err}
+		}
+
 		// Create .env content
 		envContent := fmt.Sprintf(`# notion-md-sync environment variables
 # Generated by TUI configuration
@@ -289,6 +300,40 @@ NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID=%s
 	}
 }
 
+// newNotionClient creates the client used to verify credentials; tests replace it
+var newNotionClient = func(token string) notion.Client {
+	return notion.NewClient(token)
+}
+
+// verifyCredentials checks the token and, when one is given, the parent page
+// URL or ID against the Notion API, as the CLI's init does. It returns the
+// page ID in the form the API uses and the integration's bot user.
+func verifyCredentials(ctx context.Context, token, parentPage string) (string, *notion.User, error) {
+	if err := util.ValidateNotionToken(token); err != nil {
+		return "", nil, fmt.Errorf("invalid token: %w", err)
+	}
+	pageID := ""
+	if parentPage != "" {
+		id, err := util.ParseNotionID(parentPage)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid parent page: %w", err)
+		}
+		pageID = id
+	}
+
+	client := newNotionClient(token)
+	user, err := notion.VerifyToken(ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	if pageID != "" {
+		if _, err := notion.VerifyParentPage(ctx, client, pageID); err != nil {
+			return "", nil, err
+		}
+	}
+	return pageID, user, nil
+}
+
 // SetSize sets the model dimensions
 func (m *ConfigInputModel) SetSize(width, height int) {
 	m.width = width
@@ -46,6 +46,14 @@ type Model struct {
 
 // NewModel creates a new TUI model
 func NewModel(configPath string) Model {
+	return NewModelWithLoader(configPath, func() (*config.Config, error) {
+		return config.Load(configPath)
+	})
+}
+
+// NewModelWithLoader creates a new TUI model with the configuration load
+// returns, so the caller can apply its workspace and flag overrides
+func NewModelWithLoader(configPath string, load func() (*config.Config, error)) Model {
 	m := Model{
 		currentView: UnifiedViewType,
 		config:      NewConfigModel(),
@@ -55,7 +63,7 @@ func NewModel(configPath string) Model {
 	}
 
 	// Load configuration
-	appConfig, err := config.Load(configPath)
+	appConfig, err := load()
 	if err != nil {
 		m.initError = fmt.Errorf("failed to load config: %w", err)
 		m.unified = NewUnifiedView()
@@ -3,6 +3,7 @@ package tui
 import (
 	"testing"
 
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -19,6 +20,21 @@ func TestNewModel(t *testing.T) {
 	}
 }
 
+func TestNewModelWithLoader(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Notion.Token = "test_token"
+	cfg.Notion.ParentPageID = "workspace-page"
+	cfg.Directories.MarkdownRoot = t.TempDir()
+
+	model := NewModelWithLoader("/test/config.yaml", func() (*config.Config, error) {
+		return cfg, nil
+	})
+
+	if model.appConfig != cfg {
+		t.Errorf("Expected the loaded config to be used, got %+v", model.appConfig)
+	}
+}
+
 func TestModelViewSwitching(t *testing.T) {
 	model := NewModel("/test/config.yaml")
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/charmbracelet/bubbles/list"
 )
 
@@ -38,6 +39,11 @@ func (fs *FileScanner) ScanFiles() ([]list.Item, error) {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
+	ignore, err := util.LoadIgnoreMatcher(absRoot, fs.config.Directories.ExcludedPatterns)
+	if err != nil {
+		return nil, err
+	}
+
 	// Walk the directory tree
 	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -52,23 +58,20 @@ func (fs *FileScanner) ScanFiles() ([]list.Item, error) {
 			return nil
 		}
 
-		// Check excluded patterns
-		for _, pattern := range fs.config.Directories.ExcludedPatterns {
-			matched, err := filepath.Match(pattern, info.Name())
-			if err == nil && matched {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-		}
-
 		// Get relative path for display
 		relPath, err := filepath.Rel(absRoot, path)
 		if err != nil {
 			relPath = path
 		}
 
+		// Leave out what the sync excludes
+		if ignore.Ignored(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// For directories, only include if they contain markdown files
 		if info.IsDir() {
 			// Skip root directory itself
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -252,21 +253,7 @@ func (v UnifiedView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case CommandProgressMsg:
-		v.syncProgress = msg.Message
-		if msg.CurrentFile != "" {
-			v.updateFileStatus(msg.CurrentFile, StatusPending)
-			// For messages with CurrentFile set, extract the actual status from the message
-			status := "Processing"
-			if strings.Contains(msg.Message, "✓ Completed:") {
-				status = "Completed"
-			} else if strings.Contains(msg.Message, "Pulling:") {
-				status = "Pulling from Notion"
-			} else if strings.Contains(msg.Message, "Pushing:") {
-				status = "Pushing to Notion"
-			}
-			// Add or update sync operation for the current file
-			v.addOrUpdateSyncOperation(msg.CurrentFile, status)
-		}
+		v.applyProgress(msg)
 		return v, nil
 
 	case CommandCompleteMsg:
@@ -315,12 +302,22 @@ func (v UnifiedView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return v, nil
 
 	case ProgressTickMsg:
-		if v.executor != nil && v.executor.isRunning {
+		if v.executor == nil {
+			return v, nil
+		}
+
+		// Apply the engine's progress events queued since the last tick
+		for _, progress := range v.executor.drainProgress() {
+			v.applyProgress(progress)
+		}
+
+		state := v.executor.state()
+		if state.running {
 			// Create progress message from executor state
-			elapsed := time.Since(v.executor.operationStartTime)
-			progressMsg := fmt.Sprintf("%s (%.1fs elapsed)", v.executor.currentOperation, elapsed.Seconds())
-			if v.executor.lastProgressMsg != "" {
-				progressMsg = v.executor.lastProgressMsg
+			elapsed := time.Since(state.startTime)
+			progressMsg := fmt.Sprintf("%s (%.1fs elapsed)", state.operation, elapsed.Seconds())
+			if state.message != "" {
+				progressMsg = state.message
 			}
 
 			v.syncProgress = progressMsg
@@ -329,14 +326,14 @@ func (v UnifiedView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return v, tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
 				return ProgressTickMsg{}
 			})
-		} else if v.executor != nil && !v.executor.isRunning && v.syncing {
+		} else if v.syncing {
 			// Operation completed
 			v.syncing = false
-			if strings.Contains(v.executor.lastProgressMsg, "Error:") {
-				v.errorMessage = v.executor.lastProgressMsg
+			if state.err != nil {
+				v.errorMessage = state.message
 				return v, v.clearErrorAfterDelay()
 			} else {
-				v.syncProgress = v.executor.lastProgressMsg
+				v.syncProgress = state.message
 				// Update today's sync count and refresh file list
 				v.stats.today++
 				return v, v.refreshFileList()
@@ -691,26 +688,55 @@ func (v *UnifiedView) updateSyncOperation(command, status string, duration time.
 	}
 }
 
+// applyProgress updates the file list and sync status pane from a typed
+// progress message
+func (v *UnifiedView) applyProgress(msg CommandProgressMsg) {
+	if msg.Message != "" {
+		v.syncProgress = msg.Message
+	}
+	if msg.CurrentFile == "" {
+		return
+	}
+
+	fileStatus := StatusPending
+	switch msg.Phase {
+	case PhaseCompleted:
+		fileStatus = StatusSynced
+	case PhaseFailed:
+		fileStatus = StatusError
+	}
+	v.updateFileStatus(msg.CurrentFile, fileStatus)
+
+	// Add or update sync operation for the current file
+	v.addOrUpdateSyncOperation(msg.CurrentFile, msg.Phase, msg.Err)
+}
+
+// phaseProgress returns how far along the progress bar a phase is
+func phaseProgress(phase SyncPhase) float64 {
+	switch phase {
+	case PhaseCompleted, PhaseFailed:
+		return 1.0
+	case PhasePulling:
+		return 0.3
+	default:
+		return 0.6
+	}
+}
+
 // addOrUpdateSyncOperation adds a new operation or updates existing one
-func (v *UnifiedView) addOrUpdateSyncOperation(fileName, status string) {
+func (v *UnifiedView) addOrUpdateSyncOperation(fileName string, phase SyncPhase, err error) {
 	// Check if operation already exists
 	for i := range v.syncOps {
 		if v.syncOps[i].FileName == fileName {
 			// Update status
-			v.syncOps[i].Status = status
+			v.syncOps[i].Status = phase.Status()
+			v.syncOps[i].Error = err
 			// Calculate elapsed time properly
 			if v.syncOps[i].StartTime.IsZero() {
 				v.syncOps[i].StartTime = time.Now()
 			}
 			v.syncOps[i].ElapsedTime = time.Since(v.syncOps[i].StartTime)
-			// Update progress based on status
-			if status == "Completed" {
-				v.syncOps[i].Progress = 1.0
-			} else if strings.Contains(status, "Pulling") {
-				v.syncOps[i].Progress = 0.3
-			} else {
-				v.syncOps[i].Progress = 0.6
-			}
+			v.syncOps[i].Progress = phaseProgress(phase)
 			return
 		}
 	}
@@ -718,10 +744,11 @@ func (v *UnifiedView) addOrUpdateSyncOperation(fileName, status string) {
 	// Add new operation
 	op := SyncOperation{
 		FileName:    fileName,
-		Status:      status,
+		Status:      phase.Status(),
 		StartTime:   time.Now(),
 		ElapsedTime: 0,
-		Progress:    0.3,
+		Progress:    phaseProgress(phase),
+		Error:       err,
 	}
 	v.syncOps = append(v.syncOps, op)
 
@@ -809,6 +836,22 @@ func executeInitDirectly() tea.Msg {
 		}
 	}
 
+	// Credentials already in the environment are checked before anything is
+	// written, so a bad token shows up now rather than on the first sync
+	message := "Project initialized! Press 'c' to configure your Notion credentials"
+	if token := os.Getenv("NOTION_MD_SYNC_NOTION_TOKEN"); token != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, user, err := verifyCredentials(ctx, token, os.Getenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID"))
+		if err != nil {
+			return CommandErrorMsg{
+				Command: string(CommandInit),
+				Error:   err,
+			}
+		}
+		message = fmt.Sprintf("Project initialized! Connected to Notion as %s", user.DisplayName())
+	}
+
 	// Create config.yaml with defaults
 	configContent := `# notion-md-sync configuration
 notion:
@@ -894,6 +937,6 @@ Happy syncing! 🚀
 	return CommandCompleteMsg{
 		Command:  string(CommandInit),
 		Duration: time.Since(startTime),
-		Message:  "Project initialized! Press 'c' to configure your Notion credentials",
+		Message:  message,
 	}
 }
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+)
+
+func TestUnifiedViewProgressTransitions(t *testing.T) {
+	view := NewUnifiedView()
+
+	model, _ := view.Update(CommandProgressMsg{CurrentFile: "notes.md", Phase: PhasePulling})
+	view = model.(UnifiedView)
+
+	if len(view.syncOps) != 1 {
+		t.Fatalf("Expected 1 sync operation, got %d", len(view.syncOps))
+	}
+	if view.syncOps[0].Status != "Pulling from Notion" {
+		t.Errorf("Expected status 'Pulling from Notion', got %q", view.syncOps[0].Status)
+	}
+	if view.syncOps[0].Progress != 0.3 {
+		t.Errorf("Expected progress 0.3, got %v", view.syncOps[0].Progress)
+	}
+
+	model, _ = view.Update(CommandProgressMsg{CurrentFile: "notes.md", Phase: PhaseCompleted})
+	view = model.(UnifiedView)
+
+	if len(view.syncOps) != 1 {
+		t.Fatalf("Expected operation to be updated in place, got %d operations", len(view.syncOps))
+	}
+	if view.syncOps[0].Status != "Completed" {
+		t.Errorf("Expected status 'Completed', got %q", view.syncOps[0].Status)
+	}
+	if view.syncOps[0].Progress != 1.0 {
+		t.Errorf("Expected progress 1.0, got %v", view.syncOps[0].Progress)
+	}
+}
+
+func TestUnifiedViewProgressFailure(t *testing.T) {
+	view := NewUnifiedView()
+	syncErr := errors.New("rate limited")
+
+	// A message mentioning "Completed" must not override a failed phase
+	model, _ := view.Update(CommandProgressMsg{
+		CurrentFile: "notes.md",
+		Phase:       PhaseFailed,
+		Err:         syncErr,
+		Message:     "✓ Completed: notes.md",
+	})
+	view = model.(UnifiedView)
+
+	if len(view.syncOps) != 1 {
+		t.Fatalf("Expected 1 sync operation, got %d", len(view.syncOps))
+	}
+	if view.syncOps[0].Status != "Failed" {
+		t.Errorf("Expected status 'Failed', got %q", view.syncOps[0].Status)
+	}
+	if !errors.Is(view.syncOps[0].Error, syncErr) {
+		t.Errorf("Expected operation error %v, got %v", syncErr, view.syncOps[0].Error)
+	}
+}
+
+func TestCommandExecutorProgressEvents(t *testing.T) {
+	ce := &CommandExecutor{currentOperation: string(CommandPull)}
+
+	ce.handleProgressEvent(sync.ProgressEvent{PageID: "page-1", Title: "Notes", Completed: 1, Total: 2})
+	ce.handleProgressEvent(sync.ProgressEvent{PageID: "page-2", Completed: 2, Total: 2, Err: errors.New("boom")})
+
+	msgs := ce.drainProgress()
+	if len(msgs) != 2 {
+		t.Fatalf("Expected 2 progress messages, got %d", len(msgs))
+	}
+
+	if msgs[0].CurrentFile != "Notes" || msgs[0].Phase != PhaseCompleted || msgs[0].Progress != 0.5 {
+		t.Errorf("Unexpected first message: %+v", msgs[0])
+	}
+	// Pages without a title fall back to their ID
+	if msgs[1].CurrentFile != "page-2" || msgs[1].Phase != PhaseFailed || msgs[1].Err == nil {
+		t.Errorf("Unexpected second message: %+v", msgs[1])
+	}
+
+	if remaining := ce.drainProgress(); len(remaining) != 0 {
+		t.Errorf("Expected queue to be empty after draining, got %d messages", len(remaining))
+	}
+}
+
+// credentialClient accepts only "ntn_good_token" and the page sharedPageID
+type credentialClient struct {
+	notion.Client
+	token string
+}
+
+const sharedPageID = "abcdef12-3456-7890-abcd-ef1234567890"
+
+func (c *credentialClient) GetCurrentUser(ctx context.Context) (*notion.User, error) {
+	if c.token != "ntn_good_token" {
+		return nil, fmt.Errorf("failed to get current user: %w", &notion.NotionAPIError{Code: 401, Message: "API token is invalid."})
+	}
+	return &notion.User{ID: "bot-id", Name: "Docs Sync"}, nil
+}
+
+func (c *credentialClient) GetPage(ctx context.Context, pageID string) (*notion.Page, error) {
+	if pageID != sharedPageID {
+		return nil, fmt.Errorf("failed to get page %s: %w", pageID, &notion.NotionAPIError{Code: 404, Message: "Could not find page."})
+	}
+	return &notion.Page{ID: pageID}, nil
+}
+
+func TestExecuteInitDirectlyVerifiesCredentials(t *testing.T) {
+	original := newNotionClient
+	newNotionClient = func(token string) notion.Client {
+		return &credentialClient{token: token}
+	}
+	t.Cleanup(func() { newNotionClient = original })
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	tests := []struct {
+		name    string
+		token   string
+		page    string
+		wantErr bool
+	}{
+		{name: "rejected token", token: "ntn_revoked_token", wantErr: true},
+		{name: "page not shared", token: "ntn_good_token", page: "00000000000000000000000000000000", wantErr: true},
+		{name: "valid credentials", token: "ntn_good_token", page: "https://www.notion.so/Docs-abcdef1234567890abcdef1234567890"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chdir(t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("NOTION_MD_SYNC_NOTION_TOKEN", tt.token)
+			t.Setenv("NOTION_MD_SYNC_NOTION_PARENT_PAGE_ID", tt.page)
+
+			msg := executeInitDirectly()
+			_, statErr := os.Stat("config.yaml")
+			if tt.wantErr {
+				if _, ok := msg.(CommandErrorMsg); !ok {
+					t.Fatalf("Expected CommandErrorMsg, got %T", msg)
+				}
+				if statErr == nil {
+					t.Error("Expected no config.yaml to be written for bad credentials")
+				}
+				return
+			}
+
+			complete, ok := msg.(CommandCompleteMsg)
+			if !ok {
+				t.Fatalf("Expected CommandCompleteMsg, got %#v", msg)
+			}
+			if !strings.Contains(complete.Message, "Docs Sync") {
+				t.Errorf("Expected message to name the integration, got %q", complete.Message)
+			}
+			if statErr != nil {
+				t.Errorf("Expected config.yaml to be written: %v", statErr)
+			}
+		})
+	}
+}
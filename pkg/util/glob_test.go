@@ -0,0 +1,43 @@
+package util
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"docs/**", "docs/guide.md", true},
+		{"docs/**", "docs/api/client.md", true},
+		{"docs/**", "notes/guide.md", false},
+		{"docs/*.md", "docs/guide.md", true},
+		{"docs/*.md", "docs/api/client.md", false},
+		{"**/drafts/*.md", "drafts/idea.md", true},
+		{"**/drafts/*.md", "blog/2024/drafts/idea.md", true},
+		{"**/drafts/*.md", "blog/drafts/old/idea.md", false},
+		{"*.md", "docs/api/client.md", true},
+		{"README.md", "docs/README.md", true},
+		{"docs/[", "docs/guide.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{"docs/**", "guides/*.md"}
+
+	if !MatchAnyGlob(patterns, "guides/setup.md") {
+		t.Error("Expected guides/setup.md to match")
+	}
+	if MatchAnyGlob(patterns, "notes/todo.md") {
+		t.Error("Expected notes/todo.md not to match")
+	}
+	if MatchAnyGlob(nil, "docs/guide.md") {
+		t.Error("Expected no patterns to match nothing")
+	}
+}
@@ -0,0 +1,152 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the gitignore-style file at the markdown root listing
+// paths the sync leaves alone
+const IgnoreFileName = ".notionignore"
+
+// IgnoreMatcher matches paths against gitignore-style patterns:
+//   - a pattern without a slash matches a file or directory name at any depth
+//   - a pattern with a slash is relative to the root, and "**" matches any
+//     number of directories
+//   - a trailing slash matches directories only
+//   - a leading "!" re-includes paths an earlier pattern ignored
+//
+// The last matching pattern wins, and everything under an ignored directory
+// is ignored, so "archive/*" covers archive/2023/old.md too. As in git, a
+// file can't be re-included once its directory is ignored.
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	segments []string // Pattern split on "/"
+	negate   bool
+	dirOnly  bool
+	anchored bool // Matched against the whole relative path, not the name
+}
+
+// NewIgnoreMatcher parses patterns, one per line as in a .gitignore file.
+// Blank lines and lines starting with "#" are skipped.
+func NewIgnoreMatcher(patterns ...string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, line := range patterns {
+		if rule, ok := parseIgnoreRule(line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	return m
+}
+
+// LoadIgnoreMatcher returns a matcher for patterns followed by those in
+// root's .notionignore, which can therefore override them. A missing
+// .notionignore is not an error.
+func LoadIgnoreMatcher(root string, patterns []string) (*IgnoreMatcher, error) {
+	m := NewIgnoreMatcher(patterns...)
+
+	file, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("failed to open %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return m, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	m.rules = append(m.rules, NewIgnoreMatcher(lines...).rules...)
+	return m, nil
+}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		// Escaped so the name isn't read as a comment or negation
+		line = line[1:]
+	}
+
+	line = filepath.ToSlash(line)
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// Ignored reports whether name, a path relative to the root, is ignored.
+// isDir says whether name is a directory.
+func (m *IgnoreMatcher) Ignored(name string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+
+	name = path.Clean(filepath.ToSlash(name))
+	if name == "." || name == "/" {
+		return false
+	}
+	segments := strings.Split(strings.TrimPrefix(name, "/"), "/")
+
+	for i := 1; i < len(segments); i++ {
+		if m.match(segments[:i], true) {
+			return true
+		}
+	}
+	return m.match(segments, isDir)
+}
+
+// match applies the rules in order to a single path, ignoring its parents
+func (m *IgnoreMatcher) match(segments []string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) matches(segments []string) bool {
+	if r.anchored {
+		// A trailing "/**" matches what is inside a directory, not the
+		// directory itself, so its contents can still be re-included
+		if last := len(r.segments) - 1; r.segments[last] == "**" && matchSegments(r.segments[:last], segments) {
+			return false
+		}
+		return matchSegments(r.segments, segments)
+	}
+	matched, _ := path.Match(r.segments[0], segments[len(segments)-1])
+	return matched
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{"name at root", []string{"*.tmp"}, "notes.tmp", false, true},
+		{"name at any depth", []string{"*.tmp"}, "docs/api/notes.tmp", false, true},
+		{"unmatched name", []string{"*.tmp"}, "docs/notes.md", false, false},
+		{"anchored pattern", []string{"docs/*.md"}, "docs/guide.md", false, true},
+		{"anchored pattern elsewhere", []string{"docs/*.md"}, "blog/docs/guide.md", false, false},
+		{"leading slash anchors", []string{"/todo.md"}, "docs/todo.md", false, false},
+		{"single star covers nested files", []string{"archive/*"}, "archive/2023/old.md", false, true},
+		{"double star", []string{"drafts/**"}, "drafts/a/b/c.md", false, true},
+		{"double star prefix", []string{"**/private/*.md"}, "team/notes/private/salary.md", false, true},
+		{"double star prefix at root", []string{"**/private/*.md"}, "private/salary.md", false, true},
+		{"double star in the middle", []string{"docs/**/internal.md"}, "docs/a/b/internal.md", false, true},
+		{"directory pattern", []string{"build/"}, "build/out.md", false, true},
+		{"directory pattern skips files", []string{"build/"}, "build", false, false},
+		{"directory pattern matches directories", []string{"build/"}, "src/build", true, true},
+		{"negation", []string{"*.md", "!keep.md"}, "docs/keep.md", false, false},
+		{"negation keeps others ignored", []string{"*.md", "!keep.md"}, "docs/other.md", false, true},
+		{"later pattern wins", []string{"!keep.md", "*.md"}, "keep.md", false, true},
+		{"negation can't re-include under an ignored directory", []string{"drafts/", "!drafts/ready.md"}, "drafts/ready.md", false, true},
+		{"double star contents only", []string{"drafts/**"}, "drafts", true, false},
+		{"negation under double star", []string{"drafts/**", "!drafts/ready.md"}, "drafts/ready.md", false, false},
+		{"negating contents of a directory", []string{"drafts/*", "!drafts/ready.md"}, "drafts/ready.md", false, false},
+		{"comments and blank lines", []string{"# *.md", "", "  "}, "notes.md", false, false},
+		{"escaped hash", []string{`\#notes.md`}, "#notes.md", false, true},
+		{"os separators", []string{"docs/*.md"}, filepath.Join("docs", "guide.md"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewIgnoreMatcher(tt.patterns...)
+			if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Ignored(%q) with %q = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+
+	m, err := LoadIgnoreMatcher(root, []string{"*.tmp"})
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher() without a %s: %v", IgnoreFileName, err)
+	}
+	if !m.Ignored("a.tmp", false) || m.Ignored("drafts/a.md", false) {
+		t.Error("Expected only the config patterns to apply")
+	}
+
+	content := "# Work in progress\ndrafts/\n\n!important.tmp\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err = LoadIgnoreMatcher(root, []string{"*.tmp"})
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher() = %v", err)
+	}
+
+	tests := map[string]bool{
+		"drafts/a.md":   true,
+		"a.tmp":         true,
+		"important.tmp": false, // The ignore file overrides the config patterns
+		"notes.md":      false,
+	}
+	for path, want := range tests {
+		if got := m.Ignored(path, false); got != want {
+			t.Errorf("Ignored(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -74,6 +74,17 @@ func (l *Logger) GetLevel() LogLevel {
 	return l.level
 }
 
+// Writer returns the writer the logger outputs to
+func (l *Logger) Writer() io.Writer {
+	return l.output
+}
+
+// SetOutput changes the writer the logger outputs to
+func (l *Logger) SetOutput(output io.Writer) {
+	l.output = output
+	l.logger.SetOutput(output)
+}
+
 // shouldLog determines if a message should be logged based on level
 func (l *Logger) shouldLog(level LogLevel) bool {
 	return level >= l.level
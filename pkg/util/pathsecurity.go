@@ -1,9 +1,14 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"path/filepath"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ErrPathTraversal is returned when a path traversal attempt is detected
@@ -40,8 +45,38 @@ func SecureJoin(root string, elems ...string) (string, error) {
 	return fullPath, nil
 }
 
-// SanitizeFileName removes or replaces characters that could be problematic in file paths
+// MaxFileNameLength is the longest sanitized name in bytes, leaving room under
+// the common 255-byte filesystem limit for an extension and collision suffix
+const MaxFileNameLength = 200
+
+// windowsReservedNames are device names Windows refuses to use as file names,
+// with or without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeFileName removes or replaces characters that could be problematic in file paths.
+// Control and invisible formatting characters are dropped, Unicode spaces become plain
+// spaces, reserved Windows device names are suffixed with an underscore and the result
+// is truncated to MaxFileNameLength bytes on a rune boundary.
 func SanitizeFileName(name string) string {
+	// Drop control/format runes and normalize exotic whitespace
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == utf8.RuneError:
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, name)
+
 	// First trim spaces
 	name = strings.TrimSpace(name)
 
@@ -61,13 +96,89 @@ func SanitizeFileName(name string) string {
 	name = strings.ReplaceAll(name, ">", "_")
 	name = strings.ReplaceAll(name, "|", "_")
 
-	// Trim any remaining spaces or underscores from ends
-	name = strings.Trim(name, " _")
+	name = truncateToBytes(name, MaxFileNameLength)
+
+	// Trim any remaining spaces or underscores from ends; Windows also
+	// silently drops trailing dots
+	name = strings.TrimRight(strings.Trim(name, " _"), ". ")
 
 	// If the name is empty after sanitization, provide a default
 	if name == "" {
-		name = "untitled"
+		return "untitled"
+	}
+
+	// Reserved device names are reserved regardless of extension (e.g. "con.txt")
+	base, rest := name, ""
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		base, rest = name[:i], name[i:]
+	}
+	if windowsReservedNames[strings.ToUpper(strings.TrimSpace(base))] {
+		name = base + "_" + rest
+	}
+
+	return name
+}
+
+// truncateToBytes shortens s to at most maxBytes without splitting a rune
+func truncateToBytes(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
+
+// FileNameRegistry sanitizes names consistently and keeps the results unique
+// within each directory: when two different names in the same directory
+// would sanitize to the same result (compared case-insensitively), later
+// ones get a short hash of the original appended. A nil registry falls back
+// to plain SanitizeFileName.
+type FileNameRegistry struct {
+	mu       sync.Mutex
+	assigned map[dirEntry]string // directory and original name -> sanitized name
+	owners   map[dirEntry]string // lowercased directory and sanitized name -> original name
+}
+
+// dirEntry is a name within a directory
+type dirEntry struct {
+	dir  string
+	name string
+}
+
+// NewFileNameRegistry creates an empty FileNameRegistry
+func NewFileNameRegistry() *FileNameRegistry {
+	return &FileNameRegistry{
+		assigned: make(map[dirEntry]string),
+		owners:   make(map[dirEntry]string),
+	}
+}
+
+// Sanitize returns the sanitized name for original in dir, reusing earlier
+// results so the same input always maps to the same output
+func (r *FileNameRegistry) Sanitize(dir, original string) string {
+	if r == nil {
+		return SanitizeFileName(original)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	if name, ok := r.assigned[dirEntry{dir, original}]; ok {
+		return name
+	}
+
+	name := SanitizeFileName(original)
+	owner := dirEntry{strings.ToLower(dir), strings.ToLower(name)}
+	if taken, ok := r.owners[owner]; ok && taken != original {
+		sum := sha256.Sum256([]byte(original))
+		name = name + "_" + hex.EncodeToString(sum[:])[:8]
+		owner.name = strings.ToLower(name)
 	}
 
+	r.assigned[dirEntry{dir, original}] = name
+	r.owners[owner] = original
 	return name
 }
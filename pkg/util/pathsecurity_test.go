@@ -2,7 +2,9 @@ package util
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestSecureJoin(t *testing.T) {
@@ -124,6 +126,41 @@ func TestSanitizeFileName(t *testing.T) {
 			input: "...",
 			want:  "untitled",
 		},
+		{
+			name:  "all punctuation title",
+			input: "?*:|<>/\\",
+			want:  "untitled",
+		},
+		{
+			name:  "reserved device name",
+			input: "CON",
+			want:  "CON_",
+		},
+		{
+			name:  "reserved device name with extension",
+			input: "nul.md",
+			want:  "nul_.md",
+		},
+		{
+			name:  "reserved prefix is not reserved",
+			input: "Console",
+			want:  "Console",
+		},
+		{
+			name:  "trailing dots",
+			input: "Notes...",
+			want:  "Notes",
+		},
+		{
+			name:  "control and zero-width characters",
+			input: "Tab\there\u200bzero\x00",
+			want:  "Tab herezero",
+		},
+		{
+			name:  "unicode title",
+			input: "Café 日本語",
+			want:  "Café 日本語",
+		},
 	}
 
 	for _, tt := range tests {
@@ -134,3 +171,59 @@ func TestSanitizeFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestSanitizeFileName_LengthLimit(t *testing.T) {
+	// Multi-byte runes must not be split when truncating
+	got := SanitizeFileName(strings.Repeat("日", 100))
+	if len(got) > MaxFileNameLength {
+		t.Errorf("SanitizeFileName() length = %d, want <= %d", len(got), MaxFileNameLength)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("SanitizeFileName() produced invalid UTF-8: %q", got)
+	}
+}
+
+func TestFileNameRegistry_Sanitize(t *testing.T) {
+	r := NewFileNameRegistry()
+
+	// These titles collide under plain SanitizeFileName
+	first := r.Sanitize("docs", "Q1: Plan")
+	second := r.Sanitize("docs", "Q1? Plan")
+	if SanitizeFileName("Q1: Plan") != SanitizeFileName("Q1? Plan") {
+		t.Fatal("expected test titles to collide without the registry")
+	}
+
+	if first != "Q1_ Plan" {
+		t.Errorf("first name = %q, want %q", first, "Q1_ Plan")
+	}
+	if second == first {
+		t.Errorf("colliding titles both sanitized to %q", first)
+	}
+	if !strings.HasPrefix(second, "Q1_ Plan_") || len(second) != len("Q1_ Plan_")+8 {
+		t.Errorf("second name = %q, want hash-suffixed %q", second, "Q1_ Plan_<hash>")
+	}
+
+	// Results are stable for repeated inputs
+	if again := r.Sanitize("docs", "Q1? Plan"); again != second {
+		t.Errorf("repeated Sanitize() = %q, want %q", again, second)
+	}
+
+	// Names differing only by case collide on case-insensitive filesystems
+	if upper := r.Sanitize("docs", "Q1_ PLAN"); strings.EqualFold(upper, first) {
+		t.Errorf("case-only variant sanitized to %q, colliding with %q", upper, first)
+	}
+
+	// Names only need to be unique within a directory
+	if other := r.Sanitize("docs/archive", "Q1? Plan"); other != first {
+		t.Errorf("name in another directory = %q, want %q", other, first)
+	}
+	if same := r.Sanitize("docs/", "Q1? Plan"); same != second {
+		t.Errorf("name in the same directory = %q, want %q", same, second)
+	}
+
+	// A nil registry behaves like SanitizeFileName
+	var nilRegistry *FileNameRegistry
+	if got := nilRegistry.Sanitize("docs", "a/b"); got != "a_b" {
+		t.Errorf("nil registry Sanitize() = %q, want %q", got, "a_b")
+	}
+}
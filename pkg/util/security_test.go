@@ -46,7 +46,7 @@ func TestSecurityValidation_ComprehensiveTesting(t *testing.T) {
 			expected string
 		}{
 			{"../../../etc/passwd", "etc_passwd"},
-			{"con.txt", "con.txt"},                  // Windows reserved name
+			{"con.txt", "con_.txt"},                 // Windows reserved name
 			{"null", "null"},                        // Valid on most systems
 			{"file<>:\"|?*.txt", "file_______.txt"}, // Invalid chars - note the dot remains
 			{"normal_file.md", "normal_file.md"},    // Should remain unchanged
@@ -3,7 +3,9 @@ package util
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -22,9 +24,40 @@ var (
 // ValidSyncDirections contains all valid sync directions
 var ValidSyncDirections = []string{"push", "pull", "bidirectional"}
 
+// ValidConflictStrategies contains all valid conflict resolution strategies.
+// markdown_wins and notion_wins are older names for local and remote
+var ValidConflictStrategies = []string{"local", "remote", "newer", "manual", "diff", "markdown_wins", "notion_wins"}
+
+// ValidOrphanedPagesStrategies are what a push may do when a file's
+// notion_id points at a page deleted or archived in Notion
+var ValidOrphanedPagesStrategies = []string{"error", "recreate"}
+
+// ValidEmptyPagesStrategies are what a push may do with a new file that
+// converts to no blocks
+var ValidEmptyPagesStrategies = []string{"create", "skip", "placeholder"}
+
+// ValidDeletePagesStrategies are how a push may remove a page from Notion
+var ValidDeletePagesStrategies = []string{"archive", "trash"}
+
+// ValidWorkerScalingModes are how concurrent pulls may size their workers
+var ValidWorkerScalingModes = []string{"static", "adaptive"}
+
+// ValidMarkdownFlavors are the markdown flavors a pull may write
+var ValidMarkdownFlavors = []string{"commonmark", "mdx"}
+
+// ValidLinkStyles are how pulls may write links
+var ValidLinkStyles = []string{"inline", "reference"}
+
+// ValidLineEndings are the line ending styles markdown files may be written with
+var ValidLineEndings = []string{"lf", "crlf", "auto"}
+
 // NotionPageIDRegex matches valid Notion page IDs (32 hex chars or UUID format)
 var NotionPageIDRegex = regexp.MustCompile(`^[a-fA-F0-9]{32}$|^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$`)
 
+// notionURLIDRegex matches the page ID ending a Notion URL path segment,
+// either the whole segment or the part after a title slug
+var notionURLIDRegex = regexp.MustCompile(`(?:^|-)([a-fA-F0-9]{32}|[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12})$`)
+
 // NotionTokenRegex matches valid Notion integration tokens (deprecated - not used anymore)
 // We now accept any token format and let Notion validate it
 var NotionTokenRegex = regexp.MustCompile(`^.{10,}$`)
@@ -37,21 +70,72 @@ func ValidateRequired(input, fieldName string) error {
 	return nil
 }
 
-// ValidateSyncDirection validates sync direction parameter
-func ValidateSyncDirection(direction string) error {
-	if err := ValidateRequired(direction, "sync direction"); err != nil {
+// NormalizeOption returns a configured choice, such as a strategy, as it is
+// validated and compared: trimmed and lowercased
+func NormalizeOption(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// validateOneOf checks that value, normalized, is one of valid. name
+// describes the value in errors
+func validateOneOf(value, name string, valid []string) error {
+	if err := ValidateRequired(value, name); err != nil {
 		return err
 	}
 
-	direction = strings.ToLower(strings.TrimSpace(direction))
-	for _, valid := range ValidSyncDirections {
-		if direction == valid {
+	value = NormalizeOption(value)
+	for _, v := range valid {
+		if value == v {
 			return nil
 		}
 	}
 
-	return fmt.Errorf("invalid sync direction '%s', must be one of: %s",
-		direction, strings.Join(ValidSyncDirections, ", "))
+	return fmt.Errorf("invalid %s '%s', must be one of: %s", name, value, strings.Join(valid, ", "))
+}
+
+// ValidateSyncDirection validates sync direction parameter
+func ValidateSyncDirection(direction string) error {
+	return validateOneOf(direction, "sync direction", ValidSyncDirections)
+}
+
+// ValidateConflictStrategy validates a conflict resolution strategy
+func ValidateConflictStrategy(strategy string) error {
+	return validateOneOf(strategy, "conflict strategy", ValidConflictStrategies)
+}
+
+// ValidateOrphanedPagesStrategy validates how pushes handle deleted pages
+func ValidateOrphanedPagesStrategy(strategy string) error {
+	return validateOneOf(strategy, "orphaned pages strategy", ValidOrphanedPagesStrategies)
+}
+
+// ValidateEmptyPagesStrategy validates how pushes handle files without content
+func ValidateEmptyPagesStrategy(strategy string) error {
+	return validateOneOf(strategy, "empty pages strategy", ValidEmptyPagesStrategies)
+}
+
+// ValidateDeletePagesStrategy validates how pushes remove pages
+func ValidateDeletePagesStrategy(strategy string) error {
+	return validateOneOf(strategy, "delete pages strategy", ValidDeletePagesStrategies)
+}
+
+// ValidateWorkerScaling validates how concurrent pulls size their workers
+func ValidateWorkerScaling(mode string) error {
+	return validateOneOf(mode, "worker scaling mode", ValidWorkerScalingModes)
+}
+
+// ValidateMarkdownFlavor validates the markdown flavor pulls write
+func ValidateMarkdownFlavor(flavor string) error {
+	return validateOneOf(flavor, "markdown flavor", ValidMarkdownFlavors)
+}
+
+// ValidateLinkStyle validates how pulls write links
+func ValidateLinkStyle(style string) error {
+	return validateOneOf(style, "link style", ValidLinkStyles)
+}
+
+// ValidateLineEnding validates the line ending style markdown files are written with
+func ValidateLineEnding(style string) error {
+	return validateOneOf(style, "line ending style", ValidLineEndings)
 }
 
 // ValidateFilePath validates that a file path is safe and exists
@@ -115,6 +199,55 @@ func ValidateNotionPageID(pageID string) error {
 	return nil
 }
 
+// ParseNotionID accepts a page ID with or without dashes, or a Notion page
+// URL such as https://www.notion.so/My-Page-<id>, and returns the ID in the
+// dashed form the Notion API uses
+func ParseNotionID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if err := ValidateRequired(input, "Notion page ID"); err != nil {
+		return "", err
+	}
+
+	id := input
+	// IDs never contain a slash, so anything that does is a URL
+	if strings.Contains(input, "/") {
+		var err error
+		if id, err = notionIDFromURL(input); err != nil {
+			return "", err
+		}
+	}
+
+	if !NotionPageIDRegex.MatchString(id) {
+		return "", fmt.Errorf("invalid Notion page ID format: %w", ErrInvalidPageID)
+	}
+
+	id = strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32], nil
+}
+
+// notionIDFromURL extracts the page ID from a Notion URL. A page opened as a
+// peek carries its ID in the p query parameter rather than the path
+func notionIDFromURL(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid Notion URL: %w", ErrInvalidURL)
+	}
+
+	if peek := u.Query().Get("p"); NotionPageIDRegex.MatchString(peek) {
+		return peek, nil
+	}
+
+	segment := path.Base(strings.TrimSuffix(u.Path, "/"))
+	match := notionURLIDRegex.FindStringSubmatch(segment)
+	if match == nil {
+		return "", fmt.Errorf("no page ID found in Notion URL %s: %w", rawURL, ErrInvalidPageID)
+	}
+	return match[1], nil
+}
+
 // ValidateNotionToken validates a Notion integration token format
 func ValidateNotionToken(token string) error {
 	if err := ValidateRequired(token, "Notion token"); err != nil {
@@ -183,6 +316,11 @@ func SanitizeAndValidateFilename(filename string) (string, error) {
 		return "", err
 	}
 
+	// Reject overlong input up front; SanitizeFileName would silently truncate it
+	if err := ValidateStringLength(filename, "filename", 1, 255); err != nil {
+		return "", err
+	}
+
 	// Sanitize the filename
 	sanitized := SanitizeFileName(filename)
 
@@ -53,6 +53,66 @@ func TestValidateSyncDirection(t *testing.T) {
 	}
 }
 
+func TestValidateConflictStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		wantErr  bool
+	}{
+		{"local", "local", false},
+		{"remote", "remote", false},
+		{"newer", "newer", false},
+		{"manual", "manual", false},
+		{"diff", "diff", false},
+		{"legacy notion_wins", "notion_wins", false},
+		{"uppercase with spaces", " Remote ", false},
+		{"invalid strategy", "theirs", true},
+		{"empty strategy", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConflictStrategy(tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConflictStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOneOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		value    string
+		wantErr  bool
+	}{
+		{"empty pages", ValidateEmptyPagesStrategy, "skip", false},
+		{"padded empty pages", ValidateEmptyPagesStrategy, " skip", false},
+		{"invalid empty pages", ValidateEmptyPagesStrategy, "ignore", true},
+		{"orphaned pages", ValidateOrphanedPagesStrategy, "Recreate", false},
+		{"delete pages", ValidateDeletePagesStrategy, "trash ", false},
+		{"worker scaling", ValidateWorkerScaling, "adaptive", false},
+		{"markdown flavor", ValidateMarkdownFlavor, " MDX", false},
+		{"link style", ValidateLinkStyle, "reference", false},
+		{"line ending", ValidateLineEnding, "crlf", false},
+		{"blank line ending", ValidateLineEnding, "  ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := NormalizeOption(" Skip "); got != "skip" {
+		t.Errorf("NormalizeOption() = %q, want %q", got, "skip")
+	}
+}
+
 func TestValidateFilePath(t *testing.T) {
 	// Create a temporary file for testing
 	tmpFile, err := os.CreateTemp("", "test")
@@ -147,6 +207,51 @@ func TestValidateNotionPageID(t *testing.T) {
 	}
 }
 
+func TestParseNotionID(t *testing.T) {
+	const want = "abcdef12-3456-7890-abcd-ef1234567890"
+
+	inputs := []string{
+		"abcdef1234567890abcdef1234567890",
+		"abcdef12-3456-7890-abcd-ef1234567890",
+		"ABCDEF1234567890ABCDEF1234567890",
+		"  abcdef1234567890abcdef1234567890\n",
+		"https://www.notion.so/My-Page-abcdef1234567890abcdef1234567890",
+		"https://notion.so/My-Page-abcdef1234567890abcdef1234567890",
+		"https://www.notion.so/abcdef1234567890abcdef1234567890",
+		"https://www.notion.so/workspace/Project-Notes-abcdef1234567890abcdef1234567890?pvs=4",
+		"https://team.notion.site/Cafe-abcdef1234567890abcdef1234567890#heading",
+		"www.notion.so/My-Page-abcdef1234567890abcdef1234567890/",
+		"notion.so/abcdef12-3456-7890-abcd-ef1234567890",
+		"https://www.notion.so/workspace/Parent-0123456789abcdef0123456789abcdef?p=abcdef1234567890abcdef1234567890",
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseNotionID(input)
+			if err != nil {
+				t.Fatalf("ParseNotionID() error = %v", err)
+			}
+			if got != want {
+				t.Errorf("ParseNotionID() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	invalid := []string{
+		"",
+		"not-an-id",
+		"https://www.notion.so/My-Page",
+		"https://www.notion.so/My-Pageabcdef1234567890abcdef1234567890",
+		"docs/readme.md",
+	}
+	for _, input := range invalid {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if got, err := ParseNotionID(input); err == nil {
+				t.Errorf("ParseNotionID() = %q, want an error", got)
+			}
+		})
+	}
+}
+
 func TestValidateNotionToken(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,224 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/markdown"
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+)
+
+// pullGracePeriod is how long after a pull the file watcher keeps ignoring
+// the files it wrote, covering events fsnotify delivers late
+const pullGracePeriod = 3 * time.Second
+
+// PageLister lists the Notion pages a Poller watches; notion.Client
+// satisfies it
+type PageLister interface {
+	GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error)
+}
+
+// Poller periodically lists the pages under the configured parent page and
+// pulls the ones edited in Notion since the previous poll
+type Poller struct {
+	pages    PageLister
+	engine   sync.Engine
+	config   *config.Config
+	interval time.Duration
+	parser   markdown.Parser
+	guard    *pullGuard           // Shared with a Watcher in bidirectional mode
+	seen     map[string]time.Time // Page ID -> last edit time at the previous poll
+}
+
+func NewPoller(cfg *config.Config, pages PageLister, engine sync.Engine, interval time.Duration) *Poller {
+	return &Poller{
+		pages:    pages,
+		engine:   engine,
+		config:   cfg,
+		interval: interval,
+		parser:   markdown.NewParser(),
+	}
+}
+
+// Start polls until ctx is cancelled. The first poll only records the
+// current edit times, so pages are pulled once they change afterwards.
+func (p *Poller) Start(ctx context.Context) error {
+	if err := p.poll(ctx); err != nil {
+		fmt.Printf("Poll error: %v\n", err)
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.poll(ctx); err != nil {
+				fmt.Printf("Poll error: %v\n", err)
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// poll lists the pages and pulls any edited since the previous poll
+func (p *Poller) poll(ctx context.Context) error {
+	pages, err := p.pages.GetAllDescendantPages(ctx, p.config.Notion.ParentPageID, notion.UnlimitedDepth)
+	var restricted *notion.RestrictedPagesError
+	if err != nil && !errors.As(err, &restricted) {
+		return fmt.Errorf("failed to list Notion pages: %w", err)
+	}
+
+	first := p.seen == nil
+	if first {
+		p.seen = make(map[string]time.Time, len(pages))
+	}
+
+	var changed []notion.Page
+	newPages := false
+	for _, page := range pages {
+		last, known := p.seen[page.ID]
+		p.seen[page.ID] = page.LastEditedTime
+		if first {
+			continue
+		}
+		if !known {
+			newPages = true
+		} else if page.LastEditedTime.After(last) {
+			changed = append(changed, page)
+		}
+	}
+
+	if len(changed) == 0 && !newPages {
+		return nil
+	}
+
+	// New pages need the full tree to know where their files belong
+	files := p.localFiles()
+	for _, page := range changed {
+		if _, ok := files[page.ID]; !ok {
+			newPages = true
+		}
+	}
+	if newPages {
+		return p.pullAll(ctx)
+	}
+
+	for _, page := range changed {
+		p.pullPage(ctx, page, files[page.ID])
+	}
+	return nil
+}
+
+func (p *Poller) pullPage(ctx context.Context, page notion.Page, filePath string) {
+	fmt.Printf("☁️  Page changed in Notion: %s\n", page.Title())
+
+	p.guard.begin(filePath)
+	defer p.guard.end(filePath)
+
+	if err := p.engine.SyncNotionToFile(ctx, page.ID, filePath); err != nil {
+		fmt.Printf("❌ Failed to pull %s: %v\n", filePath, err)
+	} else {
+		fmt.Printf("✅ Pulled %s from Notion\n", filePath)
+	}
+}
+
+func (p *Poller) pullAll(ctx context.Context) error {
+	fmt.Println("☁️  New pages in Notion, pulling all pages")
+
+	p.guard.begin("")
+	defer p.guard.end("")
+
+	if err := p.engine.SyncAll(ctx, "pull"); err != nil {
+		return fmt.Errorf("failed to pull from Notion: %w", err)
+	}
+	return nil
+}
+
+// localFiles maps the notion_id of each markdown file under the markdown
+// root to its path
+func (p *Poller) localFiles() map[string]string {
+	files := make(map[string]string)
+	_ = filepath.Walk(p.config.Directories.MarkdownRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		doc, err := p.parser.ParseFile(path)
+		if err != nil {
+			return nil
+		}
+		frontmatter, err := markdown.ExtractFrontmatter(doc.Metadata)
+		if err != nil || frontmatter.NotionID == "" {
+			return nil
+		}
+
+		files[frontmatter.NotionID] = path
+		return nil
+	})
+	return files
+}
+
+// pullGuard tracks the files pulls are writing, so a Watcher doesn't push
+// them straight back to Notion. The empty path stands for every file. A nil
+// guard tracks nothing.
+type pullGuard struct {
+	mu     gosync.Mutex
+	active map[string]int       // Path -> pulls in progress
+	until  map[string]time.Time // Path -> end of its grace period
+}
+
+func newPullGuard() *pullGuard {
+	return &pullGuard{
+		active: make(map[string]int),
+		until:  make(map[string]time.Time),
+	}
+}
+
+func (g *pullGuard) begin(path string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active[path]++
+}
+
+func (g *pullGuard) end(path string) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active[path]--; g.active[path] <= 0 {
+		delete(g.active, path)
+	}
+	g.until[path] = time.Now().Add(pullGracePeriod)
+}
+
+// suppressed reports whether changes to path were likely made by a pull
+func (g *pullGuard) suppressed(path string) bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range []string{path, ""} {
+		if g.active[key] > 0 || now.Before(g.until[key]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/byvfx/go-notion-md-sync/pkg/notion"
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPageLister returns a fixed set of pages whose edit times tests advance
+type mockPageLister struct {
+	pages []notion.Page
+}
+
+func (m *mockPageLister) GetAllDescendantPages(ctx context.Context, parentID string, maxDepth int) ([]notion.Page, error) {
+	return append([]notion.Page{}, m.pages...), nil
+}
+
+func writePageFile(t *testing.T, dir, name, pageID string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	content := "---\nnotion_id: " + pageID + "\n---\n# " + name + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestPoller_PullsPagesEditedSincePreviousPoll(t *testing.T) {
+	tempDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	notesPath := writePageFile(t, tempDir, "notes.md", "page-1")
+	writePageFile(t, tempDir, "todo.md", "page-2")
+
+	edited := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lister := &mockPageLister{pages: []notion.Page{
+		{ID: "page-1", LastEditedTime: edited},
+		{ID: "page-2", LastEditedTime: edited},
+	}}
+	engine := &mockEngine{}
+	poller := NewPoller(createTestConfig(tempDir), lister, engine, time.Minute)
+	ctx := context.Background()
+
+	// The first poll only records edit times
+	require.NoError(t, poller.poll(ctx))
+	assert.Empty(t, engine.pulledFiles)
+
+	// Nothing changed
+	require.NoError(t, poller.poll(ctx))
+	assert.Empty(t, engine.pulledFiles)
+
+	// page-1 is edited in Notion
+	lister.pages[0].LastEditedTime = edited.Add(time.Minute)
+	require.NoError(t, poller.poll(ctx))
+	assert.Equal(t, []string{notesPath}, engine.pulledFiles)
+	assert.Zero(t, engine.fullPulls)
+
+	// The edit is only pulled once
+	require.NoError(t, poller.poll(ctx))
+	assert.Equal(t, []string{notesPath}, engine.pulledFiles)
+}
+
+func TestPoller_NewPagePullsEverything(t *testing.T) {
+	tempDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	lister := &mockPageLister{pages: []notion.Page{{ID: "page-1"}}}
+	engine := &mockEngine{}
+	poller := NewPoller(createTestConfig(tempDir), lister, engine, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, poller.poll(ctx))
+
+	lister.pages = append(lister.pages, notion.Page{ID: "page-2"})
+	require.NoError(t, poller.poll(ctx))
+	assert.Equal(t, 1, engine.fullPulls)
+	assert.Empty(t, engine.pulledFiles)
+}
+
+func TestWatcher_IgnoresFilesBeingPulled(t *testing.T) {
+	tempDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	engine := &mockEngine{}
+	cfg := createTestConfig(tempDir)
+	w, err := NewWatcher(cfg, engine)
+	require.NoError(t, err)
+	defer func() { _ = w.Close() }()
+	w.debouncer.interval = 10 * time.Millisecond
+
+	poller := NewPoller(cfg, &mockPageLister{}, engine, time.Minute)
+	w.IgnorePulls(poller)
+
+	pulled := filepath.Join(tempDir, "pulled.md")
+	other := filepath.Join(tempDir, "other.md")
+
+	poller.guard.begin(pulled)
+	w.handleEvent(context.Background(), fsnotify.Event{Name: pulled, Op: fsnotify.Write})
+	poller.guard.end(pulled)
+
+	// Still within the grace period after the pull
+	w.handleEvent(context.Background(), fsnotify.Event{Name: pulled, Op: fsnotify.Write})
+	w.handleEvent(context.Background(), fsnotify.Event{Name: other, Op: fsnotify.Write})
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{other}, engine.getSyncedFiles())
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
 	"github.com/byvfx/go-notion-md-sync/pkg/sync"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/fsnotify/fsnotify"
 )
 
@@ -17,6 +18,8 @@ type Watcher struct {
 	engine    sync.Engine
 	config    *config.Config
 	debouncer *debouncer
+	guard     *pullGuard          // Set by IgnorePulls
+	ignore    *util.IgnoreMatcher // excluded_patterns and .notionignore
 }
 
 type debouncer struct {
@@ -36,7 +39,7 @@ func NewWatcher(cfg *config.Config, engine sync.Engine) (*Watcher, error) {
 		return nil, fmt.Errorf("failed to watch directory %s: %w", cfg.Directories.MarkdownRoot, err)
 	}
 
-	return &Watcher{
+	w := &Watcher{
 		fsWatcher: fsWatcher,
 		engine:    engine,
 		config:    cfg,
@@ -44,7 +47,19 @@ func NewWatcher(cfg *config.Config, engine sync.Engine) (*Watcher, error) {
 			interval: 2 * time.Second,
 			pending:  make(map[string]*time.Timer),
 		},
-	}, nil
+	}
+	w.loadIgnore()
+	return w, nil
+}
+
+// loadIgnore reads the exclusion rules, falling back to the config patterns
+// alone when .notionignore can't be read
+func (w *Watcher) loadIgnore() {
+	ignore, err := util.LoadIgnoreMatcher(w.config.Directories.MarkdownRoot, w.config.Directories.ExcludedPatterns)
+	if err != nil {
+		fmt.Printf("Watcher error: %v\n", err)
+	}
+	w.ignore = ignore
 }
 
 func (w *Watcher) Start(ctx context.Context) error {
@@ -70,6 +85,15 @@ func (w *Watcher) Start(ctx context.Context) error {
 	}
 }
 
+// IgnorePulls makes the watcher skip changes to files while p pulls them,
+// so running both directions doesn't push pulled content straight back
+func (w *Watcher) IgnorePulls(p *Poller) {
+	if w.guard == nil {
+		w.guard = newPullGuard()
+	}
+	p.guard = w.guard
+}
+
 func (w *Watcher) Close() error {
 	if w.fsWatcher != nil {
 		return w.fsWatcher.Close()
@@ -78,6 +102,12 @@ func (w *Watcher) Close() error {
 }
 
 func (w *Watcher) handleEvent(ctx context.Context, event fsnotify.Event) {
+	// Pick up edits to the ignore file without a restart
+	if filepath.Base(event.Name) == util.IgnoreFileName {
+		w.loadIgnore()
+		return
+	}
+
 	// Only process markdown files
 	if !strings.HasSuffix(event.Name, ".md") {
 		return
@@ -93,6 +123,11 @@ func (w *Watcher) handleEvent(ctx context.Context, event fsnotify.Event) {
 		return
 	}
 
+	// Skip files written by a pull
+	if w.guard.suppressed(event.Name) {
+		return
+	}
+
 	fmt.Printf("📝 File changed: %s\n", event.Name)
 
 	// Debounce the event
@@ -109,13 +144,20 @@ func (w *Watcher) syncFile(ctx context.Context, filePath string) {
 	}
 }
 
+// isExcluded matches path like the engine does when pushing, so watched
+// files and pushed files agree
 func (w *Watcher) isExcluded(path string) bool {
 	for _, pattern := range w.config.Directories.ExcludedPatterns {
 		if matched, _ := filepath.Match(pattern, path); matched {
 			return true
 		}
 	}
-	return false
+
+	rel, err := filepath.Rel(w.config.Directories.MarkdownRoot, path)
+	if err != nil {
+		rel = path
+	}
+	return w.ignore.Ignored(rel, false)
 }
 
 func (d *debouncer) debounce(key string, fn func()) {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/byvfx/go-notion-md-sync/pkg/config"
+	"github.com/byvfx/go-notion-md-sync/pkg/util"
 	"github.com/fsnotify/fsnotify"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,8 @@ import (
 type mockEngine struct {
 	mu          sync.Mutex
 	syncedFiles []string
+	pulledFiles []string
+	fullPulls   int
 	syncError   error
 }
 
@@ -36,10 +39,18 @@ func (m *mockEngine) SyncFileToNotion(ctx context.Context, filePath string) erro
 }
 
 func (m *mockEngine) SyncNotionToFile(ctx context.Context, pageID, filePath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pulledFiles = append(m.pulledFiles, filePath)
 	return nil
 }
 
 func (m *mockEngine) SyncAll(ctx context.Context, direction string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if direction == "pull" {
+		m.fullPulls++
+	}
 	return nil
 }
 
@@ -47,6 +58,14 @@ func (m *mockEngine) SyncSpecificFile(ctx context.Context, filename, direction s
 	return nil
 }
 
+func (m *mockEngine) SyncPageSubtree(ctx context.Context, pageID, direction string) error {
+	return nil
+}
+
+func (m *mockEngine) DiffFile(ctx context.Context, filePath, direction string) (string, error) {
+	return "", nil
+}
+
 func (m *mockEngine) getSyncedFiles() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -291,7 +310,7 @@ func TestWatcher_isExcluded(t *testing.T) {
 		{
 			name:     "nested file with tmp extension",
 			path:     "docs/test.tmp",
-			excluded: false, // *.tmp doesn't match paths with directories
+			excluded: true, // *.tmp matches file names at any depth
 		},
 	}
 
@@ -303,6 +322,29 @@ func TestWatcher_isExcluded(t *testing.T) {
 	}
 }
 
+func TestWatcher_NotionIgnore(t *testing.T) {
+	tempDir, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	cfg := createTestConfig(tempDir)
+	watcher, err := NewWatcher(cfg, &mockEngine{})
+	require.NoError(t, err)
+	defer func() { _ = watcher.Close() }()
+
+	draft := filepath.Join(tempDir, "drafts", "idea.md")
+	assert.False(t, watcher.isExcluded(draft))
+
+	// Editing .notionignore takes effect without restarting the watcher
+	ignorePath := filepath.Join(tempDir, util.IgnoreFileName)
+	require.NoError(t, os.WriteFile(ignorePath, []byte("drafts/\n!*.tmp\n"), 0644))
+	watcher.handleEvent(context.Background(), fsnotify.Event{Name: ignorePath, Op: fsnotify.Write})
+
+	assert.True(t, watcher.isExcluded(draft))
+	assert.True(t, watcher.isExcluded(filepath.Join(tempDir, "drafts", "nested", "idea.md")))
+	assert.False(t, watcher.isExcluded(filepath.Join(tempDir, "temp.tmp")), ".notionignore overrides excluded_patterns")
+	assert.True(t, watcher.isExcluded(filepath.Join(tempDir, "excluded", "test.md")))
+}
+
 func TestWatcher_syncFile(t *testing.T) {
 	tempDir, cleanup := setupTestDir(t)
 	defer cleanup()